@@ -1,11 +1,14 @@
 package api
 
 import (
-	"employee-crud/dao"
+	"employee-crud/dto"
+	"employee-crud/repository"
 	"employee-crud/utils"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // AddStockRequest represents the request body for adding stock
@@ -13,13 +16,15 @@ type AddStockRequest struct {
 	ProductId    string     `json:"productId" validate:"required"`
 	StockQty     int        `json:"stockQty" validate:"required,gt=0"`
 	ExpiryDate   *time.Time `json:"expiryDate"`
-	CostPrice    float64    `json:"costPrice" validate:"gt=0"`
-	SellingPrice float64    `json:"sellingPrice" validate:"gt=0"`
+	CostPrice    dto.Money  `json:"costPrice" validate:"gt=0"`
+	SellingPrice dto.Money  `json:"sellingPrice" validate:"gt=0"`
+	LocationId   string     `json:"locationId"` // Defaults to the main location
 }
 
 // AddStock adds stock to an existing product
 // If expiry date matches existing batch, adds to that batch
 // If expiry date is different, creates new batch
+// Batches are matched and created at the request's location only
 func AddStock(c *fiber.Ctx) error {
 	var req AddStockRequest
 
@@ -27,20 +32,27 @@ func AddStock(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 
+	locationId, err := resolveLocation(req.LocationId)
+	if err != nil {
+		return respondError(c, err, "Failed to check location")
+	}
+
 	// Add stock to product
-	product, batchId, err := dao.DB_AddStockToProduct(
+	product, batchId, err := repos.Products.AddStock(
 		req.ProductId,
+		locationId,
 		req.StockQty,
 		req.ExpiryDate,
 		req.CostPrice,
 		req.SellingPrice,
+		dto.StockMovementRef{Type: dto.MovementStockAdd, UserId: requestUser(c)},
 	)
 	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return utils.SendErrorResponse(c, stockUpdateErrorStatus(err), err.Error())
 	}
 
 	// Sync stock to Stocks collection
-	if err := dao.DB_SyncSingleProductStock(product); err != nil {
+	if err := repos.Stocks.SyncProduct(product); err != nil {
 		// Log but don't fail
 	}
 
@@ -51,3 +63,16 @@ func AddStock(c *fiber.Ctx) error {
 		"product":   product,
 	})
 }
+
+// stockUpdateErrorStatus maps errors from the batch DAOs to HTTP status codes
+// A lost optimistic-locking race is reported as 409 so the client can retry
+func stockUpdateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrProductVersionConflict):
+		return fiber.StatusConflict
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return fiber.StatusNotFound
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
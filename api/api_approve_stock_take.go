@@ -0,0 +1,39 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ApproveStockTakeApi posts the counted variances of an open stock take into the batches and Stocks
+// Lines that were never counted are left as they are
+func ApproveStockTakeApi(c *fiber.Ctx) error {
+	stockTake, err := findStockTake(c.Query("stockTakeId"), dto.StockTakeOpen)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve stock take")
+	}
+
+	approved, err := repos.StockTakes.Approve(stockTake.StockTakeId, requestUser(c))
+	if err != nil {
+		return respondStockTakeUpdateError(c, err, "Failed to approve stock take")
+	}
+	utils.MetricsCache.Delete("stock_status_counts")
+	utils.MetricsCache.Delete("total_stock_quantity")
+	return c.JSON(approved)
+}
+
+// CancelStockTakeApi drops an open stock take without changing any stock
+func CancelStockTakeApi(c *fiber.Ctx) error {
+	stockTake, err := findStockTake(c.Query("stockTakeId"), dto.StockTakeOpen)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve stock take")
+	}
+
+	if err := repos.StockTakes.Cancel(stockTake.StockTakeId, time.Now().UTC()); err != nil {
+		return respondStockTakeUpdateError(c, err, "Failed to cancel stock take")
+	}
+	return c.JSON(fiber.Map{"message": "Stock take " + stockTake.StockTakeId + " cancelled"})
+}
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,7 +14,7 @@ func AssignProductToSupplierApi(c *fiber.Ctx) error {
 		return utils.NewCustomError(c, fiber.StatusBadRequest, "supplierId and productId are required", nil)
 	}
 
-	if err := dao.DB_AssignProductToSupplier(supplierId, productId); err != nil {
+	if err := repos.Suppliers.AssignProduct(supplierId, productId); err != nil {
 		return utils.NewCustomError(c, fiber.StatusBadRequest, err.Error(), nil)
 	}
 
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Keys under which RequireAuth stores the authenticated user in fiber Locals
+const (
+	localUserId   = "userId"
+	localUsername = "username"
+	localRole     = "role"
+)
+
+var (
+	authSecret   []byte
+	authTokenTTL = 12 * time.Hour
+)
+
+// InitAuth sets the secret used to sign and verify tokens and how long issued tokens stay valid
+func InitAuth(secret string, tokenTTL time.Duration) {
+	authSecret = []byte(secret)
+	if tokenTTL > 0 {
+		authTokenTTL = tokenTTL
+	}
+}
+
+// RequireAuth rejects requests without a valid "Authorization: Bearer <token>" header
+// and stores the user's id, username and role in Locals for later handlers
+// The user is re-read on every request, so deactivating an account, changing its role or its password
+// takes effect at once instead of when its tokens expire
+func RequireAuth(c *fiber.Ctx) error {
+	header := c.Get(fiber.HeaderAuthorization)
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || token == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing bearer token"})
+	}
+
+	claims, err := functions.ParseToken(token, authSecret, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	user, err := repos.Users.FindById(claims.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User no longer exists"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load user"})
+	}
+	if !user.Active {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User account is deactivated"})
+	}
+	if claims.Version != user.TokenVersion {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Token was revoked, please log in again"})
+	}
+
+	c.Locals(localUserId, user.UserId)
+	c.Locals(localUsername, user.Username)
+	c.Locals(localRole, user.Role)
+	return c.Next()
+}
+
+// RequireRoles only lets through users whose role is one of roles (admins are always allowed)
+// It must run after RequireAuth
+func RequireRoles(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals(localRole).(string)
+		if !functions.RoleAllowed(role, roles) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "You do not have permission to perform this action"})
+		}
+		return c.Next()
+	}
+}
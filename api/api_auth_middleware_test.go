@@ -0,0 +1,106 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository/memory"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireAuthUsesTheStoredUserNotTheTokenClaims(t *testing.T) {
+	mem := memory.NewRepositories()
+	previous := repos
+	SetRepositories(mem.Repositories)
+	t.Cleanup(func() { SetRepositories(previous) })
+	InitAuth("test-secret", time.Hour)
+
+	app := fiber.New()
+	app.Delete("/DeleteProductPermanent", RequireAuth, RequireRoles(dto.RoleAdmin), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// Every token is signed while the user is an active admin
+	send := func(user dto.User) int {
+		t.Helper()
+		token, _, err := functions.SignToken(&dto.User{UserId: user.UserId, Username: user.Username, Role: dto.RoleAdmin}, authSecret, time.Hour, time.Now())
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		if err := mem.Users.Create(&user); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		req := httptest.NewRequest(fiber.MethodDelete, "/DeleteProductPermanent", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := send(dto.User{UserId: "USR-001", Username: "admin", Role: dto.RoleAdmin, Active: true}); status != fiber.StatusOK {
+		t.Fatalf("expected 200 for an active admin, got %d", status)
+	}
+	if status := send(dto.User{UserId: "USR-002", Username: "fired", Role: dto.RoleAdmin, Active: false}); status != fiber.StatusUnauthorized {
+		t.Fatalf("expected 401 for a deactivated admin, got %d", status)
+	}
+	if status := send(dto.User{UserId: "USR-003", Username: "demoted", Role: dto.RoleCashier, Active: true}); status != fiber.StatusForbidden {
+		t.Fatalf("expected 403 for an admin demoted to cashier, got %d", status)
+	}
+}
+
+func TestChangingPasswordRevokesEarlierTokens(t *testing.T) {
+	mem := memory.NewRepositories()
+	previous := repos
+	SetRepositories(mem.Repositories)
+	t.Cleanup(func() { SetRepositories(previous) })
+	InitAuth("test-secret", time.Hour)
+
+	user, err := newUser(dto.CreateUserRequest{Username: "cashier", Password: "old-password", Role: dto.RoleCashier})
+	if err != nil {
+		t.Fatalf("new user: %v", err)
+	}
+	if err := mem.Users.Create(user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	app := fiber.New()
+	app.Post("/Login", LoginApi)
+
+	var login dto.LoginResponse
+	if status := doJSON(t, app, fiber.MethodPost, "/Login", dto.LoginRequest{Username: "cashier", Password: "old-password"}, &login); status != fiber.StatusOK {
+		t.Fatalf("expected 200 logging in, got %d", status)
+	}
+
+	// withToken sends the request with the token as its bearer
+	withToken := func(method string, path string, token string, body interface{}, out interface{}) int {
+		t.Helper()
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Request().Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+			return c.Next()
+		})
+		app.Put("/ChangePassword", RequireAuth, ChangePasswordApi)
+		app.Get("/GetCurrentUser", RequireAuth, GetCurrentUserApi)
+		return doJSON(t, app, method, path, body, out)
+	}
+
+	var changed struct {
+		Token string `json:"token"`
+	}
+	change := dto.ChangePasswordRequest{CurrentPassword: "old-password", NewPassword: "new-password"}
+	if status := withToken(fiber.MethodPut, "/ChangePassword", login.Token, change, &changed); status != fiber.StatusOK || changed.Token == "" {
+		t.Fatalf("expected 200 and a new token, got %d and %+v", status, changed)
+	}
+
+	if status := withToken(fiber.MethodGet, "/GetCurrentUser", login.Token, nil, nil); status != fiber.StatusUnauthorized {
+		t.Fatalf("expected 401 for the token issued before the change, got %d", status)
+	}
+	if status := withToken(fiber.MethodGet, "/GetCurrentUser", changed.Token, nil, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200 for the new token, got %d", status)
+	}
+}
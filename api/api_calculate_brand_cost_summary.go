@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,7 +13,7 @@ func GetBrandCostSummaryApi(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "brandId is required")
 	}
 
-	totalCost, expectedCost, err := dao.DB_GetBrandCostSummary(brandId)
+	totalCost, expectedCost, err := repos.Reports.GetBrandCostSummary(brandId)
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
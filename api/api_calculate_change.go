@@ -1,13 +1,18 @@
 package api
 
 import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 func CalculateChangeApi(c *fiber.Ctx) error {
+	// Either tenders (split payments) or amountReceived (all cash) can be given
 	type CalculateChangeRequest struct {
-		Total          float64 `json:"total" binding:"required"`
-		AmountReceived float64 `json:"amountReceived" binding:"required"`
+		Total          dto.Money    `json:"total" binding:"required"`
+		AmountReceived dto.Money    `json:"amountReceived"`
+		Tenders        []dto.Tender `json:"tenders,omitempty"`
 	}
 
 	var req CalculateChangeRequest
@@ -18,6 +23,20 @@ func CalculateChangeApi(c *fiber.Ctx) error {
 		})
 	}
 
+	if len(req.Tenders) > 0 {
+		tenders, cashReceived, change, err := functions.SettleTenders(req.Total, req.Tenders)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"change":         change,
+			"amountReceived": cashReceived,
+			"tenders":        tenders,
+		})
+	}
+
 	if req.AmountReceived < req.Total {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Amount received is less than total",
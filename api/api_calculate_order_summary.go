@@ -1,11 +1,16 @@
 package api
 
 import (
+	"employee-crud/config"
 	"employee-crud/dto"
+	"employee-crud/functions"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// CalculateOrderSummaryApi prices the items at their current selling price, applies the running promotions,
+// taxes each line by its tax class and takes off the cashier's discount, the same way CreateSale will
 func CalculateOrderSummaryApi(c *fiber.Ctx) error {
 	var req dto.CalculateOrderSummaryRequest
 
@@ -22,35 +27,131 @@ func CalculateOrderSummaryApi(c *fiber.Ctx) error {
 		})
 	}
 
+	response, _, err := priceOrder(req.Items, req.Discount, req.DiscountType, "", nil)
+	if err != nil {
+		return respondError(c, err, "Failed to calculate order summary")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// priceOrder prices sale lines at their products' current selling price, applies the running promotions,
+// taxes each line and takes off the cashier's discount; it is the pricing path shared by the order summary,
+// carts and checkout. The lines are priced in place and their products are returned by id.
+// When reserved is not nil each line must also fit in its product's stock at locationId less the quantity
+// reserved there by carts.
+func priceOrder(items []dto.SaleItem, discountValue float64, discountType string, locationId string, reserved map[string]int) (*dto.OrderSummaryResponse, map[string]*dto.Product, error) {
 	// Calculate subtotal
-	var subtotal float64 = 0
-	for _, item := range req.Items {
-		subtotal += item.TotalPrice
+	var subtotal dto.Money = 0
+	products := make(map[string]*dto.Product, len(items))
+	for i := range items {
+		product, err := repos.Products.FindById(items[i].ProductID)
+		if err != nil {
+			return nil, nil, newRequestError(fiber.StatusNotFound, "Product not found: "+items[i].ProductID)
+		}
+		if reserved != nil && functions.LocationStock(product, locationId)-reserved[product.ProductId] < items[i].Quantity {
+			return nil, nil, newRequestError(fiber.StatusBadRequest, "Insufficient stock for product: "+product.Name)
+		}
+		products[product.ProductId] = product
+
+		items[i].ProductName = product.Name
+		items[i].UnitPrice = product.SellingPrice
+		items[i].TotalPrice = product.SellingPrice.Times(items[i].Quantity)
+		subtotal += items[i].TotalPrice
 	}
 
-	// Calculate tax
-	var tax float64 = 0
-	if req.TaxPercentage > 0 {
-		tax = subtotal * (req.TaxPercentage / 100)
+	// Apply promotions; tax and the cashier's discount are worked out on what is left
+	applied, promotionDiscount, err := applyPromotions(items, products)
+	if err != nil {
+		return nil, nil, newRequestError(fiber.StatusInternalServerError, "Failed to load promotions")
 	}
+	net := subtotal - promotionDiscount
 
-	// Calculate discount
-	var discount float64 = 0
-	if req.DiscountType == "percentage" {
-		discount = subtotal * (req.Discount / 100)
+	// Calculate tax per line from each product's tax class
+	tax, includedTax, taxBreakdown, err := applyLineTaxes(items, products)
+	if err != nil {
+		return nil, nil, newRequestError(fiber.StatusInternalServerError, "Failed to load tax classes")
+	}
+	if taxBreakdown == nil {
+		taxBreakdown = []dto.TaxSummary{}
+	}
+
+	// Calculate discount; it can take the bill down to zero but never below
+	if discountValue < 0 {
+		return nil, nil, newRequestError(fiber.StatusBadRequest, "Discount cannot be negative")
+	}
+	var discount dto.Money = 0
+	if discountType == "percentage" {
+		if discountValue > 100 {
+			return nil, nil, newRequestError(fiber.StatusBadRequest, "Percentage discount cannot be more than 100")
+		}
+		discount = net.Percent(discountValue)
 	} else {
-		discount = req.Discount
+		discount = dto.MoneyFromFloat(discountValue)
+	}
+	if discount > net+tax {
+		return nil, nil, newRequestError(fiber.StatusBadRequest, "Discount of "+discount.String()+" is more than the "+(net+tax).String()+" due")
 	}
 
 	// Calculate total
-	total := subtotal + tax - discount
+	total := net + tax - discount
 
-	response := dto.OrderSummaryResponse{
-		Subtotal: subtotal,
-		Tax:      tax,
-		Discount: discount,
-		Total:    total,
+	return &dto.OrderSummaryResponse{
+		Items:             items,
+		Subtotal:          subtotal,
+		PromotionDiscount: promotionDiscount,
+		Promotions:        applied,
+		Tax:               tax,
+		IncludedTax:       includedTax,
+		TaxBreakdown:      taxBreakdown,
+		Discount:          discount,
+		Total:             total,
+	}, products, nil
+}
+
+// applyPromotions discounts priced sale lines with the promotions running now and returns the promotions
+// that applied and the total discount; products holds each line's product by id
+func applyPromotions(items []dto.SaleItem, products map[string]*dto.Product) ([]dto.AppliedPromotion, dto.Money, error) {
+	now := time.Now().In(config.Location())
+	promotions, err := repos.Promotions.FindRunning(now)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response)
+	applied, discount := functions.ApplyPromotions(items, products, promotions, now)
+	if applied == nil {
+		applied = []dto.AppliedPromotion{}
+	}
+	return applied, discount, nil
+}
+
+// applyLineTaxes taxes priced sale lines by the tax class of their product, or else of its category,
+// and returns the tax to add, the tax included in prices and the breakdown by class
+func applyLineTaxes(items []dto.SaleItem, products map[string]*dto.Product) (dto.Money, dto.Money, []dto.TaxSummary, error) {
+	classes, err := repos.Taxes.FindAllClasses()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	categoryClasses, err := repos.Taxes.CategoryClasses()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	byId := make(map[string]*dto.TaxClass, len(classes))
+	for i := range classes {
+		byId[classes[i].TaxClassID] = &classes[i]
+	}
+	lineClasses := make(map[string]*dto.TaxClass, len(products))
+	for productId, product := range products {
+		classId := product.TaxClassID
+		if classId == "" {
+			classId = categoryClasses[product.CategoryID]
+		}
+		if class, ok := byId[classId]; ok {
+			lineClasses[productId] = class
+		}
+	}
+
+	tax, includedTax, breakdown := functions.ApplyLineTaxes(items, lineClasses)
+	return tax, includedTax, breakdown, nil
 }
@@ -1,13 +1,11 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
 func CalculateTotalAndExpectedCost(c *fiber.Ctx) error {
-	totalCost, expectedCost, err := dao.DB_CalculateTotalAndExpectedCost()
+	totalCost, expectedCost, err := repos.Reports.CalculateTotalAndExpectedCost()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -1,15 +1,13 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
 // CleanupOrphanedStocksApi removes stock entries with null or empty batchId
 // This is a maintenance endpoint to fix data integrity issues
 func CleanupOrphanedStocksApi(c *fiber.Ctx) error {
-	deletedCount, err := dao.DB_CleanupOrphanedStocks()
+	deletedCount, err := repos.Stocks.CleanupOrphaned()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -25,7 +23,7 @@ func CleanupOrphanedStocksApi(c *fiber.Ctx) error {
 // ValidateStockIntegrityApi checks for inconsistencies in stock data
 // Returns a report of any issues found
 func ValidateStockIntegrityApi(c *fiber.Ctx) error {
-	report, err := dao.DB_ValidateStockIntegrity()
+	report, err := repos.Stocks.ValidateIntegrity()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
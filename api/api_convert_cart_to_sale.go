@@ -0,0 +1,58 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConvertCartToSaleApi checks out an open or parked cart through the same path as CreateSale
+// The cart is marked converted together with the sale; if the sale fails the cart stays as it was
+func ConvertCartToSaleApi(c *fiber.Ctx) error {
+	var req dto.ConvertCartRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	cart, err := findCart(req.CartID, dto.CartOpen, dto.CartParked)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve cart")
+	}
+	if len(cart.Items) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "The cart is empty"})
+	}
+
+	saleReq := dto.CreateSaleRequest{
+		CustomerID:   cart.CustomerID,
+		CustomerName: cart.CustomerName,
+		MobileNumber: cart.MobileNumber,
+		LocationId:   cart.LocationId,
+		Items:        functions.CartSaleItems(cart.Items),
+		Discount:     req.Discount,
+		DiscountType: req.DiscountType,
+		Tenders:      req.Tenders,
+		RedeemPoints: req.RedeemPoints,
+		RedeemAs:     req.RedeemAs,
+	}
+	if req.CustomerID != "" || req.MobileNumber != "" {
+		saleReq.CustomerID = req.CustomerID
+		saleReq.MobileNumber = req.MobileNumber
+		saleReq.CustomerName = req.CustomerName
+	} else if req.CustomerName != "" {
+		saleReq.CustomerName = req.CustomerName
+	}
+
+	// The cart is converted in the checkout transaction, so it cannot be sold twice or left converted without a sale
+	sale, applied, err := checkoutSale(saleReq, requestUser(c), cart)
+	if err != nil {
+		return respondError(c, err, "Failed to create sale")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":    "Sale created successfully and stocks updated",
+		"sale":       sale,
+		"promotions": applied,
+		"cart":       cart,
+	})
+}
@@ -1,13 +1,11 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
 func GetTotalGRNsCount(c *fiber.Ctx) error {
-	count, err := dao.DB_CountTotalGRNs()
+	count, err := repos.GRNs.CountTotal()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
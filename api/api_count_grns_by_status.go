@@ -1,13 +1,11 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
 func GetCompletedGRNsCount(c *fiber.Ctx) error {
-	count, err := dao.DB_CountGRNsByStatus("completed")
+	count, err := repos.GRNs.CountByStatus("completed")
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -20,7 +18,7 @@ func GetCompletedGRNsCount(c *fiber.Ctx) error {
 }
 
 func GetPendingGRNsCount(c *fiber.Ctx) error {
-	count, err := dao.DB_CountGRNsByStatus("pending")
+	count, err := repos.GRNs.CountByStatus("pending")
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -33,7 +31,7 @@ func GetPendingGRNsCount(c *fiber.Ctx) error {
 }
 
 func GetPartialReceivedGRNsCount(c *fiber.Ctx) error {
-	count, err := dao.DB_CountGRNsByStatus("partial_received")
+	count, err := repos.GRNs.CountByStatus("partial_received")
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
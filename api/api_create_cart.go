@@ -0,0 +1,144 @@
+package api
+
+import (
+	"employee-crud/config"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CreateCartApi opens a draft cart at a terminal, optionally with its first lines
+// With reserveStock the cart's quantities are held back from other carts and sales for carts.reservationMinutes
+// after each change, which is renewed while the cart is worked on, parked or resumed
+func CreateCartApi(c *fiber.Ctx) error {
+	var req dto.CreateCartRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.TerminalID = strings.TrimSpace(req.TerminalID)
+	if req.TerminalID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "terminalId is required"})
+	}
+	locationId, err := resolveLocation(req.LocationId)
+	if err != nil {
+		return respondError(c, err, "Failed to check location")
+	}
+
+	now := time.Now().UTC()
+	cart := &dto.Cart{
+		CartID:       uuid.New().String(),
+		TerminalID:   req.TerminalID,
+		Status:       dto.CartOpen,
+		CustomerID:   req.CustomerID,
+		CustomerName: req.CustomerName,
+		MobileNumber: req.MobileNumber,
+		LocationId:   locationId,
+		Items:        []dto.CartItem{},
+		ReserveStock: req.ReserveStock,
+		CreatedBy:    requestUser(c),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "quantity must be positive"})
+		}
+		quantity := functions.CartLineQuantity(cart.Items, item.ProductID) + item.Quantity
+		product, err := checkCartStock(cart, item.ProductID, quantity)
+		if err != nil {
+			return respondError(c, err, "Failed to check stock")
+		}
+		cart.Items = functions.SetCartLine(cart.Items, product.ProductId, product.Name, quantity)
+	}
+	refreshCartReservation(cart, now)
+
+	if err := repos.Carts.Create(cart); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create cart"})
+	}
+
+	response, err := cartResponse(cart)
+	if err != nil {
+		return respondError(c, err, "Failed to price cart")
+	}
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// cartResponse prices a cart's lines the way CreateSale will, without a cashier's discount
+func cartResponse(cart *dto.Cart) (*dto.CartResponse, error) {
+	summary, _, err := priceOrder(functions.CartSaleItems(cart.Items), 0, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CartResponse{Cart: *cart, Summary: *summary}, nil
+}
+
+// findCart loads a cart and checks it is in one of statuses
+func findCart(cartId string, statuses ...string) (*dto.Cart, error) {
+	if cartId == "" {
+		return nil, newRequestError(fiber.StatusBadRequest, "cartId is required")
+	}
+	cart, err := repos.Carts.FindById(cartId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, newRequestError(fiber.StatusNotFound, "Cart not found")
+		}
+		return nil, err
+	}
+	for _, status := range statuses {
+		if cart.Status == status {
+			return cart, nil
+		}
+	}
+	return nil, newRequestError(fiber.StatusConflict, "Cart is "+cart.Status)
+}
+
+// checkCartStock checks a product can be put in a cart at quantity: it must exist and the quantity must fit
+// in its stock at the cart's location less what other carts reserve there
+func checkCartStock(cart *dto.Cart, productId string, quantity int) (*dto.Product, error) {
+	product, err := repos.Products.FindById(productId)
+	if err != nil {
+		return nil, newRequestError(fiber.StatusNotFound, "Product not found: "+productId)
+	}
+	reserved, err := repos.Carts.ReservedQuantities(cart.LocationId, cart.CartID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if functions.LocationStock(product, cart.LocationId)-reserved[product.ProductId] < quantity {
+		return nil, newRequestError(fiber.StatusBadRequest, "Insufficient stock for product: "+product.Name)
+	}
+	return product, nil
+}
+
+// refreshCartReservation renews the reservation of a cart that reserves stock from now
+func refreshCartReservation(cart *dto.Cart, now time.Time) {
+	if !cart.ReserveStock {
+		return
+	}
+	until := now.Add(time.Duration(config.Get().Carts.ReservationMinutes) * time.Minute)
+	cart.ReservedUntil = &until
+}
+
+// saveCart stores a changed cart if it is still in one of fromStatuses and responds with it priced
+func saveCart(c *fiber.Ctx, cart *dto.Cart, fromStatuses ...string) error {
+	if err := repos.Carts.Update(cart, fromStatuses...); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Cart not found"})
+		case errors.Is(err, repository.ErrCartStatusChanged):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Cart was changed by another request, please reload it"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update cart"})
+	}
+
+	response, err := cartResponse(cart)
+	if err != nil {
+		return respondError(c, err, "Failed to price cart")
+	}
+	return c.JSON(response)
+}
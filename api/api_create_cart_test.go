@@ -0,0 +1,196 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newCartTestApp(t *testing.T) (*fiber.App, func(method string, path string, body interface{}) (int, dto.CartResponse)) {
+	t.Helper()
+
+	app, mem := newTestApp(t)
+	app.Post("/CreateCart", CreateCartApi)
+	app.Post("/AddCartItem", AddCartItemApi)
+	app.Put("/UpdateCartItem", UpdateCartItemApi)
+	app.Put("/RemoveCartItem", RemoveCartItemApi)
+	app.Put("/ParkCart", ParkCartApi)
+	app.Put("/ResumeCart", ResumeCartApi)
+	app.Get("/FindParkedCarts", FindParkedCartsApi)
+	app.Delete("/CancelCart", CancelCartApi)
+	app.Post("/ConvertCartToSale", ConvertCartToSaleApi)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+	seedProduct(t, mem, "PRD-002", dto.Batch{BatchId: "BATCH-002", StockQty: 5, SellingPrice: dto.MoneyFromFloat(100)})
+
+	do := func(method string, path string, body interface{}) (int, dto.CartResponse) {
+		var response dto.CartResponse
+		status := doJSON(t, app, method, path, body, &response)
+		return status, response
+	}
+	return app, do
+}
+
+func TestCartParkResumeAndConvert(t *testing.T) {
+	app, do := newCartTestApp(t)
+
+	status, created := do(fiber.MethodPost, "/CreateCart", dto.CreateCartRequest{
+		TerminalID: "POS-1",
+		Items:      []dto.CartItem{{ProductID: "PRD-001", Quantity: 2}},
+	})
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	cartId := created.Cart.CartID
+
+	do(fiber.MethodPost, "/AddCartItem", dto.CartItemRequest{CartID: cartId, ProductID: "PRD-001", Quantity: 1})
+	do(fiber.MethodPost, "/AddCartItem", dto.CartItemRequest{CartID: cartId, ProductID: "PRD-002", Quantity: 4})
+	status, updated := do(fiber.MethodPut, "/UpdateCartItem", dto.CartItemRequest{CartID: cartId, ProductID: "PRD-002", Quantity: 2})
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if len(updated.Cart.Items) != 2 || updated.Cart.Items[0].Quantity != 3 || updated.Summary.Subtotal != dto.MoneyFromFloat(500) {
+		t.Fatalf("expected PRD-001 x3 and PRD-002 x2 priced at 500, got %+v and %v", updated.Cart.Items, updated.Summary.Subtotal)
+	}
+
+	if status, _ := do(fiber.MethodPut, "/ParkCart", dto.ParkCartRequest{CartID: cartId, Note: "Customer went back for bread"}); status != fiber.StatusOK {
+		t.Fatalf("expected 200 parking, got %d", status)
+	}
+	// A parked cart cannot be changed until it is resumed
+	if status, _ := do(fiber.MethodPost, "/AddCartItem", dto.CartItemRequest{CartID: cartId, ProductID: "PRD-001", Quantity: 1}); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 changing a parked cart, got %d", status)
+	}
+
+	var parked struct {
+		Carts []dto.Cart `json:"carts"`
+	}
+	doJSON(t, app, fiber.MethodGet, "/FindParkedCarts?terminalId=POS-1", nil, &parked)
+	if len(parked.Carts) != 1 || parked.Carts[0].Note != "Customer went back for bread" {
+		t.Fatalf("expected the cart parked at POS-1, got %+v", parked.Carts)
+	}
+	doJSON(t, app, fiber.MethodGet, "/FindParkedCarts?terminalId=POS-2", nil, &parked)
+	if len(parked.Carts) != 0 {
+		t.Fatalf("expected no carts parked at POS-2, got %+v", parked.Carts)
+	}
+
+	status, resumed := do(fiber.MethodPut, "/ResumeCart", dto.ResumeCartRequest{CartID: cartId, TerminalID: "POS-2"})
+	if status != fiber.StatusOK || resumed.Cart.Status != dto.CartOpen || resumed.Cart.TerminalID != "POS-2" {
+		t.Fatalf("expected the cart open at POS-2, got %d %+v", status, resumed.Cart)
+	}
+
+	// Conversion goes through CreateSale's checks: tenders must cover the total
+	short := dto.ConvertCartRequest{CartID: cartId, Tenders: []dto.Tender{{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(100)}}}
+	if status := doJSON(t, app, fiber.MethodPost, "/ConvertCartToSale", short, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for short tenders, got %d", status)
+	}
+
+	var body struct {
+		Sale dto.Sale `json:"sale"`
+		Cart dto.Cart `json:"cart"`
+	}
+	convert := dto.ConvertCartRequest{CartID: cartId, Tenders: []dto.Tender{{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(1000)}}}
+	if status := doJSON(t, app, fiber.MethodPost, "/ConvertCartToSale", convert, &body); status != fiber.StatusCreated {
+		t.Fatalf("expected 201 after the failed attempt left the cart open, got %d", status)
+	}
+	if body.Sale.Total != dto.MoneyFromFloat(500) || body.Sale.Change != dto.MoneyFromFloat(500) {
+		t.Fatalf("expected a 500 sale with 500 change, got %v and %v", body.Sale.Total, body.Sale.Change)
+	}
+	if body.Cart.Status != dto.CartConverted || body.Cart.SaleID != body.Sale.SaleID {
+		t.Fatalf("expected the cart converted to sale %s, got %+v", body.Sale.SaleID, body.Cart)
+	}
+
+	if status := doJSON(t, app, fiber.MethodPost, "/ConvertCartToSale", convert, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 converting the cart twice, got %d", status)
+	}
+}
+
+func TestCartReservationHoldsStock(t *testing.T) {
+	app, do := newCartTestApp(t)
+
+	status, reserving := do(fiber.MethodPost, "/CreateCart", dto.CreateCartRequest{
+		TerminalID:   "POS-1",
+		ReserveStock: true,
+		Items:        []dto.CartItem{{ProductID: "PRD-002", Quantity: 4}},
+	})
+	if status != fiber.StatusCreated || reserving.Cart.ReservedUntil == nil {
+		t.Fatalf("expected a reserving cart, got %d %+v", status, reserving.Cart)
+	}
+	do(fiber.MethodPut, "/ParkCart", dto.ParkCartRequest{CartID: reserving.Cart.CartID})
+
+	// Only 1 of the 5 in stock is left for other carts and sales
+	_, other := do(fiber.MethodPost, "/CreateCart", dto.CreateCartRequest{TerminalID: "POS-2"})
+	if status, _ := do(fiber.MethodPost, "/AddCartItem", dto.CartItemRequest{CartID: other.Cart.CartID, ProductID: "PRD-002", Quantity: 2}); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 adding reserved stock to another cart, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-002", 2), nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 selling reserved stock, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-002", 1), nil); status != fiber.StatusCreated {
+		t.Fatalf("expected 201 selling the unreserved unit, got %d", status)
+	}
+
+	// The reserving cart itself can still be sold once resumed
+	do(fiber.MethodPut, "/ResumeCart", dto.ResumeCartRequest{CartID: reserving.Cart.CartID})
+	convert := dto.ConvertCartRequest{CartID: reserving.Cart.CartID, Tenders: []dto.Tender{{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(400)}}}
+	if status := doJSON(t, app, fiber.MethodPost, "/ConvertCartToSale", convert, nil); status != fiber.StatusCreated {
+		t.Fatalf("expected 201 converting the reserving cart, got %d", status)
+	}
+
+	// Cancelling releases a reservation
+	_, held := do(fiber.MethodPost, "/CreateCart", dto.CreateCartRequest{
+		TerminalID:   "POS-1",
+		ReserveStock: true,
+		Items:        []dto.CartItem{{ProductID: "PRD-001", Quantity: 10}},
+	})
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 1), nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 while every unit is reserved, got %d", status)
+	}
+	if status, _ := do(fiber.MethodDelete, "/CancelCart?cartId="+held.Cart.CartID, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200 cancelling, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 1), nil); status != fiber.StatusCreated {
+		t.Fatalf("expected 201 after the reservation was released, got %d", status)
+	}
+}
+
+func TestCheckoutKeepsNothingWhenTheCartChanged(t *testing.T) {
+	_, mem := newTestApp(t)
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+
+	cart := dto.Cart{CartID: "CRT-001", TerminalID: "POS-1", Status: dto.CartOpen, Items: []dto.CartItem{{ProductID: "PRD-001", Quantity: 2}}}
+	if err := mem.Carts.Create(&cart); err != nil {
+		t.Fatalf("create cart: %v", err)
+	}
+
+	// Another terminal cancels the cart after it was read for conversion
+	read := cart
+	cancelled := cart
+	cancelled.Status = dto.CartCancelled
+	if err := mem.Carts.Update(&cancelled, dto.CartOpen); err != nil {
+		t.Fatalf("cancel cart: %v", err)
+	}
+
+	sale := &dto.Sale{SaleID: "SAL-001", Items: functions.CartSaleItems(read.Items)}
+	if err := mem.Sales.Checkout(sale, "USR-TEST", &read); !errors.Is(err, repository.ErrCartStatusChanged) {
+		t.Fatalf("expected ErrCartStatusChanged, got %v", err)
+	}
+
+	product, err := mem.Products.FindById("PRD-001")
+	if err != nil {
+		t.Fatalf("find product: %v", err)
+	}
+	if product.StockQty != 10 {
+		t.Fatalf("expected no stock deducted, got %d left", product.StockQty)
+	}
+	if _, err := mem.Sales.FindById("SAL-001"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected the sale not recorded, got %v", err)
+	}
+	stored, _ := mem.Carts.FindById("CRT-001")
+	if stored.Status != dto.CartCancelled {
+		t.Fatalf("expected the cart left cancelled, got %s", stored.Status)
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CreateCustomerApi(c *fiber.Ctx) error {
+	var req dto.CustomerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	name, mobileNumber, err := validateCustomerRequest(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	id, err := repos.Ids.NextId(context.Background(), "Customers", "CUS")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	now := time.Now().UTC()
+	customer := &dto.Customer{
+		CustomerID:   id,
+		Name:         name,
+		MobileNumber: mobileNumber,
+		Email:        strings.TrimSpace(req.Email),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := repos.Customers.Create(customer); err != nil {
+		if errors.Is(err, repository.ErrMobileTaken) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create customer"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(customer)
+}
+
+// validateCustomerRequest returns the trimmed name and normalized mobile number of a create or update request
+func validateCustomerRequest(req dto.CustomerRequest) (string, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", "", errors.New("Name is required")
+	}
+	if strings.TrimSpace(req.MobileNumber) == "" {
+		return "", "", errors.New("Mobile number is required")
+	}
+	mobileNumber := functions.NormalizeMobile(req.MobileNumber)
+	if mobileNumber == "" {
+		return "", "", errors.New("Mobile number may only contain digits, spaces, dashes and a leading +")
+	}
+	return name, mobileNumber, nil
+}
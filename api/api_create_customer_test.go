@@ -0,0 +1,111 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/utils"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCustomerLoyaltyAcrossSales(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Post("/CreateCustomer", CreateCustomerApi)
+	app.Get("/FindCustomerById", FindCustomerByIdApi)
+	app.Get("/GetCustomerPurchaseHistory", GetCustomerPurchaseHistoryApi)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 50, SellingPrice: dto.MoneyFromFloat(100)})
+
+	var customer dto.Customer
+	req := dto.CustomerRequest{Name: "Nimal Perera", MobileNumber: "077 123 4567"}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateCustomer", req, &customer); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if customer.MobileNumber != "0771234567" {
+		t.Fatalf("expected the mobile number normalized, got %q", customer.MobileNumber)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateCustomer", req, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 for a duplicate mobile number, got %d", status)
+	}
+
+	// Linked by the mobile number typed at checkout: 500 earns 5 points at the default 1 per 100
+	sale := saleRequest("PRD-001", 5)
+	sale.MobileNumber = "077-123-4567"
+	var body struct {
+		Sale dto.Sale `json:"sale"`
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", sale, &body); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if body.Sale.CustomerID != customer.CustomerID || body.Sale.PointsEarned != 5 {
+		t.Fatalf("expected the sale linked to %s earning 5 points, got %q and %d", customer.CustomerID, body.Sale.CustomerID, body.Sale.PointsEarned)
+	}
+
+	// Redeemed as a discount: 3 points off a 100 sale, which then earns nothing
+	sale = saleRequest("PRD-001", 1)
+	sale.CustomerID = customer.CustomerID
+	sale.RedeemPoints = 3
+	body.Sale = dto.Sale{}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", sale, &body); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if body.Sale.Total != dto.MoneyFromFloat(97) || body.Sale.LoyaltyDiscount != dto.MoneyFromFloat(3) || body.Sale.PointsEarned != 0 {
+		t.Fatalf("expected total 97 after a 3 discount earning no points, got %v, %v and %d", body.Sale.Total, body.Sale.LoyaltyDiscount, body.Sale.PointsEarned)
+	}
+
+	// More than the remaining 2 points
+	sale.RedeemPoints = 5
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", sale, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 when redeeming more than the balance, got %d", status)
+	}
+
+	// Redeemed as a tender: the total stays 200, 2 points pay part of it and 198 earns 1 point
+	sale = saleRequest("PRD-001", 2)
+	sale.CustomerID = customer.CustomerID
+	sale.RedeemPoints = 2
+	sale.RedeemAs = dto.RedeemAsTender
+	body.Sale = dto.Sale{}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", sale, &body); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if body.Sale.Total != dto.MoneyFromFloat(200) || body.Sale.PaymentMethod != dto.PaymentSplit || body.Sale.PointsEarned != 1 {
+		t.Fatalf("expected a 200 split sale earning 1 point, got %v, %s and %d", body.Sale.Total, body.Sale.PaymentMethod, body.Sale.PointsEarned)
+	}
+
+	var found dto.Customer
+	if status := doJSON(t, app, fiber.MethodGet, "/FindCustomerById?customerId="+customer.CustomerID, nil, &found); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if found.LoyaltyPoints != 1 || found.SaleCount != 3 || found.LifetimeSpend != dto.MoneyFromFloat(797) {
+		t.Fatalf("expected 1 point, 3 sales and 797 spent, got %d, %d and %v", found.LoyaltyPoints, found.SaleCount, found.LifetimeSpend)
+	}
+
+	var history struct {
+		Customer dto.Customer            `json:"customer"`
+		Sales    utils.PaginatedResponse `json:"sales"`
+	}
+	if status := doJSON(t, app, fiber.MethodGet, "/GetCustomerPurchaseHistory?customerId="+customer.CustomerID, nil, &history); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if history.Sales.Total != 3 {
+		t.Fatalf("expected 3 sales in the purchase history, got %d", history.Sales.Total)
+	}
+}
+
+func TestCreateSaleRedeemWithoutCustomer(t *testing.T) {
+	app, mem := newTestApp(t)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 5, SellingPrice: dto.MoneyFromFloat(100)})
+
+	sale := saleRequest("PRD-001", 1)
+	sale.RedeemPoints = 10
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", sale, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", status)
+	}
+
+	sale = saleRequest("PRD-001", 1)
+	sale.CustomerID = "CUS-404"
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", sale, nil); status != fiber.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown customer, got %d", status)
+	}
+}
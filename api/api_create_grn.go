@@ -2,10 +2,11 @@ package api
 
 import (
 	"context"
-	"employee-crud/dao"
 	"employee-crud/dto"
 	"employee-crud/functions"
+	"employee-crud/repository"
 	"employee-crud/utils"
+	"errors"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -19,8 +20,22 @@ func CreateGRN(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	// A GRN against a purchase order takes its supplier and expected quantities from the PO
+	if inputObj.PurchaseOrderId != "" {
+		if status, err := prefillGRNFromPurchaseOrder(&inputObj); err != nil {
+			return utils.SendErrorResponse(c, status, err.Error())
+		}
+	}
+
+	// Goods are received into the batches of the GRN's location
+	locationId, err := resolveLocation(inputObj.LocationId)
+	if err != nil {
+		return respondError(c, err, "Failed to check location")
+	}
+	inputObj.LocationId = locationId
+
 	ctx := context.Background()
-	id, err := dao.GenerateId(ctx, "GRNs", "GRN")
+	id, err := repos.Ids.NextId(ctx, "GRNs", "GRN")
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -30,9 +45,14 @@ func CreateGRN(c *fiber.Ctx) error {
 	inputObj.UpdatedAt = now
 
 	// Calculate total amount for each item and overall total
-	var totalAmount float64
+	var totalAmount dto.Money
 	for i := range inputObj.Items {
-		inputObj.Items[i].TotalCost = float64(inputObj.Items[i].ReceivedQty) * inputObj.Items[i].UnitCost
+		// Posting details are only ever set by the server
+		inputObj.Items[i].PostedBatchId = ""
+		inputObj.Items[i].PostedQty = 0
+		inputObj.Items[i].PostedAt = nil
+
+		inputObj.Items[i].TotalCost = inputObj.Items[i].UnitCost.Times(inputObj.Items[i].ReceivedQty)
 		totalAmount += inputObj.Items[i].TotalCost
 	}
 	inputObj.TotalAmount = totalAmount
@@ -45,19 +65,44 @@ func CreateGRN(c *fiber.Ctx) error {
 	// Set default deleted status
 	inputObj.Deleted = false
 
-	if err := functions.UniqueCheck(inputObj, "GRNs", []string{"GRNId"}); err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
-	}
-
 	validate := validator.New()
 	if validationErr := validate.Struct(inputObj); validationErr != nil {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, validationErr.Error())
 	}
 
-	err = dao.DB_CreateGRN(&inputObj)
-	if err != nil {
+	// A GRN created as already received goes straight into inventory, in the same step that saves it
+	if err := repos.GRNs.Create(&inputObj, requestUser(c)); err != nil {
+		if errors.Is(err, functions.ErrNothingOutstanding) || errors.Is(err, repository.ErrProductVersionConflict) {
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
 
 	return utils.SendSuccessResponse(c)
 }
+
+// prefillGRNFromPurchaseOrder fills a GRN from the purchase order it references,
+// returning the status to respond with when the GRN cannot be raised against it
+func prefillGRNFromPurchaseOrder(grn *dto.GRN) (int, error) {
+	po, err := repos.PurchaseOrders.FindById(grn.PurchaseOrderId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fiber.StatusNotFound, errors.New("purchase order not found")
+		}
+		return fiber.StatusInternalServerError, err
+	}
+
+	// Units already on GRNs waiting to be posted are not expected again
+	raised, err := repos.GRNs.FindByPurchaseOrder(po.POId)
+	if err != nil {
+		return fiber.StatusInternalServerError, err
+	}
+
+	if err := functions.PrefillGRNFromPurchaseOrder(grn, po, raised); err != nil {
+		if errors.Is(err, functions.ErrPurchaseOrderSupplierMismatch) {
+			return fiber.StatusBadRequest, err
+		}
+		return fiber.StatusConflict, err
+	}
+	return fiber.StatusOK, nil
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateLocationApi adds a store or warehouse that can then hold batches, take sales and receive GRNs
+func CreateLocationApi(c *fiber.Ctx) error {
+	var location dto.Location
+	if err := c.BodyParser(&location); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	location.Status = ""
+	if err := functions.ValidateLocation(&location); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	id, err := repos.Ids.NextId(context.Background(), "Locations", "LOC")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	now := time.Now().UTC()
+	location.LocationId = id
+	location.CreatedAt = now
+	location.UpdatedAt = now
+
+	if err := repos.Locations.Create(&location); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create location"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(location)
+}
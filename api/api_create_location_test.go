@@ -0,0 +1,97 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestLocationsKeepTheirOwnBatchesSalesAndReports(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Post("/CreateLocation", CreateLocationApi)
+	app.Put("/UpdateLocation", UpdateLocationApi)
+	app.Post("/AddStock", AddStock)
+	app.Get("/FindAllStocks", FindAllStocksApi)
+	app.Get("/GetDailySalesSummary", GetDailySalesSummaryApi)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-MAIN", StockQty: 5, SellingPrice: dto.MoneyFromFloat(100)})
+
+	var branch dto.Location
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateLocation", dto.Location{Name: "Branch"}, &branch); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if branch.LocationId == "" || branch.Type != dto.LocationStore || branch.Status != "active" {
+		t.Fatalf("expected an active store, got %+v", branch)
+	}
+
+	addStock := AddStockRequest{ProductId: "PRD-001", StockQty: 10, CostPrice: dto.MoneyFromFloat(60), SellingPrice: dto.MoneyFromFloat(100), LocationId: branch.LocationId}
+	if status := doJSON(t, app, fiber.MethodPost, "/AddStock", addStock, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200 adding stock at the branch, got %d", status)
+	}
+
+	// The branch sells from its own batch only
+	branchSale := saleRequest("PRD-001", 8)
+	branchSale.LocationId = branch.LocationId
+	var created struct {
+		Sale dto.Sale `json:"sale"`
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", branchSale, &created); status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for the branch sale, got %d", status)
+	}
+	if created.Sale.LocationId != branch.LocationId || len(created.Sale.Items[0].Batches) != 1 || created.Sale.Items[0].Batches[0].BatchID == "BATCH-MAIN" {
+		t.Fatalf("expected the branch sale to come out of the branch batch, got %+v", created.Sale)
+	}
+
+	// Main still has 5 of the 7 units left in the business
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 6), nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 selling more than main holds, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 2), nil); status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for the main sale, got %d", status)
+	}
+
+	unknown := saleRequest("PRD-001", 1)
+	unknown.LocationId = "LOC-404"
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", unknown, nil); status != fiber.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown location, got %d", status)
+	}
+
+	var stocks struct {
+		Data       []dto.Stock `json:"data"`
+		TotalCount int64       `json:"total_count"`
+	}
+	if status := doJSON(t, app, fiber.MethodGet, "/FindAllStocks?locationId="+branch.LocationId, nil, &stocks); status != fiber.StatusOK {
+		t.Fatalf("expected 200 listing stocks, got %d", status)
+	}
+	if stocks.TotalCount != 1 || len(stocks.Data) != 1 || stocks.Data[0].StockQty != 2 || stocks.Data[0].LocationId != branch.LocationId {
+		t.Fatalf("expected the branch batch with 2 units, got %+v", stocks)
+	}
+
+	date := time.Now().UTC().Format("2006-01-02")
+	var summary struct {
+		Data dto.DailySalesSummary `json:"data"`
+	}
+	doJSON(t, app, fiber.MethodGet, "/GetDailySalesSummary?date="+date+"&locationId="+branch.LocationId, nil, &summary)
+	if summary.Data.TotalSales != 1 || summary.Data.TotalRevenue != dto.MoneyFromFloat(800) {
+		t.Fatalf("expected the branch's one sale of 800, got %+v", summary.Data)
+	}
+	doJSON(t, app, fiber.MethodGet, "/GetDailySalesSummary?date="+date, nil, &summary)
+	if summary.Data.TotalSales != 2 || summary.Data.TotalRevenue != dto.MoneyFromFloat(1000) {
+		t.Fatalf("expected both sales for the whole business, got %+v", summary.Data)
+	}
+
+	// An inactive location takes no sales; the main location cannot be deactivated
+	branch.Status = "inactive"
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateLocation", branch, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200 deactivating the branch, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", branchSale, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 selling at an inactive location, got %d", status)
+	}
+	main := dto.Location{LocationId: dto.MainLocationId, Name: "Main store", Status: "inactive"}
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateLocation", main, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 deactivating the main location, got %d", status)
+	}
+}
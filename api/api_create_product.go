@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"employee-crud/dao"
 	"employee-crud/dto"
 	"employee-crud/functions"
 	"employee-crud/utils"
@@ -20,11 +19,19 @@ func CreateProduct(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	if err := checkTaxClass(inputObj.TaxClassID); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := functions.ValidateStockThresholds(inputObj.StockThresholds); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	ctx := context.Background()
 	now := time.Now().UTC()
 
 	// Check if a product with same category, brand, and subcategory exists
-	existingProduct, err := dao.DB_FindProductByAttributes(
+	existingProduct, err := repos.Products.FindByAttributes(
 		inputObj.CategoryID,
 		inputObj.BrandID,
 		inputObj.SubCategoryID,
@@ -50,7 +57,7 @@ func CreateProduct(c *fiber.Ctx) error {
 		}
 
 		// Different expiry date - create a new batch
-		batchId, err := dao.GenerateId(ctx, "Batches", "BATCH")
+		batchId, err := repos.Ids.NextId(ctx, "Batches", "BATCH")
 		if err != nil {
 			return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 		}
@@ -68,7 +75,7 @@ func CreateProduct(c *fiber.Ctx) error {
 		// If existing product doesn't have batches array, initialize it with existing product data
 		if len(existingProduct.Batches) == 0 {
 			// Create first batch from existing product data
-			firstBatchId, err := dao.GenerateId(ctx, "Batches", "BATCH")
+			firstBatchId, err := repos.Ids.NextId(ctx, "Batches", "BATCH")
 			if err != nil {
 				return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 			}
@@ -84,24 +91,29 @@ func CreateProduct(c *fiber.Ctx) error {
 			}
 
 			// Update existing product to use batches
-			existingProduct.Batches = []dto.Batch{firstBatch}
-			if err := dao.DB_UpdateProductWithBatch(existingProduct, firstBatch); err != nil {
-				return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+			conversionRef := dto.StockMovementRef{
+				Type:   dto.MovementBatchAdjustment,
+				UserId: requestUser(c),
+				Note:   "Converted legacy stock into a batch",
+			}
+			if err := repos.Products.ConvertToBatches(existingProduct, firstBatch, conversionRef); err != nil {
+				return utils.SendErrorResponse(c, stockUpdateErrorStatus(err), err.Error())
 			}
 		}
 
 		// Add new batch to product
-		if err := dao.DB_AddBatchToProduct(existingProduct.ProductId, newBatch); err != nil {
+		createRef := dto.StockMovementRef{Type: dto.MovementProductCreate, UserId: requestUser(c)}
+		if err := repos.Products.AddBatch(existingProduct.ProductId, newBatch, createRef); err != nil {
 			return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 		}
 
 		// Sync stocks - fetch updated product first
-		updatedProduct, err := dao.DB_FindProductById(existingProduct.ProductId)
+		updatedProduct, err := repos.Products.FindById(existingProduct.ProductId)
 		if err != nil {
 			return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 		}
 
-		if err := dao.DB_SyncSingleProductStock(updatedProduct); err != nil {
+		if err := repos.Stocks.SyncProduct(updatedProduct); err != nil {
 			// Log but don't fail
 		}
 
@@ -113,30 +125,27 @@ func CreateProduct(c *fiber.Ctx) error {
 	}
 
 	// No existing product found - create new product
-	id, err := dao.GenerateId(ctx, "Products", "PRD")
+	id, err := repos.Ids.NextId(ctx, "Products", "PRD")
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
 	inputObj.ProductId = id
+	inputObj.Version = 0
 	inputObj.CreatedAt = now
 	inputObj.UpdatedAt = now
 
-	if err := functions.UniqueCheck(inputObj, "Products", []string{"ProductId"}); err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
-	}
-
 	validate := validator.New()
 	if validationErr := validate.Struct(inputObj); validationErr != nil {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, validationErr.Error())
 	}
 
-	err = dao.DB_CreateProduct(&inputObj)
+	err = repos.Products.Create(&inputObj, dto.StockMovementRef{Type: dto.MovementProductCreate, UserId: requestUser(c)})
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
 
 	// Automatically sync the product stock to Stocks collection
-	if err := dao.DB_SyncSingleProductStock(&inputObj); err != nil {
+	if err := repos.Stocks.SyncProduct(&inputObj); err != nil {
 		// Log the error but don't fail the product creation
 		// You can add logging here if needed
 		// For now, we'll silently continue
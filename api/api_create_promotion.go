@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreatePromotionApi stores a promotion rule; it is evaluated by CalculateOrderSummary and CreateSale from then on
+func CreatePromotionApi(c *fiber.Ctx) error {
+	var req dto.PromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	promotion := req.Promotion
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	promotion.Active = req.Active == nil || *req.Active
+	promotion.Deleted = false
+	if err := functions.ValidatePromotion(&promotion); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	id, err := repos.Ids.NextId(context.Background(), "Promotions", "PRM")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	now := time.Now().UTC()
+	promotion.PromotionID = id
+	promotion.CreatedAt = now
+	promotion.UpdatedAt = now
+
+	if err := repos.Promotions.Create(&promotion); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create promotion"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(promotion)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestPromotionsAppliedToSummaryAndSale(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Post("/CreatePromotion", CreatePromotionApi)
+	app.Put("/UpdatePromotion", UpdatePromotionApi)
+	app.Post("/CalculateOrderSummary", CalculateOrderSummaryApi)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+	seedProduct(t, mem, "PRD-002", dto.Batch{BatchId: "BATCH-002", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+
+	var promotion dto.Promotion
+	req := dto.PromotionRequest{Promotion: dto.Promotion{
+		Name: "Buy 2 get 1", Type: dto.PromotionBuyXGetY, Scope: dto.PromotionScopeProduct,
+		TargetIDs: []string{"PRD-001"}, BuyQty: 2, GetQty: 1,
+	}}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreatePromotion", req, &promotion); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if !promotion.Active {
+		t.Fatalf("expected a new promotion to be active")
+	}
+
+	items := []dto.SaleItem{{ProductID: "PRD-001", Quantity: 3}, {ProductID: "PRD-002", Quantity: 1}}
+	var summary dto.OrderSummaryResponse
+	summaryReq := dto.CalculateOrderSummaryRequest{Items: items}
+	if status := doJSON(t, app, fiber.MethodPost, "/CalculateOrderSummary", summaryReq, &summary); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	// 400 less one free unit
+	if summary.Subtotal != dto.MoneyFromFloat(400) || summary.PromotionDiscount != dto.MoneyFromFloat(100) || summary.Total != dto.MoneyFromFloat(300) {
+		t.Fatalf("expected 400 - 100 = 300, got %+v", summary)
+	}
+
+	sale := saleRequest("PRD-001", 3)
+	sale.Items = append(sale.Items, dto.SaleItem{ProductID: "PRD-002", Quantity: 1})
+	var body struct {
+		Sale       dto.Sale               `json:"sale"`
+		Promotions []dto.AppliedPromotion `json:"promotions"`
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", sale, &body); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	line := body.Sale.Items[0]
+	if line.Discount != dto.MoneyFromFloat(100) || len(line.PromotionIDs) != 1 || line.PromotionIDs[0] != promotion.PromotionID {
+		t.Fatalf("expected 100 off the first line from %s, got %v from %v", promotion.PromotionID, line.Discount, line.PromotionIDs)
+	}
+	if body.Sale.Items[1].Discount != 0 || body.Sale.Total != dto.MoneyFromFloat(300) || len(body.Promotions) != 1 {
+		t.Fatalf("expected only the first line discounted and a total of 300, got %+v", body.Sale)
+	}
+
+	// Switched off, the promotion no longer applies
+	off := false
+	req.PromotionID = promotion.PromotionID
+	req.Active = &off
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdatePromotion", req, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CalculateOrderSummary", summaryReq, &summary); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if summary.PromotionDiscount != 0 || summary.Total != dto.MoneyFromFloat(400) {
+		t.Fatalf("expected no promotion and a total of 400, got %+v", summary)
+	}
+}
+
+func TestCreatePromotionRejectsInvalidRule(t *testing.T) {
+	app, _ := newTestApp(t)
+	app.Post("/CreatePromotion", CreatePromotionApi)
+
+	req := dto.PromotionRequest{Promotion: dto.Promotion{Name: "Half off", Type: dto.PromotionPercentage, Scope: dto.PromotionScopeBrand, Value: 50}}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreatePromotion", req, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a brand promotion without targetIds, got %d", status)
+	}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreatePurchaseOrderApi drafts a purchase order with a supplier at agreed unit costs
+// The draft can be changed with UpdatePurchaseOrder until a manager approves it
+func CreatePurchaseOrderApi(c *fiber.Ctx) error {
+	var req dto.PurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.SupplierId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "supplierId is required"})
+	}
+
+	supplier, err := repos.Suppliers.FindById(req.SupplierId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Supplier not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve supplier"})
+	}
+	if supplier.Status == "inactive" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Supplier is inactive"})
+	}
+
+	items, err := purchaseOrderItems(req.Items)
+	if err != nil {
+		return respondError(c, err, "Failed to check purchase order lines")
+	}
+
+	po, err := createPurchaseOrder(supplier, items, requestUser(c), func(po *dto.PurchaseOrder) {
+		po.PONumber = strings.TrimSpace(req.PONumber)
+		po.ExpectedDate = req.ExpectedDate
+		po.Notes = strings.TrimSpace(req.Notes)
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create purchase order"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(po)
+}
+
+// createPurchaseOrder saves a new draft purchase order with a supplier; fill sets the optional header fields
+// A PO without a number is numbered after its id
+func createPurchaseOrder(supplier *dto.Supplier, items []dto.PurchaseOrderItem, userId string, fill func(po *dto.PurchaseOrder)) (*dto.PurchaseOrder, error) {
+	id, err := repos.Ids.NextId(context.Background(), "PurchaseOrders", "PO")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	po := &dto.PurchaseOrder{
+		POId:         id,
+		SupplierId:   supplier.SupplierId,
+		SupplierName: supplier.Name,
+		Status:       dto.PODraft,
+		Items:        items,
+		TotalAmount:  functions.PurchaseOrderTotal(items),
+		GRNIds:       []string{},
+		CreatedBy:    userId,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if fill != nil {
+		fill(po)
+	}
+	if po.PONumber == "" {
+		po.PONumber = id
+	}
+
+	if err := repos.PurchaseOrders.Create(po); err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+// UpdatePurchaseOrderApi replaces the number, lines, expected date and notes of a draft purchase order
+func UpdatePurchaseOrderApi(c *fiber.Ctx) error {
+	var req dto.PurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	po, err := findPurchaseOrder(req.POId, dto.PODraft)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve purchase order")
+	}
+	if req.SupplierId != "" && req.SupplierId != po.SupplierId {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "The supplier of a purchase order cannot be changed"})
+	}
+
+	items, err := purchaseOrderItems(req.Items)
+	if err != nil {
+		return respondError(c, err, "Failed to check purchase order lines")
+	}
+
+	if number := strings.TrimSpace(req.PONumber); number != "" {
+		po.PONumber = number
+	}
+	po.Items = items
+	po.TotalAmount = functions.PurchaseOrderTotal(items)
+	po.ExpectedDate = req.ExpectedDate
+	po.Notes = strings.TrimSpace(req.Notes)
+	po.UpdatedAt = time.Now().UTC()
+
+	if err := repos.PurchaseOrders.Update(po, dto.PODraft); err != nil {
+		return respondPurchaseOrderUpdateError(c, err)
+	}
+	return c.JSON(po)
+}
+
+// purchaseOrderItems checks the requested lines and names them after their products
+// Every line needs an existing product, a positive quantity and a cost that is not negative; a product is ordered once
+func purchaseOrderItems(lines []dto.PurchaseOrderLineRequest) ([]dto.PurchaseOrderItem, error) {
+	if len(lines) == 0 {
+		return nil, newRequestError(fiber.StatusBadRequest, "A purchase order needs at least one line")
+	}
+
+	items := make([]dto.PurchaseOrderItem, 0, len(lines))
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		if line.OrderedQty <= 0 {
+			return nil, newRequestError(fiber.StatusBadRequest, "orderedQty must be positive")
+		}
+		if line.UnitCost < 0 {
+			return nil, newRequestError(fiber.StatusBadRequest, "unitCost cannot be negative")
+		}
+		if seen[line.ProductId] {
+			return nil, newRequestError(fiber.StatusBadRequest, "Product ordered twice: "+line.ProductId)
+		}
+		seen[line.ProductId] = true
+
+		product, err := repos.Products.FindById(line.ProductId)
+		if err != nil {
+			return nil, newRequestError(fiber.StatusNotFound, "Product not found: "+line.ProductId)
+		}
+		items = append(items, dto.PurchaseOrderItem{
+			ProductId:   product.ProductId,
+			ProductName: product.Name,
+			OrderedQty:  line.OrderedQty,
+			UnitCost:    dto.MoneyFromFloat(line.UnitCost),
+		})
+	}
+	return items, nil
+}
+
+// findPurchaseOrder loads a purchase order and checks it is in one of statuses
+func findPurchaseOrder(poId string, statuses ...string) (*dto.PurchaseOrder, error) {
+	if poId == "" {
+		return nil, newRequestError(fiber.StatusBadRequest, "poId is required")
+	}
+	po, err := repos.PurchaseOrders.FindById(poId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, newRequestError(fiber.StatusNotFound, "Purchase order not found")
+		}
+		return nil, err
+	}
+	for _, status := range statuses {
+		if po.Status == status {
+			return po, nil
+		}
+	}
+	return nil, newRequestError(fiber.StatusConflict, "Purchase order is "+po.Status)
+}
+
+func respondPurchaseOrderUpdateError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Purchase order not found"})
+	case errors.Is(err, repository.ErrPurchaseOrderStatusChanged):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Purchase order was changed by another request, please reload it"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update purchase order"})
+}
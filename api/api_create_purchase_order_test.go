@@ -0,0 +1,205 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newPurchaseOrderTestApp(t *testing.T) (*fiber.App, func(method string, path string, body interface{}) (int, dto.PurchaseOrder)) {
+	t.Helper()
+
+	app, mem := newTestApp(t)
+	app.Post("/CreatePurchaseOrder", CreatePurchaseOrderApi)
+	app.Put("/UpdatePurchaseOrder", UpdatePurchaseOrderApi)
+	app.Put("/UpdatePurchaseOrderStatus", UpdatePurchaseOrderStatusApi)
+	app.Get("/GetPurchaseOrderVariance", GetPurchaseOrderVarianceApi)
+
+	if err := mem.Suppliers.Create(&dto.Supplier{SupplierId: "SUP-001", Name: "Acme Wholesale", Status: "active"}); err != nil {
+		t.Fatalf("seed supplier: %v", err)
+	}
+	seedProduct(t, mem, "PRD-001")
+	seedProduct(t, mem, "PRD-002")
+
+	do := func(method string, path string, body interface{}) (int, dto.PurchaseOrder) {
+		var po dto.PurchaseOrder
+		status := doJSON(t, app, method, path, body, &po)
+		return status, po
+	}
+	return app, do
+}
+
+func TestPurchaseOrderReceivedOverSeveralGRNs(t *testing.T) {
+	app, do := newPurchaseOrderTestApp(t)
+
+	status, po := do(fiber.MethodPost, "/CreatePurchaseOrder", dto.PurchaseOrderRequest{
+		SupplierId: "SUP-001",
+		Items: []dto.PurchaseOrderLineRequest{
+			{ProductId: "PRD-001", OrderedQty: 10, UnitCost: 50},
+			{ProductId: "PRD-002", OrderedQty: 4, UnitCost: 20},
+		},
+	})
+	if status != fiber.StatusCreated || po.Status != dto.PODraft || po.TotalAmount != dto.MoneyFromFloat(580) || po.SupplierName != "Acme Wholesale" {
+		t.Fatalf("expected a 580 draft for Acme Wholesale, got %d %+v", status, po)
+	}
+
+	// Goods cannot be received against a draft
+	early := dto.GRN{PurchaseOrderId: po.POId, GRNNumber: "GRN-A", ReceivedDate: time.Now().UTC(), Status: "pending", ReceivedBy: "USR-TEST", Notes: "early"}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", early, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 receiving against a draft, got %d", status)
+	}
+
+	if status, _ := do(fiber.MethodPut, "/UpdatePurchaseOrderStatus", dto.UpdatePurchaseOrderStatusRequest{POId: po.POId, Status: dto.POSent}); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 sending an unapproved PO, got %d", status)
+	}
+	for _, next := range []string{dto.POApproved, dto.POSent} {
+		if status, _ := do(fiber.MethodPut, "/UpdatePurchaseOrderStatus", dto.UpdatePurchaseOrderStatusRequest{POId: po.POId, Status: next}); status != fiber.StatusOK {
+			t.Fatalf("expected 200 moving to %s, got %d", next, status)
+		}
+	}
+	if status, _ := do(fiber.MethodPut, "/UpdatePurchaseOrder", dto.PurchaseOrderRequest{POId: po.POId, Items: []dto.PurchaseOrderLineRequest{{ProductId: "PRD-001", OrderedQty: 1, UnitCost: 1}}}); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 editing a sent PO, got %d", status)
+	}
+
+	// First delivery: 6 of PRD-001 at a higher price than agreed, nothing of PRD-002
+	first := dto.GRN{
+		PurchaseOrderId: po.POId, GRNNumber: "GRN-A", ReceivedDate: time.Now().UTC(), Status: "completed", ReceivedBy: "USR-TEST", Notes: "first delivery",
+		Items: []dto.GRNItem{{ProductId: "PRD-001", ReceivedQty: 6, UnitCost: dto.MoneyFromFloat(52)}},
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", first, nil); status != fiber.StatusOK {
+		t.Fatalf("create first GRN: expected 200, got %d", status)
+	}
+	stored, _ := repos.PurchaseOrders.FindById(po.POId)
+	if stored.Status != dto.POPartiallyReceived || stored.Items[0].ReceivedQty != 6 || len(stored.GRNIds) != 1 {
+		t.Fatalf("expected 6 of PRD-001 received and the PO partially received, got %s %+v", stored.Status, stored.Items)
+	}
+
+	// Second delivery without lines receives whatever is outstanding at the agreed costs
+	second := dto.GRN{PurchaseOrderId: po.POId, GRNNumber: "GRN-B", ReceivedDate: time.Now().UTC(), Status: "pending", ReceivedBy: "USR-TEST", Notes: "rest"}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", second, nil); status != fiber.StatusOK {
+		t.Fatalf("create second GRN: expected 200, got %d", status)
+	}
+	grn, err := repos.GRNs.FindById("GRN-002")
+	if err != nil {
+		t.Fatalf("find second GRN: %v", err)
+	}
+	if grn.SupplierId != "SUP-001" || len(grn.Items) != 2 || grn.Items[0].ExpectedQty != 4 || grn.Items[1].ExpectedQty != 4 || grn.TotalAmount != dto.MoneyFromFloat(280) {
+		t.Fatalf("expected the outstanding 4 + 4 prefilled for 280, got %+v", grn)
+	}
+	stored, _ = repos.PurchaseOrders.FindById(po.POId)
+	if stored.Status != dto.POPartiallyReceived || len(stored.GRNIds) != 2 {
+		t.Fatalf("a pending GRN should be linked without counting as received, got %s %v", stored.Status, stored.GRNIds)
+	}
+
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateGRNStatus", UpdateGRNStatusRequest{GRNId: "GRN-002", Status: "completed"}, nil); status != fiber.StatusOK {
+		t.Fatalf("complete second GRN: expected 200, got %d", status)
+	}
+	stored, _ = repos.PurchaseOrders.FindById(po.POId)
+	if stored.Status != dto.POClosed || stored.ClosedAt == nil {
+		t.Fatalf("expected the PO closed once everything was received, got %s", stored.Status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", second, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 receiving against a closed PO, got %d", status)
+	}
+
+	var variance struct {
+		Data []dto.PurchaseOrderVarianceReport `json:"data"`
+	}
+	if status := doJSON(t, app, fiber.MethodGet, "/GetPurchaseOrderVariance?supplierId=SUP-001", nil, &variance); status != fiber.StatusOK {
+		t.Fatalf("variance: expected 200, got %d", status)
+	}
+	if len(variance.Data) != 1 {
+		t.Fatalf("expected one PO reported, got %+v", variance.Data)
+	}
+	report := variance.Data[0]
+	// 6 units at 2 over the agreed cost
+	if report.PriceVariance != dto.MoneyFromFloat(12) || report.QuantityVariance != 0 || report.Lines[0].ReceivedQty != 10 || len(report.Lines[0].Receipts) != 2 {
+		t.Fatalf("unexpected variance report %+v", report)
+	}
+}
+
+func TestPurchaseOrderClosedShort(t *testing.T) {
+	app, do := newPurchaseOrderTestApp(t)
+
+	_, po := do(fiber.MethodPost, "/CreatePurchaseOrder", dto.PurchaseOrderRequest{
+		SupplierId: "SUP-001",
+		Items:      []dto.PurchaseOrderLineRequest{{ProductId: "PRD-001", OrderedQty: 10, UnitCost: 50}},
+	})
+	do(fiber.MethodPut, "/UpdatePurchaseOrderStatus", dto.UpdatePurchaseOrderStatusRequest{POId: po.POId, Status: dto.POApproved})
+
+	grn := dto.GRN{
+		PurchaseOrderId: po.POId, GRNNumber: "GRN-A", ReceivedDate: time.Now().UTC(), Status: "completed", ReceivedBy: "USR-TEST", Notes: "short",
+		Items: []dto.GRNItem{{ProductId: "PRD-001", ReceivedQty: 7}},
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", grn, nil); status != fiber.StatusOK {
+		t.Fatalf("create GRN: expected 200, got %d", status)
+	}
+
+	status, closed := do(fiber.MethodPut, "/UpdatePurchaseOrderStatus", dto.UpdatePurchaseOrderStatusRequest{POId: po.POId, Status: dto.POClosed})
+	if status != fiber.StatusOK || closed.Status != dto.POClosed {
+		t.Fatalf("expected the PO closed short, got %d %+v", status, closed)
+	}
+	stored, _ := repos.PurchaseOrders.FindById(po.POId)
+	if stored.Items[0].ReceivedQty != 7 {
+		t.Fatalf("closing must keep the received quantities, got %+v", stored.Items[0])
+	}
+
+	var variance struct {
+		Data []dto.PurchaseOrderVarianceReport `json:"data"`
+	}
+	doJSON(t, app, fiber.MethodGet, "/GetPurchaseOrderVariance?poId="+po.POId, nil, &variance)
+	if len(variance.Data) != 1 || variance.Data[0].QuantityVariance != -3 || variance.Data[0].Lines[0].OutstandingQty != 3 {
+		t.Fatalf("expected 3 units short, got %+v", variance.Data)
+	}
+}
+
+func TestPendingGRNsDoNotExpectTheSameUnits(t *testing.T) {
+	app, do := newPurchaseOrderTestApp(t)
+
+	_, po := do(fiber.MethodPost, "/CreatePurchaseOrder", dto.PurchaseOrderRequest{
+		SupplierId: "SUP-001",
+		Items:      []dto.PurchaseOrderLineRequest{{ProductId: "PRD-001", OrderedQty: 10, UnitCost: 50}},
+	})
+	do(fiber.MethodPut, "/UpdatePurchaseOrderStatus", dto.UpdatePurchaseOrderStatusRequest{POId: po.POId, Status: dto.POApproved})
+
+	first := dto.GRN{PurchaseOrderId: po.POId, GRNNumber: "GRN-A", ReceivedDate: time.Now().UTC(), Status: "pending", ReceivedBy: "USR-TEST", Notes: "all of it"}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", first, nil); status != fiber.StatusOK {
+		t.Fatalf("create first GRN: expected 200, got %d", status)
+	}
+	// The first GRN already expects all 10, so a second one has nothing left to receive
+	second := dto.GRN{PurchaseOrderId: po.POId, GRNNumber: "GRN-B", ReceivedDate: time.Now().UTC(), Status: "pending", ReceivedBy: "USR-TEST", Notes: "all of it again"}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", second, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 for a second GRN of the same units, got %d", status)
+	}
+
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateGRNStatus", UpdateGRNStatusRequest{GRNId: "GRN-001", Status: "completed"}, nil); status != fiber.StatusOK {
+		t.Fatalf("complete first GRN: expected 200, got %d", status)
+	}
+	stored, _ := repos.PurchaseOrders.FindById(po.POId)
+	if stored.Status != dto.POClosed || stored.Items[0].ReceivedQty != 10 {
+		t.Fatalf("expected the PO closed with 10 received, got %s %+v", stored.Status, stored.Items)
+	}
+}
+
+func TestGRNCreatedAsReceivedIsNotSavedWhenPostingFails(t *testing.T) {
+	app, _ := newPurchaseOrderTestApp(t)
+
+	grn := dto.GRN{
+		GRNNumber: "GRN-A", ReceivedDate: time.Now().UTC(), Status: "completed", ReceivedBy: "USR-TEST", Notes: "unknown product",
+		Items: []dto.GRNItem{{ProductId: "PRD-001", ReceivedQty: 2}, {ProductId: "PRD-404", ReceivedQty: 1}},
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", grn, nil); status == fiber.StatusOK {
+		t.Fatalf("expected posting an unknown product to fail")
+	}
+	if _, err := repos.GRNs.FindById("GRN-001"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected the GRN not saved, got %v", err)
+	}
+	product, _ := repos.Products.FindById("PRD-001")
+	if product.StockQty != 0 {
+		t.Fatalf("expected no stock posted, got %d", product.StockQty)
+	}
+}
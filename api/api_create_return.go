@@ -1,12 +1,15 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func CreateReturnApi(c *fiber.Ctx) error {
@@ -14,14 +17,37 @@ func CreateReturnApi(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request payload"})
 	}
-	if req.CustomerName == "" || req.ContactNumber == "" || len(req.Products) == 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "CustomerName, ContactNumber, and Products are required"})
+
+	// Older clients send the saleId as the original bill number
+	if req.SaleID == "" {
+		req.SaleID = req.OriginalBillNumber
+	}
+	if req.SaleID == "" || len(req.Products) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "SaleId and Products are required"})
 	}
-	id := uuid.New().String()
-	req.ID = id
+
+	req.ID = uuid.New().String()
+	req.ProcessedBy = requestUser(c)
 	req.CreatedAt = time.Now().Format(time.RFC3339)
-	if err := dao.InsertReturn(c.Context(), req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save return"})
+
+	// Validate against the sale, restock and record write-offs in one transaction
+	if err := repos.Returns.CreateSaleReturn(&req, req.ProcessedBy); err != nil {
+		if errors.Is(err, functions.ErrInvalidReturn) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Sale not found: " + req.SaleID})
+		}
+		if errors.Is(err, repository.ErrProductVersionConflict) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":   "Stock was updated by another request, please retry",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to save return",
+			"details": err.Error(),
+		})
 	}
 	return c.Status(fiber.StatusCreated).JSON(req)
 }
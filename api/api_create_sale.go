@@ -1,12 +1,16 @@
 package api
 
 import (
-	"employee-crud/dao"
+	"employee-crud/config"
 	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func CreateSaleApi(c *fiber.Ctx) error {
@@ -18,114 +22,188 @@ func CreateSaleApi(c *fiber.Ctx) error {
 		})
 	}
 
+	sale, applied, err := checkoutSale(req, requestUser(c), nil)
+	if err != nil {
+		return respondError(c, err, "Failed to create sale")
+	}
+
+	// Return success
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":    "Sale created successfully and stocks updated",
+		"sale":       sale,
+		"promotions": applied,
+	})
+}
+
+// checkoutSale validates a sale request, prices it through priceOrder, settles its tenders and loyalty points
+// and records it, deducting stock; shared by CreateSale and cart conversion.
+// A sale made from cart (nil for CreateSale) converts the cart in the same transaction; stock reserved by
+// any other cart is not available to the sale.
+// Failures are requestErrors carrying the response to send.
+func checkoutSale(req dto.CreateSaleRequest, userId string, cart *dto.Cart) (*dto.Sale, []dto.AppliedPromotion, error) {
 	// Validate that items array is not empty
 	if len(req.Items) == 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "At least one item is required",
-		})
+		return nil, nil, newRequestError(fiber.StatusBadRequest, "At least one item is required")
 	}
 
-	// Validate payment method
-	if req.PaymentMethod != "cash" && req.PaymentMethod != "card" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Payment method must be either 'cash' or 'card'",
-		})
+	// Validate tenders
+	if len(req.Tenders) == 0 {
+		return nil, nil, newRequestError(fiber.StatusBadRequest, "At least one tender is required")
 	}
-
-	// Calculate order summary
-	var subtotal float64 = 0
-	for i := range req.Items {
-		// Verify product exists and has sufficient stock
-		product, err := dao.GetProductByProductId(req.Items[i].ProductID)
-		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Product not found: " + req.Items[i].ProductID,
-			})
+	for _, tender := range req.Tenders {
+		if tender.Type == dto.TenderLoyaltyPoints {
+			return nil, nil, newRequestError(fiber.StatusBadRequest, "Use redeemPoints with redeemAs 'tender' to pay with loyalty points")
 		}
+	}
+	if req.RedeemAs != "" && req.RedeemAs != dto.RedeemAsDiscount && req.RedeemAs != dto.RedeemAsTender {
+		return nil, nil, newRequestError(fiber.StatusBadRequest, "redeemAs must be either 'discount' or 'tender'")
+	}
 
-		if product.StockQty < req.Items[i].Quantity {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Insufficient stock for product: " + product.Name,
-			})
+	// Link a registered customer, by id or by the mobile number given at checkout
+	customer, err := findSaleCustomer(req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, newRequestError(fiber.StatusNotFound, "Customer not found: "+req.CustomerID)
 		}
-
-		// Calculate total price for this item
-		req.Items[i].ProductName = product.Name
-		req.Items[i].UnitPrice = product.SellingPrice
-		req.Items[i].TotalPrice = product.SellingPrice * float64(req.Items[i].Quantity)
-		subtotal += req.Items[i].TotalPrice
+		return nil, nil, newRequestError(fiber.StatusInternalServerError, "Failed to look up customer")
+	}
+	if customer != nil {
+		req.CustomerID = customer.CustomerID
+		if req.CustomerName == "" {
+			req.CustomerName = customer.Name
+		}
+		req.MobileNumber = customer.MobileNumber
+	}
+	if req.RedeemPoints > 0 && customer == nil {
+		return nil, nil, newRequestError(fiber.StatusBadRequest, "Redeeming loyalty points needs a registered customer")
 	}
 
-	// Calculate tax
-	var tax float64 = 0
-	if req.TaxPercentage > 0 {
-		tax = subtotal * (req.TaxPercentage / 100)
-	} else {
-		tax = req.Tax
+	// Items are taken from the batches at the sale's location
+	locationId, err := resolveLocation(req.LocationId)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Calculate discount
-	var discount float64 = 0
-	if req.DiscountType == "percentage" {
-		discount = subtotal * (req.Discount / 100)
-	} else {
-		discount = req.Discount
+	// Stock held by parked and open carts is not for sale, apart from what the cart being sold holds
+	excludeCartId := ""
+	if cart != nil {
+		excludeCartId = cart.CartID
+	}
+	reserved, err := repos.Carts.ReservedQuantities(locationId, excludeCartId, time.Now().UTC())
+	if err != nil {
+		return nil, nil, newRequestError(fiber.StatusInternalServerError, "Failed to load cart reservations")
 	}
 
-	// Calculate total
-	total := subtotal + tax - discount
+	// Price the order, checking each product exists and has sufficient stock
+	summary, _, err := priceOrder(req.Items, req.Discount, req.DiscountType, locationId, reserved)
+	if err != nil {
+		return nil, nil, err
+	}
+	total := summary.Total
 
-	// Calculate change for cash payments
-	var change float64 = 0
-	if req.PaymentMethod == "cash" {
-		if req.AmountReceived < total {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Amount received is less than total",
-			})
+	// Redeem loyalty points, either taken off the total or paid as a tender
+	loyalty := config.Get().Loyalty
+	var loyaltyDiscount, loyaltyTender dto.Money
+	if req.RedeemPoints > 0 {
+		value, err := functions.RedemptionValue(req.RedeemPoints, customer.LoyaltyPoints, loyalty.PointValue, loyalty.MinRedeemPoints, total)
+		if err != nil {
+			return nil, nil, newRequestError(fiber.StatusBadRequest, err.Error())
 		}
-		change = req.AmountReceived - total
+		if req.RedeemAs == dto.RedeemAsTender {
+			loyaltyTender = value
+			req.Tenders = append(req.Tenders, dto.Tender{Type: dto.TenderLoyaltyPoints, Amount: value, Reference: customer.CustomerID})
+		} else {
+			loyaltyDiscount = value
+			total -= value
+		}
+	}
+
+	// Check the tenders cover the total; change is only given from cash
+	tenders, cashReceived, change, err := functions.SettleTenders(total, req.Tenders)
+	if err != nil {
+		return nil, nil, newRequestError(fiber.StatusBadRequest, err.Error())
+	}
+
+	// Points are earned on what the customer paid, not on points they spent
+	var pointsEarned int
+	if customer != nil {
+		pointsEarned = functions.PointsEarned(total-loyaltyTender, loyalty.EarnPerAmount)
 	}
 
 	// Create sale object
 	sale := &dto.Sale{
-		SaleID:         uuid.New().String(),
-		CustomerName:   req.CustomerName,
-		MobileNumber:   req.MobileNumber,
-		Items:          req.Items,
-		Subtotal:       subtotal,
-		Tax:            tax,
-		TaxPercentage:  req.TaxPercentage,
-		Discount:       discount,
-		DiscountType:   req.DiscountType,
-		Total:          total,
-		PaymentMethod:  req.PaymentMethod,
-		AmountReceived: req.AmountReceived,
-		Change:         change,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-	}
-
-	// Save sale to database
-	if err := dao.CreateSale(sale); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create sale",
-		})
+		SaleID:            uuid.New().String(),
+		CustomerID:        req.CustomerID,
+		CustomerName:      req.CustomerName,
+		MobileNumber:      req.MobileNumber,
+		LocationId:        locationId,
+		Items:             summary.Items,
+		Subtotal:          summary.Subtotal,
+		PromotionDiscount: summary.PromotionDiscount,
+		Tax:               summary.Tax,
+		IncludedTax:       summary.IncludedTax,
+		TaxBreakdown:      summary.TaxBreakdown,
+		Discount:          summary.Discount,
+		DiscountType:      req.DiscountType,
+		Total:             total,
+		LoyaltyDiscount:   loyaltyDiscount,
+		PointsRedeemed:    req.RedeemPoints,
+		PointsEarned:      pointsEarned,
+		Tenders:           tenders,
+		PaymentMethod:     functions.PaymentMethodOf(req.Tenders),
+		AmountReceived:    cashReceived,
+		Change:            change,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
 	}
 
-	// Update product stock for each item
+	// Save sale and deduct stock for all items in one transaction
 	// This automatically syncs each product to the Stocks collection
-	for _, item := range req.Items {
-		if err := dao.UpdateProductStock(item.ProductID, item.Quantity); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Failed to update stock for product: " + item.ProductID,
+	if err := repos.Sales.Checkout(sale, userId, cart); err != nil {
+		if errors.Is(err, repository.ErrCartStatusChanged) {
+			return nil, nil, newRequestError(fiber.StatusConflict, "Cart was changed by another request, please reload it")
+		}
+		if errors.Is(err, functions.ErrInsufficientStock) {
+			return nil, nil, &requestError{status: fiber.StatusBadRequest, body: fiber.Map{
+				"error":   "Insufficient stock to complete the sale",
+				"details": err.Error(),
+			}}
+		}
+		if errors.Is(err, functions.ErrInsufficientPoints) {
+			return nil, nil, &requestError{status: fiber.StatusConflict, body: fiber.Map{
+				"error":   "The customer's loyalty balance changed, please retry",
+				"details": err.Error(),
+			}}
+		}
+		if errors.Is(err, repository.ErrProductVersionConflict) {
+			return nil, nil, &requestError{status: fiber.StatusConflict, body: fiber.Map{
+				"error":   "Stock was updated by another request, please retry",
 				"details": err.Error(),
-			})
+			}}
 		}
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, newRequestError(fiber.StatusNotFound, "Product not found")
+		}
+		return nil, nil, err
 	}
 
-	// Return success
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Sale created successfully and stocks updated",
-		"sale":    sale,
-	})
+	return sale, summary.Promotions, nil
+}
+
+// findSaleCustomer returns the registered customer of a sale request: by customerId (which must exist),
+// otherwise by mobile number when one is registered; nil if the sale has no registered customer
+func findSaleCustomer(req dto.CreateSaleRequest) (*dto.Customer, error) {
+	if req.CustomerID != "" {
+		return repos.Customers.FindById(req.CustomerID)
+	}
+
+	mobileNumber := functions.NormalizeMobile(req.MobileNumber)
+	if mobileNumber == "" {
+		return nil, nil
+	}
+	customer, err := repos.Customers.FindByMobile(mobileNumber)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	return customer, err
 }
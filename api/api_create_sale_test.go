@@ -0,0 +1,281 @@
+package api
+
+import (
+	"bytes"
+	"employee-crud/dto"
+	"employee-crud/repository/memory"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// newTestApp wires the handlers under test to a fresh in-memory store
+// Requests run as user "USR-TEST" without going through token authentication
+func newTestApp(t *testing.T) (*fiber.App, memory.Repositories) {
+	t.Helper()
+
+	mem := memory.NewRepositories()
+	previous := repos
+	SetRepositories(mem.Repositories)
+	t.Cleanup(func() { SetRepositories(previous) })
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(localUserId, "USR-TEST")
+		return c.Next()
+	})
+	app.Post("/CreateSale", CreateSaleApi)
+	app.Post("/CreateGRN", CreateGRN)
+	app.Put("/UpdateGRNStatus", UpdateGRNStatusApi)
+	return app, mem
+}
+
+// doJSON sends body as JSON and decodes the JSON response into out (when out is not nil)
+func doJSON(t *testing.T, app *fiber.App, method string, path string, body interface{}, out interface{}) int {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// seedProduct stores a product with the given batches and a matching total stockQty
+func seedProduct(t *testing.T, mem memory.Repositories, productId string, batches ...dto.Batch) {
+	t.Helper()
+
+	now := time.Now().UTC()
+	product := &dto.Product{
+		ProductId:    productId,
+		Name:         "Product " + productId,
+		CostPrice:    dto.MoneyFromFloat(60),
+		SellingPrice: dto.MoneyFromFloat(100),
+		Batches:      batches,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	for _, batch := range batches {
+		product.StockQty += batch.StockQty
+	}
+
+	ref := dto.StockMovementRef{Type: dto.MovementProductCreate}
+	if err := mem.Products.Create(product, ref); err != nil {
+		t.Fatalf("seed product: %v", err)
+	}
+}
+
+func saleRequest(productId string, quantity int) dto.CreateSaleRequest {
+	return dto.CreateSaleRequest{
+		Items:   []dto.SaleItem{{ProductID: productId, Quantity: quantity}},
+		Tenders: []dto.Tender{{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(10000)}},
+	}
+}
+
+func TestCreateSaleDeductsEarliestExpiryFirst(t *testing.T) {
+	app, mem := newTestApp(t)
+
+	now := time.Now().UTC()
+	soon := now.AddDate(0, 0, 10)
+	later := now.AddDate(0, 3, 0)
+	seedProduct(t, mem, "PRD-001",
+		dto.Batch{BatchId: "BATCH-LATER", StockQty: 10, ExpiryDate: &later, SellingPrice: dto.MoneyFromFloat(100)},
+		dto.Batch{BatchId: "BATCH-SOON", StockQty: 4, ExpiryDate: &soon, SellingPrice: dto.MoneyFromFloat(100)},
+	)
+
+	var body struct {
+		Sale dto.Sale `json:"sale"`
+	}
+	status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 6), &body)
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if body.Sale.Total != dto.MoneyFromFloat(600) || body.Sale.Change != dto.MoneyFromFloat(9400) {
+		t.Fatalf("expected total 600 and change 9400, got %v and %v", body.Sale.Total, body.Sale.Change)
+	}
+
+	product, err := mem.Products.FindById("PRD-001")
+	if err != nil {
+		t.Fatalf("find product: %v", err)
+	}
+	if product.StockQty != 8 {
+		t.Fatalf("expected 8 units left, got %d", product.StockQty)
+	}
+	if len(product.Batches) != 1 || product.Batches[0].BatchId != "BATCH-LATER" || product.Batches[0].StockQty != 8 {
+		t.Fatalf("expected the soon batch emptied and 8 left in BATCH-LATER, got %+v", product.Batches)
+	}
+
+	saved, err := mem.Sales.FindById(body.Sale.SaleID)
+	if err != nil {
+		t.Fatalf("sale was not saved: %v", err)
+	}
+	for _, sale := range []*dto.Sale{&body.Sale, saved} {
+		batches := sale.Items[0].Batches
+		if len(batches) != 2 || batches[0].BatchID != "BATCH-SOON" || batches[0].Quantity != 4 ||
+			batches[1].BatchID != "BATCH-LATER" || batches[1].Quantity != 2 || batches[0].ExpiryDate == nil {
+			t.Fatalf("expected the line to record 4 from BATCH-SOON and 2 from BATCH-LATER, got %+v", batches)
+		}
+	}
+
+	deltas := map[string]int{}
+	for _, movement := range mem.Store.Movements() {
+		if movement.Type == dto.MovementSale && movement.ReferenceId == body.Sale.SaleID {
+			deltas[movement.BatchId] += movement.Delta
+			if movement.UserId != "USR-TEST" {
+				t.Fatalf("expected the sale movement to record the cashier, got %q", movement.UserId)
+			}
+		}
+	}
+	if deltas["BATCH-SOON"] != -4 || deltas["BATCH-LATER"] != -2 {
+		t.Fatalf("expected ledger deltas -4 on BATCH-SOON and -2 on BATCH-LATER, got %v", deltas)
+	}
+}
+
+func TestCreateSaleInsufficientStock(t *testing.T) {
+	app, mem := newTestApp(t)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 3, SellingPrice: dto.MoneyFromFloat(100)})
+
+	status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 5), nil)
+	if status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", status)
+	}
+
+	product, err := mem.Products.FindById("PRD-001")
+	if err != nil {
+		t.Fatalf("find product: %v", err)
+	}
+	if product.StockQty != 3 {
+		t.Fatalf("stock must be untouched, got %d", product.StockQty)
+	}
+	sales, _ := mem.Sales.FindAll(0, 0)
+	if len(sales) != 0 {
+		t.Fatalf("no sale must be recorded, got %d", len(sales))
+	}
+}
+
+func TestCreateSaleRejectsDiscountsOutsideTheBill(t *testing.T) {
+	app, mem := newTestApp(t)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+
+	cases := map[string]struct {
+		value        float64
+		discountType string
+	}{
+		"negative":        {value: -10, discountType: "fixed"},
+		"over 100%":       {value: 120, discountType: "percentage"},
+		"more than due":   {value: 250, discountType: "fixed"},
+		"negative amount": {value: -5, discountType: "percentage"},
+	}
+	for name, tc := range cases {
+		req := saleRequest("PRD-001", 2)
+		req.Discount = tc.value
+		req.DiscountType = tc.discountType
+		if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", req, nil); status != fiber.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, status)
+		}
+	}
+
+	// The whole bill may be discounted
+	req := saleRequest("PRD-001", 2)
+	req.Discount = 100
+	req.DiscountType = "percentage"
+	var created struct {
+		Sale dto.Sale `json:"sale"`
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", req, &created); status != fiber.StatusCreated || created.Sale.Total != 0 {
+		t.Fatalf("expected 201 with nothing to pay, got %d and %v", status, created.Sale.Total)
+	}
+}
+
+func TestCreateSaleRollsBackWhenALaterItemFails(t *testing.T) {
+	app, mem := newTestApp(t)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 5, SellingPrice: dto.MoneyFromFloat(100)})
+	seedProduct(t, mem, "PRD-002", dto.Batch{BatchId: "BATCH-002", StockQty: 2, SellingPrice: dto.MoneyFromFloat(100)})
+
+	// The same product twice passes the per-line stock check but not the combined deduction
+	req := saleRequest("PRD-001", 2)
+	req.Items = append(req.Items,
+		dto.SaleItem{ProductID: "PRD-002", Quantity: 2},
+		dto.SaleItem{ProductID: "PRD-002", Quantity: 1},
+	)
+
+	status := doJSON(t, app, fiber.MethodPost, "/CreateSale", req, nil)
+	if status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", status)
+	}
+
+	for productId, want := range map[string]int{"PRD-001": 5, "PRD-002": 2} {
+		product, err := mem.Products.FindById(productId)
+		if err != nil {
+			t.Fatalf("find product: %v", err)
+		}
+		if product.StockQty != want {
+			t.Fatalf("%s: expected stock rolled back to %d, got %d", productId, want, product.StockQty)
+		}
+	}
+	if len(mem.Store.Movements()) != 2 {
+		t.Fatalf("expected only the two seed movements in the ledger, got %d", len(mem.Store.Movements()))
+	}
+}
+
+func TestCreateSaleUnknownProduct(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-404", 1), nil)
+	if status != fiber.StatusNotFound {
+		t.Fatalf("expected 404, got %d", status)
+	}
+}
+
+func TestCreateSaleWithSplitTenders(t *testing.T) {
+	app, mem := newTestApp(t)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+
+	req := saleRequest("PRD-001", 5)
+	req.Tenders = []dto.Tender{
+		{Type: dto.TenderCard, Amount: dto.MoneyFromFloat(300), Reference: "AUTH-123"},
+		{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(500)},
+	}
+
+	var body struct {
+		Sale dto.Sale `json:"sale"`
+	}
+	status := doJSON(t, app, fiber.MethodPost, "/CreateSale", req, &body)
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	sale := body.Sale
+	if sale.PaymentMethod != dto.PaymentSplit || sale.AmountReceived != dto.MoneyFromFloat(500) || sale.Change != dto.MoneyFromFloat(300) {
+		t.Fatalf("expected a split sale with 500 cash received and 300 change, got %s, %v and %v", sale.PaymentMethod, sale.AmountReceived, sale.Change)
+	}
+	if len(sale.Tenders) != 2 || sale.Tenders[0].Amount != dto.MoneyFromFloat(300) || sale.Tenders[1].Amount != dto.MoneyFromFloat(200) {
+		t.Fatalf("expected card 300 and cash 200 applied, got %+v", sale.Tenders)
+	}
+
+	// Card alone may not exceed the total, since change is only given in cash
+	req.Tenders = []dto.Tender{{Type: dto.TenderCard, Amount: dto.MoneyFromFloat(600)}}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", req, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a card overpayment, got %d", status)
+	}
+}
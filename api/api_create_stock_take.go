@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateStockTakeApi starts a stock take of every product, a category or a brand, at one location or all of them
+// The quantity of every batch in scope is frozen now; products already being counted in another open
+// stock take cannot be counted again until it is approved or cancelled
+func CreateStockTakeApi(c *fiber.Ctx) error {
+	var req dto.CreateStockTakeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	locationId := ""
+	if req.LocationId != "" {
+		var err error
+		if locationId, err = resolveLocation(req.LocationId); err != nil {
+			return respondError(c, err, "Failed to check location")
+		}
+	}
+
+	products, err := repos.Products.FindAll()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve products"})
+	}
+	lines, err := functions.BuildStockTakeLines(products, req.Scope, req.ScopeId, locationId)
+	if err != nil {
+		if errors.Is(err, functions.ErrInvalidStockTake) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to freeze stock quantities"})
+	}
+
+	now := time.Now().UTC()
+	stockTake := &dto.StockTake{
+		Scope:      req.Scope,
+		ScopeId:    req.ScopeId,
+		LocationId: locationId,
+		Status:     dto.StockTakeOpen,
+		Lines:      lines,
+		Notes:      strings.TrimSpace(req.Notes),
+		CreatedBy:  requestUser(c),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	functions.SummarizeStockTake(stockTake)
+
+	open, err := repos.StockTakes.FindAll(dto.StockTakeOpen, true)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve open stock takes"})
+	}
+	for i := range open {
+		if functions.StockTakesOverlap(stockTake, &open[i]) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Stock take " + open[i].StockTakeId + " is already counting some of these products",
+			})
+		}
+	}
+
+	id, err := repos.Ids.NextId(context.Background(), "StockTakes", "STK")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate stock take id"})
+	}
+	stockTake.StockTakeId = id
+
+	if err := repos.StockTakes.Create(stockTake); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create stock take"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(stockTake)
+}
+
+// RecordStockTakeCountsApi records counted quantities on an open stock take
+// A count finds its line by productId or barcode (batchId too when the product has several batches);
+// add=true adds to the count, so each barcode scan can send a quantity of 1
+func RecordStockTakeCountsApi(c *fiber.Ctx) error {
+	var req dto.RecordStockTakeCountsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if len(req.Counts) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "At least one count is required"})
+	}
+
+	stockTake, err := findStockTake(req.StockTakeId, dto.StockTakeOpen)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve stock take")
+	}
+
+	now := time.Now().UTC()
+	if err := functions.ApplyStockTakeCounts(stockTake, req.Counts, requestUser(c), now); err != nil {
+		if errors.Is(err, functions.ErrInvalidStockTakeCount) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to record counts"})
+	}
+	stockTake.UpdatedAt = now
+
+	if err := repos.StockTakes.UpdateCounts(stockTake); err != nil {
+		return respondStockTakeUpdateError(c, err, "Failed to record counts")
+	}
+	return c.JSON(stockTake)
+}
+
+// findStockTake loads a stock take and checks it is in one of statuses
+func findStockTake(stockTakeId string, statuses ...string) (*dto.StockTake, error) {
+	if stockTakeId == "" {
+		return nil, newRequestError(fiber.StatusBadRequest, "stockTakeId is required")
+	}
+	stockTake, err := repos.StockTakes.FindById(stockTakeId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, newRequestError(fiber.StatusNotFound, "Stock take not found")
+		}
+		return nil, err
+	}
+	for _, status := range statuses {
+		if stockTake.Status == status {
+			return stockTake, nil
+		}
+	}
+	return nil, newRequestError(fiber.StatusConflict, "Stock take is "+stockTake.Status)
+}
+
+func respondStockTakeUpdateError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrStockTakeStatusChanged):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Stock take is no longer open, please reload it"})
+	case errors.Is(err, repository.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Stock take not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback, "details": err.Error()})
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"employee-crud/dto"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestStockTakePostsOnlyTheVarianceOnApproval(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Post("/CreateStockTake", CreateStockTakeApi)
+	app.Put("/RecordStockTakeCounts", RecordStockTakeCountsApi)
+	app.Put("/ApproveStockTake", ApproveStockTakeApi)
+	app.Get("/GetStockTakePDF", GetStockTakePDFApi)
+
+	now := time.Now().UTC()
+	soon := now.AddDate(0, 0, 10)
+	later := now.AddDate(0, 3, 0)
+	seedProduct(t, mem, "PRD-001",
+		dto.Batch{BatchId: "BATCH-SOON", StockQty: 10, ExpiryDate: &soon, CostPrice: dto.MoneyFromFloat(60), SellingPrice: dto.MoneyFromFloat(100)},
+		dto.Batch{BatchId: "BATCH-LATER", StockQty: 5, ExpiryDate: &later, CostPrice: dto.MoneyFromFloat(60), SellingPrice: dto.MoneyFromFloat(100)},
+	)
+	scanned := dto.Product{
+		ProductId: "PRD-002", Name: "Scanned", Barcode: "8901234", CostPrice: dto.MoneyFromFloat(25), SellingPrice: dto.MoneyFromFloat(40), StockQty: 4,
+		Batches:   []dto.Batch{{BatchId: "BATCH-SCAN", StockQty: 4, CostPrice: dto.MoneyFromFloat(25), SellingPrice: dto.MoneyFromFloat(40)}},
+		CreatedAt: now, UpdatedAt: now,
+	}
+	if err := mem.Products.Create(&scanned, dto.StockMovementRef{}); err != nil {
+		t.Fatalf("seed product: %v", err)
+	}
+
+	var stockTake dto.StockTake
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateStockTake", dto.CreateStockTakeRequest{Scope: dto.StockTakeAll}, &stockTake); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if len(stockTake.Lines) != 3 || stockTake.ExpectedValue != dto.MoneyFromFloat(1000) {
+		t.Fatalf("expected 3 lines worth 1000 at cost, got %d worth %v", len(stockTake.Lines), stockTake.ExpectedValue)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateStockTake", dto.CreateStockTakeRequest{Scope: dto.StockTakeBrand, ScopeId: ""}, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a brand stock take without a brand, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateStockTake", dto.CreateStockTakeRequest{Scope: dto.StockTakeAll}, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 counting the same products twice, got %d", status)
+	}
+
+	// PRD-001 has two batches, so a count needs the batch
+	ambiguous := dto.RecordStockTakeCountsRequest{StockTakeId: stockTake.StockTakeId, Counts: []dto.StockTakeCount{{ProductId: "PRD-001", Quantity: 8}}}
+	if status := doJSON(t, app, fiber.MethodPut, "/RecordStockTakeCounts", ambiguous, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 without a batchId, got %d", status)
+	}
+
+	counts := dto.RecordStockTakeCountsRequest{StockTakeId: stockTake.StockTakeId, Counts: []dto.StockTakeCount{
+		{ProductId: "PRD-001", BatchId: "BATCH-SOON", Quantity: 8},
+		{ProductId: "PRD-001", BatchId: "BATCH-LATER", Quantity: 7},
+		{Barcode: "8901234", Quantity: 1, Add: true},
+		{Barcode: "8901234", Quantity: 1, Add: true},
+	}}
+	if status := doJSON(t, app, fiber.MethodPut, "/RecordStockTakeCounts", counts, &stockTake); status != fiber.StatusOK {
+		t.Fatalf("expected 200 recording counts, got %d", status)
+	}
+	if stockTake.CountedLines != 3 || stockTake.ShortageValue != dto.MoneyFromFloat(170) ||
+		stockTake.SurplusValue != dto.MoneyFromFloat(120) || stockTake.VarianceValue != dto.MoneyFromFloat(-50) {
+		t.Fatalf("expected 170 short, 120 over and -50 net, got %+v", stockTake)
+	}
+
+	// A sale while counting comes out of the soon batch and must not be undone by the approval
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 3), nil); status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for the sale, got %d", status)
+	}
+
+	if status := doJSON(t, app, fiber.MethodPut, "/ApproveStockTake?stockTakeId="+stockTake.StockTakeId, nil, &stockTake); status != fiber.StatusOK {
+		t.Fatalf("expected 200 approving, got %d", status)
+	}
+	if stockTake.Status != dto.StockTakeApproved || stockTake.ApprovedBy != "USR-TEST" {
+		t.Fatalf("expected the stock take approved by USR-TEST, got %s by %s", stockTake.Status, stockTake.ApprovedBy)
+	}
+
+	product, _ := mem.Products.FindById("PRD-001")
+	quantities := map[string]int{}
+	for _, batch := range product.Batches {
+		quantities[batch.BatchId] = batch.StockQty
+	}
+	if quantities["BATCH-SOON"] != 5 || quantities["BATCH-LATER"] != 7 || product.StockQty != 12 {
+		t.Fatalf("expected 5 and 7 left after the sale and the variance, got %+v", product.Batches)
+	}
+	if product, _ := mem.Products.FindById("PRD-002"); product.StockQty != 2 {
+		t.Fatalf("expected 2 of the scanned product, got %d", product.StockQty)
+	}
+
+	var posted int
+	for _, movement := range mem.Store.Movements() {
+		if movement.Type == dto.MovementStockTake && movement.ReferenceId == stockTake.StockTakeId {
+			posted += movement.Delta
+		}
+	}
+	if posted != -2 {
+		t.Fatalf("expected the ledger to record a net -2 for the stock take, got %d", posted)
+	}
+
+	if status := doJSON(t, app, fiber.MethodPut, "/RecordStockTakeCounts", counts, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 counting an approved stock take, got %d", status)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/GetStockTakePDF?stockTakeId="+stockTake.StockTakeId, nil), -1)
+	if err != nil {
+		t.Fatalf("get PDF: %v", err)
+	}
+	defer resp.Body.Close()
+	pdf, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK || !bytes.HasPrefix(pdf, []byte("%PDF")) {
+		t.Fatalf("expected a PDF, got %d", resp.StatusCode)
+	}
+}
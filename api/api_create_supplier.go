@@ -2,9 +2,7 @@ package api
 
 import (
 	"context"
-	"employee-crud/dao"
 	"employee-crud/dto"
-	"employee-crud/functions"
 	"employee-crud/utils"
 	"time"
 
@@ -19,7 +17,7 @@ func CreateSupplier(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 	ctx := context.Background()
-	id, err := dao.GenerateId(ctx, "Suppliers", "SUPl")
+	id, err := repos.Ids.NextId(ctx, "Suppliers", "SUPl")
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -31,15 +29,11 @@ func CreateSupplier(c *fiber.Ctx) error {
 	// Set status to active by default
 	inputObj.Status = "active"
 
-	if err := functions.UniqueCheck(inputObj, "Suppliers", []string{"SupplierId"}); err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
-	}
-
 	validate := validator.New()
 	if validationErr := validate.Struct(inputObj); validationErr != nil {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, validationErr.Error())
 	}
-	err = dao.DB_CreateSupplier(&inputObj)
+	err = repos.Suppliers.Create(&inputObj)
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
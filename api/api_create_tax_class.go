@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateTaxClassApi adds a tax class that products and categories can then be assigned to
+func CreateTaxClassApi(c *fiber.Ctx) error {
+	var class dto.TaxClass
+	if err := c.BodyParser(&class); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	class.Name = strings.TrimSpace(class.Name)
+	class.Deleted = false
+	if err := functions.ValidateTaxClass(&class); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	id, err := repos.Ids.NextId(context.Background(), "TaxClasses", "TAX")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	now := time.Now().UTC()
+	class.TaxClassID = id
+	class.CreatedAt = now
+	class.UpdatedAt = now
+
+	if err := repos.Taxes.CreateClass(&class); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create tax class"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(class)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"employee-crud/dto"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// assignProduct puts a seeded product in a category and, if taxClassId is set, its own tax class
+func assignProduct(t *testing.T, productId string, categoryId string, taxClassId string) {
+	t.Helper()
+
+	product, err := repos.Products.FindById(productId)
+	if err != nil {
+		t.Fatalf("find product: %v", err)
+	}
+	product.CategoryID = categoryId
+	if err := repos.Products.Update(context.Background(), product, dto.ProductSettingChanges{TaxClassID: &taxClassId}); err != nil {
+		t.Fatalf("update product: %v", err)
+	}
+}
+
+func TestCreateSaleTaxesEachLineByClass(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Post("/CreateTaxClass", CreateTaxClassApi)
+	app.Put("/SetCategoryTaxClass", SetCategoryTaxClassApi)
+	app.Delete("/DeleteTaxClass", DeleteTaxClassApi)
+
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+	seedProduct(t, mem, "PRD-002", dto.Batch{BatchId: "BATCH-002", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+	seedProduct(t, mem, "PRD-003", dto.Batch{BatchId: "BATCH-003", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+
+	var standard, inclusive dto.TaxClass
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateTaxClass", dto.TaxClass{Name: "Standard", Rate: 10}, &standard); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateTaxClass", dto.TaxClass{Name: "Luxury", Rate: 25, Inclusive: true}, &inclusive); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+
+	// PRD-001 and PRD-002 take the category's standard class, PRD-002 overrides it; PRD-003 is exempt
+	assignProduct(t, "PRD-001", "CAT-001", "")
+	assignProduct(t, "PRD-002", "CAT-001", inclusive.TaxClassID)
+	if status := doJSON(t, app, fiber.MethodPut, "/SetCategoryTaxClass?categoryId=CAT-001&taxClassId="+standard.TaxClassID, nil, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+
+	sale := saleRequest("PRD-001", 1)
+	sale.Items = append(sale.Items, dto.SaleItem{ProductID: "PRD-002", Quantity: 1}, dto.SaleItem{ProductID: "PRD-003", Quantity: 1})
+	var body struct {
+		Sale dto.Sale `json:"sale"`
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", sale, &body); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+
+	// 10 added on PRD-001; PRD-002's 100 already includes 20 of tax
+	if body.Sale.Tax != dto.MoneyFromFloat(10) || body.Sale.IncludedTax != dto.MoneyFromFloat(20) || body.Sale.Total != dto.MoneyFromFloat(310) {
+		t.Fatalf("expected 10 added, 20 included and a total of 310, got %v, %v and %v", body.Sale.Tax, body.Sale.IncludedTax, body.Sale.Total)
+	}
+	if body.Sale.Items[2].TaxClassID != "" || len(body.Sale.TaxBreakdown) != 2 {
+		t.Fatalf("expected PRD-003 untaxed and two classes in the breakdown, got %+v", body.Sale)
+	}
+
+	// Still assigned to the category and a product
+	if status := doJSON(t, app, fiber.MethodDelete, "/DeleteTaxClass?taxClassId="+standard.TaxClassID, nil, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 for a tax class in use, got %d", status)
+	}
+}
+
+func TestSetCategoryTaxClassRejectsUnknownClass(t *testing.T) {
+	app, _ := newTestApp(t)
+	app.Put("/SetCategoryTaxClass", SetCategoryTaxClassApi)
+
+	if status := doJSON(t, app, fiber.MethodPut, "/SetCategoryTaxClass?categoryId=CAT-001&taxClassId=TAX-404", nil, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", status)
+	}
+}
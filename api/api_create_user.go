@@ -0,0 +1,82 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func CreateUserApi(c *fiber.Ctx) error {
+	var req dto.CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Username == "" || req.Password == "" || req.Role == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Username, password and role are required"})
+	}
+
+	user, err := newUser(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := repos.Users.Create(user); err != nil {
+		if errors.Is(err, repository.ErrUsernameTaken) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create user"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(user)
+}
+
+// EnsureInitialAdmin creates an admin account when the Users collection is empty,
+// so a fresh installation can log in and create the other users
+func EnsureInitialAdmin(username string, password string) error {
+	count, err := repos.Users.Count()
+	if err != nil || count > 0 {
+		return err
+	}
+	if username == "" || password == "" {
+		return errors.New("no users exist: set ADMIN_USERNAME and ADMIN_PASSWORD to create the first admin")
+	}
+
+	user, err := newUser(dto.CreateUserRequest{
+		Username: username,
+		FullName: "Administrator",
+		Password: password,
+		Role:     dto.RoleAdmin,
+	})
+	if err != nil {
+		return err
+	}
+	return repos.Users.Create(user)
+}
+
+func newUser(req dto.CreateUserRequest) (*dto.User, error) {
+	if !functions.IsValidRole(req.Role) {
+		return nil, errors.New("role must be one of cashier, stock_keeper, manager or admin")
+	}
+
+	hash, err := functions.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &dto.User{
+		UserId:       uuid.New().String(),
+		Username:     req.Username,
+		FullName:     req.FullName,
+		PasswordHash: hash,
+		Role:         req.Role,
+		Active:       true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"employee-crud/repository"
+	"employee-crud/utils"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeleteCustomerApi soft deletes a customer; their sales keep the link for reporting
+func DeleteCustomerApi(c *fiber.Ctx) error {
+	customerId := c.Query("customerId")
+	if customerId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "customerId is required"})
+	}
+
+	if err := repos.Customers.Delete(customerId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Customer not found or already deleted"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete customer"})
+	}
+
+	return utils.SendSuccessResponse(c)
+}
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,7 +13,7 @@ func DeleteProductPermanentApi(c *fiber.Ctx) error {
 		return utils.NewCustomError(c, fiber.StatusBadRequest, "Product ID is required", nil)
 	}
 
-	if err := dao.DB_PermanentDeleteProductByID(productId); err != nil {
+	if err := repos.Products.DeletePermanent(productId); err != nil {
 		return utils.NewCustomError(c, fiber.StatusNotFound, err.Error(), nil)
 	}
 
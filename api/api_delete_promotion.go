@@ -0,0 +1,26 @@
+package api
+
+import (
+	"employee-crud/repository"
+	"employee-crud/utils"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeletePromotionApi soft deletes a promotion; sales that used it keep its id on their lines
+func DeletePromotionApi(c *fiber.Ctx) error {
+	promotionId := c.Query("promotionId")
+	if promotionId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "promotionId is required"})
+	}
+
+	if err := repos.Promotions.Delete(promotionId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Promotion not found or already deleted"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete promotion"})
+	}
+
+	return utils.SendSuccessResponse(c)
+}
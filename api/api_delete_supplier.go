@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,7 +13,7 @@ func DeleteSupplierApi(c *fiber.Ctx) error {
 		return utils.NewCustomError(c, fiber.StatusBadRequest, "Supplier ID is required", nil)
 	}
 
-	if err := dao.DB_DeleteSupplierByID(id); err != nil {
+	if err := repos.Suppliers.Delete(id); err != nil {
 		return utils.NewCustomError(c, fiber.StatusNotFound, err.Error(), nil)
 	}
 
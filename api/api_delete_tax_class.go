@@ -0,0 +1,29 @@
+package api
+
+import (
+	"employee-crud/repository"
+	"employee-crud/utils"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeleteTaxClassApi soft deletes a tax class once no product or category is assigned to it
+func DeleteTaxClassApi(c *fiber.Ctx) error {
+	taxClassId := c.Query("taxClassId")
+	if taxClassId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "taxClassId is required"})
+	}
+
+	if err := repos.Taxes.DeleteClass(taxClassId); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Tax class not found or already deleted"})
+		case errors.Is(err, repository.ErrTaxClassInUse):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete tax class"})
+	}
+
+	return utils.SendSuccessResponse(c)
+}
@@ -1,7 +1,7 @@
 package api
 
 import (
-	"employee-crud/dao"
+	"employee-crud/dto"
 	"employee-crud/utils"
 	"time"
 
@@ -13,6 +13,7 @@ type EditBatchStockRequest struct {
 	ProductId string `json:"productId" validate:"required"`
 	BatchId   string `json:"batchId" validate:"required"`
 	StockQty  int    `json:"stockQty" validate:"gte=0"`
+	Reason    string `json:"reason"` // Optional, recorded on the stock movement
 }
 
 // EditBatchDetailsRequest represents the request to edit batch details
@@ -20,8 +21,8 @@ type EditBatchDetailsRequest struct {
 	ProductId    string     `json:"productId" validate:"required"`
 	BatchId      string     `json:"batchId" validate:"required"`
 	ExpiryDate   *time.Time `json:"expiryDate"`
-	CostPrice    float64    `json:"costPrice"`
-	SellingPrice float64    `json:"sellingPrice"`
+	CostPrice    dto.Money  `json:"costPrice"`
+	SellingPrice dto.Money  `json:"sellingPrice"`
 }
 
 // EditBatchStock updates the stock quantity of a specific batch
@@ -32,13 +33,14 @@ func EditBatchStock(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 
-	product, err := dao.DB_EditBatchStock(req.ProductId, req.BatchId, req.StockQty)
+	product, err := repos.Products.EditBatchStock(req.ProductId, req.BatchId, req.StockQty,
+		dto.StockMovementRef{Type: dto.MovementBatchAdjustment, UserId: requestUser(c), Note: req.Reason})
 	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return utils.SendErrorResponse(c, stockUpdateErrorStatus(err), err.Error())
 	}
 
 	// Sync stock to Stocks collection
-	if err := dao.DB_SyncSingleProductStock(product); err != nil {
+	if err := repos.Stocks.SyncProduct(product); err != nil {
 		// Log but don't fail
 	}
 
@@ -56,19 +58,20 @@ func EditBatchDetails(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 
-	product, err := dao.DB_EditBatchDetails(
+	product, err := repos.Products.EditBatchDetails(
 		req.ProductId,
 		req.BatchId,
 		req.ExpiryDate,
 		req.CostPrice,
 		req.SellingPrice,
+		dto.StockMovementRef{Type: dto.MovementBatchEdit, UserId: requestUser(c)},
 	)
 	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return utils.SendErrorResponse(c, stockUpdateErrorStatus(err), err.Error())
 	}
 
 	// Sync stock to Stocks collection
-	if err := dao.DB_SyncSingleProductStock(product); err != nil {
+	if err := repos.Stocks.SyncProduct(product); err != nil {
 		// Log but don't fail
 	}
 
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// FindAllCustomersApi lists active customers by name
+// Query params:
+//   - search: optional, matches part of the name (case-insensitive) or mobile number
+func FindAllCustomersApi(c *fiber.Ctx) error {
+	customers, err := repos.Customers.FindAll(c.Query("search"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve customers"})
+	}
+	return c.JSON(customers)
+}
@@ -1,13 +1,11 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
 func FindAllDeletedProductsApi(c *fiber.Ctx) error {
-	brands, err := dao.DB_FindAllDeletedProducts()
+	brands, err := repos.Products.FindAllDeleted()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 	"math"
 	"strconv"
@@ -35,7 +34,7 @@ func FindAllGRNs(c *fiber.Ctx) error {
 	}
 
 	// Always use paginated version for consistent response format
-	grns, total, err := dao.DB_FindAllGRNsPaginated(page, perPage)
+	grns, total, err := repos.GRNs.FindAllPaginated(page, perPage)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 	"strconv"
 
@@ -28,7 +27,7 @@ func FindAllProducts(c *fiber.Ctx) error {
 	}
 
 	// Always use cursor-based pagination for optimal performance
-	products, nextCursor, hasMore, err := dao.DB_FindAllProductsCursorPaginated(perPage, cursor)
+	products, nextCursor, hasMore, err := repos.Products.FindAllCursorPaginated(perPage, cursor)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 	"strconv"
 
@@ -28,7 +27,7 @@ func GetProductsByBarcodeApi(c *fiber.Ctx) error {
 		perPage = 15
 	}
 
-	products, nextCursor, hasMore, err := dao.DB_FindProductsByBarcodeCursorPaginated(barcode, perPage, cursor)
+	products, nextCursor, hasMore, err := repos.Products.FindByBarcodeCursorPaginated(barcode, perPage, cursor)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
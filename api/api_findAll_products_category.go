@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 	"strconv"
 
@@ -34,7 +33,7 @@ func GetProductsByCategoryApi(c *fiber.Ctx) error {
 	}
 
 	// Use cursor-based pagination for optimal performance
-	products, nextCursor, hasMore, err := dao.DB_FindProductsByCategoryCursorPaginated(categoryId, perPage, cursor)
+	products, nextCursor, hasMore, err := repos.Products.FindByCategoryCursorPaginated(categoryId, perPage, cursor)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
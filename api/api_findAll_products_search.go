@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/dto"
 	"strings"
 
@@ -11,7 +10,7 @@ import (
 func FindAllProductsSearch(c *fiber.Ctx) error {
 	search := c.Query("search")
 
-	products, err := dao.DB_FindAllProducts()
+	products, err := repos.Products.FindAll()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
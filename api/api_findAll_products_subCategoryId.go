@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/dto"
 	"employee-crud/utils"
 
@@ -15,7 +14,7 @@ func GetAllProductsBySubCategoryApi(c *fiber.Ctx) error {
 		return utils.NewCustomError(c, fiber.StatusBadRequest, "SubCategoryId is required", nil)
 	}
 
-	products, err := dao.DB_FindProductsBySubCategory(categoryId)
+	products, err := repos.Products.FindBySubCategory(categoryId)
 	if err != nil {
 		return utils.NewCustomError(c, fiber.StatusInternalServerError, err.Error(), nil)
 	}
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 	"strconv"
 
@@ -32,7 +31,7 @@ func FindAllProductsWithStockApi(c *fiber.Ctx) error {
 	}
 
 	// Get products with stock
-	productsWithStock, nextCursor, hasMore, err := dao.DB_FindAllProductsWithStock(perPage, cursor)
+	productsWithStock, nextCursor, hasMore, err := repos.Stocks.FindProductsWithStock(perPage, cursor)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -40,7 +39,7 @@ func FindAllProductsWithStockApi(c *fiber.Ctx) error {
 	}
 
 	// Get total count (optional)
-	totalCount, _ := dao.DB_GetProductsWithStockCount()
+	totalCount, _ := repos.Stocks.CountProductsWithStock()
 
 	response := fiber.Map{
 		"data":        productsWithStock,
@@ -76,7 +75,7 @@ func FindAllProductsWithStockLiteApi(c *fiber.Ctx) error {
 	}
 
 	// Get products with stock
-	productsWithStock, nextCursor, hasMore, err := dao.DB_FindAllProductsWithStock(perPage, cursor)
+	productsWithStock, nextCursor, hasMore, err := repos.Stocks.FindProductsWithStock(perPage, cursor)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
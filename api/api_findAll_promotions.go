@@ -0,0 +1,16 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// FindAllPromotionsApi lists promotions, highest priority first
+// Query params:
+//   - active: optional, "true" to leave out promotions that are switched off
+func FindAllPromotionsApi(c *fiber.Ctx) error {
+	promotions, err := repos.Promotions.FindAll(c.Query("active") == "true")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve promotions"})
+	}
+	return c.JSON(promotions)
+}
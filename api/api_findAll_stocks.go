@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 	"strconv"
 
@@ -12,10 +11,12 @@ import (
 // Query params:
 //   - cursor: optional, for pagination (pass the next_cursor from previous response)
 //   - per_page: optional, default 15, allowed values: 15, 25, 50
+//   - locationId: optional, only the batches held at that location
 func FindAllStocksApi(c *fiber.Ctx) error {
 	// Get cursor and per_page parameters
 	cursor := c.Query("cursor", "")
 	perPageStr := c.Query("per_page", "15")
+	locationId := c.Query("locationId")
 
 	// Parse per_page
 	perPage, err := strconv.Atoi(perPageStr)
@@ -32,7 +33,7 @@ func FindAllStocksApi(c *fiber.Ctx) error {
 	}
 
 	// Use cursor-based pagination for optimal performance with large datasets
-	stocks, nextCursor, hasMore, err := dao.DB_FindAllStocksCursorPaginated(perPage, cursor)
+	stocks, nextCursor, hasMore, err := repos.Stocks.FindAllCursorPaginated(perPage, cursor, locationId)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -40,7 +41,7 @@ func FindAllStocksApi(c *fiber.Ctx) error {
 	}
 
 	// Get total count (optional, can be removed for better performance)
-	totalCount, _ := dao.DB_GetStocksCount()
+	totalCount, _ := repos.Stocks.Count(locationId)
 
 	response := fiber.Map{
 		"data":        stocks,
@@ -59,6 +60,7 @@ func FindAllStocksLightweightApi(c *fiber.Ctx) error {
 	// Get cursor and per_page parameters
 	cursor := c.Query("cursor", "")
 	perPageStr := c.Query("per_page", "15")
+	locationId := c.Query("locationId")
 
 	// Parse per_page
 	perPage, err := strconv.Atoi(perPageStr)
@@ -75,7 +77,7 @@ func FindAllStocksLightweightApi(c *fiber.Ctx) error {
 	}
 
 	// Use cursor-based pagination for optimal performance
-	stocks, nextCursor, hasMore, err := dao.DB_FindAllStocksCursorPaginated(perPage, cursor)
+	stocks, nextCursor, hasMore, err := repos.Stocks.FindAllCursorPaginated(perPage, cursor, locationId)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
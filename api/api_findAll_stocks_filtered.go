@@ -1,22 +1,30 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/dto"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// stockStatusLabels maps the status query parameter to the stock status it filters on
+var stockStatusLabels = map[string]string{
+	"low":     dto.StockStatusLow,
+	"average": dto.StockStatusAverage,
+	"good":    dto.StockStatusGood,
+}
+
 // FindAllStocksFilteredApi retrieves stocks filtered by status with cursor-based pagination
 // Query params:
 //   - cursor: optional, for pagination (pass the next_cursor from previous response)
 //   - per_page: optional, default 15, allowed values: 15, 25, 50
+//   - locationId: optional, judges the stock held at that location and lists only its batches
 //   - status: required, allowed values: "low", "average", "good"
 func FindAllStocksFilteredApi(c *fiber.Ctx) error {
 	// Get cursor and per_page parameters
 	cursor := c.Query("cursor", "")
 	perPageStr := c.Query("per_page", "15")
+	locationId := c.Query("locationId")
 	statusFilter := c.Query("status", "")
 
 	// Validate status filter
@@ -26,24 +34,9 @@ func FindAllStocksFilteredApi(c *fiber.Ctx) error {
 		})
 	}
 
-	// Normalize status filter
-	var minQty, maxQty int
-	var statusLabel string
-
-	switch statusFilter {
-	case "low":
-		minQty = 0
-		maxQty = 9 // StockQty < 10
-		statusLabel = "Low Stock"
-	case "average":
-		minQty = 10
-		maxQty = 24 // StockQty >= 10 and < 25
-		statusLabel = "Average Stock"
-	case "good":
-		minQty = 25
-		maxQty = -1 // StockQty >= 25 (no upper limit)
-		statusLabel = "Good Stock"
-	default:
+	// Normalize status filter; each product is judged by its own, its category's or the global thresholds
+	statusLabel, ok := stockStatusLabels[statusFilter]
+	if !ok {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid status value. Allowed values: low, average, good",
 		})
@@ -64,7 +57,7 @@ func FindAllStocksFilteredApi(c *fiber.Ctx) error {
 	}
 
 	// Use cursor-based pagination with filtering for optimal performance
-	stocks, nextCursor, hasMore, err := dao.DB_FindAllStocksFilteredCursorPaginated(perPage, cursor, minQty, maxQty)
+	stocks, nextCursor, hasMore, err := repos.Stocks.FindFilteredCursorPaginated(perPage, cursor, statusLabel, locationId)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -72,7 +65,7 @@ func FindAllStocksFilteredApi(c *fiber.Ctx) error {
 	}
 
 	// Get total count for filtered results (optional)
-	totalCount, _ := dao.DB_GetStocksCountFiltered(minQty, maxQty)
+	totalCount, _ := repos.Stocks.CountFiltered(statusLabel, locationId)
 
 	response := fiber.Map{
 		"data":        stocks,
@@ -90,11 +83,13 @@ func FindAllStocksFilteredApi(c *fiber.Ctx) error {
 // Query params:
 //   - cursor: optional, for pagination
 //   - per_page: optional, default 15, allowed values: 15, 25, 50
+//   - locationId: optional, judges the stock held at that location and lists only its batches
 //   - status: required, allowed values: "low", "average", "good"
 func FindAllStocksFilteredLightweightApi(c *fiber.Ctx) error {
 	// Get cursor and per_page parameters
 	cursor := c.Query("cursor", "")
 	perPageStr := c.Query("per_page", "15")
+	locationId := c.Query("locationId")
 	statusFilter := c.Query("status", "")
 
 	// Validate status filter
@@ -104,24 +99,9 @@ func FindAllStocksFilteredLightweightApi(c *fiber.Ctx) error {
 		})
 	}
 
-	// Normalize status filter
-	var minQty, maxQty int
-	var statusLabel string
-
-	switch statusFilter {
-	case "low":
-		minQty = 0
-		maxQty = 9
-		statusLabel = "Low Stock"
-	case "average":
-		minQty = 10
-		maxQty = 24
-		statusLabel = "Average Stock"
-	case "good":
-		minQty = 25
-		maxQty = -1
-		statusLabel = "Good Stock"
-	default:
+	// Normalize status filter; each product is judged by its own, its category's or the global thresholds
+	statusLabel, ok := stockStatusLabels[statusFilter]
+	if !ok {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid status value. Allowed values: low, average, good",
 		})
@@ -142,7 +122,7 @@ func FindAllStocksFilteredLightweightApi(c *fiber.Ctx) error {
 	}
 
 	// Use cursor-based pagination with filtering
-	stocks, nextCursor, hasMore, err := dao.DB_FindAllStocksFilteredCursorPaginated(perPage, cursor, minQty, maxQty)
+	stocks, nextCursor, hasMore, err := repos.Stocks.FindFilteredCursorPaginated(perPage, cursor, statusLabel, locationId)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
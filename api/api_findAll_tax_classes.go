@@ -0,0 +1,14 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// FindAllTaxClassesApi lists the tax classes by name
+func FindAllTaxClassesApi(c *fiber.Ctx) error {
+	classes, err := repos.Taxes.FindAllClasses()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve tax classes"})
+	}
+	return c.JSON(classes)
+}
@@ -0,0 +1,11 @@
+package api
+
+import "github.com/gofiber/fiber/v2"
+
+func FindAllUsersApi(c *fiber.Ctx) error {
+	users, err := repos.Users.FindAll()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch users"})
+	}
+	return c.JSON(users)
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"employee-crud/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FindCartByIdApi returns a cart in any status with its lines priced at today's prices
+func FindCartByIdApi(c *fiber.Ctx) error {
+	cart, err := findCart(c.Query("cartId"), dto.CartOpen, dto.CartParked, dto.CartConverted, dto.CartCancelled)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve cart")
+	}
+
+	response, err := cartResponse(cart)
+	if err != nil {
+		return respondError(c, err, "Failed to price cart")
+	}
+	return c.JSON(response)
+}
+
+// FindParkedCartsApi lists the parked carts of a terminal, or of every terminal without terminalId,
+// most recently parked first
+func FindParkedCartsApi(c *fiber.Ctx) error {
+	carts, err := repos.Carts.FindByTerminal(c.Query("terminalId"), dto.CartParked)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve parked carts"})
+	}
+	return c.JSON(fiber.Map{
+		"carts": carts,
+		"total": len(carts),
+	})
+}
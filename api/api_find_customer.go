@@ -0,0 +1,44 @@
+package api
+
+import (
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FindCustomerByIdApi returns a customer with their loyalty balance and lifetime spend
+func FindCustomerByIdApi(c *fiber.Ctx) error {
+	customerId := c.Query("customerId")
+	if customerId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "customerId is required"})
+	}
+
+	customer, err := repos.Customers.FindById(customerId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Customer not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve customer"})
+	}
+	return c.JSON(customer)
+}
+
+// FindCustomerByMobileApi looks a customer up by mobile number, e.g. at checkout
+// The number is matched however it was typed (spaces and dashes are ignored)
+func FindCustomerByMobileApi(c *fiber.Ctx) error {
+	mobileNumber := functions.NormalizeMobile(c.Query("mobileNumber"))
+	if mobileNumber == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "A valid mobileNumber is required"})
+	}
+
+	customer, err := repos.Customers.FindByMobile(mobileNumber)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "No customer registered with this mobile number"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve customer"})
+	}
+	return c.JSON(customer)
+}
@@ -1,9 +1,7 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/dto"
-	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -16,7 +14,7 @@ func FindGRNByIdApi(c *fiber.Ctx) error {
 		})
 	}
 
-	grn, err := dao.DB_FindGRNById(grnId)
+	grn, err := repos.GRNs.FindById(grnId)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{
 			"error": "GRN not found",
@@ -70,6 +68,6 @@ func calculateTotalReceivedQty(items []dto.GRNItem) int {
 	return total
 }
 
-func formatCurrency(amount float64) string {
-	return "Rs. " + strconv.FormatFloat(amount, 'f', 2, 64)
+func formatCurrency(amount dto.Money) string {
+	return "Rs. " + amount.String()
 }
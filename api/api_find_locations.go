@@ -0,0 +1,52 @@
+package api
+
+import (
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FindAllLocationsApi lists the locations by name, optionally only those in ?status=active|inactive
+func FindAllLocationsApi(c *fiber.Ctx) error {
+	status := c.Query("status")
+	if status != "" && status != "active" && status != "inactive" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "status must be active or inactive"})
+	}
+
+	locations, err := repos.Locations.FindAll(status)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve locations"})
+	}
+	return c.JSON(locations)
+}
+
+// FindLocationByIdApi returns one location
+func FindLocationByIdApi(c *fiber.Ctx) error {
+	location, err := repos.Locations.FindById(c.Query("locationId"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Location not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve location"})
+	}
+	return c.JSON(location)
+}
+
+// resolveLocation checks that stock can be moved at a location and returns its id, the main location when empty
+func resolveLocation(locationId string) (string, error) {
+	locationId = functions.NormalizeLocationId(locationId)
+	location, err := repos.Locations.FindById(locationId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", newRequestError(fiber.StatusNotFound, fmt.Sprintf("Location %s not found", locationId))
+		}
+		return "", err
+	}
+	if location.Status != "active" {
+		return "", newRequestError(fiber.StatusBadRequest, fmt.Sprintf("Location %s is inactive", locationId))
+	}
+	return locationId, nil
+}
@@ -1,8 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -15,7 +13,7 @@ func FindProductByID(c *fiber.Ctx) error {
 		})
 	}
 
-	product, err := dao.DB_FindProductByID(productId)
+	product, err := repos.Products.FindById(productId)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Product not found",
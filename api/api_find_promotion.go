@@ -0,0 +1,24 @@
+package api
+
+import (
+	"employee-crud/repository"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func FindPromotionByIdApi(c *fiber.Ctx) error {
+	promotionId := c.Query("promotionId")
+	if promotionId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "promotionId is required"})
+	}
+
+	promotion, err := repos.Promotions.FindById(promotionId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Promotion not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve promotion"})
+	}
+	return c.JSON(promotion)
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"employee-crud/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FindPurchaseOrderByIdApi returns a purchase order with the GRNs raised against it
+func FindPurchaseOrderByIdApi(c *fiber.Ctx) error {
+	po, err := findPurchaseOrder(c.Query("poId"), dto.PODraft, dto.POApproved, dto.POSent, dto.POPartiallyReceived, dto.POClosed)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve purchase order")
+	}
+
+	grns, err := repos.GRNs.FindByPurchaseOrder(po.POId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve GRNs"})
+	}
+	return c.JSON(fiber.Map{
+		"purchaseOrder": po,
+		"grns":          grns,
+	})
+}
+
+// FindAllPurchaseOrdersApi lists purchase orders newest first, optionally of one supplier and in one status
+func FindAllPurchaseOrdersApi(c *fiber.Ctx) error {
+	orders, err := repos.PurchaseOrders.FindAll(c.Query("supplierId"), c.Query("status"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve purchase orders"})
+	}
+	return c.JSON(fiber.Map{
+		"purchaseOrders": orders,
+		"total":          len(orders),
+	})
+}
@@ -1,8 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -15,7 +13,7 @@ func FindSaleByIdApi(c *fiber.Ctx) error {
 		})
 	}
 
-	sale, err := dao.FindSaleBySaleId(saleId)
+	sale, err := repos.Sales.FindById(saleId)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Sale not found",
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"employee-crud/config"
+	"employee-crud/dao"
+	"employee-crud/utils"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FindStockMovementsApi lists stock ledger entries, newest first
+// Query params:
+//   - productId, batchId, type: optional filters
+//   - startDate, endDate: optional, format YYYY-MM-DD (both inclusive, business timezone)
+//   - page: optional, default 1
+//   - per_page: optional, default 15, allowed values: 15, 25, 50
+func FindStockMovementsApi(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	perPage, err := strconv.Atoi(c.Query("per_page", "15"))
+	if err != nil {
+		perPage = 15
+	}
+
+	// Validate per_page values (only allow 15, 25, 50)
+	switch perPage {
+	case 15, 25, 50:
+		// Valid per_page value
+	default:
+		perPage = 15 // Default to 15 if invalid value provided
+	}
+
+	filter := dao.StockMovementFilter{
+		ProductId: c.Query("productId"),
+		BatchId:   c.Query("batchId"),
+		Type:      c.Query("type"),
+	}
+
+	businessLoc := config.Location()
+
+	if startStr := c.Query("startDate"); startStr != "" {
+		startDate, err := time.ParseInLocation("2006-01-02", startStr, businessLoc)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid startDate format. Use YYYY-MM-DD (e.g., 2025-10-19)",
+			})
+		}
+		filter.StartDate = &startDate
+	}
+
+	if endStr := c.Query("endDate"); endStr != "" {
+		endDate, err := time.ParseInLocation("2006-01-02", endStr, businessLoc)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid endDate format. Use YYYY-MM-DD (e.g., 2025-10-19)",
+			})
+		}
+		// Include the whole end day
+		endExclusive := endDate.AddDate(0, 0, 1)
+		filter.EndDate = &endExclusive
+	}
+
+	movements, total, err := repos.Stocks.FindMovements(filter, page, perPage)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	response := utils.PaginatedResponse{
+		Data:       movements,
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: int(math.Ceil(float64(total) / float64(perPage))),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"employee-crud/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FindStockTakeByIdApi returns a stock take with its lines
+func FindStockTakeByIdApi(c *fiber.Ctx) error {
+	stockTake, err := findStockTake(c.Query("stockTakeId"), dto.StockTakeOpen, dto.StockTakeApproved, dto.StockTakeCancelled)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve stock take")
+	}
+	return c.JSON(stockTake)
+}
+
+// FindAllStockTakesApi lists stock takes newest first without their lines, optionally in one status
+func FindAllStockTakesApi(c *fiber.Ctx) error {
+	stockTakes, err := repos.StockTakes.FindAll(c.Query("status"), false)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve stock takes"})
+	}
+	return c.JSON(fiber.Map{
+		"stockTakes": stockTakes,
+		"total":      len(stockTakes),
+	})
+}
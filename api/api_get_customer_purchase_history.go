@@ -0,0 +1,62 @@
+package api
+
+import (
+	"employee-crud/repository"
+	"employee-crud/utils"
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetCustomerPurchaseHistoryApi returns a customer with one page of their sales, newest first
+// Query params:
+//   - customerId: required
+//   - page: optional, default 1
+//   - per_page: optional, default 15, allowed values: 15, 25, 50
+func GetCustomerPurchaseHistoryApi(c *fiber.Ctx) error {
+	customerId := c.Query("customerId")
+	if customerId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "customerId is required"})
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	perPage, err := strconv.Atoi(c.Query("per_page", "15"))
+	if err != nil {
+		perPage = 15
+	}
+	switch perPage {
+	case 15, 25, 50:
+	default:
+		perPage = 15
+	}
+
+	customer, err := repos.Customers.FindById(customerId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Customer not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve customer"})
+	}
+
+	sales, total, err := repos.Customers.FindSales(customerId, page, perPage)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"customer": customer,
+		"sales": utils.PaginatedResponse{
+			Data:       sales,
+			Page:       page,
+			PerPage:    perPage,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(perPage))),
+		},
+	})
+}
@@ -1,7 +1,7 @@
 package api
 
 import (
-	"employee-crud/dao"
+	"employee-crud/config"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,9 +15,9 @@ func GetDailySalesSummaryApi(c *fiber.Ctx) error {
 	var err error
 
 	if dateStr == "" {
-		// Use today's date in Sri Lanka timezone (UTC+5:30)
-		sriLankaLoc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
-		targetDate = time.Now().In(sriLankaLoc)
+		// Use today's date in the business timezone
+		businessLoc := config.Location()
+		targetDate = time.Now().In(businessLoc)
 	} else {
 		// Parse the provided date
 		targetDate, err = time.Parse("2006-01-02", dateStr)
@@ -28,8 +28,8 @@ func GetDailySalesSummaryApi(c *fiber.Ctx) error {
 		}
 	}
 
-	// Get sales summary for the date
-	summary, err := dao.GetDailySalesSummary(targetDate)
+	// Get sales summary for the date, of one location when locationId is given
+	summary, err := repos.Reports.GetDailySalesSummary(targetDate, c.Query("locationId"))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve sales summary: " + err.Error(),
@@ -2,8 +2,9 @@ package api
 
 import (
 	"bytes"
-	"employee-crud/dao"
+	"employee-crud/config"
 	"employee-crud/dto"
+	"employee-crud/functions"
 	"fmt"
 	"strconv"
 	"time"
@@ -20,9 +21,9 @@ func GetDailySalesSummaryPDFApi(c *fiber.Ctx) error {
 	var err error
 
 	if dateStr == "" {
-		// Use today's date in Sri Lanka timezone (UTC+5:30)
-		sriLankaLoc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
-		targetDate = time.Now().In(sriLankaLoc)
+		// Use today's date in the business timezone
+		businessLoc := config.Location()
+		targetDate = time.Now().In(businessLoc)
 	} else {
 		// Parse the provided date
 		targetDate, err = time.Parse("2006-01-02", dateStr)
@@ -33,8 +34,8 @@ func GetDailySalesSummaryPDFApi(c *fiber.Ctx) error {
 		}
 	}
 
-	// Get sales summary for the date
-	summary, err := dao.GetDailySalesSummary(targetDate)
+	// Get sales summary for the date, of one location when locationId is given
+	summary, err := repos.Reports.GetDailySalesSummary(targetDate, c.Query("locationId"))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve sales summary: " + err.Error(),
@@ -75,6 +76,9 @@ func generateDailySalesSummaryPDF(summary *dto.DailySalesSummary) ([]byte, error
 	pdf.SetFont("Arial", "", 12)
 	pdf.SetTextColor(60, 60, 60)
 	pdf.CellFormat(0, 8, "Report Date: "+summary.ReportDate.Format("Monday, January 2, 2006"), "", 1, "C", false, 0, "")
+	if summary.LocationId != "" {
+		pdf.CellFormat(0, 8, "Location: "+summary.LocationId, "", 1, "C", false, 0, "")
+	}
 
 	pdf.SetFont("Arial", "", 10)
 	pdf.SetTextColor(100, 100, 100)
@@ -90,11 +94,11 @@ func generateDailySalesSummaryPDF(summary *dto.DailySalesSummary) ([]byte, error
 	// Create bordered box for overview
 	currentY := pdf.GetY()
 	pdf.SetFillColor(245, 248, 250)
-	pdf.Rect(15, currentY, 180, 52, "FD")
+	pdf.Rect(15, currentY, 180, 37, "FD")
 
 	pdf.SetXY(15, currentY+5)
 
-	// Layout the overview in a grid (2x3)
+	// Layout the overview in a grid (2x2)
 	leftColX := 20.0
 	rightColX := 105.0
 	rowHeight := 15.0
@@ -117,36 +121,19 @@ func generateDailySalesSummaryPDF(summary *dto.DailySalesSummary) ([]byte, error
 	pdf.Cell(40, 6, "Total Revenue:")
 	pdf.SetFont("Arial", "B", 14)
 	pdf.SetTextColor(0, 153, 51)
-	pdf.Cell(0, 6, "Rs. "+strconv.FormatFloat(summary.TotalRevenue, 'f', 2, 64))
+	pdf.Cell(0, 6, "Rs. "+summary.TotalRevenue.String())
 
 	// Row 2
 	currentY += rowHeight
 	pdf.SetTextColor(0, 0, 0)
 
-	// Cash Sales
-	pdf.SetXY(leftColX, currentY)
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(40, 6, "Cash Sales:")
-	pdf.SetFont("Arial", "B", 11)
-	pdf.Cell(0, 6, strconv.Itoa(summary.CashSales)+" (Rs. "+strconv.FormatFloat(summary.CashRevenue, 'f', 2, 64)+")")
-
-	// Card Sales
-	pdf.SetXY(rightColX, currentY)
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(40, 6, "Card Sales:")
-	pdf.SetFont("Arial", "B", 11)
-	pdf.Cell(0, 6, strconv.Itoa(summary.CardSales)+" (Rs. "+strconv.FormatFloat(summary.CardRevenue, 'f', 2, 64)+")")
-
-	// Row 3
-	currentY += rowHeight
-
 	// Total Discount
 	pdf.SetXY(leftColX, currentY)
 	pdf.SetFont("Arial", "", 10)
 	pdf.Cell(40, 6, "Total Discount:")
 	pdf.SetFont("Arial", "B", 11)
 	pdf.SetTextColor(204, 0, 0)
-	pdf.Cell(0, 6, "Rs. "+strconv.FormatFloat(summary.TotalDiscount, 'f', 2, 64))
+	pdf.Cell(0, 6, "Rs. "+summary.TotalDiscount.String())
 
 	// Total Tax
 	pdf.SetXY(rightColX, currentY)
@@ -154,11 +141,38 @@ func generateDailySalesSummaryPDF(summary *dto.DailySalesSummary) ([]byte, error
 	pdf.SetTextColor(0, 0, 0)
 	pdf.Cell(40, 6, "Total Tax:")
 	pdf.SetFont("Arial", "B", 11)
-	pdf.Cell(0, 6, "Rs. "+strconv.FormatFloat(summary.TotalTax, 'f', 2, 64))
+	pdf.Cell(0, 6, "Rs. "+summary.TotalTax.String())
 
 	pdf.SetTextColor(0, 0, 0)
 	pdf.Ln(20)
 
+	// Revenue by Tender Section
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(0, 10, "Revenue by Tender", "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	addTenderTable(pdf, summary.Tenders)
+	pdf.Ln(8)
+
+	// Tax by Class Section
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(0, 10, "Tax by Class", "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	addTaxTable(pdf, summary.TaxBreakdown)
+	pdf.Ln(8)
+
+	// Gross Profit Section, costed at the batches each line was sold from
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(0, 10, "Gross Profit", "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	addProfitOverview(pdf, &summary.Profit)
+	pdf.Ln(4)
+	addProfitTable(pdf, "Category", summary.Profit.ByCategory, len(summary.Profit.ByCategory))
+	pdf.Ln(4)
+	addProfitTable(pdf, "Brand", summary.Profit.ByBrand, len(summary.Profit.ByBrand))
+	pdf.Ln(4)
+	addProfitTable(pdf, "Product (top 10 by profit)", summary.Profit.ByProduct, 10)
+	pdf.Ln(8)
+
 	// Top Selling Items Section
 	if len(summary.TopSellingItems) > 0 {
 		pdf.SetFont("Arial", "B", 16)
@@ -206,8 +220,8 @@ func generateDailySalesSummaryPDF(summary *dto.DailySalesSummary) ([]byte, error
 				rank,
 				productName,
 				strconv.Itoa(item.Quantity),
-				"Rs. " + strconv.FormatFloat(item.UnitPrice, 'f', 2, 64),
-				"Rs. " + strconv.FormatFloat(item.TotalAmount, 'f', 2, 64),
+				"Rs. " + item.UnitPrice.String(),
+				"Rs. " + item.TotalAmount.String(),
 			}
 
 			for i, data := range rowData {
@@ -226,6 +240,15 @@ func generateDailySalesSummaryPDF(summary *dto.DailySalesSummary) ([]byte, error
 		pdf.Ln(5)
 	}
 
+	// Voided Sales Section, not counted in the figures above
+	if len(summary.VoidedSales) > 0 {
+		pdf.SetFont("Arial", "B", 16)
+		pdf.CellFormat(0, 10, "Voided Sales", "", 1, "L", false, 0, "")
+		pdf.Ln(2)
+		addVoidedSalesTable(pdf, summary.VoidedSales)
+		pdf.Ln(8)
+	}
+
 	// All Products Sold Section
 	if len(summary.ProductsSold) > 0 {
 		pdf.SetFont("Arial", "B", 16)
@@ -288,8 +311,8 @@ func generateDailySalesSummaryPDF(summary *dto.DailySalesSummary) ([]byte, error
 				item.ProductID,
 				productName,
 				strconv.Itoa(item.Quantity),
-				"Rs. " + strconv.FormatFloat(item.UnitPrice, 'f', 2, 64),
-				"Rs. " + strconv.FormatFloat(item.TotalAmount, 'f', 2, 64),
+				"Rs. " + item.UnitPrice.String(),
+				"Rs. " + item.TotalAmount.String(),
 			}
 
 			for i, data := range rowData {
@@ -321,7 +344,7 @@ func generateDailySalesSummaryPDF(summary *dto.DailySalesSummary) ([]byte, error
 	pdf.SetFont("Arial", "B", 12)
 	pdf.CellFormat(90, 10, "Grand Total Revenue:", "", 0, "R", false, 0, "")
 	pdf.SetFont("Arial", "B", 14)
-	pdf.CellFormat(90, 10, "Rs. "+strconv.FormatFloat(summary.TotalRevenue, 'f', 2, 64), "", 0, "L", false, 0, "")
+	pdf.CellFormat(90, 10, "Rs. "+summary.TotalRevenue.String(), "", 0, "L", false, 0, "")
 
 	// Footer
 	pdf.SetY(-25)
@@ -341,3 +364,204 @@ func generateDailySalesSummaryPDF(summary *dto.DailySalesSummary) ([]byte, error
 
 	return buf.Bytes(), nil
 }
+
+// addTaxTable writes the taxable amount and tax of each tax class and rate as a table
+func addTaxTable(pdf *gofpdf.Fpdf, breakdown []dto.TaxSummary) {
+	colWidths := []float64{60, 25, 25, 35, 35}
+	headers := []string{"Tax Class", "Rate", "Prices", "Taxable", "Tax"}
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(52, 73, 94)
+	pdf.SetTextColor(255, 255, 255)
+	for i, header := range headers {
+		pdf.CellFormat(colWidths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "", 8)
+	pdf.SetTextColor(0, 0, 0)
+	if len(breakdown) == 0 {
+		pdf.SetFillColor(255, 255, 255)
+		pdf.CellFormat(colWidths[0]+colWidths[1]+colWidths[2]+colWidths[3]+colWidths[4], 7, "No tax charged", "1", 1, "C", true, 0, "")
+		return
+	}
+
+	for idx, tax := range breakdown {
+		if idx%2 == 0 {
+			pdf.SetFillColor(245, 245, 245)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+		prices := "Exclusive"
+		if tax.Inclusive {
+			prices = "Inclusive"
+		}
+		taxable := "Rs. " + tax.TaxableAmount.String()
+		if tax.TaxClassID == "" {
+			// Bill-level tax from before tax classes
+			prices = "-"
+			if tax.TaxableAmount == 0 {
+				taxable = "-"
+			}
+		}
+		pdf.CellFormat(colWidths[0], 7, tax.Name, "1", 0, "L", true, 0, "")
+		pdf.CellFormat(colWidths[1], 7, strconv.FormatFloat(tax.Rate, 'f', -1, 64)+"%", "1", 0, "R", true, 0, "")
+		pdf.CellFormat(colWidths[2], 7, prices, "1", 0, "C", true, 0, "")
+		pdf.CellFormat(colWidths[3], 7, taxable, "1", 0, "R", true, 0, "")
+		pdf.CellFormat(colWidths[4], 7, "Rs. "+tax.Tax.String(), "1", 0, "R", true, 0, "")
+		pdf.Ln(7)
+	}
+}
+
+// addTenderTable writes the sales count and revenue of each tender type as a table
+func addTenderTable(pdf *gofpdf.Fpdf, tenders []dto.TenderSummary) {
+	colWidths := []float64{70, 40, 70}
+	headers := []string{"Tender", "Sales", "Revenue"}
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(52, 73, 94)
+	pdf.SetTextColor(255, 255, 255)
+	for i, header := range headers {
+		pdf.CellFormat(colWidths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "", 8)
+	pdf.SetTextColor(0, 0, 0)
+	if len(tenders) == 0 {
+		pdf.SetFillColor(255, 255, 255)
+		pdf.CellFormat(colWidths[0]+colWidths[1]+colWidths[2], 7, "No payments received", "1", 1, "C", true, 0, "")
+		return
+	}
+
+	for idx, tender := range tenders {
+		if idx%2 == 0 {
+			pdf.SetFillColor(245, 245, 245)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+		pdf.CellFormat(colWidths[0], 7, functions.TenderLabel(tender.Type), "1", 0, "L", true, 0, "")
+		pdf.CellFormat(colWidths[1], 7, strconv.Itoa(tender.Sales), "1", 0, "R", true, 0, "")
+		pdf.CellFormat(colWidths[2], 7, "Rs. "+tender.Amount.String(), "1", 0, "R", true, 0, "")
+		pdf.Ln(7)
+	}
+}
+
+// addVoidedSalesTable lists the voided sales of a day with their reason; they are not part of any total
+func addVoidedSalesTable(pdf *gofpdf.Fpdf, voided []dto.VoidedSaleSummary) {
+	colWidths := []float64{45, 25, 30, 80}
+	headers := []string{"Sale", "Sold At", "Total", "Reason"}
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(52, 73, 94)
+	pdf.SetTextColor(255, 255, 255)
+	for i, header := range headers {
+		pdf.CellFormat(colWidths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "", 8)
+	pdf.SetTextColor(0, 0, 0)
+	for idx, sale := range voided {
+		if pdf.GetY() > 260 {
+			pdf.AddPage()
+		}
+		if idx%2 == 0 {
+			pdf.SetFillColor(245, 245, 245)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+
+		saleId := sale.SaleID
+		if len(saleId) > 24 {
+			saleId = saleId[:21] + "..."
+		}
+		reason := sale.Reason
+		if len(reason) > 45 {
+			reason = reason[:42] + "..."
+		}
+		pdf.CellFormat(colWidths[0], 7, saleId, "1", 0, "L", true, 0, "")
+		pdf.CellFormat(colWidths[1], 7, sale.SoldAt.In(config.Location()).Format("15:04"), "1", 0, "C", true, 0, "")
+		pdf.CellFormat(colWidths[2], 7, "Rs. "+sale.Total.String(), "1", 0, "R", true, 0, "")
+		pdf.CellFormat(colWidths[3], 7, reason, "1", 0, "L", true, 0, "")
+		pdf.Ln(7)
+	}
+}
+
+// addProfitOverview writes the revenue, cost of goods sold, gross profit and margin of a report as a one-row table
+func addProfitOverview(pdf *gofpdf.Fpdf, profit *dto.GrossProfitReport) {
+	colWidths := []float64{45, 45, 45, 45}
+	headers := []string{"Revenue (net)", "Cost of Goods Sold", "Gross Profit", "Margin"}
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(52, 73, 94)
+	pdf.SetTextColor(255, 255, 255)
+	for i, header := range headers {
+		pdf.CellFormat(colWidths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFillColor(245, 245, 245)
+	pdf.CellFormat(colWidths[0], 7, "Rs. "+profit.Revenue.String(), "1", 0, "R", true, 0, "")
+	pdf.CellFormat(colWidths[1], 7, "Rs. "+profit.COGS.String(), "1", 0, "R", true, 0, "")
+	pdf.CellFormat(colWidths[2], 7, "Rs. "+profit.GrossProfit.String(), "1", 0, "R", true, 0, "")
+	pdf.CellFormat(colWidths[3], 7, strconv.FormatFloat(profit.MarginPercent, 'f', 2, 64)+"%", "1", 0, "R", true, 0, "")
+	pdf.Ln(7)
+
+	if profit.EstimatedCostLines > 0 {
+		pdf.SetFont("Arial", "I", 8)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(0, 6, fmt.Sprintf("%d line(s) sold before batch costs were recorded are costed at the current cost price", profit.EstimatedCostLines), "", 1, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+}
+
+// addProfitTable writes the gross profit of each product, brand or category as a table, at most limit rows
+func addProfitTable(pdf *gofpdf.Fpdf, label string, groups []dto.ProfitSummary, limit int) {
+	colWidths := []float64{60, 20, 30, 30, 25, 15}
+	headers := []string{label, "Qty", "Revenue", "COGS", "Profit", "Margin"}
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(52, 73, 94)
+	pdf.SetTextColor(255, 255, 255)
+	for i, header := range headers {
+		pdf.CellFormat(colWidths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(8)
+
+	pdf.SetFont("Arial", "", 8)
+	pdf.SetTextColor(0, 0, 0)
+	if len(groups) == 0 {
+		pdf.SetFillColor(255, 255, 255)
+		pdf.CellFormat(180, 7, "No sales", "1", 1, "C", true, 0, "")
+		return
+	}
+
+	for idx, group := range groups {
+		if idx == limit {
+			break
+		}
+		if pdf.GetY() > 260 {
+			pdf.AddPage()
+		}
+		if idx%2 == 0 {
+			pdf.SetFillColor(245, 245, 245)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+
+		name := group.Name
+		if len(name) > 35 {
+			name = name[:32] + "..."
+		}
+		pdf.CellFormat(colWidths[0], 7, name, "1", 0, "L", true, 0, "")
+		pdf.CellFormat(colWidths[1], 7, strconv.Itoa(group.Quantity), "1", 0, "R", true, 0, "")
+		pdf.CellFormat(colWidths[2], 7, "Rs. "+group.Revenue.String(), "1", 0, "R", true, 0, "")
+		pdf.CellFormat(colWidths[3], 7, "Rs. "+group.COGS.String(), "1", 0, "R", true, 0, "")
+		pdf.CellFormat(colWidths[4], 7, "Rs. "+group.GrossProfit.String(), "1", 0, "R", true, 0, "")
+		pdf.CellFormat(colWidths[5], 7, strconv.FormatFloat(group.MarginPercent, 'f', 1, 64)+"%", "1", 0, "R", true, 0, "")
+		pdf.Ln(7)
+	}
+}
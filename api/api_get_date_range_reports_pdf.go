@@ -2,8 +2,9 @@ package api
 
 import (
 	"bytes"
-	"employee-crud/dao"
+	"employee-crud/config"
 	"employee-crud/dto"
+	"employee-crud/functions"
 	"fmt"
 	"strconv"
 	"time"
@@ -13,6 +14,7 @@ import (
 )
 
 // GetDateRangeReportsPDFApi downloads all saved reports from selected date to end of month
+// ?locationId= picks one location's reports instead of those for the whole business
 func GetDateRangeReportsPDFApi(c *fiber.Ctx) error {
 	// Get start date parameter from query (format: YYYY-MM-DD)
 	dateStr := c.Query("startDate")
@@ -30,17 +32,17 @@ func GetDateRangeReportsPDFApi(c *fiber.Ctx) error {
 		})
 	}
 
-	// Convert to Sri Lanka timezone
-	sriLankaLoc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
-	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, sriLankaLoc)
+	// Convert to the business timezone
+	businessLoc := config.Location()
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, businessLoc)
 
 	// Calculate end of month
 	year := startDate.Year()
 	month := startDate.Month()
-	endOfMonth := time.Date(year, month+1, 0, 23, 59, 59, 0, sriLankaLoc) // Last day of the month
+	endOfMonth := time.Date(year, month+1, 0, 23, 59, 59, 0, businessLoc) // Last day of the month
 
 	// Check if the requested date is in the future
-	now := time.Now().In(sriLankaLoc)
+	now := time.Now().In(businessLoc)
 	if startDate.After(now) {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Cannot generate reports for future dates",
@@ -50,11 +52,11 @@ func GetDateRangeReportsPDFApi(c *fiber.Ctx) error {
 	// If end of month is in the future, use yesterday as the end date
 	if endOfMonth.After(now) {
 		endOfMonth = now.AddDate(0, 0, -1) // Yesterday
-		endOfMonth = time.Date(endOfMonth.Year(), endOfMonth.Month(), endOfMonth.Day(), 23, 59, 59, 0, sriLankaLoc)
+		endOfMonth = time.Date(endOfMonth.Year(), endOfMonth.Month(), endOfMonth.Day(), 23, 59, 59, 0, businessLoc)
 	}
 
 	// Fetch all reports for the month
-	reports, err := dao.GetDailyReportsByMonth(year, int(month))
+	reports, err := repos.Reports.GetSavedDailyReportsByMonth(year, int(month), c.Query("locationId"))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve reports: " + err.Error(),
@@ -64,7 +66,7 @@ func GetDateRangeReportsPDFApi(c *fiber.Ctx) error {
 	// Filter reports from startDate to endOfMonth
 	var filteredReports []dto.DailyReportDocument
 	for _, report := range reports {
-		reportDate := report.ReportDate.In(sriLankaLoc)
+		reportDate := report.ReportDate.In(businessLoc)
 		if (reportDate.Equal(startDate) || reportDate.After(startDate)) &&
 			(reportDate.Equal(endOfMonth) || reportDate.Before(endOfMonth)) {
 			filteredReports = append(filteredReports, report)
@@ -137,19 +139,22 @@ func addCoverPage(pdf *gofpdf.Fpdf, reports []dto.DailyReportDocument, startDate
 	pdf.Ln(20)
 
 	// Calculate totals
-	var totalRevenue, totalDiscount, totalTax float64
+	var totalRevenue, totalDiscount, totalTax, totalProfit dto.Money
 	var totalSalesCount int
 	for _, report := range reports {
 		totalRevenue += report.TotalRevenue
 		totalDiscount += report.TotalDiscount
 		totalTax += report.TotalTax
 		totalSalesCount += report.TotalSales
+		if report.Profit != nil {
+			totalProfit += report.Profit.GrossProfit
+		}
 	}
 
 	// Summary box
 	currentY := pdf.GetY()
 	pdf.SetFillColor(240, 248, 255)
-	pdf.Rect(30, currentY, 150, 70, "FD")
+	pdf.Rect(30, currentY, 150, 80, "FD")
 
 	pdf.SetXY(30, currentY+10)
 	pdf.SetFont("Arial", "B", 14)
@@ -172,7 +177,7 @@ func addCoverPage(pdf *gofpdf.Fpdf, reports []dto.DailyReportDocument, startDate
 	pdf.Cell(60, 8, "Total Revenue:")
 	pdf.SetFont("Arial", "B", 12)
 	pdf.SetTextColor(0, 153, 51)
-	pdf.Cell(0, 8, "Rs. "+strconv.FormatFloat(totalRevenue, 'f', 2, 64))
+	pdf.Cell(0, 8, "Rs. "+totalRevenue.String())
 
 	pdf.SetTextColor(0, 0, 0)
 	pdf.SetXY(40, currentY+55)
@@ -180,13 +185,20 @@ func addCoverPage(pdf *gofpdf.Fpdf, reports []dto.DailyReportDocument, startDate
 	pdf.Cell(60, 8, "Total Discount:")
 	pdf.SetFont("Arial", "B", 12)
 	pdf.SetTextColor(204, 0, 0)
-	pdf.Cell(0, 8, "Rs. "+strconv.FormatFloat(totalDiscount, 'f', 2, 64))
+	pdf.Cell(0, 8, "Rs. "+totalDiscount.String())
 
+	// Reports saved before profit reporting add nothing to the gross profit
 	pdf.SetTextColor(0, 0, 0)
+	pdf.SetXY(40, currentY+65)
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(60, 8, "Gross Profit:")
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(0, 8, "Rs. "+totalProfit.String())
+
 	pdf.Ln(50)
 
 	// Daily breakdown table
-	pdf.SetY(currentY + 85)
+	pdf.SetY(currentY + 95)
 	pdf.SetFont("Arial", "B", 14)
 	pdf.CellFormat(0, 10, "Daily Breakdown", "", 1, "L", false, 0, "")
 	pdf.Ln(3)
@@ -196,8 +208,8 @@ func addCoverPage(pdf *gofpdf.Fpdf, reports []dto.DailyReportDocument, startDate
 	pdf.SetFillColor(52, 73, 94)
 	pdf.SetTextColor(255, 255, 255)
 
-	colWidths := []float64{40, 30, 30, 40, 40}
-	headers := []string{"Date", "Sales", "Discount", "Tax", "Revenue"}
+	colWidths := []float64{30, 20, 25, 25, 30, 25, 25}
+	headers := []string{"Date", "Sales", "Discount", "Tax", "Revenue", "COGS", "Profit"}
 
 	for i, header := range headers {
 		pdf.CellFormat(colWidths[i], 8, header, "1", 0, "C", true, 0, "")
@@ -229,12 +241,21 @@ func addCoverPage(pdf *gofpdf.Fpdf, reports []dto.DailyReportDocument, startDate
 			pdf.SetFillColor(255, 255, 255)
 		}
 
+		// Reports saved before profit reporting have no cost side
+		cogs, profit := "-", "-"
+		if report.Profit != nil {
+			cogs = "Rs. " + report.Profit.COGS.String()
+			profit = "Rs. " + report.Profit.GrossProfit.String()
+		}
+
 		rowData := []string{
 			report.ReportDate.Format("Jan 2, 2006"),
 			strconv.Itoa(report.TotalSales),
-			"Rs. " + strconv.FormatFloat(report.TotalDiscount, 'f', 2, 64),
-			"Rs. " + strconv.FormatFloat(report.TotalTax, 'f', 2, 64),
-			"Rs. " + strconv.FormatFloat(report.TotalRevenue, 'f', 2, 64),
+			"Rs. " + report.TotalDiscount.String(),
+			"Rs. " + report.TotalTax.String(),
+			"Rs. " + report.TotalRevenue.String(),
+			cogs,
+			profit,
 		}
 
 		for i, data := range rowData {
@@ -268,7 +289,7 @@ func addDailyReportPage(pdf *gofpdf.Fpdf, report *dto.DailyReportDocument, pageN
 	// Sales overview box
 	currentY := pdf.GetY()
 	pdf.SetFillColor(245, 248, 250)
-	pdf.Rect(15, currentY, 180, 45, "FD")
+	pdf.Rect(15, currentY, 180, 33, "FD")
 	pdf.SetXY(15, currentY+5)
 
 	leftColX := 20.0
@@ -290,42 +311,51 @@ func addDailyReportPage(pdf *gofpdf.Fpdf, report *dto.DailyReportDocument, pageN
 	pdf.Cell(40, 6, "Total Revenue:")
 	pdf.SetFont("Arial", "B", 12)
 	pdf.SetTextColor(0, 153, 51)
-	pdf.Cell(0, 6, "Rs. "+strconv.FormatFloat(report.TotalRevenue, 'f', 2, 64))
+	pdf.Cell(0, 6, "Rs. "+report.TotalRevenue.String())
 
 	// Row 2
 	currentY += rowHeight
 	pdf.SetTextColor(0, 0, 0)
 	pdf.SetXY(leftColX, currentY)
 	pdf.SetFont("Arial", "", 9)
-	pdf.Cell(40, 6, "Cash Sales:")
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(0, 6, strconv.Itoa(report.CashSales)+" (Rs. "+strconv.FormatFloat(report.CashRevenue, 'f', 2, 64)+")")
-
-	pdf.SetXY(rightColX, currentY)
-	pdf.SetFont("Arial", "", 9)
-	pdf.Cell(40, 6, "Card Sales:")
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(0, 6, strconv.Itoa(report.CardSales)+" (Rs. "+strconv.FormatFloat(report.CardRevenue, 'f', 2, 64)+")")
-
-	// Row 3
-	currentY += rowHeight
-	pdf.SetXY(leftColX, currentY)
-	pdf.SetFont("Arial", "", 9)
 	pdf.Cell(40, 6, "Total Discount:")
 	pdf.SetFont("Arial", "B", 10)
 	pdf.SetTextColor(204, 0, 0)
-	pdf.Cell(0, 6, "Rs. "+strconv.FormatFloat(report.TotalDiscount, 'f', 2, 64))
+	pdf.Cell(0, 6, "Rs. "+report.TotalDiscount.String())
 
 	pdf.SetXY(rightColX, currentY)
 	pdf.SetFont("Arial", "", 9)
 	pdf.SetTextColor(0, 0, 0)
 	pdf.Cell(40, 6, "Total Tax:")
 	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(0, 6, "Rs. "+strconv.FormatFloat(report.TotalTax, 'f', 2, 64))
+	pdf.Cell(0, 6, "Rs. "+report.TotalTax.String())
 
 	pdf.SetTextColor(0, 0, 0)
 	pdf.Ln(18)
 
+	// Revenue per tender, including the cash/card split of reports saved before tenders
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, "Revenue by Tender", "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	addTenderTable(pdf, functions.ReportTenders(report))
+	pdf.Ln(6)
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, "Tax by Class", "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	addTaxTable(pdf, functions.ReportTaxBreakdown(report))
+	pdf.Ln(6)
+
+	if report.Profit != nil {
+		pdf.SetFont("Arial", "B", 12)
+		pdf.CellFormat(0, 8, "Gross Profit", "", 1, "L", false, 0, "")
+		pdf.Ln(2)
+		addProfitOverview(pdf, report.Profit)
+		pdf.Ln(3)
+		addProfitTable(pdf, "Category", report.Profit.ByCategory, len(report.Profit.ByCategory))
+		pdf.Ln(6)
+	}
+
 	// Top selling items
 	if len(report.TopSellingItems) > 0 {
 		pdf.SetFont("Arial", "B", 12)
@@ -372,8 +402,8 @@ func addDailyReportPage(pdf *gofpdf.Fpdf, report *dto.DailyReportDocument, pageN
 				strconv.Itoa(idx + 1),
 				productName,
 				strconv.Itoa(item.Quantity),
-				"Rs. " + strconv.FormatFloat(item.UnitPrice, 'f', 2, 64),
-				"Rs. " + strconv.FormatFloat(item.TotalAmount, 'f', 2, 64),
+				"Rs. " + item.UnitPrice.String(),
+				"Rs. " + item.TotalAmount.String(),
 			}
 
 			for i, data := range rowData {
@@ -390,6 +420,15 @@ func addDailyReportPage(pdf *gofpdf.Fpdf, report *dto.DailyReportDocument, pageN
 		}
 	}
 
+	// Voided sales, not counted in the figures above
+	if len(report.VoidedSales) > 0 {
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "B", 12)
+		pdf.CellFormat(0, 8, "Voided Sales", "", 1, "L", false, 0, "")
+		pdf.Ln(2)
+		addVoidedSalesTable(pdf, report.VoidedSales)
+	}
+
 	// Footer
 	pdf.SetY(-15)
 	pdf.SetFont("Arial", "I", 7)
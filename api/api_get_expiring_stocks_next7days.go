@@ -1,7 +1,7 @@
 package api
 
 import (
-	"employee-crud/dao"
+	"employee-crud/config"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -9,14 +9,14 @@ import (
 
 // Handler to get top 10 stocks expiring within next 7 days, sorted by highest stock quantity
 func GetExpiringStocksNext7Days(c *fiber.Ctx) error {
-	// Use Sri Lanka timezone (UTC+5:30)
-	sriLankaLoc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
-	now := time.Now().In(sriLankaLoc)
+	// Use the configured business timezone
+	businessLoc := config.Location()
+	now := time.Now().In(businessLoc)
 	sevenDaysLater := now.AddDate(0, 0, 7)
 
 	// Use optimized DAO method
 	topN := 10
-	expiringStocks, err := dao.DB_FindTopExpiringStocksNext7Days(topN, now, sevenDaysLater)
+	expiringStocks, err := repos.Stocks.FindTopExpiring(topN, now, sevenDaysLater)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to fetch expiring stocks: " + err.Error(),
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"employee-crud/config"
 	"employee-crud/dao"
 	"fmt"
 	"strconv"
@@ -18,14 +19,14 @@ import (
 
 // Handler to generate PDF report of stocks expiring within 3 months
 func GetExpiringStocksReportPDF(c *fiber.Ctx) error {
-	// Use Sri Lanka timezone (UTC+5:30)
-	sriLankaLoc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
-	now := time.Now().In(sriLankaLoc)
+	// Use the configured business timezone
+	businessLoc := config.Location()
+	now := time.Now().In(businessLoc)
 	threeMonthsLater := now.AddDate(0, 3, 0)
 
 	// Fetch all products with stock (use DAO method with high limit, no cursor)
 	const maxLimit = 10000
-	stocks, _, _, err := dao.DB_FindAllProductsWithStock(maxLimit, "")
+	stocks, _, _, err := repos.Stocks.FindProductsWithStock(maxLimit, "")
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to fetch stock data: " + err.Error(),
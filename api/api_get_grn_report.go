@@ -1,9 +1,7 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/dto"
-	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,7 +15,7 @@ func GetGRNReportApi(c *fiber.Ctx) error {
 		})
 	}
 
-	grn, err := dao.DB_FindGRNById(grnId)
+	grn, err := repos.GRNs.FindById(grnId)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{
 			"error": "GRN not found",
@@ -95,7 +93,7 @@ func generateGRNReport(grn *dto.GRN) fiber.Map {
 		"summary": fiber.Map{
 			"financials": fiber.Map{
 				"totalAmount":          grn.TotalAmount,
-				"formattedTotalAmount": "Rs. " + strconv.FormatFloat(grn.TotalAmount, 'f', 2, 64),
+				"formattedTotalAmount": "Rs. " + grn.TotalAmount.String(),
 				"currency":             "LKR",
 			},
 			"quantities": fiber.Map{
@@ -151,8 +149,8 @@ func enrichItemsForReport(items []dto.GRNItem) []fiber.Map {
 			"costs": fiber.Map{
 				"unitCost":           item.UnitCost,
 				"totalCost":          item.TotalCost,
-				"formattedUnitCost":  "Rs. " + strconv.FormatFloat(item.UnitCost, 'f', 2, 64),
-				"formattedTotalCost": "Rs. " + strconv.FormatFloat(item.TotalCost, 'f', 2, 64),
+				"formattedUnitCost":  "Rs. " + item.UnitCost.String(),
+				"formattedTotalCost": "Rs. " + item.TotalCost.String(),
 			},
 			"additionalInfo": fiber.Map{
 				"expiryDate":      item.ExpiryDate,
@@ -2,7 +2,6 @@ package api
 
 import (
 	"bytes"
-	"employee-crud/dao"
 	"employee-crud/dto"
 	"fmt"
 	"strconv"
@@ -20,7 +19,7 @@ func GetGRNReportPDFApi(c *fiber.Ctx) error {
 		})
 	}
 
-	grn, err := dao.DB_FindGRNById(grnId)
+	grn, err := repos.GRNs.FindById(grnId)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{
 			"error": "GRN not found",
@@ -191,7 +190,7 @@ func generateGRNReportPDF(grn *dto.GRN) ([]byte, error) {
 	pdf.SetFont("Arial", "B", 10)
 	pdf.Cell(40, 6, "Total Amount:")
 	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(0, 6, "Rs. "+strconv.FormatFloat(grn.TotalAmount, 'f', 2, 64))
+	pdf.Cell(0, 6, "Rs. "+grn.TotalAmount.String())
 	pdf.Ln(8)
 
 	pdf.SetX(rightColX)
@@ -254,8 +253,8 @@ func generateGRNReportPDF(grn *dto.GRN) ([]byte, error) {
 			strconv.Itoa(item.ExpectedQty),
 			strconv.Itoa(item.ReceivedQty),
 			status,
-			"Rs. " + strconv.FormatFloat(item.UnitCost, 'f', 2, 64),
-			"Rs. " + strconv.FormatFloat(item.TotalCost, 'f', 2, 64),
+			"Rs. " + item.UnitCost.String(),
+			"Rs. " + item.TotalCost.String(),
 		}
 
 		for i, data := range rowData {
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"employee-crud/config"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxProfitReportDays caps the range of a gross profit report, whose sales are all read at once
+const maxProfitReportDays = 366
+
+// GetGrossProfitReportApi returns revenue, cost of goods sold, gross profit and margin of the sales
+// from startDate to endDate (inclusive, business days) by product, brand, category and day
+// ?startDate=2025-10-01&endDate=2025-10-31; endDate defaults to startDate, and locationId limits it to one location
+func GetGrossProfitReportApi(c *fiber.Ctx) error {
+	businessLoc := config.Location()
+
+	startStr := c.Query("startDate")
+	if startStr == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "startDate parameter is required. Use format YYYY-MM-DD (e.g., 2025-10-01)",
+		})
+	}
+	startDate, err := time.ParseInLocation("2006-01-02", startStr, businessLoc)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid startDate format. Use YYYY-MM-DD (e.g., 2025-10-01)",
+		})
+	}
+
+	endDate := startDate
+	if endStr := c.Query("endDate"); endStr != "" {
+		endDate, err = time.ParseInLocation("2006-01-02", endStr, businessLoc)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid endDate format. Use YYYY-MM-DD (e.g., 2025-10-31)",
+			})
+		}
+	}
+	if endDate.Before(startDate) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "endDate must not be before startDate",
+		})
+	}
+
+	end := endDate.AddDate(0, 0, 1)
+	if end.Sub(startDate) > maxProfitReportDays*24*time.Hour {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "The range cannot be longer than 366 days",
+		})
+	}
+
+	report, err := repos.Reports.GetGrossProfitReport(startDate, end, c.Query("locationId"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to calculate gross profit: " + err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":   "Gross profit report retrieved successfully",
+		"startDate": startDate.Format("2006-01-02"),
+		"endDate":   endDate.Format("2006-01-02"),
+		"data":      report,
+	})
+}
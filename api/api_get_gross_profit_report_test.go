@@ -0,0 +1,48 @@
+package api
+
+import (
+	"employee-crud/config"
+	"employee-crud/dto"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGrossProfitReportUsesBatchCosts(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Get("/GetGrossProfitReport", GetGrossProfitReportApi)
+
+	now := time.Now().UTC()
+	soon := now.AddDate(0, 0, 10)
+	later := now.AddDate(0, 3, 0)
+	seedProduct(t, mem, "PRD-001",
+		dto.Batch{BatchId: "BATCH-SOON", StockQty: 2, ExpiryDate: &soon, CostPrice: dto.MoneyFromFloat(50), SellingPrice: dto.MoneyFromFloat(100)},
+		dto.Batch{BatchId: "BATCH-LATER", StockQty: 10, ExpiryDate: &later, CostPrice: dto.MoneyFromFloat(70), SellingPrice: dto.MoneyFromFloat(100)},
+	)
+
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 3), nil); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+
+	today := time.Now().In(config.Location()).Format("2006-01-02")
+	var body struct {
+		Data dto.GrossProfitReport `json:"data"`
+	}
+	if status := doJSON(t, app, fiber.MethodGet, "/GetGrossProfitReport?startDate="+today, nil, &body); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+
+	// 2 at 50 from BATCH-SOON and 1 at 70 from BATCH-LATER
+	report := body.Data
+	if report.Revenue != dto.MoneyFromFloat(300) || report.COGS != dto.MoneyFromFloat(170) || report.GrossProfit != dto.MoneyFromFloat(130) {
+		t.Fatalf("expected 300 revenue, 170 COGS and 130 profit, got %v, %v and %v", report.Revenue, report.COGS, report.GrossProfit)
+	}
+	if len(report.ByDay) != 1 || report.ByDay[0].Key != today || len(report.ByProduct) != 1 {
+		t.Fatalf("expected one day and one product, got %+v and %+v", report.ByDay, report.ByProduct)
+	}
+
+	if status := doJSON(t, app, fiber.MethodGet, "/GetGrossProfitReport?startDate="+today+"&endDate=2000-01-01", nil, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for an end before the start, got %d", status)
+	}
+}
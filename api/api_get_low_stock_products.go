@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,7 +14,7 @@ func GetLowStockProductsHandler(c *fiber.Ctx) error {
 			limit = parsed
 		}
 	}
-	products, err := dao.DB_GetTopLowStockProducts(limit)
+	products, err := repos.Stocks.FindLowStockProducts(limit)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -2,7 +2,7 @@ package api
 
 import (
 	"bytes"
-	"employee-crud/dao"
+	"employee-crud/config"
 	"employee-crud/dto"
 	"fmt"
 	"strconv"
@@ -17,17 +17,17 @@ func GetMonthlyReturnsReportPDF(c *fiber.Ctx) error {
 	if monthStr == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Missing month parameter (format: YYYY-MM)"})
 	}
-	// Parse month and set Sri Lanka timezone
-	sriLankaLoc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
-	monthTime, err := time.ParseInLocation("2006-01", monthStr, sriLankaLoc)
+	// Parse month and set the business timezone
+	businessLoc := config.Location()
+	monthTime, err := time.ParseInLocation("2006-01", monthStr, businessLoc)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid month format. Use YYYY-MM (e.g., 2026-08)"})
 	}
 	// Calculate start and end of month
-	start := time.Date(monthTime.Year(), monthTime.Month(), 1, 0, 0, 0, 0, sriLankaLoc)
+	start := time.Date(monthTime.Year(), monthTime.Month(), 1, 0, 0, 0, 0, businessLoc)
 	end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
 
-	returns, err := dao.GetReturnsByDateRange(c.Context(), start, end)
+	returns, err := repos.Returns.FindByDateRange(c.Context(), start, end)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch returns: " + err.Error()})
 	}
@@ -110,7 +110,7 @@ func generateMonthlyReturnsPDF(month time.Time, returns []dto.ReturnDTO) ([]byte
 			pdf.CellFormat(colWidths[2], 7, ret.ContactNumber, "1", 0, "L", fillColor, 0, "")
 			pdf.CellFormat(colWidths[3], 7, billNumber, "1", 0, "L", fillColor, 0, "")
 			pdf.CellFormat(colWidths[4], 7, productID, "1", 0, "L", fillColor, 0, "")
-			pdf.CellFormat(colWidths[5], 7, fmt.Sprintf("%.2f", prod.Amount), "1", 0, "R", fillColor, 0, "")
+			pdf.CellFormat(colWidths[5], 7, prod.Amount.String(), "1", 0, "R", fillColor, 0, "")
 			pdf.CellFormat(colWidths[6], 7, reason, "1", 0, "L", fillColor, 0, "")
 			pdf.CellFormat(colWidths[7], 7, notes, "1", 0, "L", fillColor, 0, "")
 			pdf.Ln(7)
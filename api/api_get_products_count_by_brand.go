@@ -1,8 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -16,7 +14,7 @@ func GetProductsCountByBrandApi(c *fiber.Ctx) error {
 		})
 	}
 
-	count, err := dao.DB_GetProductsCountByBrand(brandId)
+	count, err := repos.Products.CountByBrand(brandId)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -1,8 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -16,7 +14,7 @@ func GetProductsCountByCategoryApi(c *fiber.Ctx) error {
 		})
 	}
 
-	count, err := dao.DB_GetProductsCountByCategory(categoryId)
+	count, err := repos.Products.CountByCategory(categoryId)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,7 +13,7 @@ func GetProductsBySupplierApi(c *fiber.Ctx) error {
 		return utils.NewCustomError(c, fiber.StatusBadRequest, "supplierId is required", nil)
 	}
 
-	products, err := dao.DB_FindProductsBySupplierID(supplierId)
+	products, err := repos.Suppliers.FindProductsBySupplier(supplierId)
 	if err != nil {
 		return utils.NewCustomError(c, fiber.StatusInternalServerError, err.Error(), nil)
 	}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPurchaseOrderVarianceApi compares what was ordered with what was received at which cost
+// With poId it reports that purchase order; with supplierId it reports every purchase order of the supplier
+// that has been approved, newest first
+func GetPurchaseOrderVarianceApi(c *fiber.Ctx) error {
+	poId := c.Query("poId")
+	supplierId := c.Query("supplierId")
+
+	var orders []dto.PurchaseOrder
+	switch {
+	case poId != "":
+		po, err := findPurchaseOrder(poId, dto.PODraft, dto.POApproved, dto.POSent, dto.POPartiallyReceived, dto.POClosed)
+		if err != nil {
+			return respondError(c, err, "Failed to retrieve purchase order")
+		}
+		orders = []dto.PurchaseOrder{*po}
+	case supplierId != "":
+		all, err := repos.PurchaseOrders.FindAll(supplierId, "")
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve purchase orders"})
+		}
+		for _, po := range all {
+			if po.Status != dto.PODraft {
+				orders = append(orders, po)
+			}
+		}
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "poId or supplierId is required"})
+	}
+
+	reports := make([]dto.PurchaseOrderVarianceReport, 0, len(orders))
+	for i := range orders {
+		grns, err := repos.GRNs.FindByPurchaseOrder(orders[i].POId)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve GRNs"})
+		}
+		reports = append(reports, functions.PurchaseOrderVariance(&orders[i], grns))
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Purchase order variances retrieved successfully",
+		"data":    reports,
+	})
+}
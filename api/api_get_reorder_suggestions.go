@@ -0,0 +1,273 @@
+package api
+
+import (
+	"bytes"
+	"employee-crud/config"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// GetReorderSuggestionsApi lists the products to reorder grouped by supplier, optionally for one supplierId
+// format=pdf returns the list as a PDF to send or file
+func GetReorderSuggestionsApi(c *fiber.Ctx) error {
+	report, err := buildReorderSuggestions(time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to work out reorder suggestions",
+			"details": err.Error(),
+		})
+	}
+
+	if supplierId := c.Query("supplierId"); supplierId != "" {
+		var groups []dto.ReorderSupplierGroup
+		for _, group := range report.Suppliers {
+			if group.SupplierId == supplierId {
+				groups = append(groups, group)
+			}
+		}
+		report.Suppliers = groups
+	}
+	if report.Suppliers == nil {
+		report.Suppliers = []dto.ReorderSupplierGroup{}
+	}
+
+	switch c.Query("format") {
+	case "", "json":
+		return c.JSON(fiber.Map{
+			"message": "Reorder suggestions retrieved successfully",
+			"data":    report,
+		})
+	case "pdf":
+		pdfBytes, err := generateReorderSuggestionsPDF(report)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to generate PDF: " + err.Error(),
+			})
+		}
+		c.Set("Content-Type", "application/pdf")
+		c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=Reorder-Suggestions-%s.pdf",
+			report.GeneratedAt.Format("2006-01-02")))
+		c.Set("Content-Length", strconv.Itoa(len(pdfBytes)))
+		return c.Send(pdfBytes)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "format must be json or pdf"})
+	}
+}
+
+// CreateReorderPurchaseOrdersApi turns the current suggestions into one draft purchase order per supplier
+// at the products' cost prices; products without a supplier are left out
+// The drafts count as on order, so asking again does not order the same products twice
+func CreateReorderPurchaseOrdersApi(c *fiber.Ctx) error {
+	var req dto.CreateReorderPurchaseOrdersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	report, err := buildReorderSuggestions(time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to work out reorder suggestions",
+			"details": err.Error(),
+		})
+	}
+
+	suppliers := make(map[string]bool, len(req.SupplierIds))
+	for _, supplierId := range req.SupplierIds {
+		suppliers[supplierId] = true
+	}
+	products := make(map[string]bool, len(req.ProductIds))
+	for _, productId := range req.ProductIds {
+		products[productId] = true
+	}
+
+	created := []dto.PurchaseOrder{}
+	for _, group := range report.Suppliers {
+		if group.SupplierId == "" || (len(suppliers) > 0 && !suppliers[group.SupplierId]) {
+			continue
+		}
+		var suggestions []dto.ReorderSuggestion
+		for _, suggestion := range group.Suggestions {
+			if len(products) == 0 || products[suggestion.ProductId] {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+		if len(suggestions) == 0 {
+			continue
+		}
+
+		supplier := &dto.Supplier{SupplierId: group.SupplierId, Name: group.SupplierName}
+		expected := report.GeneratedAt.AddDate(0, 0, group.LeadTimeDays)
+		po, err := createPurchaseOrder(supplier, functions.ReorderPurchaseOrderItems(suggestions), requestUser(c), func(po *dto.PurchaseOrder) {
+			po.ExpectedDate = &expected
+			po.Notes = "Created from reorder suggestions"
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":          "Failed to create purchase order for supplier " + group.SupplierId,
+				"purchaseOrders": created,
+			})
+		}
+		created = append(created, *po)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":        fmt.Sprintf("%d draft purchase orders created", len(created)),
+		"purchaseOrders": created,
+	})
+}
+
+// buildReorderSuggestions gathers products, active suppliers and their assignments, recent saved daily reports
+// and open purchase orders, and works out what to reorder
+func buildReorderSuggestions(now time.Time) (*dto.ReorderSuggestionsReport, error) {
+	cfg := config.Get()
+
+	products, err := repos.Products.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	suppliers, err := repos.Suppliers.FindAll("active")
+	if err != nil {
+		return nil, err
+	}
+	var assignments []dto.SupplierProduct
+	for _, supplier := range suppliers {
+		supplierProducts, err := repos.Suppliers.FindProductsBySupplier(supplier.SupplierId)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, supplierProducts...)
+	}
+
+	var reports []dto.DailyReportDocument
+	if cfg.Reorder.VelocityDays > 0 {
+		start, end := functions.ReorderVelocityWindow(now, cfg.Reorder.VelocityDays, config.Location())
+		if reports, err = repos.Reports.GetSavedDailyReportsBetween(start, end, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	orders, err := repos.PurchaseOrders.FindAll("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	categoryThresholds, err := repos.Stocks.CategoryThresholds()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := functions.ReorderSettings{
+		LeadTimeDays:       cfg.Reorder.LeadTimeDays,
+		SafetyDays:         cfg.Reorder.SafetyDays,
+		CoverDays:          cfg.Reorder.CoverDays,
+		StockThresholds:    config.Get().Stock.Thresholds(),
+		CategoryThresholds: categoryThresholds,
+	}
+	return &dto.ReorderSuggestionsReport{
+		GeneratedAt:  now.In(config.Location()),
+		VelocityDays: cfg.Reorder.VelocityDays,
+		ReportDays:   len(reports),
+		Suppliers:    functions.BuildReorderSuggestions(products, assignments, suppliers, reports, orders, settings),
+	}, nil
+}
+
+// generateReorderSuggestionsPDF lays out one table per supplier, each with its estimated cost
+func generateReorderSuggestionsPDF(report *dto.ReorderSuggestionsReport) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 20)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(0, 15, "Reorder Suggestions", "", 1, "C", false, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(0, 6, "Generated on: "+report.GeneratedAt.Format("2006-01-02 15:04:05"), "", 1, "C", false, 0, "")
+	velocity := "Sales velocity not used: reorder points from the stock thresholds"
+	if report.VelocityDays > 0 {
+		velocity = fmt.Sprintf("Average daily sales over the last %d days (%d with a saved report)", report.VelocityDays, report.ReportDays)
+	}
+	pdf.CellFormat(0, 6, velocity, "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	if len(report.Suppliers) == 0 {
+		pdf.SetFont("Arial", "", 12)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(0, 10, "Nothing needs reordering.", "", 1, "C", false, 0, "")
+	}
+
+	headers := []string{"Product", "Stock", "On Order", "Avg/Day", "Reorder At", "Order Qty", "Unit Cost", "Est. Cost"}
+	widths := []float64{50, 15, 17, 16, 19, 18, 22, 23}
+	for _, group := range report.Suppliers {
+		name := group.SupplierName
+		if group.SupplierId == "" {
+			name = "No supplier assigned"
+		}
+
+		pdf.SetFont("Arial", "B", 13)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(0, 8, name, "", 1, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 9)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Lead time %d days   Estimated cost Rs. %s", group.LeadTimeDays, group.EstimatedCost), "", 1, "L", false, 0, "")
+		pdf.Ln(1)
+
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		pdf.SetTextColor(0, 0, 0)
+		for i, header := range headers {
+			pdf.CellFormat(widths[i], 7, header, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+
+		pdf.SetFont("Arial", "", 9)
+		for _, suggestion := range group.Suggestions {
+			productName := suggestion.ProductName
+			if len(productName) > 30 {
+				productName = productName[:27] + "..."
+			}
+			reorderAt := strconv.Itoa(suggestion.ReorderPoint)
+			if suggestion.ReorderPointSource != dto.ReorderFromSales {
+				reorderAt += "*"
+			}
+			cells := []string{
+				productName,
+				strconv.Itoa(suggestion.StockQty),
+				strconv.Itoa(suggestion.OnOrderQty),
+				strconv.FormatFloat(suggestion.AverageDailySales, 'f', 2, 64),
+				reorderAt,
+				strconv.Itoa(suggestion.SuggestedQty),
+				suggestion.UnitCost.String(),
+				suggestion.EstimatedCost.String(),
+			}
+			for i, cell := range cells {
+				align := "R"
+				if i == 0 {
+					align = "L"
+				}
+				pdf.CellFormat(widths[i], 6, cell, "1", 0, align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+		pdf.Ln(6)
+	}
+
+	pdf.SetFont("Arial", "I", 8)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.MultiCell(0, 4, "* Reorder point set on the product or, for products without sales, the product's low stock threshold.", "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"employee-crud/config"
+	"employee-crud/dto"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestReorderSuggestionsBecomeDraftPurchaseOrders(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Get("/GetReorderSuggestions", GetReorderSuggestionsApi)
+	app.Post("/CreateReorderPurchaseOrders", CreateReorderPurchaseOrdersApi)
+
+	if err := mem.Suppliers.Create(&dto.Supplier{SupplierId: "SUP-001", Name: "Acme Wholesale", Status: "active", LeadTimeDays: 2}); err != nil {
+		t.Fatalf("seed supplier: %v", err)
+	}
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 5})
+	seedProduct(t, mem, "PRD-002", dto.Batch{BatchId: "BATCH-002", StockQty: 50})
+	for _, productId := range []string{"PRD-001", "PRD-002"} {
+		if err := mem.Suppliers.AssignProduct("SUP-001", productId); err != nil {
+			t.Fatalf("assign product: %v", err)
+		}
+	}
+
+	// Three of PRD-001 sold on each of the last two days
+	today := time.Now().In(config.Location())
+	for days := 1; days <= 2; days++ {
+		day := time.Date(today.Year(), today.Month(), today.Day()-days, 0, 0, 0, 0, config.Location())
+		mem.Store.SaveDailyReport(dto.DailyReportDocument{
+			ReportDate:   day,
+			ProductsSold: []dto.ProductSoldSummary{{ProductID: "PRD-001", ProductName: "Product PRD-001", Quantity: 3}},
+		})
+	}
+
+	var suggestions struct {
+		Data dto.ReorderSuggestionsReport `json:"data"`
+	}
+	if status := doJSON(t, app, fiber.MethodGet, "/GetReorderSuggestions", nil, &suggestions); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	report := suggestions.Data
+	if report.ReportDays != 2 || len(report.Suppliers) != 1 || len(report.Suppliers[0].Suggestions) != 1 {
+		t.Fatalf("expected only PRD-001 suggested from two days of sales, got %+v", report)
+	}
+	// 3 a day over 2 days' lead time and 3 safety days, then 14 days of sales on top
+	suggestion := report.Suppliers[0].Suggestions[0]
+	if suggestion.ProductId != "PRD-001" || suggestion.ReorderPoint != 15 || suggestion.SuggestedQty != 52 {
+		t.Fatalf("expected 52 of PRD-001 at a reorder point of 15, got %+v", suggestion)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/GetReorderSuggestions?format=pdf", nil), -1)
+	if err != nil {
+		t.Fatalf("GetReorderSuggestions: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get("Content-Type") != "application/pdf" || !bytes.HasPrefix(body, []byte("%PDF")) {
+		t.Fatalf("expected a PDF, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	var created struct {
+		PurchaseOrders []dto.PurchaseOrder `json:"purchaseOrders"`
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateReorderPurchaseOrders", dto.CreateReorderPurchaseOrdersRequest{}, &created); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if len(created.PurchaseOrders) != 1 {
+		t.Fatalf("expected one draft purchase order, got %+v", created.PurchaseOrders)
+	}
+	po := created.PurchaseOrders[0]
+	if po.Status != dto.PODraft || po.SupplierId != "SUP-001" || len(po.Items) != 1 || po.Items[0].OrderedQty != 52 || po.TotalAmount != dto.MoneyFromFloat(3120) {
+		t.Fatalf("expected a draft for 52 of PRD-001 at cost 60, got %+v", po)
+	}
+
+	// The draft is on order, so nothing is suggested again
+	doJSON(t, app, fiber.MethodGet, "/GetReorderSuggestions", nil, &suggestions)
+	if len(suggestions.Data.Suppliers) != 0 {
+		t.Fatalf("expected no suggestions once the draft is on order, got %+v", suggestions.Data.Suppliers)
+	}
+}
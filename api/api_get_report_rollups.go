@@ -0,0 +1,167 @@
+package api
+
+import (
+	"employee-crud/config"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RefreshReportRollupRequest selects the month whose rollups are rebuilt
+type RefreshReportRollupRequest struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+}
+
+// GetReportRollupApi returns one permanent rollup
+// Monthly: ?period=monthly&year=2025&month=10, yearly: ?period=yearly&year=2025
+func GetReportRollupApi(c *fiber.Ctx) error {
+	period, year, month, err := parseRollupQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	rollup, err := repos.Reports.GetRollup(period, year, month)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "No rollup found for the specified period",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve rollup: " + err.Error(),
+		})
+	}
+
+	return c.JSON(rollup)
+}
+
+// CompareReportRollupsApi returns the rollups of a range of periods with the change between consecutive periods
+// Monthly: ?period=monthly&from=2025-01&to=2025-12, yearly: ?period=yearly&from=2023&to=2025
+func CompareReportRollupsApi(c *fiber.Ctx) error {
+	period, from, to, err := parseRollupRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	rollups, err := repos.Reports.FindRollups(period, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve rollups: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"period":      period,
+		"from":        from,
+		"to":          to,
+		"periodCount": len(rollups),
+		"comparison":  functions.CompareRollups(rollups),
+	})
+}
+
+// RefreshReportRollupApi rebuilds a month's rollup (and its year's rollup) from the saved daily reports
+// The scheduler does this every night; this endpoint is for backfilling and corrections
+func RefreshReportRollupApi(c *fiber.Ctx) error {
+	var req RefreshReportRollupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	if req.Year < 2000 || req.Year > 2100 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid year",
+		})
+	}
+	if req.Month < 1 || req.Month > 12 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid month (must be 1-12)",
+		})
+	}
+
+	rollup, err := repos.Reports.RefreshRollups(req.Year, req.Month)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "No saved daily reports found for the specified month",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to refresh rollup: " + err.Error(),
+		})
+	}
+
+	return c.JSON(rollup)
+}
+
+// parseRollupQuery reads period, year and (for monthly rollups) month from the query string
+func parseRollupQuery(c *fiber.Ctx) (string, int, int, error) {
+	period := c.Query("period", dto.RollupMonthly)
+	if period != dto.RollupMonthly && period != dto.RollupYearly {
+		return "", 0, 0, fmt.Errorf("Invalid period parameter (must be %s or %s)", dto.RollupMonthly, dto.RollupYearly)
+	}
+
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil || year < 2000 || year > 2100 {
+		return "", 0, 0, errors.New("Invalid year parameter (e.g., ?year=2025)")
+	}
+
+	if period == dto.RollupYearly {
+		return period, year, 0, nil
+	}
+
+	month, err := strconv.Atoi(c.Query("month"))
+	if err != nil || month < 1 || month > 12 {
+		return "", 0, 0, errors.New("Invalid month parameter (must be 1-12)")
+	}
+	return period, year, month, nil
+}
+
+// parseRollupRange reads period, from and to from the query string and returns the first and last period starts
+// Monthly ranges use YYYY-MM, yearly ranges use YYYY
+func parseRollupRange(c *fiber.Ctx) (string, time.Time, time.Time, error) {
+	period := c.Query("period", dto.RollupMonthly)
+
+	layout := "2006-01"
+	example := "?period=monthly&from=2025-01&to=2025-12"
+	switch period {
+	case dto.RollupMonthly:
+	case dto.RollupYearly:
+		layout = "2006"
+		example = "?period=yearly&from=2023&to=2025"
+	default:
+		return "", time.Time{}, time.Time{}, fmt.Errorf("Invalid period parameter (must be %s or %s)", dto.RollupMonthly, dto.RollupYearly)
+	}
+
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	if fromStr == "" || toStr == "" {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("from and to parameters are required (e.g., %s)", example)
+	}
+
+	from, err := time.ParseInLocation(layout, fromStr, config.Location())
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("Invalid from parameter (e.g., %s)", example)
+	}
+	to, err := time.ParseInLocation(layout, toStr, config.Location())
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("Invalid to parameter (e.g., %s)", example)
+	}
+	if to.Before(from) {
+		return "", time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+
+	return period, from, to, nil
+}
@@ -0,0 +1,350 @@
+package api
+
+import (
+	"bytes"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// GetReportRollupPDFApi downloads one monthly or yearly rollup as a PDF
+// Takes the same query parameters as GetReportRollupApi
+func GetReportRollupPDFApi(c *fiber.Ctx) error {
+	period, year, month, err := parseRollupQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	rollup, err := repos.Reports.GetRollup(period, year, month)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "No rollup found for the specified period",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve rollup: " + err.Error(),
+		})
+	}
+
+	pdfBytes, err := generateReportRollupPDF(rollup)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate PDF: " + err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=Sales-Rollup-%s.pdf", rollupLabel(rollup, "2006-01")))
+	c.Set("Content-Length", strconv.Itoa(len(pdfBytes)))
+
+	return c.Send(pdfBytes)
+}
+
+// CompareReportRollupsPDFApi downloads a period-over-period comparison as a PDF
+// Takes the same query parameters as CompareReportRollupsApi
+func CompareReportRollupsPDFApi(c *fiber.Ctx) error {
+	period, from, to, err := parseRollupRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	rollups, err := repos.Reports.FindRollups(period, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve rollups: " + err.Error(),
+		})
+	}
+	if len(rollups) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "No rollups found for the specified range",
+		})
+	}
+
+	pdfBytes, err := generateRollupComparisonPDF(period, functions.CompareRollups(rollups))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate PDF: " + err.Error(),
+		})
+	}
+
+	layout := "2006-01"
+	if period == dto.RollupYearly {
+		layout = "2006"
+	}
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=Sales-Comparison-%s-to-%s.pdf",
+		from.Format(layout), to.Format(layout)))
+	c.Set("Content-Length", strconv.Itoa(len(pdfBytes)))
+
+	return c.Send(pdfBytes)
+}
+
+// rollupLabel names the rollup's period, e.g. "October 2025" with layout "January 2006", or "2025" for a yearly rollup
+func rollupLabel(rollup *dto.ReportRollup, layout string) string {
+	if rollup.Period == dto.RollupYearly {
+		return strconv.Itoa(rollup.Year)
+	}
+	return time.Date(rollup.Year, time.Month(rollup.Month), 1, 0, 0, 0, 0, time.UTC).Format(layout)
+}
+
+func formatRupees(amount dto.Money) string {
+	return "Rs. " + amount.String()
+}
+
+func generateReportRollupPDF(rollup *dto.ReportRollup) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	// Title
+	title := "Monthly Sales Summary"
+	coverage := fmt.Sprintf("Based on %d daily reports", rollup.DaysCovered)
+	if rollup.Period == dto.RollupYearly {
+		title = "Yearly Sales Summary"
+		coverage = fmt.Sprintf("Based on %d monthly summaries", rollup.MonthsCovered)
+	}
+	pdf.SetFont("Arial", "B", 22)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(0, 12, title, "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 14)
+	pdf.SetTextColor(60, 60, 60)
+	pdf.CellFormat(0, 8, rollupLabel(rollup, "January 2006"), "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(0, 5, coverage, "", 1, "C", false, 0, "")
+	pdf.Ln(8)
+
+	// Overview box
+	currentY := pdf.GetY()
+	pdf.SetFillColor(245, 248, 250)
+	pdf.Rect(15, currentY, 180, 33, "FD")
+
+	leftColX := 20.0
+	rightColX := 105.0
+	rowHeight := 12.0
+	currentY += 5
+
+	rows := [][2][2]string{
+		{{"Total Sales:", strconv.Itoa(rollup.TotalSales)}, {"Total Revenue:", formatRupees(rollup.TotalRevenue)}},
+		{{"Total Discount:", formatRupees(rollup.TotalDiscount)}, {"Total Tax:", formatRupees(rollup.TotalTax)}},
+	}
+	pdf.SetTextColor(0, 0, 0)
+	for _, row := range rows {
+		for col, x := range []float64{leftColX, rightColX} {
+			pdf.SetXY(x, currentY)
+			pdf.SetFont("Arial", "", 9)
+			pdf.Cell(40, 6, row[col][0])
+			pdf.SetFont("Arial", "B", 10)
+			pdf.Cell(0, 6, row[col][1])
+		}
+		currentY += rowHeight
+	}
+	pdf.SetY(currentY + 5)
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, "Revenue by Tender", "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	addTenderTable(pdf, rollup.Tenders)
+	pdf.Ln(6)
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, "Tax by Class", "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	addTaxTable(pdf, rollup.TaxBreakdown)
+	pdf.Ln(6)
+
+	// Products sold, best sellers first
+	if len(rollup.TopSellingItems) > 0 {
+		pdf.SetFont("Arial", "B", 12)
+		pdf.CellFormat(0, 8, "Top Selling Items", "", 1, "L", false, 0, "")
+		pdf.Ln(2)
+
+		colWidths := []float64{15, 75, 25, 30, 35}
+		headers := []string{"Rank", "Product", "Qty", "Avg Price", "Total"}
+
+		pdf.SetFont("Arial", "B", 8)
+		pdf.SetFillColor(52, 73, 94)
+		pdf.SetTextColor(255, 255, 255)
+		for i, header := range headers {
+			pdf.CellFormat(colWidths[i], 7, header, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(7)
+
+		pdf.SetFont("Arial", "", 7)
+		pdf.SetTextColor(0, 0, 0)
+		for idx, item := range rollup.TopSellingItems {
+			if idx%2 == 0 {
+				pdf.SetFillColor(245, 245, 245)
+			} else {
+				pdf.SetFillColor(255, 255, 255)
+			}
+
+			productName := item.ProductName
+			if len(productName) > 45 {
+				productName = productName[:42] + "..."
+			}
+
+			rowData := []string{
+				strconv.Itoa(idx + 1),
+				productName,
+				strconv.Itoa(item.Quantity),
+				formatRupees(item.UnitPrice),
+				formatRupees(item.TotalAmount),
+			}
+			for i, data := range rowData {
+				align := "L"
+				if i == 0 {
+					align = "C"
+				}
+				if i > 1 {
+					align = "R"
+				}
+				pdf.CellFormat(colWidths[i], 6, data, "1", 0, align, true, 0, "")
+			}
+			pdf.Ln(6)
+		}
+	}
+
+	addRollupFooter(pdf)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func generateRollupComparisonPDF(period string, comparison []dto.RollupComparison) ([]byte, error) {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	title := "Monthly Sales Comparison"
+	labelLayout := "Jan 2006"
+	if period == dto.RollupYearly {
+		title = "Yearly Sales Comparison"
+	}
+	first := &comparison[0].Rollup
+	last := &comparison[len(comparison)-1].Rollup
+
+	pdf.SetFont("Arial", "B", 22)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(0, 12, title, "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 12)
+	pdf.SetTextColor(60, 60, 60)
+	pdf.CellFormat(0, 8, fmt.Sprintf("%s to %s", rollupLabel(first, labelLayout), rollupLabel(last, labelLayout)), "", 1, "C", false, 0, "")
+	pdf.Ln(8)
+
+	colWidths := []float64{32, 22, 32, 32, 32, 32, 32, 38, 24}
+	headers := []string{"Period", "Sales", "Cash", "Other Tenders", "Discount", "Tax", "Revenue", "Change", "Change %"}
+	writeHeader := func() {
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetFillColor(52, 73, 94)
+		pdf.SetTextColor(255, 255, 255)
+		for i, header := range headers {
+			pdf.CellFormat(colWidths[i], 8, header, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(8)
+		pdf.SetFont("Arial", "", 8)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	writeHeader()
+
+	var totalSales int
+	var totalRevenue, totalDiscount, totalTax dto.Money
+	for idx, entry := range comparison {
+		if pdf.GetY() > 180 {
+			pdf.AddPage()
+			writeHeader()
+		}
+		if idx%2 == 0 {
+			pdf.SetFillColor(250, 250, 250)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+
+		rollup := entry.Rollup
+		totalSales += rollup.TotalSales
+		totalRevenue += rollup.TotalRevenue
+		totalDiscount += rollup.TotalDiscount
+		totalTax += rollup.TotalTax
+
+		change, changePct := "-", "-"
+		if entry.RevenueChange != nil {
+			change = formatRupees(*entry.RevenueChange)
+		}
+		if entry.RevenueChangePct != nil {
+			changePct = strconv.FormatFloat(*entry.RevenueChangePct, 'f', 1, 64) + "%"
+		}
+
+		var cash dto.Money
+		for _, tender := range rollup.Tenders {
+			if tender.Type == dto.TenderCash {
+				cash = tender.Amount
+			}
+		}
+
+		rowData := []string{
+			rollupLabel(&rollup, labelLayout),
+			strconv.Itoa(rollup.TotalSales),
+			formatRupees(cash),
+			formatRupees(rollup.TotalRevenue - cash),
+			formatRupees(rollup.TotalDiscount),
+			formatRupees(rollup.TotalTax),
+			formatRupees(rollup.TotalRevenue),
+			change,
+			changePct,
+		}
+		for i, data := range rowData {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			pdf.CellFormat(colWidths[i], 7, data, "1", 0, align, true, 0, "")
+		}
+		pdf.Ln(7)
+	}
+
+	// Totals row
+	pdf.SetFont("Arial", "B", 8)
+	pdf.SetFillColor(240, 248, 255)
+	totals := []string{"Total", strconv.Itoa(totalSales), "", "", formatRupees(totalDiscount), formatRupees(totalTax), formatRupees(totalRevenue), "", ""}
+	for i, data := range totals {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(colWidths[i], 7, data, "1", 0, align, true, 0, "")
+	}
+	pdf.Ln(7)
+
+	addRollupFooter(pdf)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func addRollupFooter(pdf *gofpdf.Fpdf) {
+	pdf.SetY(-20)
+	pdf.SetFont("Arial", "I", 8)
+	pdf.SetTextColor(120, 120, 120)
+	pdf.CellFormat(0, 5, "System Generated Report", "", 1, "C", false, 0, "")
+	pdf.CellFormat(0, 5, fmt.Sprintf("Generated on: %s", time.Now().Format("2006-01-02 15:04:05")), "", 1, "C", false, 0, "")
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"employee-crud/config"
+	"employee-crud/dto"
+	"employee-crud/repository/memory"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// seedDailyReport stores a saved daily report with a single product line
+func seedDailyReport(mem memory.Repositories, year int, month int, day int, sales int, amount float64) {
+	revenue := dto.MoneyFromFloat(amount)
+	mem.Store.SaveDailyReport(dto.DailyReportDocument{
+		ReportDate:   time.Date(year, time.Month(month), day, 0, 0, 0, 0, config.Location()),
+		Year:         year,
+		Month:        month,
+		TotalSales:   sales,
+		TotalRevenue: revenue,
+		Tenders:      []dto.TenderSummary{{Type: dto.TenderCash, Sales: sales, Amount: revenue}},
+		ProductsSold: []dto.ProductSoldSummary{
+			{ProductID: "PROD-001", ProductName: "Product PROD-001", Quantity: sales, UnitPrice: revenue.Div(sales), TotalAmount: revenue},
+		},
+	})
+}
+
+func TestReportRollupsRefreshAndCompare(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Get("/GetReportRollup", GetReportRollupApi)
+	app.Get("/CompareReportRollups", CompareReportRollupsApi)
+	app.Post("/RefreshReportRollup", RefreshReportRollupApi)
+
+	seedDailyReport(mem, 2025, 1, 1, 2, 200)
+	seedDailyReport(mem, 2025, 1, 2, 3, 300)
+	seedDailyReport(mem, 2025, 2, 1, 5, 750)
+
+	for _, month := range []int{1, 2} {
+		req := RefreshReportRollupRequest{Year: 2025, Month: month}
+		if status := doJSON(t, app, http.MethodPost, "/RefreshReportRollup", req, nil); status != http.StatusOK {
+			t.Fatalf("refresh 2025-%02d: status %d, want 200", month, status)
+		}
+	}
+
+	var january dto.ReportRollup
+	if status := doJSON(t, app, http.MethodGet, "/GetReportRollup?period=monthly&year=2025&month=1", nil, &january); status != http.StatusOK {
+		t.Fatalf("get monthly rollup: status %d, want 200", status)
+	}
+	if january.DaysCovered != 2 || january.TotalSales != 5 || january.TotalRevenue != dto.MoneyFromFloat(500) {
+		t.Errorf("january rollup = %d days, %d sales, %s revenue; want 2, 5, 500", january.DaysCovered, january.TotalSales, january.TotalRevenue)
+	}
+
+	var yearly dto.ReportRollup
+	if status := doJSON(t, app, http.MethodGet, "/GetReportRollup?period=yearly&year=2025", nil, &yearly); status != http.StatusOK {
+		t.Fatalf("get yearly rollup: status %d, want 200", status)
+	}
+	if yearly.MonthsCovered != 2 || yearly.TotalRevenue != dto.MoneyFromFloat(1250) {
+		t.Errorf("yearly rollup = %d months, %s revenue; want 2, 1250", yearly.MonthsCovered, yearly.TotalRevenue)
+	}
+
+	var comparison struct {
+		PeriodCount int                    `json:"periodCount"`
+		Comparison  []dto.RollupComparison `json:"comparison"`
+	}
+	if status := doJSON(t, app, http.MethodGet, "/CompareReportRollups?period=monthly&from=2025-01&to=2025-12", nil, &comparison); status != http.StatusOK {
+		t.Fatalf("compare rollups: status %d, want 200", status)
+	}
+	if comparison.PeriodCount != 2 {
+		t.Fatalf("periodCount = %d, want 2", comparison.PeriodCount)
+	}
+	february := comparison.Comparison[1]
+	if february.RevenueChange == nil || *february.RevenueChange != dto.MoneyFromFloat(250) {
+		t.Errorf("february revenueChange = %v, want 250", february.RevenueChange)
+	}
+}
+
+func TestRefreshReportRollupWithoutReports(t *testing.T) {
+	app, _ := newTestApp(t)
+	app.Post("/RefreshReportRollup", RefreshReportRollupApi)
+
+	req := RefreshReportRollupRequest{Year: 2025, Month: 3}
+	if status := doJSON(t, app, http.MethodPost, "/RefreshReportRollup", req, nil); status != http.StatusNotFound {
+		t.Fatalf("status %d, want 404", status)
+	}
+}
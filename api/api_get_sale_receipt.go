@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bytes"
+	"employee-crud/config"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// GetSaleReceiptApi renders the receipt of a sale with the configured store header and footer
+// ?saleId=...&format=text|escpos|pdf&width=58|80; format defaults to text and width to receipt.paperWidthMM
+// The ESC/POS stream is sent to the printer as it is; the PDF is one page the width of the paper roll
+func GetSaleReceiptApi(c *fiber.Ctx) error {
+	saleId := c.Query("saleId")
+	if saleId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Sale ID is required",
+		})
+	}
+
+	format := c.Query("format", dto.ReceiptFormatText)
+	if format != dto.ReceiptFormatText && format != dto.ReceiptFormatESCPOS && format != dto.ReceiptFormatPDF {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "format must be text, escpos or pdf",
+		})
+	}
+
+	receiptConfig := config.Get().Receipt
+	paperWidth := receiptConfig.PaperWidthMM
+	if widthStr := c.Query("width"); widthStr != "" {
+		width, err := strconv.Atoi(widthStr)
+		if err != nil || (width != 58 && width != 80) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "width must be 58 or 80",
+			})
+		}
+		paperWidth = width
+	}
+
+	sale, err := repos.Sales.FindById(saleId)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Sale not found",
+		})
+	}
+
+	receipt := functions.BuildReceipt(sale, config.Location())
+	receipt.StoreName = receiptConfig.StoreName
+	receipt.Header = receiptConfig.Header
+	receipt.Footer = receiptConfig.Footer
+
+	switch format {
+	case dto.ReceiptFormatESCPOS:
+		c.Set("Content-Type", "application/octet-stream")
+		c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=Receipt-%s.bin", sale.SaleID))
+		return c.Send(functions.ReceiptESCPOS(&receipt, paperWidth))
+	case dto.ReceiptFormatPDF:
+		pdfBytes, err := generateReceiptPDF(&receipt, paperWidth)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to generate PDF: " + err.Error(),
+			})
+		}
+		c.Set("Content-Type", "application/pdf")
+		c.Set("Content-Disposition", fmt.Sprintf("inline; filename=Receipt-%s.pdf", sale.SaleID))
+		c.Set("Content-Length", strconv.Itoa(len(pdfBytes)))
+		return c.Send(pdfBytes)
+	}
+
+	c.Set("Content-Type", "text/plain; charset=utf-8")
+	return c.SendString(functions.ReceiptText(&receipt, functions.ReceiptColumns(paperWidth)))
+}
+
+// Receipt PDF layout, in mm
+const (
+	receiptMargin        = 3.0
+	receiptBarcodeHeight = 10.0
+	receiptQuietModules  = 10 // Blank modules either side of the barcode
+)
+
+// generateReceiptPDF renders a receipt on one page the width of the paper roll and as long as the receipt,
+// in Courier so the rows line up as they do on a thermal printer, with a Code 128 barcode of the saleId
+func generateReceiptPDF(receipt *dto.Receipt, paperWidthMM int) ([]byte, error) {
+	columns := functions.ReceiptColumns(paperWidthMM)
+	rows := functions.ReceiptRows(receipt, columns)
+	bars, err := functions.Code128(receipt.SaleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Courier characters are 0.6 of the font size wide; size the font so a row fills the printable width
+	printable := float64(paperWidthMM) - 2*receiptMargin
+	fontSize := printable / float64(columns) / (0.6 * 25.4 / 72)
+	lineHeight := fontSize * 25.4 / 72 * 1.3
+
+	height := 2 * receiptMargin
+	for _, row := range rows {
+		switch {
+		case row.Barcode:
+			height += receiptBarcodeHeight + lineHeight
+		case row.Large:
+			height += 2 * lineHeight
+		default:
+			height += lineHeight
+		}
+	}
+
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "mm",
+		Size:    gofpdf.SizeType{Wd: float64(paperWidthMM), Ht: height},
+	})
+	pdf.SetMargins(receiptMargin, receiptMargin, receiptMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+
+	for _, row := range rows {
+		if row.Barcode {
+			addReceiptBarcode(pdf, bars, printable, lineHeight)
+			continue
+		}
+
+		style := ""
+		if row.Bold {
+			style = "B"
+		}
+		size, rowHeight := fontSize, lineHeight
+		if row.Large {
+			size, rowHeight = 2*fontSize, 2*lineHeight
+		}
+		align := "L"
+		if row.Center {
+			align = "C"
+		}
+		pdf.SetFont("Courier", style, size)
+		pdf.CellFormat(printable, rowHeight, row.Text, "", 1, align, false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// addReceiptBarcode draws Code 128 bars across the printable width, with their quiet zones, below the current row
+func addReceiptBarcode(pdf *gofpdf.Fpdf, bars []int, printable float64, gap float64) {
+	modules := 2 * receiptQuietModules
+	for _, width := range bars {
+		modules += width
+	}
+	moduleWidth := printable / float64(modules)
+
+	x := receiptMargin + receiptQuietModules*moduleWidth
+	y := pdf.GetY() + gap/2
+	pdf.SetFillColor(0, 0, 0)
+	for i, width := range bars {
+		if i%2 == 0 {
+			pdf.Rect(x, y, float64(width)*moduleWidth, receiptBarcodeHeight, "F")
+		}
+		x += float64(width) * moduleWidth
+	}
+	pdf.SetY(y + receiptBarcodeHeight + gap/2)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"bytes"
+	"employee-crud/dto"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetSaleReceiptFormats(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Get("/GetSaleReceipt", GetSaleReceiptApi)
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+
+	var created struct {
+		Sale dto.Sale `json:"sale"`
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 2), &created); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	saleId := created.Sale.SaleID
+
+	get := func(query string) (int, string, []byte) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/GetSaleReceipt?"+query, nil), -1)
+		if err != nil {
+			t.Fatalf("GetSaleReceipt: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, resp.Header.Get("Content-Type"), body
+	}
+
+	status, contentType, body := get("saleId=" + saleId + "&width=58")
+	if status != fiber.StatusOK || !strings.HasPrefix(contentType, "text/plain") {
+		t.Fatalf("expected a text receipt, got %d %s", status, contentType)
+	}
+	if !strings.Contains(string(body), "Product PRD-001") || !strings.Contains(string(body), "Change") {
+		t.Fatalf("expected the sale line and change on the receipt, got:\n%s", body)
+	}
+
+	status, _, body = get("saleId=" + saleId + "&format=escpos")
+	if status != fiber.StatusOK || !bytes.HasPrefix(body, []byte{0x1B, 0x40}) || !bytes.Contains(body, []byte(saleId)) {
+		t.Fatalf("expected an ESC/POS stream carrying the saleId, got %d", status)
+	}
+
+	status, contentType, body = get("saleId=" + saleId + "&format=pdf")
+	if status != fiber.StatusOK || contentType != "application/pdf" || !bytes.HasPrefix(body, []byte("%PDF")) {
+		t.Fatalf("expected a PDF, got %d %s", status, contentType)
+	}
+
+	if status, _, _ := get("saleId=" + saleId + "&width=70"); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for an unsupported width, got %d", status)
+	}
+	if status, _, _ := get("saleId=missing"); status != fiber.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown sale, got %d", status)
+	}
+}
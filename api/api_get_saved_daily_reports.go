@@ -1,14 +1,13 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// GetSavedDailyReportApi retrieves a saved daily report by date
+// GetSavedDailyReportApi retrieves a saved daily report by date, for the whole business or ?locationId=
 func GetSavedDailyReportApi(c *fiber.Ctx) error {
 	// Get date parameter from query (format: YYYY-MM-DD)
 	dateStr := c.Query("date")
@@ -27,7 +26,7 @@ func GetSavedDailyReportApi(c *fiber.Ctx) error {
 	}
 
 	// Get the saved report
-	report, err := dao.GetDailyReportByDate(targetDate)
+	report, err := repos.Reports.GetSavedDailyReport(targetDate, c.Query("locationId"))
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "No saved report found for the specified date",
@@ -37,7 +36,7 @@ func GetSavedDailyReportApi(c *fiber.Ctx) error {
 	return c.JSON(report)
 }
 
-// GetMonthlyReportsApi retrieves all saved daily reports for a specific month
+// GetMonthlyReportsApi retrieves all saved daily reports for a specific month, for the whole business or ?locationId=
 func GetMonthlyReportsApi(c *fiber.Ctx) error {
 	// Get year and month parameters
 	yearStr := c.Query("year")
@@ -64,7 +63,7 @@ func GetMonthlyReportsApi(c *fiber.Ctx) error {
 	}
 
 	// Get the reports
-	reports, err := dao.GetDailyReportsByMonth(year, month)
+	reports, err := repos.Reports.GetSavedDailyReportsByMonth(year, month, c.Query("locationId"))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve monthly reports: " + err.Error(),
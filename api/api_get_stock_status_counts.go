@@ -30,7 +30,7 @@ func GetStockStatusCountsApi(c *fiber.Ctx) error {
 	}
 
 	// Get stock counts by status from database
-	counts, err := dao.DB_GetStockStatusCounts()
+	counts, err := repos.Stocks.StatusCounts()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve stock status counts",
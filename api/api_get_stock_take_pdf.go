@@ -0,0 +1,142 @@
+package api
+
+import (
+	"bytes"
+	"employee-crud/config"
+	"employee-crud/dto"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// GetStockTakePDFApi returns a stock take as a PDF: counting sheet while open, variance report once approved
+func GetStockTakePDFApi(c *fiber.Ctx) error {
+	stockTake, err := findStockTake(c.Query("stockTakeId"), dto.StockTakeOpen, dto.StockTakeApproved, dto.StockTakeCancelled)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve stock take")
+	}
+
+	pdfBytes, err := generateStockTakePDF(stockTake)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate PDF: " + err.Error(),
+		})
+	}
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=Stock-Take-%s.pdf", stockTake.StockTakeId))
+	c.Set("Content-Length", strconv.Itoa(len(pdfBytes)))
+	return c.Send(pdfBytes)
+}
+
+// generateStockTakePDF lists every line with its expected, counted and variance quantities and the variance at cost
+func generateStockTakePDF(stockTake *dto.StockTake) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 20)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(0, 15, "Stock Take "+stockTake.StockTakeId, "", 1, "C", false, 0, "")
+
+	scope := "All products"
+	switch stockTake.Scope {
+	case dto.StockTakeCategory:
+		scope = "Category " + stockTake.ScopeId
+	case dto.StockTakeBrand:
+		scope = "Brand " + stockTake.ScopeId
+	}
+	if stockTake.LocationId != "" {
+		scope += " at " + stockTake.LocationId
+	}
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(0, 6, fmt.Sprintf("%s   Status: %s", scope, stockTake.Status), "", 1, "C", false, 0, "")
+	started := "Started " + stockTake.CreatedAt.In(config.Location()).Format("2006-01-02 15:04")
+	if stockTake.CreatedBy != "" {
+		started += " by " + stockTake.CreatedBy
+	}
+	if stockTake.ApprovedAt != nil {
+		started += "   Approved " + stockTake.ApprovedAt.In(config.Location()).Format("2006-01-02 15:04")
+		if stockTake.ApprovedBy != "" {
+			started += " by " + stockTake.ApprovedBy
+		}
+	}
+	pdf.CellFormat(0, 6, started, "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	headers := []string{"Product", "Batch", "Expiry", "Unit Cost", "Expected", "Counted", "Variance", "Value"}
+	widths := []float64{48, 24, 20, 18, 16, 16, 16, 22}
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.SetTextColor(0, 0, 0)
+	for i, header := range headers {
+		pdf.CellFormat(widths[i], 7, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 8)
+	for _, line := range stockTake.Lines {
+		productName := line.ProductName
+		if len(productName) > 28 {
+			productName = productName[:25] + "..."
+		}
+		expiry := ""
+		if line.ExpiryDate != nil {
+			expiry = line.ExpiryDate.Format("2006-01-02")
+		}
+		counted, variance, value := "", "", ""
+		if line.CountedQty != nil {
+			counted = strconv.Itoa(*line.CountedQty)
+			variance = strconv.Itoa(line.VarianceQty)
+			value = line.VarianceValue.String()
+		}
+		cells := []string{
+			productName,
+			line.BatchId,
+			expiry,
+			line.UnitCost.String(),
+			strconv.Itoa(line.ExpectedQty),
+			counted,
+			variance,
+			value,
+		}
+		for i, cell := range cells {
+			align := "R"
+			if i < 3 {
+				align = "L"
+			}
+			pdf.CellFormat(widths[i], 6, cell, "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(6)
+
+	pdf.SetFont("Arial", "B", 10)
+	summary := [][2]string{
+		{"Lines counted", fmt.Sprintf("%d of %d", stockTake.CountedLines, len(stockTake.Lines))},
+		{"Expected value at cost", "Rs. " + stockTake.ExpectedValue.String()},
+		{"Shortage at cost", "Rs. " + stockTake.ShortageValue.String()},
+		{"Surplus at cost", "Rs. " + stockTake.SurplusValue.String()},
+		{"Net variance at cost", "Rs. " + stockTake.VarianceValue.String()},
+	}
+	for _, row := range summary {
+		pdf.CellFormat(60, 7, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 7, row[1], "", 1, "R", false, 0, "")
+	}
+
+	if stockTake.Status == dto.StockTakeOpen {
+		pdf.Ln(4)
+		pdf.SetFont("Arial", "I", 8)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.MultiCell(0, 4, "Expected quantities were frozen when the stock take started. Nothing is posted until a manager approves it.", "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"employee-crud/config"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetStockThresholdsApi returns the global stock thresholds and those set on categories
+// Query params:
+//   - productId: optional, also returns the thresholds that apply to the product and where they come from
+func GetStockThresholdsApi(c *fiber.Ctx) error {
+	categories, err := repos.Stocks.CategoryThresholds()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve category thresholds"})
+	}
+	global := config.Get().Stock.Thresholds()
+
+	response := fiber.Map{
+		"global":     global,
+		"categories": categories,
+	}
+
+	if productId := c.Query("productId"); productId != "" {
+		product, err := repos.Products.FindById(productId)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve product"})
+		}
+		thresholds, source := functions.ResolveStockThresholds(product, categories, global)
+		response["product"] = fiber.Map{
+			"productId":  product.ProductId,
+			"thresholds": thresholds,
+			"source":     source,
+			"status":     thresholds.Status(product.StockQty),
+		}
+	}
+
+	return c.JSON(response)
+}
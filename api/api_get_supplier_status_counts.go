@@ -2,13 +2,12 @@ package api
 
 import (
 	"context"
-	"employee-crud/dao"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func GetSupplierStatusCounts(c *fiber.Ctx) error {
-	active, inactive, err := dao.DB_GetSupplierStatusCounts(context.Background())
+	active, inactive, err := repos.Suppliers.StatusCounts(context.Background())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
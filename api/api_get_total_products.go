@@ -1,14 +1,12 @@
 package api
 
 import (
-	"employee-crud/dao"
-
 	"github.com/gofiber/fiber/v2"
 )
 
 // GetTotalProducts returns the total number of products in the database
 func GetTotalProducts(c *fiber.Ctx) error {
-	total, err := dao.DB_GetTotalProducts()
+	total, err := repos.Products.CountTotal()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
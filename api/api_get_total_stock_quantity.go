@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 	"time"
 
@@ -26,7 +25,7 @@ func GetTotalStockQuantityApi(c *fiber.Ctx) error {
 	}
 
 	// Calculate total stock quantity from database
-	totalQty, err := dao.DB_CalculateTotalStockQuantity()
+	totalQty, err := repos.Stocks.TotalQuantity()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to calculate total stock quantity",
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LoginApi checks a username and password and issues a signed token
+func LoginApi(c *fiber.Ctx) error {
+	var req dto.LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Username == "" || req.Password == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Username and password are required"})
+	}
+
+	// Unknown users and wrong passwords get the same answer
+	user, err := repos.Users.FindByUsername(req.Username)
+	if err != nil || !user.Active || !functions.CheckPassword(user.PasswordHash, req.Password) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid username or password"})
+	}
+
+	token, expiresAt, err := functions.SignToken(user, authSecret, authTokenTTL, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to issue token"})
+	}
+
+	return c.JSON(dto.LoginResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		User:      *user,
+	})
+}
+
+// GetCurrentUserApi returns the authenticated user's account
+func GetCurrentUserApi(c *fiber.Ctx) error {
+	user, err := repos.Users.FindById(requestUser(c))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+	return c.JSON(user)
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ParkCartApi puts an open cart on hold, e.g. while the customer fetches something, so the terminal can serve others
+// A reserving cart keeps its stock for carts.reservationMinutes from when it is parked
+func ParkCartApi(c *fiber.Ctx) error {
+	var req dto.ParkCartRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	cart, err := findCart(req.CartID, dto.CartOpen)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve cart")
+	}
+	if len(cart.Items) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "An empty cart cannot be parked"})
+	}
+
+	now := time.Now().UTC()
+	cart.Status = dto.CartParked
+	cart.Note = strings.TrimSpace(req.Note)
+	cart.ParkedAt = &now
+	refreshCartReservation(cart, now)
+	cart.UpdatedAt = now
+	return saveCart(c, cart, dto.CartOpen)
+}
+
+// ResumeCartApi reopens a parked cart, at the terminal that parked it or at the one given
+// Lines are not re-checked against stock here; conversion to a sale checks them
+func ResumeCartApi(c *fiber.Ctx) error {
+	var req dto.ResumeCartRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	cart, err := findCart(req.CartID, dto.CartParked)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve cart")
+	}
+
+	now := time.Now().UTC()
+	cart.Status = dto.CartOpen
+	if terminalId := strings.TrimSpace(req.TerminalID); terminalId != "" {
+		cart.TerminalID = terminalId
+	}
+	cart.ParkedAt = nil
+	refreshCartReservation(cart, now)
+	cart.UpdatedAt = now
+	return saveCart(c, cart, dto.CartParked)
+}
+
+// CancelCartApi abandons an open or parked cart and releases its reservation
+func CancelCartApi(c *fiber.Ctx) error {
+	cart, err := findCart(c.Query("cartId"), dto.CartOpen, dto.CartParked)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve cart")
+	}
+
+	from := cart.Status
+	cart.Status = dto.CartCancelled
+	cart.ReservedUntil = nil
+	cart.UpdatedAt = time.Now().UTC()
+	return saveCart(c, cart, from)
+}
@@ -1,7 +1,7 @@
 package api
 
 import (
-	"employee-crud/dao"
+	"employee-crud/dto"
 	"employee-crud/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,12 +12,14 @@ type RemoveStockRequest struct {
 	ProductId        string `json:"productId" validate:"required"`
 	BatchId          string `json:"batchId" validate:"required"`
 	QuantityToRemove int    `json:"quantityToRemove" validate:"required,gt=0"`
+	Reason           string `json:"reason"` // Optional, recorded on the stock movement
 }
 
 // DeleteBatchRequest represents the request to delete a batch
 type DeleteBatchRequest struct {
 	ProductId string `json:"productId" validate:"required"`
 	BatchId   string `json:"batchId" validate:"required"`
+	Reason    string `json:"reason"` // Optional, recorded on the stock movement
 }
 
 // RemoveStockFromBatch reduces stock from a specific batch
@@ -28,13 +30,14 @@ func RemoveStockFromBatch(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 
-	product, err := dao.DB_RemoveStockFromBatch(req.ProductId, req.BatchId, req.QuantityToRemove)
+	product, err := repos.Products.RemoveStockFromBatch(req.ProductId, req.BatchId, req.QuantityToRemove,
+		dto.StockMovementRef{Type: dto.MovementStockRemove, UserId: requestUser(c), Note: req.Reason})
 	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return utils.SendErrorResponse(c, stockUpdateErrorStatus(err), err.Error())
 	}
 
 	// Sync stock to Stocks collection
-	if err := dao.DB_SyncSingleProductStock(product); err != nil {
+	if err := repos.Stocks.SyncProduct(product); err != nil {
 		// Log but don't fail
 	}
 
@@ -52,13 +55,14 @@ func DeleteBatch(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 
-	product, err := dao.DB_DeleteBatch(req.ProductId, req.BatchId)
+	product, err := repos.Products.DeleteBatch(req.ProductId, req.BatchId,
+		dto.StockMovementRef{Type: dto.MovementBatchDelete, UserId: requestUser(c), Note: req.Reason})
 	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return utils.SendErrorResponse(c, stockUpdateErrorStatus(err), err.Error())
 	}
 
 	// Sync stock to Stocks collection
-	if err := dao.DB_SyncSingleProductStock(product); err != nil {
+	if err := repos.Stocks.SyncProduct(product); err != nil {
 		// Log but don't fail
 	}
 
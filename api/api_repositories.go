@@ -0,0 +1,12 @@
+package api
+
+import "employee-crud/repository"
+
+// repos is the data access used by the handlers
+// It defaults to MongoDB; tests swap in the in-memory implementation with SetRepositories
+var repos = repository.NewMongoRepositories()
+
+// SetRepositories replaces the repositories used by the handlers
+func SetRepositories(r repository.Repositories) {
+	repos = r
+}
@@ -0,0 +1,10 @@
+package api
+
+import "github.com/gofiber/fiber/v2"
+
+// requestUser returns the id of the authenticated user performing the request
+// It is recorded on stock movements and other audit fields
+func requestUser(c *fiber.Ctx) string {
+	userId, _ := c.Locals(localUserId).(string)
+	return userId
+}
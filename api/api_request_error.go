@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestError is a failed request carrying the status and JSON body to respond with,
+// so helpers shared by several handlers can decide the response
+type requestError struct {
+	status int
+	body   fiber.Map
+}
+
+func (e *requestError) Error() string {
+	message, _ := e.body["error"].(string)
+	return message
+}
+
+func newRequestError(status int, message string) *requestError {
+	return &requestError{status: status, body: fiber.Map{"error": message}}
+}
+
+// respondError writes a requestError as is and any other error as a 500 with the fallback message
+func respondError(c *fiber.Ctx, err error, fallback string) error {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return c.Status(reqErr.status).JSON(reqErr.body)
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   fallback,
+		"details": err.Error(),
+	})
+}
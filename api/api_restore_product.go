@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,7 +16,7 @@ func RestoreProductApi(c *fiber.Ctx) error {
 		return utils.NewCustomError(c, fiber.StatusBadRequest, "ProductId, CategoryId, BrandId and SubCategoryId are required", nil)
 	}
 
-	if err := dao.DB_RestoreProductByID(productId, categoryId, brandId, subCategoryId); err != nil {
+	if err := repos.Products.Restore(productId, categoryId, brandId, subCategoryId); err != nil {
 		return utils.NewCustomError(c, fiber.StatusNotFound, err.Error(), nil)
 	}
 
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"employee-crud/utils"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetCategoryStockThresholdsApi sets the stock thresholds of a category, used by its products that have none of their own
+// Query params:
+//   - categoryId: required
+//   - lowThreshold, averageThreshold: optional, leave both empty to return the category to the global thresholds
+func SetCategoryStockThresholdsApi(c *fiber.Ctx) error {
+	categoryId := c.Query("categoryId")
+	if categoryId == "" {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "categoryId is required")
+	}
+
+	var thresholds *dto.StockThresholds
+	low, average := c.Query("lowThreshold"), c.Query("averageThreshold")
+	if low != "" || average != "" {
+		lowThreshold, lowErr := strconv.Atoi(low)
+		averageThreshold, averageErr := strconv.Atoi(average)
+		if lowErr != nil || averageErr != nil {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, "lowThreshold and averageThreshold must both be whole numbers")
+		}
+		thresholds = &dto.StockThresholds{LowThreshold: lowThreshold, AverageThreshold: averageThreshold}
+	}
+	if err := functions.ValidateStockThresholds(thresholds); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := repos.Stocks.SetCategoryThresholds(categoryId, thresholds); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, "Category not found")
+		}
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	// Statuses may have moved, so the cached counts are stale
+	utils.MetricsCache.Delete("stock_status_counts")
+
+	return utils.SendSuccessResponse(c)
+}
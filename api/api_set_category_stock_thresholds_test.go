@@ -0,0 +1,85 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestStockStatusesFollowProductAndCategoryThresholds(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Put("/SetCategoryStockThresholds", SetCategoryStockThresholdsApi)
+	app.Get("/FindAllStocksFiltered", FindAllStocksFilteredApi)
+	app.Get("/GetStockStatusCounts", GetStockStatusCountsApi)
+	app.Get("/GetLowStockProducts", GetLowStockProductsHandler)
+	app.Get("/GetStockThresholds", GetStockThresholdsApi)
+
+	now := time.Now().UTC()
+	for _, product := range []dto.Product{
+		{ProductId: "PRD-RICE", Name: "Rice 5kg", CategoryID: "CAT-RICE", StockQty: 40},
+		{ProductId: "PRD-MATCHES", Name: "Matches", CategoryID: "CAT-HOUSEHOLD", StockQty: 20},
+		{ProductId: "PRD-CANDLES", Name: "Candles", CategoryID: "CAT-HOUSEHOLD", StockQty: 20,
+			StockThresholds: &dto.StockThresholds{LowThreshold: 2, AverageThreshold: 5}},
+	} {
+		product.CreatedAt, product.UpdatedAt = now, now
+		if err := mem.Products.Create(&product, dto.StockMovementRef{}); err != nil {
+			t.Fatalf("seed product: %v", err)
+		}
+	}
+
+	if status := doJSON(t, app, fiber.MethodPut, "/SetCategoryStockThresholds?categoryId=CAT-RICE&lowThreshold=50&averageThreshold=50", nil, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected thresholds that do not rise to be rejected, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPut, "/SetCategoryStockThresholds?categoryId=CAT-RICE&lowThreshold=50&averageThreshold=200", nil, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+
+	// 40 bags of rice are low for the rice category, 20 boxes of matches average under the global thresholds
+	// and 20 candles good under the candles' own thresholds
+	var low struct {
+		Data       []dto.Stock `json:"data"`
+		TotalCount int64       `json:"total_count"`
+	}
+	if status := doJSON(t, app, fiber.MethodGet, "/FindAllStocksFiltered?status=low", nil, &low); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if low.TotalCount != 1 || len(low.Data) != 1 || low.Data[0].ProductId != "PRD-RICE" || low.Data[0].Status != dto.StockStatusLow {
+		t.Fatalf("expected only the rice to be low, got %+v", low)
+	}
+
+	var counts struct {
+		LowStock     int64 `json:"low_stock"`
+		AverageStock int64 `json:"average_stock"`
+		GoodStock    int64 `json:"good_stock"`
+	}
+	doJSON(t, app, fiber.MethodGet, "/GetStockStatusCounts", nil, &counts)
+	if counts.LowStock != 1 || counts.AverageStock != 1 || counts.GoodStock != 1 {
+		t.Fatalf("expected one product in each status, got %+v", counts)
+	}
+
+	var lowProducts []dto.Product
+	doJSON(t, app, fiber.MethodGet, "/GetLowStockProducts", nil, &lowProducts)
+	if len(lowProducts) != 1 || lowProducts[0].ProductId != "PRD-RICE" {
+		t.Fatalf("expected the rice as the only low stock product, got %+v", lowProducts)
+	}
+
+	var thresholds struct {
+		Product struct {
+			Thresholds dto.StockThresholds `json:"thresholds"`
+			Source     string              `json:"source"`
+		} `json:"product"`
+	}
+	doJSON(t, app, fiber.MethodGet, "/GetStockThresholds?productId=PRD-CANDLES", nil, &thresholds)
+	if thresholds.Product.Source != dto.StockThresholdsFromProduct || thresholds.Product.Thresholds.LowThreshold != 2 {
+		t.Fatalf("expected the candles' own thresholds, got %+v", thresholds.Product)
+	}
+
+	// Clearing the category's thresholds puts the rice back on the global ones
+	doJSON(t, app, fiber.MethodPut, "/SetCategoryStockThresholds?categoryId=CAT-RICE", nil, nil)
+	doJSON(t, app, fiber.MethodGet, "/FindAllStocksFiltered?status=low", nil, &low)
+	if low.TotalCount != 0 {
+		t.Fatalf("expected nothing low once the rice thresholds are cleared, got %+v", low)
+	}
+}
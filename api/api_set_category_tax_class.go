@@ -0,0 +1,48 @@
+package api
+
+import (
+	"employee-crud/repository"
+	"employee-crud/utils"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetCategoryTaxClassApi assigns a tax class to a category, used by its products that have no class of their own
+// Query params:
+//   - categoryId: required
+//   - taxClassId: optional, leave empty to remove the category's tax class
+func SetCategoryTaxClassApi(c *fiber.Ctx) error {
+	categoryId := c.Query("categoryId")
+	if categoryId == "" {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "categoryId is required")
+	}
+
+	taxClassId := c.Query("taxClassId")
+	if err := checkTaxClass(taxClassId); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := repos.Taxes.SetCategoryClass(categoryId, taxClassId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return utils.SendErrorResponse(c, fiber.StatusNotFound, "Category not found")
+		}
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccessResponse(c)
+}
+
+// checkTaxClass returns an error if taxClassId is set but is not an existing tax class
+func checkTaxClass(taxClassId string) error {
+	if taxClassId == "" {
+		return nil
+	}
+	if _, err := repos.Taxes.FindClassById(taxClassId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errors.New("Tax class not found: " + taxClassId)
+		}
+		return err
+	}
+	return nil
+}
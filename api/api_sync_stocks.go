@@ -1,7 +1,6 @@
 package api
 
 import (
-	"employee-crud/dao"
 	"employee-crud/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -11,7 +10,7 @@ import (
 // This should be called after initial setup or periodically to ensure stocks are up to date
 // For large datasets, this may take some time
 func SyncStocksApi(c *fiber.Ctx) error {
-	err := dao.DB_SyncStocksFromProducts()
+	err := repos.Stocks.SyncAll()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"operation": "Failed",
@@ -25,7 +24,7 @@ func SyncStocksApi(c *fiber.Ctx) error {
 	utils.MetricsCache.Delete("total_stock_quantity")
 
 	// Get the total count of synced stocks
-	count, _ := dao.DB_GetStocksCount()
+	count, _ := repos.Stocks.Count("")
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"operation":    "Success",
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AddCartItemApi adds quantity of a product to an open cart, on its existing line if it has one
+func AddCartItemApi(c *fiber.Ctx) error {
+	var req dto.CartItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Quantity <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "quantity must be positive"})
+	}
+
+	cart, err := findCart(req.CartID, dto.CartOpen)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve cart")
+	}
+	return setCartLine(c, cart, req.ProductID, functions.CartLineQuantity(cart.Items, req.ProductID)+req.Quantity)
+}
+
+// UpdateCartItemApi sets the quantity of a product's line in an open cart; a quantity of 0 removes the line
+func UpdateCartItemApi(c *fiber.Ctx) error {
+	var req dto.CartItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Quantity < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "quantity cannot be negative"})
+	}
+
+	cart, err := findCart(req.CartID, dto.CartOpen)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve cart")
+	}
+	return setCartLine(c, cart, req.ProductID, req.Quantity)
+}
+
+// RemoveCartItemApi removes a product's line from an open cart
+func RemoveCartItemApi(c *fiber.Ctx) error {
+	var req dto.CartItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	cart, err := findCart(req.CartID, dto.CartOpen)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve cart")
+	}
+	return setCartLine(c, cart, req.ProductID, 0)
+}
+
+// setCartLine sets a line's quantity after checking the stock for it, renews the reservation and saves the cart
+func setCartLine(c *fiber.Ctx, cart *dto.Cart, productId string, quantity int) error {
+	if productId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "productId is required"})
+	}
+
+	if quantity == 0 {
+		if functions.CartLineQuantity(cart.Items, productId) == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product is not in the cart: " + productId})
+		}
+		cart.Items = functions.SetCartLine(cart.Items, productId, "", 0)
+	} else {
+		product, err := checkCartStock(cart, productId, quantity)
+		if err != nil {
+			return respondError(c, err, "Failed to check stock")
+		}
+		cart.Items = functions.SetCartLine(cart.Items, product.ProductId, product.Name, quantity)
+	}
+
+	now := time.Now().UTC()
+	refreshCartReservation(cart, now)
+	cart.UpdatedAt = now
+	return saveCart(c, cart, dto.CartOpen)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UpdateCustomerApi changes a customer's name, mobile number and email
+func UpdateCustomerApi(c *fiber.Ctx) error {
+	var req dto.CustomerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.CustomerID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "customerId is required"})
+	}
+
+	name, mobileNumber, err := validateCustomerRequest(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	customer := &dto.Customer{
+		CustomerID:   req.CustomerID,
+		Name:         name,
+		MobileNumber: mobileNumber,
+		Email:        strings.TrimSpace(req.Email),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := repos.Customers.Update(customer); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Customer not found"})
+		case errors.Is(err, repository.ErrMobileTaken):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update customer"})
+	}
+
+	updated, err := repos.Customers.FindById(req.CustomerID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load updated customer"})
+	}
+	return c.JSON(updated)
+}
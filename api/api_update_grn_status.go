@@ -1,8 +1,10 @@
 package api
 
 import (
-	"employee-crud/dao"
+	"employee-crud/functions"
+	"employee-crud/repository"
 	"employee-crud/utils"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -41,7 +43,7 @@ func UpdateGRNStatusApi(c *fiber.Ctx) error {
 	}
 
 	// Check if GRN exists
-	exists, err := dao.DB_CheckGRNExists(req.GRNId)
+	exists, err := repos.GRNs.Exists(req.GRNId)
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Error checking GRN existence")
 	}
@@ -50,15 +52,22 @@ func UpdateGRNStatusApi(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusNotFound, "GRN not found")
 	}
 
-	// Update the status
-	err = dao.DB_UpdateGRNStatus(req.GRNId, req.Status, time.Now().UTC())
+	// Update the status (posts received goods into inventory when completed or partially received)
+	grn, err := repos.GRNs.UpdateStatus(req.GRNId, req.Status, time.Now().UTC(), requestUser(c))
 	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to update GRN status")
+		if errors.Is(err, repository.ErrGRNAlreadyPosted) {
+			return utils.SendErrorResponse(c, fiber.StatusConflict, "GRN has already been posted to inventory and cannot be moved back to pending")
+		}
+		if errors.Is(err, repository.ErrProductVersionConflict) || errors.Is(err, functions.ErrNothingOutstanding) {
+			return utils.SendErrorResponse(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to update GRN status: "+err.Error())
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "GRN status updated successfully",
 		"grnId":   req.GRNId,
 		"status":  req.Status,
+		"items":   grn.Items,
 	})
 }
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func grnRequest(status string, items ...dto.GRNItem) dto.GRN {
+	return dto.GRN{
+		GRNNumber:    "GRN-TEST",
+		SupplierId:   "SUP-001",
+		ReceivedDate: time.Now().UTC(),
+		Items:        items,
+		Status:       status,
+		ReceivedBy:   "USR-TEST",
+		Notes:        "test delivery",
+	}
+}
+
+func TestCompletingGRNPostsReceivedLinesOnce(t *testing.T) {
+	app, mem := newTestApp(t)
+
+	expiry := time.Now().UTC().AddDate(0, 6, 0).Truncate(time.Second)
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-OLD", StockQty: 2, CostPrice: dto.MoneyFromFloat(60), SellingPrice: dto.MoneyFromFloat(100)})
+
+	item := dto.GRNItem{ProductId: "PRD-001", ExpectedQty: 12, ReceivedQty: 10, UnitCost: dto.MoneyFromFloat(55), ExpiryDate: &expiry, BatchNumber: "LOT-7"}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", grnRequest("pending", item), nil); status != fiber.StatusOK {
+		t.Fatalf("create GRN: expected 200, got %d", status)
+	}
+
+	product, _ := mem.Products.FindById("PRD-001")
+	if product.StockQty != 2 {
+		t.Fatalf("a pending GRN must not change stock, got %d", product.StockQty)
+	}
+
+	update := UpdateGRNStatusRequest{GRNId: "GRN-001", Status: "completed"}
+	for attempt := 0; attempt < 2; attempt++ {
+		if status := doJSON(t, app, fiber.MethodPut, "/UpdateGRNStatus", update, nil); status != fiber.StatusOK {
+			t.Fatalf("complete GRN: expected 200, got %d", status)
+		}
+	}
+
+	product, _ = mem.Products.FindById("PRD-001")
+	if product.StockQty != 12 {
+		t.Fatalf("expected the 10 received units posted exactly once, got stock %d", product.StockQty)
+	}
+	if len(product.Batches) != 2 {
+		t.Fatalf("expected a new batch for the received lot, got %+v", product.Batches)
+	}
+	received := product.Batches[1]
+	if received.StockQty != 10 || received.CostPrice != dto.MoneyFromFloat(55) || received.SellingPrice != dto.MoneyFromFloat(100) || received.BatchNumber != "LOT-7" {
+		t.Fatalf("unexpected received batch %+v", received)
+	}
+
+	grn, err := mem.GRNs.FindById("GRN-001")
+	if err != nil {
+		t.Fatalf("find GRN: %v", err)
+	}
+	if grn.Status != "completed" || grn.Items[0].PostedBatchId != received.BatchId || grn.Items[0].PostedQty != 10 {
+		t.Fatalf("expected the line marked as posted into %s, got %+v", received.BatchId, grn.Items[0])
+	}
+}
+
+func TestPostedGRNCannotGoBackToPending(t *testing.T) {
+	app, mem := newTestApp(t)
+
+	seedProduct(t, mem, "PRD-001")
+
+	item := dto.GRNItem{ProductId: "PRD-001", ExpectedQty: 5, ReceivedQty: 5, UnitCost: dto.MoneyFromFloat(40)}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateGRN", grnRequest("completed", item), nil); status != fiber.StatusOK {
+		t.Fatalf("create GRN: expected 200, got %d", status)
+	}
+
+	product, _ := mem.Products.FindById("PRD-001")
+	if product.StockQty != 5 {
+		t.Fatalf("a GRN created as completed must post its lines, got stock %d", product.StockQty)
+	}
+
+	update := UpdateGRNStatusRequest{GRNId: "GRN-001", Status: "pending"}
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateGRNStatus", update, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409, got %d", status)
+	}
+
+	grn, _ := mem.GRNs.FindById("GRN-001")
+	if grn.Status != "completed" {
+		t.Fatalf("status must stay completed, got %s", grn.Status)
+	}
+}
+
+func TestUpdateStatusOfUnknownGRN(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	update := UpdateGRNStatusRequest{GRNId: "GRN-404", Status: "completed"}
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateGRNStatus", update, nil); status != fiber.StatusNotFound {
+		t.Fatalf("expected 404, got %d", status)
+	}
+}
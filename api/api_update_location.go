@@ -0,0 +1,44 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UpdateLocationApi changes a location's name, type, address, phone and status
+// The main location cannot be deactivated, as everything recorded without a location belongs to it
+func UpdateLocationApi(c *fiber.Ctx) error {
+	var location dto.Location
+	if err := c.BodyParser(&location); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if location.LocationId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "locationId is required"})
+	}
+
+	if err := functions.ValidateLocation(&location); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if location.LocationId == dto.MainLocationId && location.Status != "active" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "The main location cannot be deactivated"})
+	}
+	location.UpdatedAt = time.Now().UTC()
+
+	if err := repos.Locations.Update(&location); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Location not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update location"})
+	}
+
+	updated, err := repos.Locations.FindById(location.LocationId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load updated location"})
+	}
+	return c.JSON(updated)
+}
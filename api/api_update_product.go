@@ -2,8 +2,8 @@ package api
 
 import (
 	"context"
-	"employee-crud/dao"
 	"employee-crud/dto"
+	"employee-crud/functions"
 	"employee-crud/utils"
 	"time"
 
@@ -12,12 +12,13 @@ import (
 )
 
 func UpdateProductApi(c *fiber.Ctx) error {
-	inputObj := dto.Product{}
+	req := dto.UpdateProductRequest{}
 
-	if err := c.BodyParser(&inputObj); err != nil {
+	if err := c.BodyParser(&req); err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request payload")
 	}
 
+	inputObj := req.Product
 	if inputObj.ProductId == "" {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "ProductId is required")
 	}
@@ -25,20 +26,44 @@ func UpdateProductApi(c *fiber.Ctx) error {
 	validate := validator.New()
 	if validationErr := validate.StructPartial(inputObj,
 		"Name", "Barcode", "CategoryID", "BrandID", "SubCategoryID",
-		"CostPrice", "SellingPrice", "StockQty", "ExpiryDate", "Deleted"); validationErr != nil {
+		"CostPrice", "SellingPrice", "ExpiryDate", "Deleted"); validationErr != nil {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, validationErr.Error())
 	}
 
+	// The tax class, reorder settings and stock thresholds are only changed when sent
+	settings := req.Settings()
+	if (settings.ReorderPoint != nil && *settings.ReorderPoint < 0) || (settings.ReorderQty != nil && *settings.ReorderQty < 0) {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "reorderPoint and reorderQty cannot be negative")
+	}
+
+	if settings.TaxClassID != nil {
+		if err := checkTaxClass(*settings.TaxClassID); err != nil {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	// Leaving stockThresholds out keeps the product's own; clearStockThresholds returns it to its category's
+	// or the global thresholds
+	if settings.StockThresholds != nil && settings.ClearStockThresholds {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Send either stockThresholds or clearStockThresholds, not both")
+	}
+	if err := functions.ValidateStockThresholds(settings.StockThresholds); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	inputObj.UpdatedAt = time.Now().UTC()
 
-	if err := dao.DB_UpdateProduct(context.Background(), &inputObj); err != nil {
+	if err := repos.Products.Update(context.Background(), &inputObj, settings); err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
 
 	// Automatically sync the product stock to Stocks collection
-	if err := dao.DB_SyncSingleProductStock(&inputObj); err != nil {
-		// Log the error but don't fail the product update
-		// You can add logging here if needed
+	// The request carries no batches, so sync from the saved product
+	if updated, err := repos.Products.FindById(inputObj.ProductId); err == nil {
+		if err := repos.Stocks.SyncProduct(updated); err != nil {
+			// Log the error but don't fail the product update
+			// You can add logging here if needed
+		}
 	}
 
 	return utils.SendSuccessResponse(c)
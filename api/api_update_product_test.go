@@ -0,0 +1,71 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestUpdateProductKeepsSettingsThatAreNotSent(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Put("/UpdateProduct", UpdateProductApi)
+	seedProduct(t, mem, "PRD-001", dto.Batch{BatchId: "BATCH-001", StockQty: 10, SellingPrice: dto.MoneyFromFloat(100)})
+
+	settings := fiber.Map{
+		"productId":       "PRD-001",
+		"name":            "Rice 5kg",
+		"reorderPoint":    6,
+		"reorderQty":      24,
+		"stockThresholds": dto.StockThresholds{LowThreshold: 2, AverageThreshold: 5},
+	}
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateProduct", settings, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200 setting the reorder settings and thresholds, got %d", status)
+	}
+
+	// Renaming the product sends none of its settings, they stay as they were
+	rename := fiber.Map{"productId": "PRD-001", "name": "Rice 10kg"}
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateProduct", rename, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200 renaming, got %d", status)
+	}
+	product, err := mem.Products.FindById("PRD-001")
+	if err != nil {
+		t.Fatalf("find product: %v", err)
+	}
+	if product.Name != "Rice 10kg" || product.ReorderPoint != 6 || product.ReorderQty != 24 {
+		t.Fatalf("expected the rename to keep the reorder settings, got %+v", product)
+	}
+	if product.StockThresholds == nil || product.StockThresholds.LowThreshold != 2 {
+		t.Fatalf("expected the rename to keep the thresholds, got %+v", product.StockThresholds)
+	}
+
+	// Sending a reorder point of 0 is a change: it goes back to being worked out from sales
+	reset := fiber.Map{"productId": "PRD-001", "name": "Rice 10kg", "reorderPoint": 0}
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateProduct", reset, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	product, _ = mem.Products.FindById("PRD-001")
+	if product.ReorderPoint != 0 || product.ReorderQty != 24 {
+		t.Fatalf("expected only the reorder point reset, got %d and %d", product.ReorderPoint, product.ReorderQty)
+	}
+
+	negative := fiber.Map{"productId": "PRD-001", "name": "Rice 10kg", "reorderQty": -1}
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateProduct", negative, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a negative reorder quantity, got %d", status)
+	}
+
+	// Thresholds are only dropped on request
+	both := fiber.Map{"productId": "PRD-001", "name": "Rice 10kg", "clearStockThresholds": true,
+		"stockThresholds": dto.StockThresholds{LowThreshold: 2, AverageThreshold: 5}}
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateProduct", both, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 setting and clearing the thresholds at once, got %d", status)
+	}
+	drop := fiber.Map{"productId": "PRD-001", "name": "Rice 10kg", "clearStockThresholds": true}
+	if status := doJSON(t, app, fiber.MethodPut, "/UpdateProduct", drop, nil); status != fiber.StatusOK {
+		t.Fatalf("expected 200 clearing the thresholds, got %d", status)
+	}
+	product, _ = mem.Products.FindById("PRD-001")
+	if product.StockThresholds != nil || product.ReorderQty != 24 {
+		t.Fatalf("expected only the thresholds cleared, got %+v and %d", product.StockThresholds, product.ReorderQty)
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UpdatePromotionApi replaces a promotion's rule and conditions
+// Leave active out to keep the promotion switched on or off as it is
+func UpdatePromotionApi(c *fiber.Ctx) error {
+	var req dto.PromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.PromotionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "promotionId is required"})
+	}
+
+	existing, err := repos.Promotions.FindById(req.PromotionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Promotion not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve promotion"})
+	}
+
+	promotion := req.Promotion
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	promotion.Active = existing.Active
+	if req.Active != nil {
+		promotion.Active = *req.Active
+	}
+	promotion.Deleted = false
+	if err := functions.ValidatePromotion(&promotion); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	promotion.CreatedAt = existing.CreatedAt
+	promotion.UpdatedAt = time.Now().UTC()
+
+	if err := repos.Promotions.Update(&promotion); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Promotion not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update promotion"})
+	}
+
+	return c.JSON(promotion)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UpdatePurchaseOrderStatusApi approves a draft, marks an approved purchase order as sent to the supplier,
+// or closes a purchase order short so no more goods are received against it
+func UpdatePurchaseOrderStatusApi(c *fiber.Ctx) error {
+	var req dto.UpdatePurchaseOrderStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Status != dto.POApproved && req.Status != dto.POSent && req.Status != dto.POClosed {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid status. Must be one of: approved, sent, closed"})
+	}
+
+	po, err := findPurchaseOrder(req.POId, dto.PODraft, dto.POApproved, dto.POSent, dto.POPartiallyReceived, dto.POClosed)
+	if err != nil {
+		return respondError(c, err, "Failed to retrieve purchase order")
+	}
+	if !functions.PurchaseOrderStatusChangeAllowed(po.Status, req.Status) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "A " + po.Status + " purchase order cannot be " + req.Status})
+	}
+
+	now := time.Now().UTC()
+	from := po.Status
+	po.Status = req.Status
+	switch req.Status {
+	case dto.POApproved:
+		po.ApprovedBy = requestUser(c)
+		po.ApprovedAt = &now
+	case dto.POSent:
+		po.SentAt = &now
+	case dto.POClosed:
+		po.ClosedAt = &now
+	}
+	po.UpdatedAt = now
+
+	if err := repos.PurchaseOrders.UpdateStatus(po, from); err != nil {
+		return respondPurchaseOrderUpdateError(c, err)
+	}
+	return c.JSON(po)
+}
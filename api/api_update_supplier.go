@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"employee-crud/dao"
 	"employee-crud/dto"
 	"employee-crud/utils"
 	"time"
@@ -28,9 +27,13 @@ func UpdateSupplierApi(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, validationErr.Error())
 	}
 
+	if inputObj.LeadTimeDays < 0 {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "leadTimeDays cannot be negative")
+	}
+
 	inputObj.UpdatedAt = time.Now().UTC()
 
-	if err := dao.DB_UpdateSupplier(context.Background(), &inputObj); err != nil {
+	if err := repos.Suppliers.Update(context.Background(), &inputObj); err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"employee-crud/dao"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -23,7 +22,7 @@ func UpdateSupplierStatus(c *fiber.Ctx) error {
 	if req.SupplierId == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "SupplierId is required"})
 	}
-	if err := dao.DB_UpdateSupplierStatus(context.Background(), req.SupplierId, req.Status); err != nil {
+	if err := repos.Suppliers.UpdateStatus(context.Background(), req.SupplierId, req.Status); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Supplier status updated successfully"})
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UpdateTaxClassApi changes a tax class's name, rate and inclusive flag
+// Sales already made keep the rate they were taxed at
+func UpdateTaxClassApi(c *fiber.Ctx) error {
+	var class dto.TaxClass
+	if err := c.BodyParser(&class); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if class.TaxClassID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "taxClassId is required"})
+	}
+
+	class.Name = strings.TrimSpace(class.Name)
+	if err := functions.ValidateTaxClass(&class); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	class.UpdatedAt = time.Now().UTC()
+
+	if err := repos.Taxes.UpdateClass(&class); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Tax class not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update tax class"})
+	}
+
+	updated, err := repos.Taxes.FindClassById(class.TaxClassID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load updated tax class"})
+	}
+	return c.JSON(updated)
+}
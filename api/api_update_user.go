@@ -0,0 +1,85 @@
+package api
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UpdateUserApi lets an admin change a user's name, role, password or active flag
+func UpdateUserApi(c *fiber.Ctx) error {
+	var req dto.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.UserId == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "UserId is required"})
+	}
+
+	changes := dto.UserChanges{FullName: req.FullName, Role: req.Role, Active: req.Active}
+	if req.Role != nil && !functions.IsValidRole(*req.Role) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "role must be one of cashier, stock_keeper, manager or admin"})
+	}
+	if req.Password != nil {
+		hash, err := functions.HashPassword(*req.Password)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		changes.PasswordHash = &hash
+	}
+	if changes == (dto.UserChanges{}) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Nothing to update"})
+	}
+
+	// An admin locking themselves out would leave nobody able to manage users
+	if req.UserId == requestUser(c) && ((req.Active != nil && !*req.Active) || (req.Role != nil && *req.Role != dto.RoleAdmin)) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "You cannot deactivate or demote your own account"})
+	}
+
+	if err := repos.Users.Update(req.UserId, changes); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user"})
+	}
+
+	return c.JSON(fiber.Map{"message": "User updated successfully"})
+}
+
+// ChangePasswordApi lets the authenticated user change their own password
+// Tokens issued before the change stop working; the response carries a new one
+func ChangePasswordApi(c *fiber.Ctx) error {
+	var req dto.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	user, err := repos.Users.FindById(requestUser(c))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+	if !functions.CheckPassword(user.PasswordHash, req.CurrentPassword) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Current password is incorrect"})
+	}
+
+	hash, err := functions.HashPassword(req.NewPassword)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := repos.Users.Update(user.UserId, dto.UserChanges{PasswordHash: &hash}); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to change password"})
+	}
+
+	// The change revoked every token of the user, including this one, so hand out a new one
+	user.TokenVersion++
+	token, expiresAt, err := functions.SignToken(user, authSecret, authTokenTTL, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to issue token"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Password changed successfully", "token": token, "expiresAt": expiresAt})
+}
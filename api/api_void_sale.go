@@ -0,0 +1,81 @@
+package api
+
+import (
+	"employee-crud/config"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// VoidSaleApi undoes a sale: it is kept but marked voided with the reason, every unit goes back to the batch
+// it was taken from and the customer's loyalty booking is reversed
+// Voided sales are left out of the daily sales summary and listed apart in the daily report
+func VoidSaleApi(c *fiber.Ctx) error {
+	var req dto.VoidSaleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.SaleID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "saleId is required"})
+	}
+	if req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "reason is required"})
+	}
+
+	sale, err := repos.Sales.Void(req.SaleID, req.Reason, requestUser(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, functions.ErrInvalidVoid):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, repository.ErrProductVersionConflict):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":   "Stock was updated by another request, please retry",
+				"details": err.Error(),
+			})
+		case errors.Is(err, repository.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "Sale not found, or one of its products no longer exists",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to void sale",
+			"details": err.Error(),
+		})
+	}
+
+	resaveDailyReport(sale.CreatedAt, "")
+	resaveDailyReport(sale.CreatedAt, functions.SaleLocation(sale))
+
+	return c.JSON(fiber.Map{
+		"message": "Sale voided and stock restored",
+		"sale":    sale,
+	})
+}
+
+// resaveDailyReport saves again the daily report of a day and location (empty for the whole business) that
+// already has one, and its rollups, so a change to the day's sales after the report was saved reaches the saved reports
+func resaveDailyReport(soldAt time.Time, locationId string) {
+	day := soldAt.In(config.Location())
+	if _, err := repos.Reports.GetSavedDailyReport(day, locationId); err != nil {
+		return
+	}
+
+	summary, err := repos.Reports.GetDailySalesSummary(day, locationId)
+	if err == nil {
+		err = repos.Reports.SaveDailyReport(summary)
+	}
+	if err == nil {
+		_, err = repos.Reports.RefreshRollups(day.Year(), int(day.Month()))
+	}
+	if err != nil {
+		log.Printf("Failed to update the saved report for %s: %v", day.Format("2006-01-02"), err)
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"employee-crud/config"
+	"employee-crud/dto"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestVoidSaleRestoresBatchesAndReports(t *testing.T) {
+	app, mem := newTestApp(t)
+	app.Put("/VoidSale", VoidSaleApi)
+
+	now := time.Now().UTC()
+	soon := now.AddDate(0, 0, 10)
+	later := now.AddDate(0, 3, 0)
+	seedProduct(t, mem, "PRD-001",
+		dto.Batch{BatchId: "BATCH-SOON", StockQty: 2, ExpiryDate: &soon, SellingPrice: dto.MoneyFromFloat(100)},
+		dto.Batch{BatchId: "BATCH-LATER", StockQty: 10, ExpiryDate: &later, SellingPrice: dto.MoneyFromFloat(100)},
+	)
+
+	var created struct {
+		Sale dto.Sale `json:"sale"`
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/CreateSale", saleRequest("PRD-001", 5), &created); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	saleId := created.Sale.SaleID
+
+	// The day's report was already saved when the sale is voided
+	summary, _ := mem.Reports.GetDailySalesSummary(created.Sale.CreatedAt.In(config.Location()), "")
+	if err := mem.Reports.SaveDailyReport(summary); err != nil {
+		t.Fatalf("save report: %v", err)
+	}
+
+	if status := doJSON(t, app, fiber.MethodPut, "/VoidSale", dto.VoidSaleRequest{SaleID: saleId}, nil); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 without a reason, got %d", status)
+	}
+
+	var voided struct {
+		Sale dto.Sale `json:"sale"`
+	}
+	if status := doJSON(t, app, fiber.MethodPut, "/VoidSale", dto.VoidSaleRequest{SaleID: saleId, Reason: "Wrong items rung up"}, &voided); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if !voided.Sale.Voided || voided.Sale.VoidReason != "Wrong items rung up" || voided.Sale.VoidedBy != "USR-TEST" {
+		t.Fatalf("expected the sale voided with its reason, got %+v", voided.Sale)
+	}
+
+	// BATCH-SOON was emptied by the sale and comes back under its own id
+	product, err := mem.Products.FindById("PRD-001")
+	if err != nil {
+		t.Fatalf("find product: %v", err)
+	}
+	if product.StockQty != 12 {
+		t.Fatalf("expected all 12 units back, got %d", product.StockQty)
+	}
+	for _, batch := range product.Batches {
+		want := map[string]int{"BATCH-SOON": 2, "BATCH-LATER": 10}[batch.BatchId]
+		if batch.StockQty != want {
+			t.Fatalf("%s: expected %d units, got %d", batch.BatchId, want, batch.StockQty)
+		}
+	}
+
+	if status := doJSON(t, app, fiber.MethodPut, "/VoidSale", dto.VoidSaleRequest{SaleID: saleId, Reason: "Again"}, nil); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 voiding twice, got %d", status)
+	}
+
+	report, err := mem.Reports.GetSavedDailyReport(created.Sale.CreatedAt.In(config.Location()), "")
+	if err != nil {
+		t.Fatalf("find report: %v", err)
+	}
+	if report.TotalSales != 0 || report.TotalRevenue != 0 || len(report.VoidedSales) != 1 || report.VoidedSales[0].SaleID != saleId {
+		t.Fatalf("expected the saved report to list the sale as voided only, got %+v", report)
+	}
+}
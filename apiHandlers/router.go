@@ -2,18 +2,12 @@ package apiHandlers
 
 import (
 	"employee-crud/api"
+	"employee-crud/dto"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func SetupRoutes(app *fiber.App) {
-	// Returns Monthly PDF Report
-	app.Get("/GetMonthlyReturnsPDF", api.GetMonthlyReturnsReportPDF)
-	// Expiring Stocks Report Route
-	app.Get("/GetExpiringStocksReportPDF", api.GetExpiringStocksReportPDF)
-	// Top 10 Expiring Stocks in 7 Days (JSON)
-	app.Get("/GetExpiringStocksNext7Days", api.GetExpiringStocksNext7Days)
-
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.SendString("Hello from Fiber on Render!")
 	})
@@ -22,113 +16,216 @@ func SetupRoutes(app *fiber.App) {
 		return c.SendStatus(204)
 	})
 
-	app.Post("/CreateCategory", api.CreateCategoryApi)
-	app.Get("/FindAllCategory", api.FindAllCategoriesApi)
-	app.Delete("/DeleteCategory", api.DeleteCategoryApi)
+	// Public: exchange username and password for a bearer token
+	app.Post("/Login", api.LoginApi)
+
+	// Every route registered below requires a valid token
+	app.Use(api.RequireAuth)
+
+	// Role groups (admins may access every route)
+	anyRole := api.RequireRoles(dto.RoleCashier, dto.RoleStockKeeper, dto.RoleManager)
+	sales := api.RequireRoles(dto.RoleCashier, dto.RoleManager)
+	stock := api.RequireRoles(dto.RoleStockKeeper, dto.RoleManager)
+	managers := api.RequireRoles(dto.RoleManager)
+	admins := api.RequireRoles(dto.RoleAdmin)
+
+	// User Management Routes
+	app.Get("/GetCurrentUser", anyRole, api.GetCurrentUserApi)
+	app.Put("/ChangePassword", anyRole, api.ChangePasswordApi)
+	app.Post("/CreateUser", admins, api.CreateUserApi)
+	app.Get("/FindAllUsers", admins, api.FindAllUsersApi)
+	app.Put("/UpdateUser", admins, api.UpdateUserApi)
+
+	// Returns Monthly PDF Report
+	app.Get("/GetMonthlyReturnsPDF", managers, api.GetMonthlyReturnsReportPDF)
+	// Expiring Stocks Report Route
+	app.Get("/GetExpiringStocksReportPDF", stock, api.GetExpiringStocksReportPDF)
+	// Top 10 Expiring Stocks in 7 Days (JSON)
+	app.Get("/GetExpiringStocksNext7Days", anyRole, api.GetExpiringStocksNext7Days)
+
+	app.Post("/CreateCategory", stock, api.CreateCategoryApi)
+	app.Get("/FindAllCategory", anyRole, api.FindAllCategoriesApi)
+	app.Delete("/DeleteCategory", managers, api.DeleteCategoryApi)
 
 	// Category Enhancement Routes
-	app.Get("/api/products/count", api.GetCategorizedProductsCountApi)                       // Get total categorized products count
-	app.Get("/api/categories/:categoryId/products/count", api.GetProductsCountByCategoryApi) // Get product count by category
-	app.Get("/api/categories/search", api.SearchCategoriesApi)                               // Search categories
+	app.Get("/api/products/count", anyRole, api.GetCategorizedProductsCountApi)                       // Get total categorized products count
+	app.Get("/api/categories/:categoryId/products/count", anyRole, api.GetProductsCountByCategoryApi) // Get product count by category
+	app.Get("/api/categories/search", anyRole, api.SearchCategoriesApi)                               // Search categories
 
-	app.Post("/CreateBrands", api.CreateBrand)
-	app.Get("/FindAllBrands", api.FindAllBrands)
-	app.Delete("/DeleteBrand", api.DeleteBrandApi)
+	app.Post("/CreateBrands", stock, api.CreateBrand)
+	app.Get("/FindAllBrands", anyRole, api.FindAllBrands)
+	app.Delete("/DeleteBrand", managers, api.DeleteBrandApi)
 
 	// Brand Enhancement Routes
-	app.Get("/api/brands/:brandId/products/count", api.GetProductsCountByBrandApi) // Get product count by brand
-	app.Get("/api/brands/search", api.SearchBrandsApi)                             // Search brands
-
-	app.Post("/CreateSubCategory", api.CreateSubCategory)
-	app.Get("/FindAllSubCategory", api.FindAllSubCategory)
-	app.Delete("/DeleteSubCategory", api.DeleteSubCategoryApi)
-	app.Post("/CreateProduct", api.CreateProduct)
-	app.Get("/FindAllProducts", api.FindAllProducts)
-	app.Delete("/DeleteProducts", api.DeleteProductApi)
-	app.Post("/CreateSupplier", api.CreateSupplier)
-	app.Get("/FindAllSuppliers", api.FindAllSuppliers)
-	app.Delete("/DeleteSupplierById", api.DeleteSupplierApi)
-	app.Post("/AssignProductToSupplier", api.AssignProductToSupplierApi)
-	app.Get("/FindProductsBySupplierID", api.GetProductsBySupplierApi)
+	app.Get("/api/brands/:brandId/products/count", anyRole, api.GetProductsCountByBrandApi) // Get product count by brand
+	app.Get("/api/brands/search", anyRole, api.SearchBrandsApi)                             // Search brands
+
+	app.Post("/CreateSubCategory", stock, api.CreateSubCategory)
+	app.Get("/FindAllSubCategory", anyRole, api.FindAllSubCategory)
+	app.Delete("/DeleteSubCategory", managers, api.DeleteSubCategoryApi)
+	app.Post("/CreateProduct", stock, api.CreateProduct)
+	app.Get("/FindAllProducts", anyRole, api.FindAllProducts)
+	app.Delete("/DeleteProducts", managers, api.DeleteProductApi)
+	app.Post("/CreateSupplier", stock, api.CreateSupplier)
+	app.Get("/FindAllSuppliers", anyRole, api.FindAllSuppliers)
+	app.Delete("/DeleteSupplierById", managers, api.DeleteSupplierApi)
+	app.Post("/AssignProductToSupplier", stock, api.AssignProductToSupplierApi)
+	app.Get("/FindProductsBySupplierID", anyRole, api.GetProductsBySupplierApi)
 
 	// Update supplier status (active/inactive)
-	app.Put("/UpdateSupplierStatus", api.UpdateSupplierStatus)
+	app.Put("/UpdateSupplierStatus", managers, api.UpdateSupplierStatus)
 
 	// Get total number of active and inactive suppliers
-	app.Get("/GetSupplierStatusCounts", api.GetSupplierStatusCounts)
-	app.Get("/FindProductsByCategoryId", api.GetProductsByCategoryApi)
-	app.Get("/FindProductsByBrandId", api.GetProductsByBrandApi)
-	app.Get("/FindProductsBySearch", api.FindAllProductsSearch)
-	app.Get("/FindAllCategoriesSearchApi", api.FindAllCategoriesSearchApi)
-	app.Post("/RestoreProduct", api.RestoreProductApi)
-	app.Get("/FindProductByProductId", api.FindProductByID)
-	app.Get("/FindBrandsBySearch", api.FindAllBrandsSearch)
-	app.Get("/FindAllSuppliersSearch", api.FindAllSuppliersSearch)
-	app.Put("/UpdateProduct", api.UpdateProductApi)
-	app.Put("/UpdateCategory", api.UpdateCategoryApi)
-	app.Get("/FindAllDeletedProducts", api.FindAllDeletedProductsApi)
-	app.Post("/CreateGRN", api.CreateGRN)
-	app.Get("/FindAllGRNs", api.FindAllGRNs)
-	app.Get("/FindGRNById", api.FindGRNByIdApi)
-	app.Get("/GetGRNReport", api.GetGRNReportApi)
-	app.Get("/GetGRNReportPDF", api.GetGRNReportPDFApi)
-	app.Get("/GetTotalGRNsCount", api.GetTotalGRNsCount)
-	app.Get("/GetCompletedGRNsCount", api.GetCompletedGRNsCount)
-	app.Get("/GetPendingGRNsCount", api.GetPendingGRNsCount)
-	app.Get("/GetPartialReceivedGRNsCount", api.GetPartialReceivedGRNsCount)
+	app.Get("/GetSupplierStatusCounts", anyRole, api.GetSupplierStatusCounts)
+	app.Get("/FindProductsByCategoryId", anyRole, api.GetProductsByCategoryApi)
+	app.Get("/FindProductsByBrandId", anyRole, api.GetProductsByBrandApi)
+	app.Get("/FindProductsBySearch", anyRole, api.FindAllProductsSearch)
+	app.Get("/FindAllCategoriesSearchApi", anyRole, api.FindAllCategoriesSearchApi)
+	app.Post("/RestoreProduct", managers, api.RestoreProductApi)
+	app.Get("/FindProductByProductId", anyRole, api.FindProductByID)
+	app.Get("/FindBrandsBySearch", anyRole, api.FindAllBrandsSearch)
+	app.Get("/FindAllSuppliersSearch", anyRole, api.FindAllSuppliersSearch)
+	app.Put("/UpdateProduct", managers, api.UpdateProductApi)
+	app.Put("/UpdateCategory", stock, api.UpdateCategoryApi)
+	app.Get("/FindAllDeletedProducts", managers, api.FindAllDeletedProductsApi)
+	app.Post("/CreateGRN", stock, api.CreateGRN)
+	app.Get("/FindAllGRNs", stock, api.FindAllGRNs)
+	app.Get("/FindGRNById", stock, api.FindGRNByIdApi)
+	app.Get("/GetGRNReport", managers, api.GetGRNReportApi)
+	app.Get("/GetGRNReportPDF", managers, api.GetGRNReportPDFApi)
+	app.Get("/GetTotalGRNsCount", stock, api.GetTotalGRNsCount)
+	app.Get("/GetCompletedGRNsCount", stock, api.GetCompletedGRNsCount)
+	app.Get("/GetPendingGRNsCount", stock, api.GetPendingGRNsCount)
+	app.Get("/GetPartialReceivedGRNsCount", stock, api.GetPartialReceivedGRNsCount)
 
 	// Total Products Count API
-	app.Get("/GetTotalProducts", api.GetTotalProducts)
-	app.Put("/UpdateGRNStatus", api.UpdateGRNStatusApi)
-	app.Get("/FindAllProductsBySubCategory", api.GetAllProductsBySubCategoryApi)
-	app.Put("/UpdateSupplier", api.UpdateSupplierApi)
-	app.Get("/CalculateTotalCost", api.CalculateTotalAndExpectedCost)
-	app.Put("/UpdateBrand", api.UpdateBrandApi)
-	app.Get("/CalculateBrandCostSummary", api.GetBrandCostSummaryApi)
-	app.Delete("/DeleteProductPermanent", api.DeleteProductPermanentApi)
-	app.Get("/FindAllProdcustBarcode", api.GetProductsByBarcodeApi)
+	app.Get("/GetTotalProducts", anyRole, api.GetTotalProducts)
+	app.Put("/UpdateGRNStatus", stock, api.UpdateGRNStatusApi)
+
+	// Purchase Order Routes (a GRN references a PO with purchaseOrderId)
+	app.Post("/CreatePurchaseOrder", stock, api.CreatePurchaseOrderApi)
+	app.Put("/UpdatePurchaseOrder", stock, api.UpdatePurchaseOrderApi)
+	app.Put("/UpdatePurchaseOrderStatus", managers, api.UpdatePurchaseOrderStatusApi) // approved, sent or closed
+	app.Get("/FindAllPurchaseOrders", stock, api.FindAllPurchaseOrdersApi)            // Optional supplierId and status
+	app.Get("/FindPurchaseOrderById", stock, api.FindPurchaseOrderByIdApi)
+	app.Get("/GetPurchaseOrderVariance", managers, api.GetPurchaseOrderVarianceApi)     // poId or supplierId
+	app.Get("/GetReorderSuggestions", stock, api.GetReorderSuggestionsApi)              // Optional supplierId, format=json|pdf
+	app.Post("/CreateReorderPurchaseOrders", stock, api.CreateReorderPurchaseOrdersApi) // Draft POs from the suggestions
+
+	app.Get("/FindAllProductsBySubCategory", anyRole, api.GetAllProductsBySubCategoryApi)
+	app.Put("/UpdateSupplier", stock, api.UpdateSupplierApi)
+	app.Get("/CalculateTotalCost", managers, api.CalculateTotalAndExpectedCost)
+	app.Put("/UpdateBrand", stock, api.UpdateBrandApi)
+	app.Get("/CalculateBrandCostSummary", managers, api.GetBrandCostSummaryApi)
+	app.Delete("/DeleteProductPermanent", admins, api.DeleteProductPermanentApi)
+	app.Get("/FindAllProdcustBarcode", anyRole, api.GetProductsByBarcodeApi)
 
 	// Sales Management Routes
-	app.Post("/CreateSale", api.CreateSaleApi)
-	app.Get("/FindAllSales", api.FindAllSalesApi)
-	app.Get("/FindSaleById", api.FindSaleByIdApi)
-	app.Post("/CalculateOrderSummary", api.CalculateOrderSummaryApi)
-	app.Post("/CalculateChange", api.CalculateChangeApi)
-	app.Get("/GetDailySalesSummary", api.GetDailySalesSummaryApi)
-	app.Get("/GetDailySalesSummaryPDF", api.GetDailySalesSummaryPDFApi)
+	app.Post("/CreateSale", sales, api.CreateSaleApi)
+	app.Get("/FindAllSales", sales, api.FindAllSalesApi)
+	app.Get("/FindSaleById", sales, api.FindSaleByIdApi)
+	app.Get("/GetSaleReceipt", sales, api.GetSaleReceiptApi)
+	app.Put("/VoidSale", managers, api.VoidSaleApi)
+	app.Post("/CalculateOrderSummary", sales, api.CalculateOrderSummaryApi)
+	app.Post("/CalculateChange", sales, api.CalculateChangeApi)
+	app.Get("/GetDailySalesSummary", managers, api.GetDailySalesSummaryApi)
+	app.Get("/GetDailySalesSummaryPDF", managers, api.GetDailySalesSummaryPDFApi)
+	app.Get("/GetGrossProfitReport", managers, api.GetGrossProfitReportApi)
+
+	// Draft Cart Routes
+	app.Post("/CreateCart", sales, api.CreateCartApi)
+	app.Post("/AddCartItem", sales, api.AddCartItemApi)
+	app.Put("/UpdateCartItem", sales, api.UpdateCartItemApi)
+	app.Put("/RemoveCartItem", sales, api.RemoveCartItemApi)
+	app.Put("/ParkCart", sales, api.ParkCartApi)
+	app.Put("/ResumeCart", sales, api.ResumeCartApi)
+	app.Get("/FindParkedCarts", sales, api.FindParkedCartsApi) // Optional terminalId
+	app.Get("/FindCartById", sales, api.FindCartByIdApi)
+	app.Delete("/CancelCart", sales, api.CancelCartApi)
+	app.Post("/ConvertCartToSale", sales, api.ConvertCartToSaleApi)
+
+	// Customer Registry Routes
+	app.Post("/CreateCustomer", sales, api.CreateCustomerApi)
+	app.Get("/FindAllCustomers", sales, api.FindAllCustomersApi)
+	app.Get("/FindCustomerById", sales, api.FindCustomerByIdApi)
+	app.Get("/FindCustomerByMobile", sales, api.FindCustomerByMobileApi)
+	app.Put("/UpdateCustomer", sales, api.UpdateCustomerApi)
+	app.Delete("/DeleteCustomer", managers, api.DeleteCustomerApi)
+	app.Get("/GetCustomerPurchaseHistory", sales, api.GetCustomerPurchaseHistoryApi)
+
+	// Promotion Routes
+	app.Post("/CreatePromotion", managers, api.CreatePromotionApi)
+	app.Get("/FindAllPromotions", sales, api.FindAllPromotionsApi)
+	app.Get("/FindPromotionById", sales, api.FindPromotionByIdApi)
+	app.Put("/UpdatePromotion", managers, api.UpdatePromotionApi)
+	app.Delete("/DeletePromotion", managers, api.DeletePromotionApi)
+
+	// Tax Class Routes
+	app.Post("/CreateTaxClass", managers, api.CreateTaxClassApi)
+	app.Get("/FindAllTaxClasses", anyRole, api.FindAllTaxClassesApi)
+	app.Put("/UpdateTaxClass", managers, api.UpdateTaxClassApi)
+	app.Delete("/DeleteTaxClass", managers, api.DeleteTaxClassApi)
+	app.Put("/SetCategoryTaxClass", managers, api.SetCategoryTaxClassApi)
 
 	// Saved Daily Reports Routes
-	app.Get("/GetSavedDailyReport", api.GetSavedDailyReportApi)
-	app.Get("/GetMonthlyReports", api.GetMonthlyReportsApi)
-	app.Get("/GetDateRangeReportsPDF", api.GetDateRangeReportsPDFApi)
+	app.Get("/GetSavedDailyReport", managers, api.GetSavedDailyReportApi)
+	app.Get("/GetMonthlyReports", managers, api.GetMonthlyReportsApi)
+	app.Get("/GetDateRangeReportsPDF", managers, api.GetDateRangeReportsPDFApi)
+
+	// Permanent Monthly/Yearly Report Rollup Routes
+	app.Get("/GetReportRollup", managers, api.GetReportRollupApi)
+	app.Get("/GetReportRollupPDF", managers, api.GetReportRollupPDFApi)
+	app.Get("/CompareReportRollups", managers, api.CompareReportRollupsApi)
+	app.Get("/CompareReportRollupsPDF", managers, api.CompareReportRollupsPDFApi)
+	app.Post("/RefreshReportRollup", managers, api.RefreshReportRollupApi)
 
 	// Stock Management Routes
-	app.Post("/SyncStocks", api.SyncStocksApi)                                     // Sync all product stocks to Stocks collection
-	app.Get("/FindAllStocks", api.FindAllStocksApi)                                // Get all stocks with pagination (includes total count)
-	app.Get("/FindAllStocksLite", api.FindAllStocksLightweightApi)                 // Get all stocks with pagination (lightweight, no total count)
-	app.Get("/FindAllProductsWithStock", api.FindAllProductsWithStockApi)          // Get all products with stock info (includes products without batches)
-	app.Get("/FindAllProductsWithStockLite", api.FindAllProductsWithStockLiteApi)  // Lightweight version - all products with stock info
-	app.Get("/FindAllStocksFiltered", api.FindAllStocksFilteredApi)                // Get filtered stocks by status with pagination (includes total count)
-	app.Get("/FindAllStocksFilteredLite", api.FindAllStocksFilteredLightweightApi) // Get filtered stocks by status with pagination (lightweight)
-	app.Get("/GetTotalStockQuantity", api.GetTotalStockQuantityApi)                // Get sum of all stockQty (total quantity in inventory)
-	app.Get("/GetStockStatusCounts", api.GetStockStatusCountsApi)                  // Get count of stocks by status (Low/Average/Good)
+	app.Post("/SyncStocks", admins, api.SyncStocksApi)                                      // Sync all product stocks to Stocks collection
+	app.Get("/FindAllStocks", anyRole, api.FindAllStocksApi)                                // Get all stocks with pagination (includes total count)
+	app.Get("/FindAllStocksLite", anyRole, api.FindAllStocksLightweightApi)                 // Get all stocks with pagination (lightweight, no total count)
+	app.Get("/FindAllProductsWithStock", anyRole, api.FindAllProductsWithStockApi)          // Get all products with stock info (includes products without batches)
+	app.Get("/FindAllProductsWithStockLite", anyRole, api.FindAllProductsWithStockLiteApi)  // Lightweight version - all products with stock info
+	app.Get("/FindAllStocksFiltered", anyRole, api.FindAllStocksFilteredApi)                // Get filtered stocks by status with pagination (includes total count)
+	app.Get("/FindAllStocksFilteredLite", anyRole, api.FindAllStocksFilteredLightweightApi) // Get filtered stocks by status with pagination (lightweight)
+	app.Get("/GetTotalStockQuantity", anyRole, api.GetTotalStockQuantityApi)                // Get sum of all stockQty (total quantity in inventory)
+	app.Get("/GetStockStatusCounts", anyRole, api.GetStockStatusCountsApi)                  // Get count of stocks by status (Low/Average/Good)
+	app.Get("/GetStockThresholds", anyRole, api.GetStockThresholdsApi)                      // Get global and category stock thresholds, or those of one product
+	app.Put("/SetCategoryStockThresholds", managers, api.SetCategoryStockThresholdsApi)     // Set or clear a category's stock thresholds
+
+	// Location Routes (stores and warehouses that hold their own batches)
+	app.Post("/CreateLocation", admins, api.CreateLocationApi)
+	app.Put("/UpdateLocation", admins, api.UpdateLocationApi)      // MAIN cannot be deactivated
+	app.Get("/FindAllLocations", anyRole, api.FindAllLocationsApi) // Optional status
+	app.Get("/FindLocationById", anyRole, api.FindLocationByIdApi)
+
+	// Stock Take Routes (expected quantities are frozen at the start, variances posted on approval)
+	app.Post("/CreateStockTake", stock, api.CreateStockTakeApi)            // scope all, category or brand; optional locationId
+	app.Put("/RecordStockTakeCounts", stock, api.RecordStockTakeCountsApi) // Counts by productId or barcode; add=true for scans
+	app.Get("/FindAllStockTakes", stock, api.FindAllStockTakesApi)         // Optional status
+	app.Get("/FindStockTakeById", stock, api.FindStockTakeByIdApi)
+	app.Get("/GetStockTakePDF", stock, api.GetStockTakePDFApi)
+	app.Put("/ApproveStockTake", managers, api.ApproveStockTakeApi) // Post the variances into the batches and Stocks
+	app.Put("/CancelStockTake", managers, api.CancelStockTakeApi)
 
 	// Low Stock Products API
-	app.Get("/GetLowStockProducts", api.GetLowStockProductsHandler) // Get top 10 lowest stock products
+	app.Get("/GetLowStockProducts", anyRole, api.GetLowStockProductsHandler) // Get top 10 lowest stock products
 
 	// Stock Maintenance Routes
-	app.Delete("/CleanupOrphanedStocks", api.CleanupOrphanedStocksApi) // Remove stock entries with null/empty batchId
-	app.Get("/ValidateStockIntegrity", api.ValidateStockIntegrityApi)  // Check for stock data inconsistencies
+	app.Delete("/CleanupOrphanedStocks", admins, api.CleanupOrphanedStocksApi)  // Remove stock entries with null/empty batchId
+	app.Get("/ValidateStockIntegrity", managers, api.ValidateStockIntegrityApi) // Check for stock data inconsistencies
 
 	// Batch Stock Management Routes
-	app.Post("/AddStock", api.AddStock)                // Add stock to existing product (adds to existing batch or creates new batch based on expiry date)
-	app.Put("/EditBatchStock", api.EditBatchStock)     // Edit stock quantity of a specific batch
-	app.Put("/EditBatchDetails", api.EditBatchDetails) // Edit batch details (expiry date, prices)
-	app.Put("/RemoveStock", api.RemoveStockFromBatch)  // Remove/reduce stock from a specific batch
-	app.Delete("/DeleteBatch", api.DeleteBatch)        // Delete a batch completely
+	app.Post("/AddStock", stock, api.AddStock)                   // Add stock to existing product (adds to existing batch or creates new batch based on expiry date)
+	app.Put("/EditBatchStock", stock, api.EditBatchStock)        // Edit stock quantity of a specific batch
+	app.Put("/EditBatchDetails", managers, api.EditBatchDetails) // Edit batch details (expiry date, prices)
+	app.Put("/RemoveStock", stock, api.RemoveStockFromBatch)     // Remove/reduce stock from a specific batch
+	app.Delete("/DeleteBatch", stock, api.DeleteBatch)           // Delete a batch completely
+
+	// Stock Movement Ledger
+	app.Get("/FindStockMovements", stock, api.FindStockMovementsApi) // Filter by productId, batchId, type, startDate, endDate
 
 	// Return APIs
-	app.Post("/returns", api.CreateReturnApi)
-	app.Get("/returns", api.FindAllReturnsApi)
-	app.Get("/returns/:id", api.FindReturnByIdApi)
+	app.Post("/returns", sales, api.CreateReturnApi)
+	app.Get("/returns", sales, api.FindAllReturnsApi)
+	app.Get("/returns/:id", sales, api.FindReturnByIdApi)
 }
@@ -0,0 +1,372 @@
+package config
+
+import (
+	"employee-crud/dto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Business time zones must resolve even on images without zoneinfo
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting the server reads at startup
+// Values come from the optional config file first and are then overridden by environment variables
+type Config struct {
+	Mongo    MongoConfig    `json:"mongo" yaml:"mongo"`
+	Server   ServerConfig   `json:"server" yaml:"server"`
+	Auth     AuthConfig     `json:"auth" yaml:"auth"`
+	Business BusinessConfig `json:"business" yaml:"business"`
+	Stock    StockConfig    `json:"stock" yaml:"stock"`
+	Sales    SalesConfig    `json:"sales" yaml:"sales"`
+	Loyalty  LoyaltyConfig  `json:"loyalty" yaml:"loyalty"`
+	Carts    CartsConfig    `json:"carts" yaml:"carts"`
+	Receipt  ReceiptConfig  `json:"receipt" yaml:"receipt"`
+	Reorder  ReorderConfig  `json:"reorder" yaml:"reorder"`
+	TTL      TTLConfig      `json:"ttl" yaml:"ttl"`
+}
+
+type MongoConfig struct {
+	URI      string `json:"uri" yaml:"uri"`           // MONGO_URI
+	Database string `json:"database" yaml:"database"` // DB_NAME
+}
+
+type ServerConfig struct {
+	Port        string   `json:"port" yaml:"port"`               // PORT
+	CORSOrigins []string `json:"corsOrigins" yaml:"corsOrigins"` // CORS_ORIGINS (comma separated)
+}
+
+type AuthConfig struct {
+	TokenSecret   string `json:"tokenSecret" yaml:"tokenSecret"`     // AUTH_TOKEN_SECRET
+	TokenTTLHours int    `json:"tokenTTLHours" yaml:"tokenTTLHours"` // AUTH_TOKEN_TTL_HOURS
+	AdminUsername string `json:"adminUsername" yaml:"adminUsername"` // ADMIN_USERNAME, only used on an empty Users collection
+	AdminPassword string `json:"adminPassword" yaml:"adminPassword"` // ADMIN_PASSWORD
+}
+
+type BusinessConfig struct {
+	Timezone string `json:"timezone" yaml:"timezone"` // BUSINESS_TIMEZONE, an IANA name such as Asia/Colombo
+}
+
+// StockConfig holds the global stock status thresholds
+// A quantity below LowThreshold is "Low", below AverageThreshold is "Average", otherwise "Good"
+// Categories and products can set their own with SetCategoryStockThresholds and UpdateProduct
+type StockConfig struct {
+	LowThreshold     int `json:"lowThreshold" yaml:"lowThreshold"`         // STOCK_LOW_THRESHOLD
+	AverageThreshold int `json:"averageThreshold" yaml:"averageThreshold"` // STOCK_AVERAGE_THRESHOLD
+}
+
+// Thresholds returns the global thresholds used by products and categories without their own
+func (s StockConfig) Thresholds() dto.StockThresholds {
+	return dto.StockThresholds{LowThreshold: s.LowThreshold, AverageThreshold: s.AverageThreshold}
+}
+
+// SalesConfig controls how long sales stay in the Sales collection and how long they are kept at all
+// Sales older than ArchiveAfterDays are moved into the compressed SalesArchive collection, which reports still read
+type SalesConfig struct {
+	ArchiveAfterDays int `json:"archiveAfterDays" yaml:"archiveAfterDays"` // SALES_ARCHIVE_AFTER_DAYS, 0 never archives
+	RetentionDays    int `json:"retentionDays" yaml:"retentionDays"`       // SALES_RETENTION_DAYS, 0 keeps sales forever
+}
+
+// LoyaltyConfig is the loyalty points rule for registered customers
+// A customer earns one point per EarnPerAmount spent and can redeem points at PointValue each
+type LoyaltyConfig struct {
+	EarnPerAmount   dto.Money `json:"earnPerAmount" yaml:"earnPerAmount"`     // LOYALTY_EARN_PER_AMOUNT, 0 disables earning
+	PointValue      dto.Money `json:"pointValue" yaml:"pointValue"`           // LOYALTY_POINT_VALUE, 0 disables redeeming
+	MinRedeemPoints int       `json:"minRedeemPoints" yaml:"minRedeemPoints"` // LOYALTY_MIN_REDEEM_POINTS
+}
+
+// CartsConfig controls draft carts held at the tills
+// A cart that reserves stock holds it for ReservationMinutes after it was last changed, parked or resumed
+type CartsConfig struct {
+	ReservationMinutes int `json:"reservationMinutes" yaml:"reservationMinutes"` // CART_RESERVATION_MINUTES
+}
+
+// ReceiptConfig is what every printed receipt carries around the sale
+type ReceiptConfig struct {
+	StoreName    string   `json:"storeName" yaml:"storeName"`       // RECEIPT_STORE_NAME
+	Header       []string `json:"header" yaml:"header"`             // RECEIPT_HEADER, lines separated by "|": address, phone, tax number, ...
+	Footer       []string `json:"footer" yaml:"footer"`             // RECEIPT_FOOTER, lines separated by "|"
+	PaperWidthMM int      `json:"paperWidthMM" yaml:"paperWidthMM"` // RECEIPT_PAPER_WIDTH_MM, 58 or 80; used when a receipt request does not choose
+}
+
+// ReorderConfig controls the reorder suggestions worked out for products without their own reorder point and quantity
+// Average daily sales are taken from the saved daily reports of the last VelocityDays; a product is reordered when
+// its stock and open orders cover no more than its supplier's lead time plus SafetyDays of sales,
+// and enough is ordered to cover CoverDays of sales on top of that
+type ReorderConfig struct {
+	VelocityDays int `json:"velocityDays" yaml:"velocityDays"` // REORDER_VELOCITY_DAYS, 0 ignores sales and uses the stock thresholds
+	LeadTimeDays int `json:"leadTimeDays" yaml:"leadTimeDays"` // REORDER_LEAD_TIME_DAYS, for suppliers without their own lead time
+	SafetyDays   int `json:"safetyDays" yaml:"safetyDays"`     // REORDER_SAFETY_DAYS
+	CoverDays    int `json:"coverDays" yaml:"coverDays"`       // REORDER_COVER_DAYS
+}
+
+type TTLConfig struct {
+	DailyReportRetentionMonths int `json:"dailyReportRetentionMonths" yaml:"dailyReportRetentionMonths"` // DAILY_REPORT_RETENTION_MONTHS
+}
+
+// defaults returns the settings used when neither the file nor the environment sets a value
+func defaults() Config {
+	return Config{
+		Mongo: MongoConfig{Database: "POS"},
+		Server: ServerConfig{
+			Port:        "3000",
+			CORSOrigins: []string{"http://localhost:3000", "http://localhost:8080", "https://pos-frontend-tan.vercel.app"},
+		},
+		Auth:     AuthConfig{TokenTTLHours: 12},
+		Business: BusinessConfig{Timezone: "Asia/Colombo"},
+		Stock:    StockConfig{LowThreshold: 10, AverageThreshold: 25},
+		Sales:    SalesConfig{ArchiveAfterDays: 90},
+		Loyalty:  LoyaltyConfig{EarnPerAmount: dto.MoneyFromFloat(100), PointValue: dto.MoneyFromFloat(1)},
+		Carts:    CartsConfig{ReservationMinutes: 30},
+		Receipt:  ReceiptConfig{StoreName: "POS", Footer: []string{"Thank you for shopping with us!"}, PaperWidthMM: 80},
+		Reorder:  ReorderConfig{VelocityDays: 28, LeadTimeDays: 7, SafetyDays: 3, CoverDays: 14},
+		TTL:      TTLConfig{DailyReportRetentionMonths: 1},
+	}
+}
+
+var (
+	current  = defaults()
+	location = time.FixedZone("Asia/Colombo", 5*3600+30*60)
+)
+
+// Load reads the config file at path (JSON or YAML, chosen by extension; skipped when path is empty),
+// applies environment variable overrides and returns the result without validating it
+func Load(path string) (*Config, error) {
+	cfg := defaults()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(data, &cfg)
+		case ".json":
+			err = json.Unmarshal(data, &cfg)
+		default:
+			err = fmt.Errorf("unsupported config file type %q, use .json, .yaml or .yml", filepath.Ext(path))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Init loads the configuration from CONFIG_FILE and the environment, validates it
+// and makes it available through Get and the helpers in this package
+func Init() (*Config, error) {
+	cfg, err := Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := Set(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Set makes cfg the active configuration
+func Set(cfg *Config) error {
+	loc, err := time.LoadLocation(cfg.Business.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid business.timezone %q: %w", cfg.Business.Timezone, err)
+	}
+	current = *cfg
+	location = loc
+	return nil
+}
+
+// Get returns the active configuration (defaults until Init or Set is called)
+func Get() Config {
+	return current
+}
+
+// Location returns the business time zone used for day boundaries in reports
+func Location() *time.Location {
+	return location
+}
+
+// Validate checks required keys and value ranges and reports every problem at once
+func (cfg *Config) Validate() error {
+	var missing []string
+	if cfg.Mongo.URI == "" {
+		missing = append(missing, "mongo.uri (MONGO_URI)")
+	}
+	if cfg.Mongo.Database == "" {
+		missing = append(missing, "mongo.database (DB_NAME)")
+	}
+	if cfg.Auth.TokenSecret == "" {
+		missing = append(missing, "auth.tokenSecret (AUTH_TOKEN_SECRET)")
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing required configuration: "+strings.Join(missing, ", "))
+	}
+	if cfg.Auth.TokenSecret != "" && len(cfg.Auth.TokenSecret) < 32 {
+		problems = append(problems, "auth.tokenSecret must be at least 32 characters")
+	}
+	if cfg.Auth.TokenTTLHours <= 0 {
+		problems = append(problems, "auth.tokenTTLHours must be greater than 0")
+	}
+	if port, err := strconv.Atoi(cfg.Server.Port); err != nil || port <= 0 || port > 65535 {
+		problems = append(problems, fmt.Sprintf("server.port %q is not a valid port", cfg.Server.Port))
+	}
+	if _, err := time.LoadLocation(cfg.Business.Timezone); err != nil || cfg.Business.Timezone == "" {
+		problems = append(problems, fmt.Sprintf("business.timezone %q is not a valid IANA time zone", cfg.Business.Timezone))
+	}
+	if cfg.Stock.LowThreshold <= 0 || cfg.Stock.AverageThreshold <= cfg.Stock.LowThreshold {
+		problems = append(problems, "stock thresholds must satisfy 0 < lowThreshold < averageThreshold")
+	}
+	if cfg.Sales.ArchiveAfterDays < 0 || cfg.Sales.RetentionDays < 0 {
+		problems = append(problems, "sales.archiveAfterDays and sales.retentionDays cannot be negative")
+	}
+	if cfg.Sales.RetentionDays > 0 && cfg.Sales.ArchiveAfterDays > 0 && cfg.Sales.RetentionDays <= cfg.Sales.ArchiveAfterDays {
+		problems = append(problems, "sales.retentionDays must be longer than sales.archiveAfterDays")
+	}
+	if cfg.Loyalty.EarnPerAmount < 0 || cfg.Loyalty.PointValue < 0 || cfg.Loyalty.MinRedeemPoints < 0 {
+		problems = append(problems, "loyalty.earnPerAmount, loyalty.pointValue and loyalty.minRedeemPoints cannot be negative")
+	}
+	if cfg.Carts.ReservationMinutes <= 0 {
+		problems = append(problems, "carts.reservationMinutes must be greater than 0")
+	}
+	if cfg.Receipt.PaperWidthMM != 58 && cfg.Receipt.PaperWidthMM != 80 {
+		problems = append(problems, "receipt.paperWidthMM must be 58 or 80")
+	}
+	if cfg.Reorder.VelocityDays < 0 || cfg.Reorder.LeadTimeDays < 0 || cfg.Reorder.SafetyDays < 0 {
+		problems = append(problems, "reorder.velocityDays, reorder.leadTimeDays and reorder.safetyDays cannot be negative")
+	}
+	if cfg.Reorder.CoverDays <= 0 {
+		problems = append(problems, "reorder.coverDays must be greater than 0")
+	}
+	if cfg.TTL.DailyReportRetentionMonths <= 0 {
+		problems = append(problems, "ttl.dailyReportRetentionMonths must be greater than 0")
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// applyEnv overrides file values with any environment variables that are set
+func (cfg *Config) applyEnv() error {
+	setString(&cfg.Mongo.URI, "MONGO_URI")
+	setString(&cfg.Mongo.Database, "DB_NAME")
+	setString(&cfg.Server.Port, "PORT")
+	if origins := os.Getenv("CORS_ORIGINS"); origins != "" {
+		cfg.Server.CORSOrigins = nil
+		for _, origin := range strings.Split(origins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				cfg.Server.CORSOrigins = append(cfg.Server.CORSOrigins, origin)
+			}
+		}
+	}
+	setString(&cfg.Auth.TokenSecret, "AUTH_TOKEN_SECRET")
+	setString(&cfg.Auth.AdminUsername, "ADMIN_USERNAME")
+	setString(&cfg.Auth.AdminPassword, "ADMIN_PASSWORD")
+	setString(&cfg.Business.Timezone, "BUSINESS_TIMEZONE")
+	setString(&cfg.Receipt.StoreName, "RECEIPT_STORE_NAME")
+	setLines(&cfg.Receipt.Header, "RECEIPT_HEADER")
+	setLines(&cfg.Receipt.Footer, "RECEIPT_FOOTER")
+
+	var invalid []string
+	for key, target := range map[string]*int{
+		"AUTH_TOKEN_TTL_HOURS":          &cfg.Auth.TokenTTLHours,
+		"STOCK_LOW_THRESHOLD":           &cfg.Stock.LowThreshold,
+		"STOCK_AVERAGE_THRESHOLD":       &cfg.Stock.AverageThreshold,
+		"SALES_ARCHIVE_AFTER_DAYS":      &cfg.Sales.ArchiveAfterDays,
+		"SALES_RETENTION_DAYS":          &cfg.Sales.RetentionDays,
+		"LOYALTY_MIN_REDEEM_POINTS":     &cfg.Loyalty.MinRedeemPoints,
+		"CART_RESERVATION_MINUTES":      &cfg.Carts.ReservationMinutes,
+		"RECEIPT_PAPER_WIDTH_MM":        &cfg.Receipt.PaperWidthMM,
+		"REORDER_VELOCITY_DAYS":         &cfg.Reorder.VelocityDays,
+		"REORDER_LEAD_TIME_DAYS":        &cfg.Reorder.LeadTimeDays,
+		"REORDER_SAFETY_DAYS":           &cfg.Reorder.SafetyDays,
+		"REORDER_COVER_DAYS":            &cfg.Reorder.CoverDays,
+		"DAILY_REPORT_RETENTION_MONTHS": &cfg.TTL.DailyReportRetentionMonths,
+	} {
+		if !setInt(target, key) {
+			invalid = append(invalid, key)
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("environment variables must be integers: %s", strings.Join(invalid, ", "))
+	}
+
+	for key, target := range map[string]*dto.Money{
+		"LOYALTY_EARN_PER_AMOUNT": &cfg.Loyalty.EarnPerAmount,
+		"LOYALTY_POINT_VALUE":     &cfg.Loyalty.PointValue,
+	} {
+		if !setMoney(target, key) {
+			invalid = append(invalid, key)
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("environment variables must be numbers: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+func setString(target *string, key string) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		*target = value
+	}
+}
+
+// setLines splits the variable into lines at "|"; a set variable replaces every line
+func setLines(target *[]string, key string) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	*target = nil
+	for _, line := range strings.Split(value, "|") {
+		*target = append(*target, strings.TrimSpace(line))
+	}
+}
+
+// setInt returns false if the variable is set but is not an integer
+func setInt(target *int, key string) bool {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return false
+	}
+	*target = n
+	return true
+}
+
+// setMoney returns false if the variable is set but is not an amount
+func setMoney(target *dto.Money, key string) bool {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return true
+	}
+	amount, err := dto.ParseMoney(value)
+	if err != nil {
+		return false
+	}
+	*target = amount
+	return true
+}
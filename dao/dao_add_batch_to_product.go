@@ -4,49 +4,82 @@ import (
 	"context"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
+	"employee-crud/functions"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // DB_AddBatchToProduct adds a new batch to an existing product
-func DB_AddBatchToProduct(productId string, batch dto.Batch) error {
+// The push is atomic, so it only bumps the version to invalidate concurrent read-modify-write updates
+func DB_AddBatchToProduct(productId string, batch dto.Batch, ref dto.StockMovementRef) error {
 	collection := dbConfigs.DATABASE.Collection("Products")
 	ctx := context.Background()
+	now := time.Now().UTC()
 
 	filter := bson.M{"productId": productId}
 	update := bson.M{
 		"$push": bson.M{"batches": batch},
-		"$inc":  bson.M{"stockQty": batch.StockQty},
-		"$set":  bson.M{"updated_at": time.Now().UTC()},
+		"$inc":  bson.M{"stockQty": batch.StockQty, "version": 1},
+		"$set":  bson.M{"updated_at": now},
 	}
 
-	_, err := collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
-	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
 
-	return nil
+	return runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		var before dto.Product
+		if err := collection.FindOneAndUpdate(sessCtx, filter, update, opts).Decode(&before); err != nil {
+			return err
+		}
+
+		after := before
+		after.Batches = append(append([]dto.Batch(nil), before.Batches...), batch)
+		after.StockQty = before.StockQty + batch.StockQty
+
+		return insertStockMovements(sessCtx, functions.BuildStockMovements(&before, &after, ref, now))
+	})
 }
 
 // DB_UpdateProductWithBatch updates a product's main fields and initializes batches array
-func DB_UpdateProductWithBatch(product *dto.Product, initialBatch dto.Batch) error {
+// The product must still be at the version it was read with, otherwise ErrProductVersionConflict is returned
+func DB_UpdateProductWithBatch(product *dto.Product, initialBatch dto.Batch, ref dto.StockMovementRef) error {
 	collection := dbConfigs.DATABASE.Collection("Products")
 	ctx := context.Background()
+	now := time.Now().UTC()
 
-	filter := bson.M{"productId": product.ProductId}
+	filter := productVersionFilter(product.ProductId, product.Version)
 	update := bson.M{
 		"$set": bson.M{
 			"batches":    []dto.Batch{initialBatch},
 			"stockQty":   initialBatch.StockQty,
-			"updated_at": time.Now().UTC(),
+			"updated_at": now,
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
-	_, err := collection.UpdateOne(ctx, filter, update)
+	after := *product
+	after.Batches = []dto.Batch{initialBatch}
+	after.StockQty = initialBatch.StockQty
+	after.Version++
+
+	err := runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := collection.UpdateOne(sessCtx, filter, update)
+		if err != nil {
+			return err
+		}
+
+		if result.MatchedCount == 0 {
+			return productVersionConflict{productId: product.ProductId}
+		}
+
+		return insertStockMovements(sessCtx, functions.BuildStockMovements(product, &after, ref, now))
+	})
 	if err != nil {
 		return err
 	}
 
+	*product = after
 	return nil
 }
@@ -3,11 +3,12 @@ package dao
 import (
 	"context"
 	"employee-crud/dbConfigs"
+	"employee-crud/dto"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func DB_GetBrandCostSummary(brandId string) (float64, float64, error) {
+func DB_GetBrandCostSummary(brandId string) (dto.Money, dto.Money, error) {
 	collection := dbConfigs.DATABASE.Collection("Products")
 	ctx := context.Background()
 
@@ -17,8 +18,8 @@ func DB_GetBrandCostSummary(brandId string) (float64, float64, error) {
 	}}}
 
 	addFieldsStage := bson.D{{Key: "$addFields", Value: bson.M{
-		"totalCost":    bson.M{"$multiply": []string{"$costPrice", "$stockQty"}},
-		"expectedCost": bson.M{"$multiply": []string{"$sellingPrice", "$stockQty"}},
+		"totalCost":    bson.M{"$multiply": bson.A{bson.M{"$toDecimal": "$costPrice"}, "$stockQty"}},
+		"expectedCost": bson.M{"$multiply": bson.A{bson.M{"$toDecimal": "$sellingPrice"}, "$stockQty"}},
 	}}}
 
 	groupStage := bson.D{{Key: "$group", Value: bson.M{
@@ -33,15 +34,13 @@ func DB_GetBrandCostSummary(brandId string) (float64, float64, error) {
 	}
 	defer cursor.Close(ctx)
 
-	var results []bson.M
+	var results []costSummary
 	if err := cursor.All(ctx, &results); err != nil {
 		return 0, 0, err
 	}
 
 	if len(results) > 0 {
-		totalCost, _ := results[0]["total_cost"].(float64)
-		expectedCost, _ := results[0]["expected_cost"].(float64)
-		return totalCost, expectedCost, nil
+		return results[0].TotalCost, results[0].ExpectedCost, nil
 	}
 
 	return 0, 0, nil
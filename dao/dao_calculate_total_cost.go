@@ -3,14 +3,21 @@ package dao
 import (
 	"context"
 	"employee-crud/dbConfigs"
+	"employee-crud/dto"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+// costSummary is the result of the cost aggregations; the sums are Decimal128 so they are exact to the cent
+type costSummary struct {
+	TotalCost    dto.Money `bson:"total_cost"`
+	ExpectedCost dto.Money `bson:"expected_cost"`
+}
+
 // DB_CalculateTotalAndExpectedCost calculates:
 // - total_cost = sum(CostPrice * StockQty)
 // - expected_cost = sum(SellingPrice * StockQty)
-func DB_CalculateTotalAndExpectedCost() (float64, float64, error) {
+func DB_CalculateTotalAndExpectedCost() (dto.Money, dto.Money, error) {
 	collection := dbConfigs.DATABASE.Collection("Products")
 	ctx := context.Background()
 
@@ -19,8 +26,8 @@ func DB_CalculateTotalAndExpectedCost() (float64, float64, error) {
 
 	// Add fields for total cost and expected cost
 	addFieldsStage := bson.D{{Key: "$addFields", Value: bson.M{
-		"totalCost":    bson.M{"$multiply": []string{"$costPrice", "$stockQty"}},
-		"expectedCost": bson.M{"$multiply": []string{"$sellingPrice", "$stockQty"}},
+		"totalCost":    bson.M{"$multiply": bson.A{bson.M{"$toDecimal": "$costPrice"}, "$stockQty"}},
+		"expectedCost": bson.M{"$multiply": bson.A{bson.M{"$toDecimal": "$sellingPrice"}, "$stockQty"}},
 	}}}
 
 	// Group to sum all values
@@ -37,15 +44,13 @@ func DB_CalculateTotalAndExpectedCost() (float64, float64, error) {
 	}
 	defer cursor.Close(ctx)
 
-	var results []bson.M
+	var results []costSummary
 	if err := cursor.All(ctx, &results); err != nil {
 		return 0, 0, err
 	}
 
 	if len(results) > 0 {
-		totalCost, _ := results[0]["total_cost"].(float64)
-		expectedCost, _ := results[0]["expected_cost"].(float64)
-		return totalCost, expectedCost, nil
+		return results[0].TotalCost, results[0].ExpectedCost, nil
 	}
 
 	return 0, 0, nil
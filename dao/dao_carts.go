@@ -0,0 +1,127 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCartStatusChanged is returned when a cart is no longer in the status a change expects,
+// e.g. it was converted or cancelled by another terminal in the meantime
+var ErrCartStatusChanged = errors.New("cart status changed")
+
+func DB_CreateCart(cart *dto.Cart) error {
+	_, err := dbConfigs.DATABASE.Collection("Carts").InsertOne(context.Background(), cart)
+	return err
+}
+
+// DB_FindCartById returns mongo.ErrNoDocuments if the cart does not exist
+func DB_FindCartById(cartId string) (*dto.Cart, error) {
+	var cart dto.Cart
+	err := dbConfigs.DATABASE.Collection("Carts").FindOne(context.Background(), bson.M{"cartId": cartId}).Decode(&cart)
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// DB_FindCartsByTerminal returns the carts of a terminal in a status, most recently updated first
+// An empty terminalId returns the carts of every terminal
+func DB_FindCartsByTerminal(terminalId string, status string) ([]dto.Cart, error) {
+	ctx := context.Background()
+
+	filter := bson.M{"status": status}
+	if terminalId != "" {
+		filter["terminalId"] = terminalId
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+	cursor, err := dbConfigs.DATABASE.Collection("Carts").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	carts := []dto.Cart{}
+	if err := cursor.All(ctx, &carts); err != nil {
+		return nil, err
+	}
+	return carts, nil
+}
+
+// DB_UpdateCart replaces a cart as long as it is still in one of fromStatuses
+// Returns mongo.ErrNoDocuments if the cart does not exist, ErrCartStatusChanged if it is in another status
+func DB_UpdateCart(cart *dto.Cart, fromStatuses ...string) error {
+	return replaceCart(context.Background(), cart, fromStatuses...)
+}
+
+// replaceCart is DB_UpdateCart on ctx, so checkout can convert the cart inside its transaction
+func replaceCart(ctx context.Context, cart *dto.Cart, fromStatuses ...string) error {
+	collection := dbConfigs.DATABASE.Collection("Carts")
+
+	result, err := collection.ReplaceOne(ctx,
+		bson.M{"cartId": cart.CartID, "status": bson.M{"$in": fromStatuses}},
+		cart,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.M{"cartId": cart.CartID})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return ErrCartStatusChanged
+}
+
+// DB_FindReservedQuantities sums the quantities per product held at a location by carts whose reservation is still
+// running at now, leaving out the cart excludeCartId (the cart being changed or converted)
+func DB_FindReservedQuantities(locationId string, excludeCartId string, now time.Time) (map[string]int, error) {
+	ctx := context.Background()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"reserveStock":  true,
+			"status":        bson.M{"$in": bson.A{dto.CartOpen, dto.CartParked}},
+			"reservedUntil": bson.M{"$gt": now},
+			"cartId":        bson.M{"$ne": excludeCartId},
+			"locationId":    locationMatch(functions.NormalizeLocationId(locationId)),
+		}}},
+		{{Key: "$unwind", Value: "$items"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$items.productId",
+			"quantity": bson.M{"$sum": "$items.quantity"},
+		}}},
+	}
+	cursor, err := dbConfigs.DATABASE.Collection("Carts").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ProductID string `bson:"_id"`
+		Quantity  int    `bson:"quantity"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	reserved := make(map[string]int, len(rows))
+	for _, row := range rows {
+		reserved[row.ProductID] = row.Quantity
+	}
+	return reserved, nil
+}
@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DB_CheckoutSale records a sale and deducts its items from stock in a single transaction
+// The sale insert, FEFO batch deduction and Stocks resync for every item either all commit or none do
+// Transient errors (e.g. write conflicts with a concurrent checkout) retry the whole transaction
+// Each batch deduction is recorded in the StockMovements ledger against the saleId and on its sale line
+// A sale linked to a customer also updates their loyalty points and spend in the same transaction
+// A sale made from a cart marks the cart converted in the same transaction, as long as it is still in the status it
+// was read in; otherwise nothing is kept and ErrCartStatusChanged is returned
+func DB_CheckoutSale(sale *dto.Sale, userId string, cart *dto.Cart) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ref := dto.StockMovementRef{
+		Type:          dto.MovementSale,
+		ReferenceType: dto.ReferenceSale,
+		ReferenceId:   sale.SaleID,
+		UserId:        userId,
+	}
+
+	var converted dto.Cart
+	err := runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		// Deduct first so the sale is saved with the batches each line came from
+		for i := range sale.Items {
+			allocations, err := deductProductStock(sessCtx, sale.Items[i].ProductID, functions.SaleLocation(sale), sale.Items[i].Quantity, ref)
+			if err != nil {
+				return err
+			}
+			sale.Items[i].Batches = allocations
+		}
+
+		if _, err := dbConfigs.DATABASE.Collection("Sales").InsertOne(sessCtx, sale); err != nil {
+			return err
+		}
+
+		if err := applyCustomerSale(sessCtx, sale); err != nil {
+			return err
+		}
+
+		if cart != nil {
+			converted = *cart
+			functions.ConvertCart(&converted, sale.SaleID, time.Now().UTC())
+			return replaceCart(sessCtx, &converted, cart.Status)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if cart != nil {
+		*cart = converted
+	}
+	return nil
+}
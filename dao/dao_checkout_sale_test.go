@@ -0,0 +1,126 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// connectTestDatabase points dbConfigs at a throwaway database
+// Transactions need a replica set, so the test is skipped unless MONGO_TEST_URI is set
+func connectTestDatabase(t *testing.T) {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI not set; checkout transactions need a MongoDB replica set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	database := client.Database("POS_test_" + uuid.New().String()[:8])
+	for _, name := range []string{"Products", "Sales", "Stocks", "StockMovements"} {
+		if err := database.CreateCollection(ctx, name); err != nil {
+			t.Fatalf("create collection %s: %v", name, err)
+		}
+	}
+
+	previousClient, previousDatabase := dbConfigs.CLIENT, dbConfigs.DATABASE
+	dbConfigs.CLIENT, dbConfigs.DATABASE = client, database
+
+	t.Cleanup(func() {
+		database.Drop(context.Background())
+		client.Disconnect(context.Background())
+		dbConfigs.CLIENT, dbConfigs.DATABASE = previousClient, previousDatabase
+	})
+}
+
+func TestCheckoutSaleConcurrentLastUnit(t *testing.T) {
+	connectTestDatabase(t)
+
+	now := time.Now().UTC()
+	product := dto.Product{
+		ProductId:    "PRD-001",
+		Name:         "Last Unit",
+		SellingPrice: dto.MoneyFromFloat(100),
+		StockQty:     1,
+		Batches: []dto.Batch{
+			{BatchId: "BATCH-001", StockQty: 1, CostPrice: dto.MoneyFromFloat(60), SellingPrice: dto.MoneyFromFloat(100), CreatedAt: now, UpdatedAt: now},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := DB_CreateProduct(&product, dto.StockMovementRef{Type: dto.MovementProductCreate}); err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make([]error, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sale := &dto.Sale{
+				SaleID:        uuid.New().String(),
+				Items:         []dto.SaleItem{{ProductID: "PRD-001", ProductName: "Last Unit", Quantity: 1, UnitPrice: dto.MoneyFromFloat(100), TotalPrice: dto.MoneyFromFloat(100)}},
+				Subtotal:      dto.MoneyFromFloat(100),
+				Total:         dto.MoneyFromFloat(100),
+				PaymentMethod: "card",
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			}
+			<-start
+			results[i] = DB_CheckoutSale(sale, "", nil)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range results {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, functions.ErrInsufficientStock):
+			t.Fatalf("expected the losing checkout to fail with ErrInsufficientStock, got %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one checkout to succeed, got %d", succeeded)
+	}
+
+	ctx := context.Background()
+	salesCount, err := dbConfigs.DATABASE.Collection("Sales").CountDocuments(ctx, bson.M{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if salesCount != 1 {
+		t.Fatalf("expected one recorded sale, got %d", salesCount)
+	}
+
+	stored, err := GetProductByProductId("PRD-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.StockQty != 0 || len(stored.Batches) != 0 {
+		t.Fatalf("expected product to be sold out, got stockQty=%d batches=%d", stored.StockQty, len(stored.Batches))
+	}
+}
@@ -4,12 +4,50 @@ import (
 	"context"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func DB_CreateGRN(object *dto.GRN) error {
-	_, err := dbConfigs.DATABASE.Collection("GRNs").InsertOne(context.Background(), object)
+// DB_CreateGRN inserts a GRN; a GRN raised against a purchase order is linked to it in the same transaction
+// A GRN created in any status but pending has its received lines posted into stock in that transaction too
+func DB_CreateGRN(object *dto.GRN, userId string) error {
+	collection := dbConfigs.DATABASE.Collection("GRNs")
+	post := object.Status != "pending"
+
+	if object.PurchaseOrderId == "" && !post {
+		_, err := collection.InsertOne(context.Background(), object)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var created dto.GRN
+	err := runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		// Post a fresh copy on every attempt, a retried transaction must not see lines marked posted by the last one
+		created = *object
+		created.Items = append([]dto.GRNItem(nil), object.Items...)
+		if post {
+			if err := postGRN(sessCtx, &created, created.CreatedAt, userId); err != nil {
+				return err
+			}
+		}
+
+		if _, err := collection.InsertOne(sessCtx, &created); err != nil {
+			return err
+		}
+		if created.PurchaseOrderId != "" {
+			return receivePurchaseOrder(sessCtx, created.PurchaseOrderId, created.CreatedAt)
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	*object = created
 	return nil
 }
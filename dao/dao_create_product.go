@@ -4,13 +4,23 @@ import (
 	"context"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
+	"employee-crud/functions"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func DB_CreateProduct(object *dto.Product) error {
+// DB_CreateProduct inserts a product and records its opening stock in the StockMovements ledger
+func DB_CreateProduct(object *dto.Product, ref dto.StockMovementRef) error {
+	ctx := context.Background()
+
+	return runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		_, err := dbConfigs.DATABASE.Collection("Products").InsertOne(sessCtx, object)
+		if err != nil {
+			return err
+		}
 
-	_, err := dbConfigs.DATABASE.Collection("Products").InsertOne(context.Background(), object)
-	if err != nil {
-		return err
-	}
-	return nil
+		movements := functions.BuildStockMovements(nil, object, ref, time.Now().UTC())
+		return insertStockMovements(sessCtx, movements)
+	})
 }
@@ -0,0 +1,169 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DB_CreateSaleReturn records a return against an existing sale in a single transaction
+// Returned quantities are validated against the sale (minus earlier returns) and priced from it,
+// resellable lines marked for restock go back into a batch and damaged lines are recorded as write-offs
+// The returned share of the sale's spend and points is taken back off its customer
+// A sale that was already archived is moved back into Sales first
+// Returns mongo.ErrNoDocuments if the sale does not exist and functions.ErrInvalidReturn for bad quantities
+func DB_CreateSaleReturn(ret *dto.ReturnDTO, userId string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	requested := append([]dto.ReturnProduct(nil), ret.Products...)
+
+	return runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		salesCollection := dbConfigs.DATABASE.Collection("Sales")
+
+		var sale dto.Sale
+		err := salesCollection.FindOne(sessCtx, bson.M{"saleId": ret.SaleID}).Decode(&sale)
+		if err == mongo.ErrNoDocuments {
+			// Returns against archived sales bring the sale back; it is archived again later
+			archived, err := unarchiveSale(sessCtx, ret.SaleID)
+			if err != nil {
+				return err
+			}
+			sale = *archived
+		} else if err != nil {
+			return err
+		}
+
+		// Work on a fresh copy so a retried transaction starts from the request again
+		returnedBefore := functions.ReturnedSaleValue(&sale)
+		lines, totalRefund, err := functions.PrepareSaleReturn(&sale, append([]dto.ReturnProduct(nil), requested...))
+		if err != nil {
+			return err
+		}
+
+		// The customer loses the spend and points of what came back, so buying and returning earns nothing
+		reversal := functions.SaleReturnReversal(&sale, returnedBefore, totalRefund)
+		if err := returnCustomerSale(sessCtx, sale.CustomerID, reversal); err != nil {
+			return err
+		}
+
+		// Updating the sale makes concurrent returns against it conflict and retry
+		_, err = salesCollection.UpdateOne(sessCtx,
+			bson.M{"saleId": sale.SaleID},
+			bson.M{"$set": bson.M{"items": sale.Items, "updated_at": time.Now()}},
+		)
+		if err != nil {
+			return err
+		}
+
+		ref := dto.StockMovementRef{
+			Type:          dto.MovementReturn,
+			ReferenceType: dto.ReferenceReturn,
+			ReferenceId:   ret.ID,
+			UserId:        userId,
+		}
+
+		for i := range lines {
+			line := &lines[i]
+			line.WriteOffID = ""
+			switch {
+			case line.Condition == dto.ReturnConditionDamaged:
+				if err := writeOffReturnLine(sessCtx, line, ref); err != nil {
+					return err
+				}
+			case line.Restock:
+				soldFrom := sale.Items[*line.LineIndex].Batches
+				if err := restockReturnLine(sessCtx, line, soldFrom, functions.SaleLocation(&sale), ref); err != nil {
+					return err
+				}
+			}
+		}
+
+		ret.Products = lines
+		ret.TotalRefund = totalRefund
+		if ret.OriginalBillNumber == "" {
+			ret.OriginalBillNumber = sale.SaleID
+		}
+		if ret.CustomerName == "" {
+			ret.CustomerName = sale.CustomerName
+		}
+		if ret.ContactNumber == "" {
+			ret.ContactNumber = sale.MobileNumber
+		}
+
+		_, err = ReturnsCollection.InsertOne(sessCtx, ret)
+		return err
+	})
+}
+
+// restockReturnLine puts a resellable returned line back into stock at the sale's location, preferring a batch its
+// sale line was sold from (see functions.ApplyReturnRestock)
+func restockReturnLine(ctx context.Context, line *dto.ReturnProduct, soldFrom []dto.BatchAllocation, locationId string, ref dto.StockMovementRef) error {
+	ref.Note = line.Reason
+
+	newBatchId := batchIdGenerator(ctx)
+
+	var batchId string
+	product, err := updateProductBatches(ctx, line.ProductID, ref, func(product *dto.Product) error {
+		id, err := functions.ApplyReturnRestock(product, line.Quantity, line.BatchID, soldFrom, locationId, newBatchId, time.Now().UTC())
+		batchId = id
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restock product %s: %w", line.ProductID, err)
+	}
+
+	if err := syncSingleProductStock(ctx, product); err != nil {
+		return err
+	}
+
+	line.BatchID = batchId
+	return nil
+}
+
+// writeOffReturnLine records a damaged returned line in StockWriteOffs at cost
+// The goods never re-enter sellable stock, so batches are left untouched
+func writeOffReturnLine(ctx context.Context, line *dto.ReturnProduct, ref dto.StockMovementRef) error {
+	var product dto.Product
+	err := dbConfigs.DATABASE.Collection("Products").FindOne(ctx, bson.M{"productId": line.ProductID}).Decode(&product)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+
+	costPrice := product.CostPrice
+	for _, batch := range product.Batches {
+		if batch.BatchId == line.BatchID {
+			costPrice = batch.CostPrice
+			break
+		}
+	}
+
+	writeOff := dto.StockWriteOff{
+		WriteOffId:    uuid.New().String(),
+		ProductId:     line.ProductID,
+		ProductName:   line.ProductName,
+		Quantity:      line.Quantity,
+		CostPrice:     costPrice,
+		TotalCost:     costPrice.Times(line.Quantity),
+		Reason:        line.Reason,
+		ReferenceType: ref.ReferenceType,
+		ReferenceId:   ref.ReferenceId,
+		UserId:        ref.UserId,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if _, err := dbConfigs.DATABASE.Collection("StockWriteOffs").InsertOne(ctx, writeOff); err != nil {
+		return err
+	}
+
+	line.WriteOffID = writeOff.WriteOffId
+	line.Restock = false
+	return nil
+}
@@ -0,0 +1,229 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrMobileTaken is returned when another active customer already has the mobile number
+var ErrMobileTaken = errors.New("a customer with this mobile number already exists")
+
+func DB_CreateCustomer(customer *dto.Customer) error {
+	_, err := dbConfigs.DATABASE.Collection("Customers").InsertOne(context.Background(), customer)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrMobileTaken
+	}
+	return err
+}
+
+// DB_FindCustomerById returns mongo.ErrNoDocuments if the customer does not exist or was deleted
+func DB_FindCustomerById(customerId string) (*dto.Customer, error) {
+	return findCustomer(bson.M{"customerId": customerId, "deleted": false})
+}
+
+// DB_FindCustomerByMobile returns mongo.ErrNoDocuments if no active customer has the (normalized) mobile number
+func DB_FindCustomerByMobile(mobileNumber string) (*dto.Customer, error) {
+	return findCustomer(bson.M{"mobileNumber": mobileNumber, "deleted": false})
+}
+
+func findCustomer(filter bson.M) (*dto.Customer, error) {
+	var customer dto.Customer
+	err := dbConfigs.DATABASE.Collection("Customers").FindOne(context.Background(), filter).Decode(&customer)
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// DB_FindAllCustomers returns the active customers sorted by name, optionally only those whose name or mobile number contains search
+func DB_FindAllCustomers(search string) ([]dto.Customer, error) {
+	ctx := context.Background()
+
+	filter := bson.M{"deleted": false}
+	if search != "" {
+		pattern := regexp.QuoteMeta(search)
+		filter["$or"] = bson.A{
+			bson.M{"name": bson.M{"$regex": pattern, "$options": "i"}},
+			bson.M{"mobileNumber": bson.M{"$regex": pattern}},
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := dbConfigs.DATABASE.Collection("Customers").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	customers := []dto.Customer{}
+	if err := cursor.All(ctx, &customers); err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+// DB_UpdateCustomer changes a customer's contact details; loyalty points and spend are left alone
+// Returns mongo.ErrNoDocuments if the customer does not exist or was deleted
+func DB_UpdateCustomer(customer *dto.Customer) error {
+	result, err := dbConfigs.DATABASE.Collection("Customers").UpdateOne(context.Background(),
+		bson.M{"customerId": customer.CustomerID, "deleted": false},
+		bson.M{"$set": bson.M{
+			"name":         customer.Name,
+			"mobileNumber": customer.MobileNumber,
+			"email":        customer.Email,
+			"updated_at":   customer.UpdatedAt,
+		}},
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrMobileTaken
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DB_DeleteCustomer soft deletes a customer; their sales keep the customerId
+// Returns mongo.ErrNoDocuments if the customer does not exist or was already deleted
+func DB_DeleteCustomer(customerId string) error {
+	result, err := dbConfigs.DATABASE.Collection("Customers").UpdateOne(context.Background(),
+		bson.M{"customerId": customerId, "deleted": false},
+		bson.M{"$set": bson.M{"deleted": true, "updated_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// applyCustomerSale books a sale on its customer: points earned minus points redeemed, spend and sale count
+// The balance check is part of the update, so two checkouts cannot both spend the same points
+// Use a session context so the update commits with the sale
+func applyCustomerSale(ctx context.Context, sale *dto.Sale) error {
+	if sale.CustomerID == "" {
+		return nil
+	}
+
+	result, err := dbConfigs.DATABASE.Collection("Customers").UpdateOne(ctx,
+		bson.M{
+			"customerId":    sale.CustomerID,
+			"deleted":       false,
+			"loyaltyPoints": bson.M{"$gte": sale.PointsRedeemed},
+		},
+		bson.M{
+			"$inc": bson.M{
+				"loyaltyPoints": sale.PointsEarned - sale.PointsRedeemed,
+				"lifetimeSpend": sale.Total,
+				"saleCount":     1,
+			},
+			"$set": bson.M{"lastPurchaseAt": sale.CreatedAt, "updated_at": time.Now().UTC()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		if _, err := DB_FindCustomerById(sale.CustomerID); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: customer %s", functions.ErrInsufficientPoints, sale.CustomerID)
+	}
+	return nil
+}
+
+// reverseCustomerSale takes a voided sale back off its customer: points redeemed are refunded, points earned,
+// spend and the sale count are taken back. Points the customer already spent cannot be recovered, so the
+// balance stops at 0. Use a session context so the update commits with the void
+func reverseCustomerSale(ctx context.Context, sale *dto.Sale) error {
+	if sale.CustomerID == "" {
+		return nil
+	}
+
+	_, err := dbConfigs.DATABASE.Collection("Customers").UpdateOne(ctx,
+		bson.M{"customerId": sale.CustomerID},
+		bson.A{bson.M{"$set": bson.M{
+			"loyaltyPoints": bson.M{"$max": bson.A{0, bson.M{"$add": bson.A{"$loyaltyPoints", sale.PointsRedeemed - sale.PointsEarned}}}},
+			"lifetimeSpend": bson.M{"$subtract": bson.A{"$lifetimeSpend", sale.Total}},
+			"saleCount":     bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$saleCount", 1}}}},
+			"updated_at":    time.Now().UTC(),
+		}}},
+	)
+	return err
+}
+
+// returnCustomerSale takes a return's share of a sale back off its customer (see functions.SaleReturnReversal)
+// Like a void, the balance and sale count stop at 0. Use a session context so the update commits with the return
+func returnCustomerSale(ctx context.Context, customerId string, reversal functions.ReturnReversal) error {
+	if customerId == "" {
+		return nil
+	}
+
+	_, err := dbConfigs.DATABASE.Collection("Customers").UpdateOne(ctx,
+		bson.M{"customerId": customerId},
+		bson.A{bson.M{"$set": bson.M{
+			"loyaltyPoints": bson.M{"$max": bson.A{0, bson.M{"$add": bson.A{"$loyaltyPoints", reversal.PointsRedeemed - reversal.PointsEarned}}}},
+			"lifetimeSpend": bson.M{"$subtract": bson.A{"$lifetimeSpend", reversal.Spend}},
+			"saleCount":     bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$saleCount", reversal.SaleCount}}}},
+			"updated_at":    time.Now().UTC(),
+		}}},
+	)
+	return err
+}
+
+// DB_FindCustomerSales returns one page of a customer's sales, newest first, including archived sales
+// A customer's history is small enough to merge in memory; the live part uses the customerId index on Sales
+func DB_FindCustomerSales(customerId string, page int, limit int) ([]dto.Sale, int64, error) {
+	if page < 1 || limit < 1 {
+		return nil, 0, fmt.Errorf("page and limit must be positive")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cursor, err := dbConfigs.DATABASE.Collection("Sales").Find(ctx, bson.M{"customerId": customerId})
+	if err != nil {
+		return nil, 0, err
+	}
+	var sales []dto.Sale
+	if err := cursor.All(ctx, &sales); err != nil {
+		return nil, 0, err
+	}
+
+	archived, err := DB_FindArchivedSalesByCustomer(ctx, customerId)
+	if err != nil {
+		return nil, 0, err
+	}
+	sales = append(sales, archived...)
+
+	sort.SliceStable(sales, func(i, j int) bool {
+		return sales[i].CreatedAt.After(sales[j].CreatedAt)
+	})
+
+	total := int64(len(sales))
+	start := (page - 1) * limit
+	if start > len(sales) {
+		start = len(sales)
+	}
+	end := start + limit
+	if end > len(sales) {
+		end = len(sales)
+	}
+	return sales[start:end], total, nil
+}
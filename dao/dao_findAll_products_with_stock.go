@@ -2,8 +2,10 @@ package dao
 
 import (
 	"context"
+	"employee-crud/config"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
+	"employee-crud/functions"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -25,17 +27,37 @@ type ProductWithStockInfo struct {
 	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
-// CalculateProductStatus calculates the stock status based on PRODUCT's total stockQty
-func (p *ProductWithStockInfo) CalculateProductStatus(totalStockQty int) {
-	if totalStockQty < 10 {
+// CalculateProductStatus calculates the stock status based on PRODUCT's total stockQty and its thresholds
+func (p *ProductWithStockInfo) CalculateProductStatus(totalStockQty int, thresholds dto.StockThresholds) {
+	if totalStockQty < thresholds.LowThreshold {
 		p.ProductStatus = "Low"
-	} else if totalStockQty >= 10 && totalStockQty < 25 {
+	} else if totalStockQty < thresholds.AverageThreshold {
 		p.ProductStatus = "Average"
 	} else {
 		p.ProductStatus = "Good"
 	}
 }
 
+// calculateProductStatuses sets the status of each entry from its product's total stock and resolved thresholds
+func calculateProductStatuses(ctx context.Context, infos []ProductWithStockInfo) error {
+	productIds := make([]string, 0, len(infos))
+	for _, info := range infos {
+		productIds = append(productIds, info.ProductId)
+	}
+	thresholds, err := productStockThresholds(ctx, productIds)
+	if err != nil {
+		return err
+	}
+	for i := range infos {
+		productThresholds, ok := thresholds[infos[i].ProductId]
+		if !ok {
+			productThresholds = config.Get().Stock.Thresholds()
+		}
+		infos[i].CalculateProductStatus(infos[i].ProductStockQty, productThresholds)
+	}
+	return nil
+}
+
 // DB_FindAllProductsWithStock retrieves all products with their stock information
 // This includes products with and without batches
 func DB_FindAllProductsWithStock(limit int, cursor string) ([]ProductWithStockInfo, string, bool, error) {
@@ -77,9 +99,16 @@ func DB_FindAllProductsWithStock(limit int, cursor string) ([]ProductWithStockIn
 		return nil, "", false, err
 	}
 
+	categories, err := findCategoryStockThresholds(ctx)
+	if err != nil {
+		return nil, "", false, err
+	}
+	global := config.Get().Stock.Thresholds()
+
 	// Convert products to ProductWithStockInfo
 	var productsWithStock []ProductWithStockInfo
 	for _, product := range products {
+		thresholds, _ := functions.ResolveStockThresholds(&product, categories, global)
 		if len(product.Batches) > 0 {
 			// Product has batches - create entry for each batch
 			for _, batch := range product.Batches {
@@ -95,7 +124,7 @@ func DB_FindAllProductsWithStock(limit int, cursor string) ([]ProductWithStockIn
 					CreatedAt:       product.CreatedAt,
 					UpdatedAt:       product.UpdatedAt,
 				}
-				stockInfo.CalculateProductStatus(product.StockQty) // Use product's TOTAL stock
+				stockInfo.CalculateProductStatus(product.StockQty, thresholds) // Use product's TOTAL stock
 				productsWithStock = append(productsWithStock, stockInfo)
 			}
 		} else {
@@ -112,7 +141,7 @@ func DB_FindAllProductsWithStock(limit int, cursor string) ([]ProductWithStockIn
 				CreatedAt:       product.CreatedAt,
 				UpdatedAt:       product.UpdatedAt,
 			}
-			stockInfo.CalculateProductStatus(product.StockQty)
+			stockInfo.CalculateProductStatus(product.StockQty, thresholds)
 			productsWithStock = append(productsWithStock, stockInfo)
 		}
 	}
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"employee-crud/config"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
 	"encoding/base64"
@@ -62,11 +63,12 @@ func decodeCursor(cursor string) (*CursorData, error) {
 // DB_FindAllStocksCursorPaginated retrieves all stocks with cursor-based pagination
 // This is optimized for large datasets (10000+ records)
 // Uses compound cursor (updated_at + id) to handle duplicate timestamps
-func DB_FindAllStocksCursorPaginated(limit int, cursor string) ([]dto.Stock, string, bool, error) {
+// A non-empty locationId limits the listing to batches held at that location
+func DB_FindAllStocksCursorPaginated(limit int, cursor string, locationId string) ([]dto.Stock, string, bool, error) {
 	collection := dbConfigs.DATABASE.Collection("Stocks")
 	ctx := context.Background()
 
-	filter := bson.M{}
+	filter := stocksLocationFilter(locationId)
 
 	// If cursor is provided, add it to filter for cursor-based pagination
 	if cursor != "" {
@@ -118,9 +120,21 @@ func DB_FindAllStocksCursorPaginated(limit int, cursor string) ([]dto.Stock, str
 		return nil, "", false, err
 	}
 
-	// Calculate status for each stock
+	// Calculate status for each stock against the thresholds of its product
+	productIds := make([]string, 0, len(stocks))
+	for _, stock := range stocks {
+		productIds = append(productIds, stock.ProductId)
+	}
+	thresholds, err := productStockThresholds(ctx, productIds)
+	if err != nil {
+		return nil, "", false, err
+	}
 	for i := range stocks {
-		stocks[i].CalculateStatus()
+		productThresholds, ok := thresholds[stocks[i].ProductId]
+		if !ok {
+			productThresholds = config.Get().Stock.Thresholds()
+		}
+		stocks[i].CalculateStatus(productThresholds)
 	}
 
 	// Determine next cursor and if there are more pages
@@ -133,13 +147,12 @@ func DB_FindAllStocksCursorPaginated(limit int, cursor string) ([]dto.Stock, str
 		nextCursor = encodeCursor(lastStock.UpdatedAt, lastStock.ID)
 
 		// Check if there are more stocks after this cursor
-		checkFilter := bson.M{
-			"$or": []bson.M{
-				{"updated_at": bson.M{"$lt": lastStock.UpdatedAt}},
-				{
-					"updated_at": lastStock.UpdatedAt,
-					"_id":        bson.M{"$lt": lastStock.ID},
-				},
+		checkFilter := stocksLocationFilter(locationId)
+		checkFilter["$or"] = []bson.M{
+			{"updated_at": bson.M{"$lt": lastStock.UpdatedAt}},
+			{
+				"updated_at": lastStock.UpdatedAt,
+				"_id":        bson.M{"$lt": lastStock.ID},
 			},
 		}
 		count, err := collection.CountDocuments(ctx, checkFilter)
@@ -151,15 +164,23 @@ func DB_FindAllStocksCursorPaginated(limit int, cursor string) ([]dto.Stock, str
 	return stocks, nextCursor, hasMore, nil
 }
 
-// DB_GetStocksCount returns the total count of stocks
-func DB_GetStocksCount() (int64, error) {
+// DB_GetStocksCount returns the total count of stocks, at one location when locationId is set
+func DB_GetStocksCount(locationId string) (int64, error) {
 	collection := dbConfigs.DATABASE.Collection("Stocks")
 	ctx := context.Background()
 
-	count, err := collection.CountDocuments(ctx, bson.M{})
+	count, err := collection.CountDocuments(ctx, stocksLocationFilter(locationId))
 	if err != nil {
 		return 0, err
 	}
 
 	return count, nil
 }
+
+// stocksLocationFilter matches the Stocks entries held at a location, or every entry when locationId is empty
+func stocksLocationFilter(locationId string) bson.M {
+	if locationId == "" {
+		return bson.M{}
+	}
+	return bson.M{"locationId": locationMatch(locationId)}
+}
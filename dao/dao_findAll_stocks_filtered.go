@@ -2,39 +2,37 @@ package dao
 
 import (
 	"context"
+	"employee-crud/config"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
+	"employee-crud/functions"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// DB_FindAllStocksFilteredCursorPaginated retrieves ALL batches from products filtered by total stockQty
-// This filters PRODUCTS by their total stock status, then returns ALL their batches
+// DB_FindAllStocksFilteredCursorPaginated retrieves ALL batches from products filtered by total stock status
+// This filters PRODUCTS by the status of their total stockQty under their own, their category's or the global
+// thresholds, then returns ALL their batches
+// A non-empty locationId filters by the stock held at that location and returns only its batches
 // Parameters:
 //   - limit: number of records per page
 //   - cursor: cursor for pagination (updated_at timestamp)
-//   - minQty: minimum stock quantity (inclusive) - applied to PRODUCT total
-//   - maxQty: maximum stock quantity (inclusive, use -1 for no upper limit) - applied to PRODUCT total
-func DB_FindAllStocksFilteredCursorPaginated(limit int, cursor string, minQty int, maxQty int) ([]dto.Stock, string, bool, error) {
+//   - status: dto.StockStatusLow, dto.StockStatusAverage or dto.StockStatusGood - applied to PRODUCT total
+//   - locationId: location to filter by, empty for all locations
+func DB_FindAllStocksFilteredCursorPaginated(limit int, cursor string, status string, locationId string) ([]dto.Stock, string, bool, error) {
 	productsCollection := dbConfigs.DATABASE.Collection("Products")
 	ctx := context.Background()
 
-	// Build filter to find products by their TOTAL stockQty
-	filter := bson.M{"deleted": false}
-
-	// Add quantity filter on product's total stockQty
-	if maxQty == -1 {
-		// No upper limit (e.g., for "good" status)
-		filter["stockQty"] = bson.M{"$gte": minQty}
-	} else {
-		// Range filter
-		filter["stockQty"] = bson.M{
-			"$gte": minQty,
-			"$lte": maxQty,
-		}
+	categories, err := findCategoryStockThresholds(ctx)
+	if err != nil {
+		return nil, "", false, err
 	}
+	global := config.Get().Stock.Thresholds()
+
+	// Build filter to find products by the status of their TOTAL stockQty
+	filter := stockStatusFilter(status, categories, locationId)
 
 	// If cursor is provided, add it to filter for cursor-based pagination
 	if cursor != "" {
@@ -66,36 +64,52 @@ func DB_FindAllStocksFilteredCursorPaginated(limit int, cursor string, minQty in
 		return nil, "", false, err
 	}
 
-	// Convert products to stocks (include ALL batches from each product)
+	// Convert products to stocks (include ALL batches from each product, or those at the location)
 	var stocks []dto.Stock
 	for _, product := range products {
-		if len(product.Batches) > 0 {
-			// Product has batches - create stock entry for each batch
+		thresholds, _ := functions.ResolveStockThresholds(&product, categories, global)
+		batches := product.Batches
+		if locationId != "" {
+			batches = nil
 			for _, batch := range product.Batches {
+				if functions.BatchLocation(&batch) == locationId {
+					batches = append(batches, batch)
+				}
+			}
+		}
+		if len(batches) > 0 {
+			// Product has batches - create stock entry for each batch
+			for _, batch := range batches {
 				stock := dto.Stock{
 					ProductId:  product.ProductId,
 					Name:       product.Name,
 					BatchId:    batch.BatchId,
+					LocationId: functions.BatchLocation(&batch),
 					StockQty:   batch.StockQty,
 					ExpiryDate: batch.ExpiryDate,
 					CreatedAt:  batch.CreatedAt,
 					UpdatedAt:  batch.UpdatedAt,
 				}
-				stock.CalculateStatus()
+				stock.CalculateStatus(thresholds)
 				stocks = append(stocks, stock)
 			}
 		} else {
 			// Product has no batches - create single stock entry
+			stockQty := product.StockQty
+			if locationId != "" {
+				stockQty = functions.LocationStock(&product, locationId)
+			}
 			stock := dto.Stock{
 				ProductId:  product.ProductId,
 				Name:       product.Name,
 				BatchId:    "",
-				StockQty:   product.StockQty,
+				LocationId: locationId,
+				StockQty:   stockQty,
 				ExpiryDate: product.ExpiryDate,
 				CreatedAt:  product.CreatedAt,
 				UpdatedAt:  product.UpdatedAt,
 			}
-			stock.CalculateStatus()
+			stock.CalculateStatus(thresholds)
 			stocks = append(stocks, stock)
 		}
 	}
@@ -109,20 +123,8 @@ func DB_FindAllStocksFilteredCursorPaginated(limit int, cursor string, minQty in
 		nextCursor = lastProduct.UpdatedAt.Format("2006-01-02T15:04:05.000Z")
 
 		// Check if there are more products after this cursor with the same filter
-		checkFilter := bson.M{
-			"deleted":    false,
-			"updated_at": bson.M{"$lt": lastProduct.UpdatedAt},
-		}
-
-		// Apply the same quantity filter
-		if maxQty == -1 {
-			checkFilter["stockQty"] = bson.M{"$gte": minQty}
-		} else {
-			checkFilter["stockQty"] = bson.M{
-				"$gte": minQty,
-				"$lte": maxQty,
-			}
-		}
+		checkFilter := stockStatusFilter(status, categories, locationId)
+		checkFilter["updated_at"] = bson.M{"$lt": lastProduct.UpdatedAt}
 
 		count, err := productsCollection.CountDocuments(ctx, checkFilter)
 		if err == nil && count > 0 {
@@ -133,26 +135,18 @@ func DB_FindAllStocksFilteredCursorPaginated(limit int, cursor string, minQty in
 	return stocks, nextCursor, hasMore, nil
 }
 
-// DB_GetStocksCountFiltered returns the count of products filtered by quantity range
+// DB_GetStocksCountFiltered returns the count of products whose stock, in total or at a location, is in a status
 // This counts PRODUCTS, not individual batches
-func DB_GetStocksCountFiltered(minQty int, maxQty int) (int64, error) {
+func DB_GetStocksCountFiltered(status string, locationId string) (int64, error) {
 	collection := dbConfigs.DATABASE.Collection("Products")
 	ctx := context.Background()
 
-	// Build filter based on product's total stockQty
-	filter := bson.M{"deleted": false}
-	if maxQty == -1 {
-		// No upper limit
-		filter["stockQty"] = bson.M{"$gte": minQty}
-	} else {
-		// Range filter
-		filter["stockQty"] = bson.M{
-			"$gte": minQty,
-			"$lte": maxQty,
-		}
+	categories, err := findCategoryStockThresholds(ctx)
+	if err != nil {
+		return 0, err
 	}
 
-	count, err := collection.CountDocuments(ctx, filter)
+	count, err := collection.CountDocuments(ctx, stockStatusFilter(status, categories, locationId))
 	if err != nil {
 		return 0, err
 	}
@@ -102,7 +102,6 @@ func DB_FindTopExpiringStocksNext7Days(topN int, now, sevenDaysLater time.Time)
 					HasBatches:      false,
 					BatchCount:      0,
 				}
-				stockInfo.CalculateProductStatus(p.StockQty)
 				expiringStocks = append(expiringStocks, stockInfo)
 			}
 		} else {
@@ -118,7 +117,6 @@ func DB_FindTopExpiringStocksNext7Days(topN int, now, sevenDaysLater time.Time)
 						HasBatches:      true,
 						BatchCount:      len(p.Batches),
 					}
-					stockInfo.CalculateProductStatus(p.StockQty)
 					expiringStocks = append(expiringStocks, stockInfo)
 				}
 			}
@@ -136,11 +134,15 @@ func DB_FindTopExpiringStocksNext7Days(topN int, now, sevenDaysLater time.Time)
 				HasBatches:      false,
 				BatchCount:      0,
 			}
-			stockInfo.CalculateProductStatus(p.StockQty)
 			expiringStocks = append(expiringStocks, stockInfo)
 		}
 	}
 
+	// Statuses follow the thresholds of each product
+	if err := calculateProductStatuses(ctx, expiringStocks); err != nil {
+		return nil, err
+	}
+
 	// Sort by StockQty descending
 	if len(expiringStocks) > 1 {
 		for i := 0; i < len(expiringStocks)-1; i++ {
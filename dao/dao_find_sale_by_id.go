@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// FindSaleBySaleId returns a sale by saleId, looking in SalesArchive when it is no longer in Sales
 func FindSaleBySaleId(saleId string) (*dto.Sale, error) {
 	collection := dbConfigs.DATABASE.Collection("Sales")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -16,6 +18,9 @@ func FindSaleBySaleId(saleId string) (*dto.Sale, error) {
 
 	var sale dto.Sale
 	err := collection.FindOne(ctx, bson.M{"saleId": saleId}).Decode(&sale)
+	if err == mongo.ErrNoDocuments {
+		return DB_FindArchivedSale(ctx, saleId)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,21 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DB_FindSupplierById returns mongo.ErrNoDocuments if the supplier does not exist or is deleted
+func DB_FindSupplierById(supplierId string) (*dto.Supplier, error) {
+	var supplier dto.Supplier
+	err := dbConfigs.DATABASE.Collection("Suppliers").
+		FindOne(context.Background(), bson.M{"supplierId": supplierId, "deleted": false}).
+		Decode(&supplier)
+	if err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
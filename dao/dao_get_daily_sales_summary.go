@@ -4,15 +4,17 @@ import (
 	"context"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
-	"sort"
+	"employee-crud/functions"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// GetDailySalesSummary retrieves sales summary for a specific date
-func GetDailySalesSummary(targetDate time.Time) (*dto.DailySalesSummary, error) {
+// GetDailySalesSummary retrieves sales summary for a specific date, with its gross profit
+// Sales already moved to SalesArchive are included
+// A non-empty locationId summarizes only the sales made at that location
+func GetDailySalesSummary(targetDate time.Time, locationId string) (*dto.DailySalesSummary, error) {
 	collection := dbConfigs.DATABASE.Collection("Sales")
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -41,64 +43,19 @@ func GetDailySalesSummary(targetDate time.Time) (*dto.DailySalesSummary, error)
 		return nil, err
 	}
 
-	// Calculate summary
-	summary := &dto.DailySalesSummary{
-		ReportDate:   targetDate,
-		ProductsSold: make([]dto.ProductSoldSummary, 0),
-	}
-
-	// Map to aggregate product sales
-	productMap := make(map[string]*dto.ProductSoldSummary)
-
-	// Process each sale
-	for _, sale := range sales {
-		summary.TotalSales++
-		summary.TotalRevenue += sale.Total
-		summary.TotalDiscount += sale.Discount
-		summary.TotalTax += sale.Tax
-
-		// Count payment methods
-		if sale.PaymentMethod == "cash" {
-			summary.CashSales++
-			summary.CashRevenue += sale.Total
-		} else if sale.PaymentMethod == "card" {
-			summary.CardSales++
-			summary.CardRevenue += sale.Total
-		}
-
-		// Aggregate product sales
-		for _, item := range sale.Items {
-			if existing, exists := productMap[item.ProductID]; exists {
-				existing.Quantity += item.Quantity
-				existing.TotalAmount += item.TotalPrice
-			} else {
-				productMap[item.ProductID] = &dto.ProductSoldSummary{
-					ProductID:   item.ProductID,
-					ProductName: item.ProductName,
-					Quantity:    item.Quantity,
-					UnitPrice:   item.UnitPrice,
-					TotalAmount: item.TotalPrice,
-				}
-			}
-		}
-	}
-
-	// Convert map to slice
-	for _, product := range productMap {
-		summary.ProductsSold = append(summary.ProductsSold, *product)
+	archived, err := DB_FindArchivedSalesBetween(ctx, startOfDay, endOfDay)
+	if err != nil {
+		return nil, err
 	}
+	sales = functions.SalesAtLocation(append(sales, archived...), locationId)
 
-	// Sort products by total amount (descending)
-	sort.Slice(summary.ProductsSold, func(i, j int) bool {
-		return summary.ProductsSold[i].TotalAmount > summary.ProductsSold[j].TotalAmount
-	})
-
-	// Get top 10 selling items
-	topCount := 10
-	if len(summary.ProductsSold) < topCount {
-		topCount = len(summary.ProductsSold)
+	products, err := findSoldProducts(ctx, sales)
+	if err != nil {
+		return nil, err
 	}
-	summary.TopSellingItems = summary.ProductsSold[:topCount]
 
+	summary := functions.SummarizeSales(targetDate, sales)
+	summary.Profit = functions.SummarizeProfit(sales, products, nil)
+	summary.LocationId = locationId
 	return summary, nil
 }
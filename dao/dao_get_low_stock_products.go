@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"employee-crud/config"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
 
@@ -10,11 +11,17 @@ import (
 )
 
 // DB_GetTopLowStockProducts returns the top N products with the lowest stock quantity (excluding deleted)
+// among those below their own, their category's or the global low threshold
 func DB_GetTopLowStockProducts(limit int) ([]dto.Product, error) {
 	collection := dbConfigs.DATABASE.Collection("Products")
 	ctx := context.Background()
 
-	filter := bson.M{"deleted": false, "stockQty": bson.M{"$lt": 10}}
+	categories, err := findCategoryStockThresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	low := stockThresholdExpr("lowThreshold", categories, config.Get().Stock.Thresholds().LowThreshold)
+	filter := bson.M{"deleted": false, "$expr": bson.M{"$lt": []interface{}{"$stockQty", low}}}
 	findOptions := options.Find()
 	findOptions.SetLimit(int64(limit))
 	findOptions.SetSort(bson.D{
@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"employee-crud/dbConfigs"
+	"employee-crud/dto"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -21,34 +22,19 @@ func DB_GetStockStatusCounts() (*StockStatusCounts, error) {
 	collection := dbConfigs.DATABASE.Collection("Products")
 	ctx := context.Background()
 
-	// MongoDB aggregation pipeline to categorize and count products by stock status
+	categories, err := findCategoryStockThresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// MongoDB aggregation pipeline to categorize and count products by stock status,
+	// each against its own, its category's or the global thresholds
 	pipeline := []bson.M{
 		{
 			"$match": bson.M{"deleted": false}, // Only non-deleted products
 		},
 		{
-			"$project": bson.M{
-				"status": bson.M{
-					"$switch": bson.M{
-						"branches": []bson.M{
-							{
-								"case": bson.M{"$lt": []interface{}{"$stockQty", 10}},
-								"then": "Low Stock",
-							},
-							{
-								"case": bson.M{
-									"$and": []bson.M{
-										{"$gte": []interface{}{"$stockQty", 10}},
-										{"$lt": []interface{}{"$stockQty", 25}},
-									},
-								},
-								"then": "Average Stock",
-							},
-						},
-						"default": "Good Stock",
-					},
-				},
-			},
+			"$project": bson.M{"status": stockStatusExpr(categories, "")},
 		},
 		{
 			"$group": bson.M{
@@ -84,11 +70,11 @@ func DB_GetStockStatusCounts() (*StockStatusCounts, error) {
 	// Map results to counts
 	for _, result := range results {
 		switch result.ID {
-		case "Low Stock":
+		case dto.StockStatusLow:
 			counts.LowStock = result.Count
-		case "Average Stock":
+		case dto.StockStatusAverage:
 			counts.AverageStock = result.Count
-		case "Good Stock":
+		case dto.StockStatusGood:
 			counts.GoodStock = result.Count
 		}
 		counts.Total += result.Count
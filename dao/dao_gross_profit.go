@@ -0,0 +1,106 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/config"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DB_GetGrossProfitReport works out the gross profit of the sales created in [start, end),
+// archived ones included, by product, brand, category and business day
+// A non-empty locationId limits it to the sales made at that location
+func DB_GetGrossProfitReport(start time.Time, end time.Time, locationId string) (*dto.GrossProfitReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cursor, err := dbConfigs.DATABASE.Collection("Sales").Find(ctx, bson.M{
+		"created_at": bson.M{"$gte": start, "$lt": end},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var sales []dto.Sale
+	if err := cursor.All(ctx, &sales); err != nil {
+		return nil, err
+	}
+
+	archived, err := DB_FindArchivedSalesBetween(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	sales = functions.SalesAtLocation(append(sales, archived...), locationId)
+
+	products, err := findSoldProducts(ctx, sales)
+	if err != nil {
+		return nil, err
+	}
+
+	report := functions.SummarizeProfit(sales, products, config.Location())
+	return &report, nil
+}
+
+// findSoldProducts returns the products sold in the sales by id, deleted ones included,
+// with their brand and category names
+func findSoldProducts(ctx context.Context, sales []dto.Sale) (map[string]*dto.Product, error) {
+	seen := make(map[string]bool)
+	productIds := []string{}
+	for _, sale := range sales {
+		for _, item := range sale.Items {
+			if !seen[item.ProductID] {
+				seen[item.ProductID] = true
+				productIds = append(productIds, item.ProductID)
+			}
+		}
+	}
+
+	products := make(map[string]*dto.Product, len(productIds))
+	if len(productIds) == 0 {
+		return products, nil
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"productId": bson.M{"$in": productIds}}},
+		{"$project": bson.M{"batches": 0}},
+		{
+			"$lookup": bson.M{
+				"from":         "Categories",
+				"localField":   "categoryId",
+				"foreignField": "categoryId",
+				"as":           "categoryInfo",
+			},
+		},
+		{
+			"$lookup": bson.M{
+				"from":         "Brands",
+				"localField":   "brandId",
+				"foreignField": "brandId",
+				"as":           "brandInfo",
+			},
+		},
+		{
+			"$addFields": bson.M{
+				"categoryName": bson.M{"$arrayElemAt": []interface{}{"$categoryInfo.name", 0}},
+				"brandName":    bson.M{"$arrayElemAt": []interface{}{"$brandInfo.name", 0}},
+			},
+		},
+		{"$project": bson.M{"categoryInfo": 0, "brandInfo": 0}},
+	}
+
+	cursor, err := dbConfigs.DATABASE.Collection("Products").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var found []dto.Product
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+	for i := range found {
+		products[found[i].ProductId] = &found[i]
+	}
+	return products, nil
+}
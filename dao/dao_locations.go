@@ -0,0 +1,100 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DB_CreateLocation inserts a new store or warehouse
+func DB_CreateLocation(location *dto.Location) error {
+	_, err := dbConfigs.DATABASE.Collection("Locations").InsertOne(context.Background(), location)
+	return err
+}
+
+// DB_EnsureMainLocation creates the main location on a database without one; an existing one is left as it is
+func DB_EnsureMainLocation(name string) error {
+	now := time.Now().UTC()
+	_, err := dbConfigs.DATABASE.Collection("Locations").UpdateOne(context.Background(),
+		bson.M{"locationId": dto.MainLocationId},
+		bson.M{"$setOnInsert": dto.Location{
+			LocationId: dto.MainLocationId,
+			Name:       name,
+			Type:       dto.LocationStore,
+			Status:     "active",
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// DB_FindLocationById returns mongo.ErrNoDocuments if the location does not exist
+func DB_FindLocationById(locationId string) (*dto.Location, error) {
+	var location dto.Location
+	err := dbConfigs.DATABASE.Collection("Locations").FindOne(context.Background(), bson.M{"locationId": locationId}).Decode(&location)
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// DB_FindLocations returns the locations in a status by name; an empty status matches every status
+func DB_FindLocations(status string) ([]dto.Location, error) {
+	ctx := context.Background()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := dbConfigs.DATABASE.Collection("Locations").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	locations := []dto.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// DB_UpdateLocation saves a location's name, type, address, phone and status
+// Returns mongo.ErrNoDocuments if it does not exist
+func DB_UpdateLocation(location *dto.Location) error {
+	result, err := dbConfigs.DATABASE.Collection("Locations").UpdateOne(context.Background(),
+		bson.M{"locationId": location.LocationId},
+		bson.M{"$set": bson.M{
+			"name":       location.Name,
+			"type":       location.Type,
+			"address":    location.Address,
+			"phone":      location.Phone,
+			"status":     location.Status,
+			"updated_at": location.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// locationMatch is the filter value matching documents of a location in their locationId field
+// Documents recorded before locations have none and belong to the main location
+func locationMatch(locationId string) interface{} {
+	if locationId == dto.MainLocationId {
+		return bson.M{"$in": bson.A{nil, "", dto.MainLocationId}}
+	}
+	return locationId
+}
@@ -0,0 +1,100 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func DB_CreatePromotion(promotion *dto.Promotion) error {
+	_, err := dbConfigs.DATABASE.Collection("Promotions").InsertOne(context.Background(), promotion)
+	return err
+}
+
+// DB_FindPromotionById returns mongo.ErrNoDocuments if the promotion does not exist or was deleted
+func DB_FindPromotionById(promotionId string) (*dto.Promotion, error) {
+	var promotion dto.Promotion
+	err := dbConfigs.DATABASE.Collection("Promotions").FindOne(context.Background(),
+		bson.M{"promotionId": promotionId, "deleted": false},
+	).Decode(&promotion)
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// DB_FindAllPromotions returns the promotions that are not deleted, highest priority first
+func DB_FindAllPromotions(activeOnly bool) ([]dto.Promotion, error) {
+	filter := bson.M{"deleted": false}
+	if activeOnly {
+		filter["active"] = true
+	}
+	return findPromotions(filter)
+}
+
+// DB_FindRunningPromotions returns the active promotions whose startsAt/endsAt include now
+// Days of the week and daily time windows are not checked here
+func DB_FindRunningPromotions(now time.Time) ([]dto.Promotion, error) {
+	return findPromotions(bson.M{
+		"deleted": false,
+		"active":  true,
+		"$and": bson.A{
+			bson.M{"$or": bson.A{bson.M{"startsAt": nil}, bson.M{"startsAt": bson.M{"$lte": now}}}},
+			bson.M{"$or": bson.A{bson.M{"endsAt": nil}, bson.M{"endsAt": bson.M{"$gt": now}}}},
+		},
+	})
+}
+
+func findPromotions(filter bson.M) ([]dto.Promotion, error) {
+	ctx := context.Background()
+
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "promotionId", Value: 1}})
+	cursor, err := dbConfigs.DATABASE.Collection("Promotions").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	promotions := []dto.Promotion{}
+	if err := cursor.All(ctx, &promotions); err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// DB_UpdatePromotion replaces a promotion's rule and conditions
+// Returns mongo.ErrNoDocuments if the promotion does not exist or was deleted
+func DB_UpdatePromotion(promotion *dto.Promotion) error {
+	result, err := dbConfigs.DATABASE.Collection("Promotions").ReplaceOne(context.Background(),
+		bson.M{"promotionId": promotion.PromotionID, "deleted": false},
+		promotion,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DB_DeletePromotion soft deletes a promotion, so sales that used it still resolve its id
+// Returns mongo.ErrNoDocuments if the promotion does not exist or was already deleted
+func DB_DeletePromotion(promotionId string) error {
+	result, err := dbConfigs.DATABASE.Collection("Promotions").UpdateOne(context.Background(),
+		bson.M{"promotionId": promotionId, "deleted": false},
+		bson.M{"$set": bson.M{"deleted": true, "active": false, "updated_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrPurchaseOrderStatusChanged is returned when a purchase order is no longer in the status a change expects,
+// e.g. a GRN moved it to partially_received in the meantime
+var ErrPurchaseOrderStatusChanged = errors.New("purchase order status changed")
+
+func DB_CreatePurchaseOrder(po *dto.PurchaseOrder) error {
+	_, err := dbConfigs.DATABASE.Collection("PurchaseOrders").InsertOne(context.Background(), po)
+	return err
+}
+
+// DB_FindPurchaseOrderById returns mongo.ErrNoDocuments if the purchase order does not exist
+func DB_FindPurchaseOrderById(poId string) (*dto.PurchaseOrder, error) {
+	var po dto.PurchaseOrder
+	err := dbConfigs.DATABASE.Collection("PurchaseOrders").FindOne(context.Background(), bson.M{"poId": poId}).Decode(&po)
+	if err != nil {
+		return nil, err
+	}
+	return &po, nil
+}
+
+// DB_FindPurchaseOrders returns the purchase orders of a supplier in a status, newest first
+// An empty supplierId or status matches every supplier or status
+func DB_FindPurchaseOrders(supplierId string, status string) ([]dto.PurchaseOrder, error) {
+	ctx := context.Background()
+
+	filter := bson.M{}
+	if supplierId != "" {
+		filter["supplierId"] = supplierId
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := dbConfigs.DATABASE.Collection("PurchaseOrders").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	orders := []dto.PurchaseOrder{}
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// DB_UpdatePurchaseOrder saves the number, lines, total, expected date and notes of a purchase order
+// that is still in one of fromStatuses
+// Returns mongo.ErrNoDocuments if it does not exist, ErrPurchaseOrderStatusChanged if it is in another status
+func DB_UpdatePurchaseOrder(po *dto.PurchaseOrder, fromStatuses ...string) error {
+	return updatePurchaseOrder(po.POId, fromStatuses, bson.M{
+		"poNumber":     po.PONumber,
+		"items":        po.Items,
+		"totalAmount":  po.TotalAmount,
+		"expectedDate": po.ExpectedDate,
+		"notes":        po.Notes,
+		"updated_at":   po.UpdatedAt,
+	})
+}
+
+// DB_UpdatePurchaseOrderStatus saves the status of a purchase order and who approved, sent or closed it
+// Received quantities are left alone, they are only ever recounted from GRNs
+func DB_UpdatePurchaseOrderStatus(po *dto.PurchaseOrder, fromStatuses ...string) error {
+	return updatePurchaseOrder(po.POId, fromStatuses, bson.M{
+		"status":     po.Status,
+		"approvedBy": po.ApprovedBy,
+		"approvedAt": po.ApprovedAt,
+		"sentAt":     po.SentAt,
+		"closedAt":   po.ClosedAt,
+		"updated_at": po.UpdatedAt,
+	})
+}
+
+func updatePurchaseOrder(poId string, fromStatuses []string, set bson.M) error {
+	collection := dbConfigs.DATABASE.Collection("PurchaseOrders")
+
+	result, err := collection.UpdateOne(context.Background(),
+		bson.M{"poId": poId, "status": bson.M{"$in": fromStatuses}},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	count, err := collection.CountDocuments(context.Background(), bson.M{"poId": poId})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return ErrPurchaseOrderStatusChanged
+}
+
+// DB_FindGRNsByPurchaseOrder returns the GRNs raised against a purchase order, oldest first
+func DB_FindGRNsByPurchaseOrder(poId string) ([]dto.GRN, error) {
+	return findGRNsByPurchaseOrder(context.Background(), poId)
+}
+
+func findGRNsByPurchaseOrder(ctx context.Context, poId string) ([]dto.GRN, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := dbConfigs.DATABASE.Collection("GRNs").Find(ctx, bson.M{"purchaseOrderId": poId, "deleted": false}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	grns := []dto.GRN{}
+	if err := cursor.All(ctx, &grns); err != nil {
+		return nil, err
+	}
+	return grns, nil
+}
+
+// receivePurchaseOrder recounts a purchase order's received quantities and status from its GRNs
+// It runs inside the transaction that creates or posts one of those GRNs, so the PO always agrees with them
+func receivePurchaseOrder(ctx context.Context, poId string, now time.Time) error {
+	collection := dbConfigs.DATABASE.Collection("PurchaseOrders")
+
+	var po dto.PurchaseOrder
+	if err := collection.FindOne(ctx, bson.M{"poId": poId}).Decode(&po); err != nil {
+		return err
+	}
+	grns, err := findGRNsByPurchaseOrder(ctx, poId)
+	if err != nil {
+		return err
+	}
+
+	functions.ReceivePurchaseOrder(&po, grns, now)
+	_, err = collection.UpdateOne(ctx, bson.M{"poId": poId}, bson.M{"$set": bson.M{
+		"items":      po.Items,
+		"grnIds":     po.GRNIds,
+		"status":     po.Status,
+		"closedAt":   po.ClosedAt,
+		"updated_at": now,
+	}})
+	return err
+}
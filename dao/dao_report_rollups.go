@@ -0,0 +1,120 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/config"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DB_SaveReportRollup inserts or replaces the rollup for its period, keeping the original createdAt
+func DB_SaveReportRollup(rollup *dto.ReportRollup) error {
+	collection := dbConfigs.DATABASE.Collection("ReportRollups")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"period": rollup.Period, "year": rollup.Year, "month": rollup.Month}
+	update := bson.M{
+		"$set": bson.M{
+			"periodStart":     rollup.PeriodStart,
+			"periodEnd":       rollup.PeriodEnd,
+			"daysCovered":     rollup.DaysCovered,
+			"monthsCovered":   rollup.MonthsCovered,
+			"totalSales":      rollup.TotalSales,
+			"totalRevenue":    rollup.TotalRevenue,
+			"totalDiscount":   rollup.TotalDiscount,
+			"totalTax":        rollup.TotalTax,
+			"taxBreakdown":    rollup.TaxBreakdown,
+			"tenders":         rollup.Tenders,
+			"productsSold":    rollup.ProductsSold,
+			"topSellingItems": rollup.TopSellingItems,
+			"updatedAt":       rollup.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"createdAt": rollup.CreatedAt},
+	}
+
+	_, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// DB_FindReportRollup returns the rollup of one period (month is 0 for yearly rollups)
+func DB_FindReportRollup(period string, year int, month int) (*dto.ReportRollup, error) {
+	collection := dbConfigs.DATABASE.Collection("ReportRollups")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var rollup dto.ReportRollup
+	err := collection.FindOne(ctx, bson.M{"period": period, "year": year, "month": month}).Decode(&rollup)
+	if err != nil {
+		return nil, err
+	}
+	return &rollup, nil
+}
+
+// DB_FindReportRollups returns the rollups of one period type starting in [from, to], oldest first
+func DB_FindReportRollups(period string, from time.Time, to time.Time) ([]dto.ReportRollup, error) {
+	collection := dbConfigs.DATABASE.Collection("ReportRollups")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"period":      period,
+		"periodStart": bson.M{"$gte": from, "$lte": to},
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "periodStart", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rollups := []dto.ReportRollup{}
+	if err := cursor.All(ctx, &rollups); err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// DB_RefreshReportRollups rebuilds the month's rollup from its saved daily reports, then the year's rollup
+// A month whose daily reports have (partly) expired keeps its existing rollup, so history is never lost
+// Returns mongo.ErrNoDocuments if the month has neither daily reports nor a rollup
+func DB_RefreshReportRollups(year int, month int) (*dto.ReportRollup, error) {
+	reports, err := GetDailyReportsByMonth(year, month, "")
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := DB_FindReportRollup(dto.RollupMonthly, year, month)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	now := time.Now().In(config.Location())
+	monthly := functions.BuildMonthlyRollup(year, month, reports, config.Location(), now)
+	if existing != nil && existing.DaysCovered > monthly.DaysCovered {
+		monthly = existing
+	} else if len(reports) == 0 {
+		return nil, mongo.ErrNoDocuments
+	} else if err := DB_SaveReportRollup(monthly); err != nil {
+		return nil, err
+	}
+
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, config.Location())
+	months, err := DB_FindReportRollups(dto.RollupMonthly, yearStart, yearStart.AddDate(1, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	yearly := functions.BuildYearlyRollup(year, months, config.Location(), now)
+	if err := DB_SaveReportRollup(yearly); err != nil {
+		return nil, err
+	}
+
+	return monthly, nil
+}
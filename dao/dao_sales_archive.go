@@ -0,0 +1,229 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/config"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// archiveBatchSize bounds how many sales are read into memory per archiving pass
+const archiveBatchSize = 2000
+
+// DB_ArchiveSalesBefore moves every sale created before cutoff from Sales into SalesArchive
+// Sales are grouped per business day into one compressed document; each day is moved in its own transaction,
+// so a sale is always in exactly one of the two collections. Returns the number of sales archived
+func DB_ArchiveSalesBefore(cutoff time.Time) (int, error) {
+	salesCollection := dbConfigs.DATABASE.Collection("Sales")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	archived := 0
+	for {
+		findOptions := options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: 1}}).
+			SetLimit(archiveBatchSize)
+
+		cursor, err := salesCollection.Find(ctx, bson.M{"created_at": bson.M{"$lt": cutoff}}, findOptions)
+		if err != nil {
+			return archived, err
+		}
+		var sales []dto.Sale
+		if err := cursor.All(ctx, &sales); err != nil {
+			return archived, err
+		}
+		if len(sales) == 0 {
+			return archived, nil
+		}
+
+		days := functions.GroupSalesByDay(sales, config.Location())
+		dayKeys := make([]time.Time, 0, len(days))
+		for day := range days {
+			dayKeys = append(dayKeys, day)
+		}
+		sort.Slice(dayKeys, func(i, j int) bool { return dayKeys[i].Before(dayKeys[j]) })
+
+		for _, day := range dayKeys {
+			daySales := days[day]
+			err := runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+				return archiveDay(sessCtx, day, daySales)
+			})
+			if err != nil {
+				return archived, err
+			}
+			archived += len(daySales)
+		}
+
+		if len(sales) < archiveBatchSize {
+			return archived, nil
+		}
+	}
+}
+
+// archiveDay merges sales into the day's archive document and removes them from Sales
+func archiveDay(ctx context.Context, day time.Time, sales []dto.Sale) error {
+	archiveCollection := dbConfigs.DATABASE.Collection("SalesArchive")
+
+	merged := sales
+	var existing dto.SalesArchive
+	err := archiveCollection.FindOne(ctx, bson.M{"day": day.UTC()}).Decode(&existing)
+	switch err {
+	case nil:
+		previous, err := functions.DecompressSales(&existing)
+		if err != nil {
+			return err
+		}
+		merged = append(previous, sales...)
+	case mongo.ErrNoDocuments:
+	default:
+		return err
+	}
+
+	if err := saveArchive(ctx, day, merged); err != nil {
+		return err
+	}
+
+	saleIds := make([]string, 0, len(sales))
+	for _, sale := range sales {
+		saleIds = append(saleIds, sale.SaleID)
+	}
+	_, err = dbConfigs.DATABASE.Collection("Sales").DeleteMany(ctx, bson.M{"saleId": bson.M{"$in": saleIds}})
+	return err
+}
+
+// saveArchive replaces the day's archive document with one holding sales, or removes it when sales is empty
+func saveArchive(ctx context.Context, day time.Time, sales []dto.Sale) error {
+	archiveCollection := dbConfigs.DATABASE.Collection("SalesArchive")
+	filter := bson.M{"day": day.UTC()}
+
+	if len(sales) == 0 {
+		_, err := archiveCollection.DeleteOne(ctx, filter)
+		return err
+	}
+
+	archive, err := functions.BuildSalesArchive(day, sales, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	_, err = archiveCollection.ReplaceOne(ctx, filter, archive, options.Replace().SetUpsert(true))
+	return err
+}
+
+// DB_FindArchivedSale returns an archived sale by saleId, or mongo.ErrNoDocuments
+func DB_FindArchivedSale(ctx context.Context, saleId string) (*dto.Sale, error) {
+	var archive dto.SalesArchive
+	err := dbConfigs.DATABASE.Collection("SalesArchive").FindOne(ctx, bson.M{"saleIds": saleId}).Decode(&archive)
+	if err != nil {
+		return nil, err
+	}
+
+	sales, err := functions.DecompressSales(&archive)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sales {
+		if sales[i].SaleID == saleId {
+			return &sales[i], nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+// DB_FindArchivedSalesBetween returns the archived sales created in [start, end)
+func DB_FindArchivedSalesBetween(ctx context.Context, start time.Time, end time.Time) ([]dto.Sale, error) {
+	filter := bson.M{
+		"firstSaleAt": bson.M{"$lt": end},
+		"lastSaleAt":  bson.M{"$gte": start},
+	}
+	cursor, err := dbConfigs.DATABASE.Collection("SalesArchive").Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "day", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var archives []dto.SalesArchive
+	if err := cursor.All(ctx, &archives); err != nil {
+		return nil, err
+	}
+
+	var sales []dto.Sale
+	for i := range archives {
+		daySales, err := functions.DecompressSales(&archives[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, sale := range daySales {
+			if !sale.CreatedAt.Before(start) && sale.CreatedAt.Before(end) {
+				sales = append(sales, sale)
+			}
+		}
+	}
+	return sales, nil
+}
+
+// DB_FindArchivedSalesByCustomer returns every archived sale of a registered customer
+func DB_FindArchivedSalesByCustomer(ctx context.Context, customerId string) ([]dto.Sale, error) {
+	cursor, err := dbConfigs.DATABASE.Collection("SalesArchive").Find(ctx, bson.M{"customerIds": customerId})
+	if err != nil {
+		return nil, err
+	}
+	var archives []dto.SalesArchive
+	if err := cursor.All(ctx, &archives); err != nil {
+		return nil, err
+	}
+
+	var sales []dto.Sale
+	for i := range archives {
+		daySales, err := functions.DecompressSales(&archives[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, sale := range daySales {
+			if sale.CustomerID == customerId {
+				sales = append(sales, sale)
+			}
+		}
+	}
+	return sales, nil
+}
+
+// unarchiveSale moves an archived sale back into Sales so it can be changed (e.g. by a return)
+// Use a session context so the move commits with the caller's transaction
+func unarchiveSale(ctx context.Context, saleId string) (*dto.Sale, error) {
+	var archive dto.SalesArchive
+	err := dbConfigs.DATABASE.Collection("SalesArchive").FindOne(ctx, bson.M{"saleIds": saleId}).Decode(&archive)
+	if err != nil {
+		return nil, err
+	}
+
+	sales, err := functions.DecompressSales(&archive)
+	if err != nil {
+		return nil, err
+	}
+
+	var sale *dto.Sale
+	remaining := make([]dto.Sale, 0, len(sales))
+	for i := range sales {
+		if sales[i].SaleID == saleId {
+			sale = &sales[i]
+			continue
+		}
+		remaining = append(remaining, sales[i])
+	}
+	if sale == nil {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	if err := saveArchive(ctx, archive.Day, remaining); err != nil {
+		return nil, err
+	}
+	if _, err := dbConfigs.DATABASE.Collection("Sales").InsertOne(ctx, sale); err != nil {
+		return nil, err
+	}
+	return sale, nil
+}
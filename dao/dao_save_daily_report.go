@@ -2,8 +2,10 @@ package dao
 
 import (
 	"context"
+	"employee-crud/config"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
+	"employee-crud/functions"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -11,50 +13,36 @@ import (
 )
 
 // SaveDailyReport saves the daily sales report to the database
+// Each location has its own report per day next to the one for the whole business
 func SaveDailyReport(summary *dto.DailySalesSummary) error {
 	collection := dbConfigs.DATABASE.Collection("DailyReports")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Calculate expiration date (end of the month + 1 day)
-	// For example, October report expires on November 1st 00:00:00
+	// Calculate expiration date: the first day of the month after the retention period
+	// With the default retention of 1 month, an October report expires on November 1st 00:00:00
 	reportDate := summary.ReportDate
-	year := reportDate.Year()
-	month := reportDate.Month()
-
-	// Get the first day of next month
-	var expiresAt time.Time
-	if month == 12 {
-		expiresAt = time.Date(year+1, 1, 1, 0, 0, 0, 0, time.FixedZone("Asia/Colombo", 5*3600+30*60))
-	} else {
-		expiresAt = time.Date(year, month+1, 1, 0, 0, 0, 0, time.FixedZone("Asia/Colombo", 5*3600+30*60))
+	retentionMonths := config.Get().TTL.DailyReportRetentionMonths
+	expiresAt := time.Date(reportDate.Year(), reportDate.Month()+time.Month(retentionMonths), 1, 0, 0, 0, 0, config.Location())
+
+	// A report saved just after its month ends would expire immediately; keep it for a day so the
+	// monthly rollup refresh that follows the save can still include it
+	if minExpiry := time.Now().AddDate(0, 0, 1); expiresAt.Before(minExpiry) {
+		expiresAt = minExpiry
 	}
 
 	// Create document
-	report := dto.DailyReportDocument{
-		ReportDate:      summary.ReportDate,
-		Month:           int(summary.ReportDate.Month()),
-		Year:            summary.ReportDate.Year(),
-		TotalSales:      summary.TotalSales,
-		TotalRevenue:    summary.TotalRevenue,
-		TotalDiscount:   summary.TotalDiscount,
-		TotalTax:        summary.TotalTax,
-		CashSales:       summary.CashSales,
-		CardSales:       summary.CardSales,
-		CashRevenue:     summary.CashRevenue,
-		CardRevenue:     summary.CardRevenue,
-		ProductsSold:    summary.ProductsSold,
-		TopSellingItems: summary.TopSellingItems,
-		CreatedAt:       time.Now().In(time.FixedZone("Asia/Colombo", 5*3600+30*60)),
-		ExpiresAt:       expiresAt,
-	}
+	report := functions.BuildDailyReport(summary)
+	report.CreatedAt = time.Now().In(config.Location())
+	report.ExpiresAt = expiresAt
 
-	// Check if report already exists for this date
+	// Check if report already exists for this date and location
 	filter := bson.M{
 		"reportDate": bson.M{
-			"$gte": time.Date(reportDate.Year(), reportDate.Month(), reportDate.Day(), 0, 0, 0, 0, time.FixedZone("Asia/Colombo", 5*3600+30*60)),
-			"$lt":  time.Date(reportDate.Year(), reportDate.Month(), reportDate.Day()+1, 0, 0, 0, 0, time.FixedZone("Asia/Colombo", 5*3600+30*60)),
+			"$gte": time.Date(reportDate.Year(), reportDate.Month(), reportDate.Day(), 0, 0, 0, 0, config.Location()),
+			"$lt":  time.Date(reportDate.Year(), reportDate.Month(), reportDate.Day()+1, 0, 0, 0, 0, config.Location()),
 		},
+		"locationId": dailyReportLocationMatch(summary.LocationId),
 	}
 
 	// Use upsert to either insert or update
@@ -65,17 +53,18 @@ func SaveDailyReport(summary *dto.DailySalesSummary) error {
 	return err
 }
 
-// GetDailyReportByDate retrieves a daily report by date
-func GetDailyReportByDate(date time.Time) (*dto.DailyReportDocument, error) {
+// GetDailyReportByDate retrieves a daily report by date, for one location or the whole business when locationId is empty
+func GetDailyReportByDate(date time.Time, locationId string) (*dto.DailyReportDocument, error) {
 	collection := dbConfigs.DATABASE.Collection("DailyReports")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
 		"reportDate": bson.M{
-			"$gte": time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.FixedZone("Asia/Colombo", 5*3600+30*60)),
-			"$lt":  time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, time.FixedZone("Asia/Colombo", 5*3600+30*60)),
+			"$gte": time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, config.Location()),
+			"$lt":  time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, config.Location()),
 		},
+		"locationId": dailyReportLocationMatch(locationId),
 	}
 
 	var report dto.DailyReportDocument
@@ -87,15 +76,16 @@ func GetDailyReportByDate(date time.Time) (*dto.DailyReportDocument, error) {
 	return &report, nil
 }
 
-// GetDailyReportsByMonth retrieves all daily reports for a specific month
-func GetDailyReportsByMonth(year int, month int) ([]dto.DailyReportDocument, error) {
+// GetDailyReportsByMonth retrieves all daily reports for a specific month, for one location or the whole business
+func GetDailyReportsByMonth(year int, month int, locationId string) ([]dto.DailyReportDocument, error) {
 	collection := dbConfigs.DATABASE.Collection("DailyReports")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"year":  year,
-		"month": month,
+		"year":       year,
+		"month":      month,
+		"locationId": dailyReportLocationMatch(locationId),
 	}
 
 	cursor, err := collection.Find(ctx, filter)
@@ -112,6 +102,42 @@ func GetDailyReportsByMonth(year int, month int) ([]dto.DailyReportDocument, err
 	return reports, nil
 }
 
+// GetDailyReportsBetween retrieves the daily reports of the days starting in [start, end), oldest first,
+// for one location or the whole business when locationId is empty
+func GetDailyReportsBetween(start time.Time, end time.Time, locationId string) ([]dto.DailyReportDocument, error) {
+	collection := dbConfigs.DATABASE.Collection("DailyReports")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"reportDate": bson.M{"$gte": start, "$lt": end},
+		"locationId": dailyReportLocationMatch(locationId),
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "reportDate", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reports := []dto.DailyReportDocument{}
+	if err = cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// dailyReportLocationMatch is the filter value for the locationId of a location's daily reports
+// Reports for the whole business have none
+func dailyReportLocationMatch(locationId string) interface{} {
+	if locationId == "" {
+		return bson.M{"$in": bson.A{nil, ""}}
+	}
+	return locationId
+}
+
 // DeleteExpiredReports manually deletes reports that have passed their expiration date
 // This is a backup function in case TTL index doesn't work properly
 func DeleteExpiredReports() (int64, error) {
@@ -119,7 +145,7 @@ func DeleteExpiredReports() (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	now := time.Now().In(time.FixedZone("Asia/Colombo", 5*3600+30*60))
+	now := time.Now().In(config.Location())
 	filter := bson.M{
 		"expiresAt": bson.M{"$lte": now},
 	}
@@ -0,0 +1,89 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StockMovementFilter holds the optional filters for listing stock movements
+type StockMovementFilter struct {
+	ProductId  string
+	BatchId    string
+	LocationId string
+	Type       string
+	StartDate  *time.Time // inclusive
+	EndDate    *time.Time // exclusive
+}
+
+// insertStockMovements writes ledger entries using the caller's context, so they commit with its transaction
+func insertStockMovements(ctx context.Context, movements []dto.StockMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(movements))
+	for i := range movements {
+		documents[i] = movements[i]
+	}
+
+	_, err := dbConfigs.DATABASE.Collection("StockMovements").InsertMany(ctx, documents)
+	return err
+}
+
+// DB_FindStockMovements returns a page of stock movements (newest first) and the total matching count
+func DB_FindStockMovements(movementFilter StockMovementFilter, page int, limit int) ([]dto.StockMovement, int64, error) {
+	collection := dbConfigs.DATABASE.Collection("StockMovements")
+	ctx := context.Background()
+
+	filter := bson.M{}
+	if movementFilter.ProductId != "" {
+		filter["productId"] = movementFilter.ProductId
+	}
+	if movementFilter.BatchId != "" {
+		filter["batchId"] = movementFilter.BatchId
+	}
+	if movementFilter.LocationId != "" {
+		filter["locationId"] = locationMatch(movementFilter.LocationId)
+	}
+	if movementFilter.Type != "" {
+		filter["type"] = movementFilter.Type
+	}
+	if movementFilter.StartDate != nil || movementFilter.EndDate != nil {
+		dateFilter := bson.M{}
+		if movementFilter.StartDate != nil {
+			dateFilter["$gte"] = *movementFilter.StartDate
+		}
+		if movementFilter.EndDate != nil {
+			dateFilter["$lt"] = *movementFilter.EndDate
+		}
+		filter["created_at"] = dateFilter
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64((page - 1) * limit))
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	movements := []dto.StockMovement{}
+	if err := cursor.All(ctx, &movements); err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
@@ -0,0 +1,170 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrStockTakeStatusChanged is returned when a stock take is no longer open, e.g. it was approved in the meantime
+var ErrStockTakeStatusChanged = errors.New("stock take is no longer open")
+
+func DB_CreateStockTake(stockTake *dto.StockTake) error {
+	_, err := dbConfigs.DATABASE.Collection("StockTakes").InsertOne(context.Background(), stockTake)
+	return err
+}
+
+// DB_FindStockTakeById returns mongo.ErrNoDocuments if the stock take does not exist
+func DB_FindStockTakeById(stockTakeId string) (*dto.StockTake, error) {
+	var stockTake dto.StockTake
+	err := dbConfigs.DATABASE.Collection("StockTakes").FindOne(context.Background(), bson.M{"stockTakeId": stockTakeId}).Decode(&stockTake)
+	if err != nil {
+		return nil, err
+	}
+	return &stockTake, nil
+}
+
+// DB_FindStockTakes returns the stock takes in a status, newest first; an empty status matches every status
+// withLines false leaves the lines out, for listings
+func DB_FindStockTakes(status string, withLines bool) ([]dto.StockTake, error) {
+	ctx := context.Background()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if !withLines {
+		opts.SetProjection(bson.M{"lines": 0})
+	}
+	cursor, err := dbConfigs.DATABASE.Collection("StockTakes").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stockTakes := []dto.StockTake{}
+	if err := cursor.All(ctx, &stockTakes); err != nil {
+		return nil, err
+	}
+	return stockTakes, nil
+}
+
+// DB_UpdateStockTakeCounts saves the counted lines and totals of an open stock take
+// Returns mongo.ErrNoDocuments if it does not exist, ErrStockTakeStatusChanged if it is no longer open
+func DB_UpdateStockTakeCounts(stockTake *dto.StockTake) error {
+	return updateOpenStockTake(context.Background(), stockTake.StockTakeId, bson.M{
+		"lines":         stockTake.Lines,
+		"countedLines":  stockTake.CountedLines,
+		"expectedValue": stockTake.ExpectedValue,
+		"varianceValue": stockTake.VarianceValue,
+		"shortageValue": stockTake.ShortageValue,
+		"surplusValue":  stockTake.SurplusValue,
+		"updated_at":    stockTake.UpdatedAt,
+	})
+}
+
+// DB_CancelStockTake drops an open stock take without touching stock
+func DB_CancelStockTake(stockTakeId string, now time.Time) error {
+	return updateOpenStockTake(context.Background(), stockTakeId, bson.M{
+		"status":      dto.StockTakeCancelled,
+		"cancelledAt": now,
+		"updated_at":  now,
+	})
+}
+
+func updateOpenStockTake(ctx context.Context, stockTakeId string, set bson.M) error {
+	collection := dbConfigs.DATABASE.Collection("StockTakes")
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"stockTakeId": stockTakeId, "status": dto.StockTakeOpen},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.M{"stockTakeId": stockTakeId})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return ErrStockTakeStatusChanged
+}
+
+// DB_ApproveStockTake posts an open stock take in a single transaction: the counted variance of every line
+// goes into its batch and the Stocks collection, recorded in the StockMovements ledger against the stock take,
+// and the stock take is marked approved with the quantities actually posted
+// Products deleted since the count started are skipped
+func DB_ApproveStockTake(stockTakeId string, userId string) (*dto.StockTake, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	ref := dto.StockMovementRef{
+		Type:          dto.MovementStockTake,
+		ReferenceType: dto.ReferenceStockTake,
+		ReferenceId:   stockTakeId,
+		UserId:        userId,
+	}
+
+	var approved dto.StockTake
+	err := runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		var stockTake dto.StockTake
+		if err := dbConfigs.DATABASE.Collection("StockTakes").FindOne(sessCtx, bson.M{"stockTakeId": stockTakeId}).Decode(&stockTake); err != nil {
+			return err
+		}
+		if stockTake.Status != dto.StockTakeOpen {
+			return fmt.Errorf("%w: %s is %s", ErrStockTakeStatusChanged, stockTakeId, stockTake.Status)
+		}
+
+		now := time.Now().UTC()
+		for _, productId := range functions.StockTakeProductIds(&stockTake) {
+			product, err := updateProductBatches(sessCtx, productId, ref, func(product *dto.Product) error {
+				functions.ApplyStockTakeVariance(product, &stockTake, now)
+				return nil
+			})
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to adjust product %s: %w", productId, err)
+			}
+			if err := syncSingleProductStock(sessCtx, product); err != nil {
+				return err
+			}
+		}
+
+		stockTake.Status = dto.StockTakeApproved
+		stockTake.ApprovedBy = userId
+		stockTake.ApprovedAt = &now
+		stockTake.UpdatedAt = now
+		if err := updateOpenStockTake(sessCtx, stockTakeId, bson.M{
+			"status":     stockTake.Status,
+			"lines":      stockTake.Lines,
+			"approvedBy": stockTake.ApprovedBy,
+			"approvedAt": stockTake.ApprovedAt,
+			"updated_at": stockTake.UpdatedAt,
+		}); err != nil {
+			return err
+		}
+		approved = stockTake
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &approved, nil
+}
@@ -0,0 +1,178 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/config"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DB_SetCategoryStockThresholds sets the stock thresholds of a category; nil removes them
+// Returns mongo.ErrNoDocuments if the category does not exist or was deleted
+func DB_SetCategoryStockThresholds(categoryId string, thresholds *dto.StockThresholds) error {
+	update := bson.M{"$set": bson.M{"stockThresholds": thresholds, "updated_at": time.Now().UTC()}}
+	if thresholds == nil {
+		update = bson.M{
+			"$unset": bson.M{"stockThresholds": ""},
+			"$set":   bson.M{"updated_at": time.Now().UTC()},
+		}
+	}
+
+	result, err := dbConfigs.DATABASE.Collection("Categories").UpdateOne(context.Background(),
+		bson.M{"categoryId": categoryId, "deleted": false},
+		update,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DB_FindCategoryStockThresholds returns the stock thresholds of every active category that has its own
+func DB_FindCategoryStockThresholds() (map[string]dto.StockThresholds, error) {
+	return findCategoryStockThresholds(context.Background())
+}
+
+func findCategoryStockThresholds(ctx context.Context) (map[string]dto.StockThresholds, error) {
+	opts := options.Find().SetProjection(bson.M{"categoryId": 1, "stockThresholds": 1})
+	cursor, err := dbConfigs.DATABASE.Collection("Categories").Find(ctx,
+		bson.M{"deleted": false, "stockThresholds": bson.M{"$type": "object"}},
+		opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []dto.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+
+	thresholds := make(map[string]dto.StockThresholds, len(categories))
+	for _, category := range categories {
+		thresholds[category.CategoryId] = *category.StockThresholds
+	}
+	return thresholds, nil
+}
+
+// productStockThresholds resolves the stock thresholds of the given products, for views such as the Stocks
+// collection that do not carry the product's category or thresholds
+func productStockThresholds(ctx context.Context, productIds []string) (map[string]dto.StockThresholds, error) {
+	categories, err := findCategoryStockThresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetProjection(bson.M{"productId": 1, "categoryId": 1, "stockThresholds": 1})
+	cursor, err := dbConfigs.DATABASE.Collection("Products").Find(ctx, bson.M{"productId": bson.M{"$in": productIds}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var products []dto.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	global := config.Get().Stock.Thresholds()
+	thresholds := make(map[string]dto.StockThresholds, len(products))
+	for i := range products {
+		thresholds[products[i].ProductId], _ = functions.ResolveStockThresholds(&products[i], categories, global)
+	}
+	return thresholds, nil
+}
+
+// stockThresholdExpr is an aggregation expression for one resolved threshold of a product document:
+// the product's own, else its category's, else the global one
+// field is "lowThreshold" or "averageThreshold"
+func stockThresholdExpr(field string, categories map[string]dto.StockThresholds, global int) interface{} {
+	fallback := interface{}(global)
+	if len(categories) > 0 {
+		categoryIds := make([]string, 0, len(categories))
+		for categoryId := range categories {
+			categoryIds = append(categoryIds, categoryId)
+		}
+		sort.Strings(categoryIds)
+
+		branches := make([]bson.M, 0, len(categoryIds))
+		for _, categoryId := range categoryIds {
+			value := categories[categoryId].LowThreshold
+			if field == "averageThreshold" {
+				value = categories[categoryId].AverageThreshold
+			}
+			branches = append(branches, bson.M{
+				"case": bson.M{"$eq": []interface{}{"$categoryId", categoryId}},
+				"then": value,
+			})
+		}
+		fallback = bson.M{"$switch": bson.M{"branches": branches, "default": global}}
+	}
+	return bson.M{"$ifNull": []interface{}{"$stockThresholds." + field, fallback}}
+}
+
+// locationStockQtyExpr is an aggregation expression for a product document's stock at a location,
+// or its total stock when locationId is empty (see functions.LocationStock)
+func locationStockQtyExpr(locationId string) interface{} {
+	if locationId == "" {
+		return "$stockQty"
+	}
+	batches := bson.M{"$ifNull": []interface{}{"$batches", bson.A{}}}
+	atLocation := bson.M{"$sum": bson.M{"$map": bson.M{
+		"input": bson.M{"$filter": bson.M{
+			"input": batches,
+			"cond":  bson.M{"$eq": []interface{}{bson.M{"$ifNull": []interface{}{"$$this.locationId", dto.MainLocationId}}, locationId}},
+		}},
+		"in": "$$this.stockQty",
+	}}}
+	if locationId != dto.MainLocationId {
+		return atLocation
+	}
+	// Products without batches predate locations and are held at the main location
+	return bson.M{"$cond": []interface{}{
+		bson.M{"$gt": []interface{}{bson.M{"$size": batches}, 0}},
+		atLocation,
+		"$stockQty",
+	}}
+}
+
+// stockStatusExpr is an aggregation expression for the status of a product document's stock,
+// in total or at one location when locationId is set
+func stockStatusExpr(categories map[string]dto.StockThresholds, locationId string) bson.M {
+	global := config.Get().Stock.Thresholds()
+	qty := locationStockQtyExpr(locationId)
+	return bson.M{
+		"$switch": bson.M{
+			"branches": []bson.M{
+				{
+					"case": bson.M{"$lt": []interface{}{qty, stockThresholdExpr("lowThreshold", categories, global.LowThreshold)}},
+					"then": dto.StockStatusLow,
+				},
+				{
+					"case": bson.M{"$lt": []interface{}{qty, stockThresholdExpr("averageThreshold", categories, global.AverageThreshold)}},
+					"then": dto.StockStatusAverage,
+				},
+			},
+			"default": dto.StockStatusGood,
+		},
+	}
+}
+
+// stockStatusFilter matches the non-deleted products whose stock, in total or at a location, is in a status
+func stockStatusFilter(status string, categories map[string]dto.StockThresholds, locationId string) bson.M {
+	return bson.M{
+		"deleted": false,
+		"$expr":   bson.M{"$eq": []interface{}{stockStatusExpr(categories, locationId), status}},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"employee-crud/dbConfigs"
 	"employee-crud/dto"
+	"employee-crud/functions"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -71,6 +72,7 @@ func DB_SyncStocksFromProducts() error {
 							"productId":   product.ProductId,
 							"batchId":     batch.BatchId,
 							"name":        product.Name,
+							"locationId":  functions.BatchLocation(&batch),
 							"stockQty":    batch.StockQty,
 							"expiry_date": batch.ExpiryDate,
 							"updated_at":  currentTime,
@@ -108,51 +110,52 @@ func DB_SyncStocksFromProducts() error {
 // DB_SyncSingleProductStock syncs a single product's stock to the Stocks collection
 // Use this when a product is created or updated
 func DB_SyncSingleProductStock(product *dto.Product) error {
+	return syncSingleProductStock(context.Background(), product)
+}
+
+// syncSingleProductStock rebuilds the Stocks entries of one product
+// Pass a session context to run it as part of a transaction
+func syncSingleProductStock(ctx context.Context, product *dto.Product) error {
 	stocksCollection := dbConfigs.DATABASE.Collection("Stocks")
-	ctx := context.Background()
 
 	currentTime := time.Now()
 
-	// If product has batches, sync each batch as a separate stock entry
-	if len(product.Batches) > 0 {
-		// First, delete ALL existing stock entries for this product (including orphaned null batches)
-		// Then recreate them from the current batches - this is cleaner and ensures no orphaned data
-		stocksCollection.DeleteMany(ctx, bson.M{"productId": product.ProductId})
-
-		// Sync each batch
-		for _, batch := range product.Batches {
-			// Use productId + batchId as unique identifier
-			filter := bson.M{
-				"productId": product.ProductId,
-				"batchId":   batch.BatchId,
-			}
-			update := bson.M{
-				"$set": bson.M{
-					"productId":   product.ProductId,
-					"batchId":     batch.BatchId,
-					"name":        product.Name,
-					"stockQty":    batch.StockQty,
-					"expiry_date": batch.ExpiryDate,
-					"updated_at":  currentTime,
-				},
-				"$setOnInsert": bson.M{
-					"created_at": currentTime,
-				},
-			}
+	// First, delete ALL existing stock entries for this product (including orphaned null batches)
+	// Then recreate them from the current batches - this is cleaner and ensures no orphaned data
+	if _, err := stocksCollection.DeleteMany(ctx, bson.M{"productId": product.ProductId}); err != nil {
+		return err
+	}
 
-			opts := options.Update().SetUpsert(true)
-			_, err := stocksCollection.UpdateOne(ctx, filter, update, opts)
-			if err != nil {
-				return err
-			}
+	// Products without batches: Skip syncing to avoid orphaned stock entries
+	// Products should use the batch system - this is legacy code path
+	// Note: Products without batches won't appear in stock listing
+	// This encourages migration to batch-based inventory system
+	for _, batch := range product.Batches {
+		// Use productId + batchId as unique identifier
+		filter := bson.M{
+			"productId": product.ProductId,
+			"batchId":   batch.BatchId,
+		}
+		update := bson.M{
+			"$set": bson.M{
+				"productId":   product.ProductId,
+				"batchId":     batch.BatchId,
+				"name":        product.Name,
+				"locationId":  functions.BatchLocation(&batch),
+				"stockQty":    batch.StockQty,
+				"expiry_date": batch.ExpiryDate,
+				"updated_at":  currentTime,
+			},
+			"$setOnInsert": bson.M{
+				"created_at": currentTime,
+			},
+		}
+
+		opts := options.Update().SetUpsert(true)
+		_, err := stocksCollection.UpdateOne(ctx, filter, update, opts)
+		if err != nil {
+			return err
 		}
-	} else {
-		// Products without batches: Skip syncing to avoid orphaned stock entries
-		// Products should use the batch system - this is legacy code path
-		// Delete any existing stock entries for this product
-		stocksCollection.DeleteMany(ctx, bson.M{"productId": product.ProductId})
-		// Note: Products without batches won't appear in stock listing
-		// This encourages migration to batch-based inventory system
 	}
 
 	return nil
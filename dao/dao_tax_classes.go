@@ -0,0 +1,151 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTaxClassInUse is returned when a tax class that products or categories are assigned to is deleted
+var ErrTaxClassInUse = errors.New("tax class is still assigned to products or categories")
+
+func DB_CreateTaxClass(class *dto.TaxClass) error {
+	_, err := dbConfigs.DATABASE.Collection("TaxClasses").InsertOne(context.Background(), class)
+	return err
+}
+
+// DB_FindTaxClassById returns mongo.ErrNoDocuments if the tax class does not exist or was deleted
+func DB_FindTaxClassById(taxClassId string) (*dto.TaxClass, error) {
+	var class dto.TaxClass
+	err := dbConfigs.DATABASE.Collection("TaxClasses").FindOne(context.Background(),
+		bson.M{"taxClassId": taxClassId, "deleted": false},
+	).Decode(&class)
+	if err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// DB_FindAllTaxClasses returns the tax classes that are not deleted, by name
+func DB_FindAllTaxClasses() ([]dto.TaxClass, error) {
+	ctx := context.Background()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := dbConfigs.DATABASE.Collection("TaxClasses").Find(ctx, bson.M{"deleted": false}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	classes := []dto.TaxClass{}
+	if err := cursor.All(ctx, &classes); err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+// DB_UpdateTaxClass changes a tax class's name, rate and inclusive flag; sales already made keep the old rate
+// Returns mongo.ErrNoDocuments if the tax class does not exist or was deleted
+func DB_UpdateTaxClass(class *dto.TaxClass) error {
+	result, err := dbConfigs.DATABASE.Collection("TaxClasses").UpdateOne(context.Background(),
+		bson.M{"taxClassId": class.TaxClassID, "deleted": false},
+		bson.M{"$set": bson.M{
+			"name":       class.Name,
+			"rate":       class.Rate,
+			"inclusive":  class.Inclusive,
+			"updated_at": class.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DB_DeleteTaxClass soft deletes a tax class nothing is assigned to any more
+// Returns ErrTaxClassInUse while an active product or category uses it, mongo.ErrNoDocuments if it does not exist
+func DB_DeleteTaxClass(taxClassId string) error {
+	ctx := context.Background()
+
+	inUse := bson.M{"taxClassId": taxClassId, "deleted": false}
+	for _, collection := range []string{"Products", "Categories"} {
+		count, err := dbConfigs.DATABASE.Collection(collection).CountDocuments(ctx, inUse, options.Count().SetLimit(1))
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTaxClassInUse
+		}
+	}
+
+	result, err := dbConfigs.DATABASE.Collection("TaxClasses").UpdateOne(ctx,
+		bson.M{"taxClassId": taxClassId, "deleted": false},
+		bson.M{"$set": bson.M{"deleted": true, "updated_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DB_SetCategoryTaxClass assigns a tax class to a category; an empty taxClassId removes the assignment
+// Returns mongo.ErrNoDocuments if the category does not exist or was deleted
+func DB_SetCategoryTaxClass(categoryId string, taxClassId string) error {
+	update := bson.M{"$set": bson.M{"taxClassId": taxClassId, "updated_at": time.Now().UTC()}}
+	if taxClassId == "" {
+		update = bson.M{
+			"$unset": bson.M{"taxClassId": ""},
+			"$set":   bson.M{"updated_at": time.Now().UTC()},
+		}
+	}
+
+	result, err := dbConfigs.DATABASE.Collection("Categories").UpdateOne(context.Background(),
+		bson.M{"categoryId": categoryId, "deleted": false},
+		update,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DB_FindCategoryTaxClasses returns the tax class id of every active category that has one
+func DB_FindCategoryTaxClasses() (map[string]string, error) {
+	ctx := context.Background()
+
+	opts := options.Find().SetProjection(bson.M{"categoryId": 1, "taxClassId": 1})
+	cursor, err := dbConfigs.DATABASE.Collection("Categories").Find(ctx,
+		bson.M{"deleted": false, "taxClassId": bson.M{"$exists": true, "$ne": ""}},
+		opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []dto.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+
+	classes := make(map[string]string, len(categories))
+	for _, category := range categories {
+		classes[category.CategoryId] = category.TaxClassID
+	}
+	return classes, nil
+}
@@ -0,0 +1,32 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// runInTransaction runs fn inside a multi-document transaction
+// Transient errors (e.g. write conflicts with a concurrent request) retry the whole of fn,
+// so fn must only write through sessCtx and must be safe to run more than once
+func runInTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := dbConfigs.CLIENT.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	txnOptions := options.Transaction().
+		SetReadConcern(readconcern.Snapshot()).
+		SetWriteConcern(writeconcern.Majority())
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	}, txnOptions)
+
+	return err
+}
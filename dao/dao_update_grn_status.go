@@ -3,12 +3,19 @@ package dao
 import (
 	"context"
 	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// ErrGRNAlreadyPosted is returned when a GRN whose goods are already in stock is moved back to pending
+var ErrGRNAlreadyPosted = errors.New("GRN has already been posted to inventory")
+
 // DB_CheckGRNExists checks if a GRN exists and is not deleted
 func DB_CheckGRNExists(grnId string) (bool, error) {
 	collection := dbConfigs.DATABASE.Collection("GRNs")
@@ -28,30 +35,124 @@ func DB_CheckGRNExists(grnId string) (bool, error) {
 }
 
 // DB_UpdateGRNStatus updates the status of a GRN
-func DB_UpdateGRNStatus(grnId string, status string, updatedAt time.Time) error {
+// Moving a GRN to completed or partial_received posts every received line that has not been posted yet
+// into the product's batches and resyncs Stocks, all in one transaction
+// Each posted line records the batch it produced, so calling this again never posts a line twice
+// A GRN raised against a purchase order also recounts what the PO has received
+func DB_UpdateGRNStatus(grnId string, status string, updatedAt time.Time, userId string) (*dto.GRN, error) {
 	collection := dbConfigs.DATABASE.Collection("GRNs")
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	filter := bson.M{
-		"grnId":   grnId,
-		"deleted": false,
+	var grn dto.GRN
+	err := runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		filter := bson.M{
+			"grnId":   grnId,
+			"deleted": false,
+		}
+
+		if err := collection.FindOne(sessCtx, filter).Decode(&grn); err != nil {
+			return err
+		}
+
+		if status == "pending" {
+			for _, item := range grn.Items {
+				if item.PostedBatchId != "" {
+					return fmt.Errorf("%w: %s", ErrGRNAlreadyPosted, grnId)
+				}
+			}
+		} else if err := postGRN(sessCtx, &grn, updatedAt, userId); err != nil {
+			return err
+		}
+
+		grn.Status = status
+		grn.UpdatedAt = updatedAt
+
+		update := bson.M{
+			"$set": bson.M{
+				"status":     status,
+				"items":      grn.Items,
+				"updated_at": updatedAt,
+			},
+		}
+
+		result, err := collection.UpdateOne(sessCtx, filter, update)
+		if err != nil {
+			return err
+		}
+
+		if result.MatchedCount == 0 {
+			return mongo.ErrNoDocuments
+		}
+
+		// Posted lines count as received on the purchase order the GRN was raised against
+		if grn.PurchaseOrderId != "" {
+			return receivePurchaseOrder(sessCtx, grn.PurchaseOrderId, updatedAt)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"status":     status,
-			"updated_at": updatedAt,
-		},
+	return &grn, nil
+}
+
+// postGRN posts every received line of a GRN that has not been posted yet into the product's batches
+// A GRN raised against a purchase order is first checked against what the PO still has outstanding
+func postGRN(ctx context.Context, grn *dto.GRN, postedAt time.Time, userId string) error {
+	if grn.PurchaseOrderId != "" {
+		var po dto.PurchaseOrder
+		if err := dbConfigs.DATABASE.Collection("PurchaseOrders").FindOne(ctx, bson.M{"poId": grn.PurchaseOrderId}).Decode(&po); err != nil {
+			return err
+		}
+		if err := functions.CheckGRNPosting(grn, &po); err != nil {
+			return err
+		}
 	}
 
-	result, err := collection.UpdateOne(ctx, filter, update)
-	if err != nil {
+	ref := dto.StockMovementRef{
+		Type:          dto.MovementGRNReceipt,
+		ReferenceType: dto.ReferenceGRN,
+		ReferenceId:   grn.GRNId,
+		UserId:        userId,
+	}
+	if ref.UserId == "" {
+		ref.UserId = grn.ReceivedBy
+	}
+	for i := range grn.Items {
+		if err := postGRNItem(ctx, &grn.Items[i], grn.LocationId, postedAt, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postGRNItem receives one GRN line into the product's batches at the GRN's location
+// Lines that are already posted or have nothing received are skipped
+func postGRNItem(ctx context.Context, item *dto.GRNItem, locationId string, postedAt time.Time, ref dto.StockMovementRef) error {
+	if item.PostedBatchId != "" || item.ReceivedQty <= 0 {
+		return nil
+	}
+
+	newBatchId := batchIdGenerator(ctx)
+
+	var batchId string
+	product, err := updateProductBatches(ctx, item.ProductId, ref, func(product *dto.Product) error {
+		id, err := functions.ApplyGRNItem(product, item, locationId, newBatchId, time.Now().UTC())
+		batchId = id
 		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to post GRN item %s: %w", item.ProductId, err)
 	}
 
-	if result.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+	if err := syncSingleProductStock(ctx, product); err != nil {
+		return err
 	}
 
+	item.PostedBatchId = batchId
+	item.PostedQty = item.ReceivedQty
+	item.PostedAt = &postedAt
 	return nil
 }
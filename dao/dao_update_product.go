@@ -9,25 +9,40 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func DB_UpdateProduct(ctx context.Context, product *dto.Product) error {
+// DB_UpdateProduct saves a product's details, and of its settings only those set in settings
+// Stock quantities are left alone: they only change through batch mutations, which record them in the ledger
+func DB_UpdateProduct(ctx context.Context, product *dto.Product, settings dto.ProductSettingChanges) error {
 	collection := dbConfigs.DATABASE.Collection("Products")
 
 	filter := bson.M{"productId": product.ProductId}
 
-	update := bson.M{
-		"$set": bson.M{
-			"name":          product.Name,
-			"barcode":       product.Barcode,
-			"categoryId":    product.CategoryID,
-			"brandId":       product.BrandID,
-			"subCategoryId": product.SubCategoryID,
-			"costPrice":     product.CostPrice,
-			"sellingPrice":  product.SellingPrice,
-			"stockQty":      product.StockQty,
-			"expiry_date":   product.ExpiryDate,
-			"deleted":       product.Deleted,
-			"updated_at":    product.UpdatedAt,
-		},
+	fields := bson.M{
+		"name":          product.Name,
+		"barcode":       product.Barcode,
+		"categoryId":    product.CategoryID,
+		"brandId":       product.BrandID,
+		"subCategoryId": product.SubCategoryID,
+		"costPrice":     product.CostPrice,
+		"sellingPrice":  product.SellingPrice,
+		"expiry_date":   product.ExpiryDate,
+		"deleted":       product.Deleted,
+		"updated_at":    product.UpdatedAt,
+	}
+	if settings.TaxClassID != nil {
+		fields["taxClassId"] = *settings.TaxClassID
+	}
+	if settings.ReorderPoint != nil {
+		fields["reorderPoint"] = *settings.ReorderPoint
+	}
+	if settings.ReorderQty != nil {
+		fields["reorderQty"] = *settings.ReorderQty
+	}
+	if settings.StockThresholds != nil {
+		fields["stockThresholds"] = settings.StockThresholds
+	}
+	update := bson.M{"$set": fields}
+	if settings.ClearStockThresholds {
+		update["$unset"] = bson.M{"stockThresholds": ""}
 	}
 
 	result, err := collection.UpdateOne(ctx, filter, update)
@@ -0,0 +1,130 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrProductVersionConflict is returned when a product keeps changing underneath a batch mutation
+var ErrProductVersionConflict = errors.New("product was modified by another request, please retry")
+
+// productVersionConflict is a lost compare-and-swap on a product inside a transaction
+// It carries the TransientTransactionError label, so runInTransaction retries the whole transaction on a fresh
+// snapshot; once the retries run out the caller sees ErrProductVersionConflict
+type productVersionConflict struct{ productId string }
+
+func (e productVersionConflict) Error() string {
+	return fmt.Sprintf("%s: %s", ErrProductVersionConflict, e.productId)
+}
+
+func (e productVersionConflict) Unwrap() error {
+	return ErrProductVersionConflict
+}
+
+func (e productVersionConflict) HasErrorLabel(label string) bool {
+	return label == "TransientTransactionError"
+}
+
+// productVersionFilter matches a non-deleted product at the given version
+// Products saved before versioning have no version field and are treated as version 0
+func productVersionFilter(productId string, version int64) bson.M {
+	filter := bson.M{"productId": productId, "deleted": false}
+	if version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		filter["version"] = version
+	}
+	return filter
+}
+
+// updateProductBatches reads the product, lets mutate change its batches and stockQty,
+// and writes them back only if nobody else updated the product in between (compare-and-swap on version)
+// Every quantity change is recorded in the StockMovements ledger with the reason given in ref;
+// ctx must be a transaction session so the product write and its ledger entries commit together.
+// A re-read inside the transaction would see the same snapshot, so a lost race is returned as a
+// productVersionConflict and the whole transaction is retried instead
+func updateProductBatches(ctx context.Context, productId string, ref dto.StockMovementRef, mutate func(product *dto.Product) error) (*dto.Product, error) {
+	collection := dbConfigs.DATABASE.Collection("Products")
+
+	var product dto.Product
+	err := collection.FindOne(ctx, bson.M{"productId": productId, "deleted": false}).Decode(&product)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+
+	version := product.Version
+	before := product
+	before.Batches = append([]dto.Batch(nil), product.Batches...)
+	if err := mutate(&product); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	update := bson.M{
+		"$set": bson.M{
+			"batches":    product.Batches,
+			"stockQty":   product.StockQty,
+			"updated_at": now,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := collection.UpdateOne(ctx, productVersionFilter(productId, version), update)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, productVersionConflict{productId: productId}
+	}
+
+	product.Version = version + 1
+	product.UpdatedAt = now
+
+	movements := functions.BuildStockMovements(&before, &product, ref, now)
+	if err := insertStockMovements(ctx, movements); err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// updateProductBatchesInTransaction runs updateProductBatches in its own transaction,
+// so the product write and its StockMovements entries are kept or lost together
+func updateProductBatchesInTransaction(productId string, ref dto.StockMovementRef, mutate func(product *dto.Product) error) (*dto.Product, error) {
+	var product *dto.Product
+	err := runInTransaction(context.Background(), func(sessCtx mongo.SessionContext) error {
+		var err error
+		product, err = updateProductBatches(sessCtx, productId, ref, mutate)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// batchIdGenerator returns a newBatchId func for the functions.Apply* mutations
+// The id is only generated if the mutation actually needs a new batch, and at most once
+// Create it inside the transaction so a retried transaction generates its id again
+func batchIdGenerator(ctx context.Context) func() (string, error) {
+	var batchId string
+	return func() (string, error) {
+		if batchId == "" {
+			id, err := GenerateId(ctx, "Batches", "BATCH")
+			if err != nil {
+				return "", err
+			}
+			batchId = id
+		}
+		return batchId, nil
+	}
+}
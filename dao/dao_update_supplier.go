@@ -16,11 +16,12 @@ func DB_UpdateSupplier(ctx context.Context, supplier *dto.Supplier) error {
 
 	update := bson.M{
 		"$set": bson.M{
-			"name":       supplier.Name,
-			"contact":    supplier.Contact,
-			"email":      supplier.Email,
-			"address":    supplier.Address,
-			"updated_at": supplier.UpdatedAt,
+			"name":         supplier.Name,
+			"contact":      supplier.Contact,
+			"email":        supplier.Email,
+			"address":      supplier.Address,
+			"leadTimeDays": supplier.LeadTimeDays,
+			"updated_at":   supplier.UpdatedAt,
 		},
 	}
 
@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrUsernameTaken is returned when creating a user whose username already exists
+var ErrUsernameTaken = errors.New("username already exists")
+
+func DB_CreateUser(user *dto.User) error {
+	_, err := dbConfigs.DATABASE.Collection("Users").InsertOne(context.Background(), user)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrUsernameTaken
+	}
+	return err
+}
+
+// DB_FindUserByUsername returns mongo.ErrNoDocuments if no user has the username
+func DB_FindUserByUsername(username string) (*dto.User, error) {
+	var user dto.User
+	err := dbConfigs.DATABASE.Collection("Users").FindOne(context.Background(), bson.M{"username": username}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DB_FindUserById returns mongo.ErrNoDocuments if the user does not exist
+func DB_FindUserById(userId string) (*dto.User, error) {
+	var user dto.User
+	err := dbConfigs.DATABASE.Collection("Users").FindOne(context.Background(), bson.M{"userId": userId}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func DB_FindAllUsers() ([]dto.User, error) {
+	ctx := context.Background()
+	opts := options.Find().SetSort(bson.D{{Key: "username", Value: 1}})
+	cursor, err := dbConfigs.DATABASE.Collection("Users").Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []dto.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// DB_UpdateUser applies the changes to a user; a new password hash also bumps its token version
+// Returns mongo.ErrNoDocuments if the user does not exist
+func DB_UpdateUser(userId string, changes dto.UserChanges) error {
+	fields := bson.M{"updated_at": time.Now().UTC()}
+	if changes.FullName != nil {
+		fields["fullName"] = *changes.FullName
+	}
+	if changes.Role != nil {
+		fields["role"] = *changes.Role
+	}
+	if changes.Active != nil {
+		fields["active"] = *changes.Active
+	}
+	update := bson.M{"$set": fields}
+	if changes.PasswordHash != nil {
+		fields["passwordHash"] = *changes.PasswordHash
+		update["$inc"] = bson.M{"tokenVersion": 1}
+	}
+
+	result, err := dbConfigs.DATABASE.Collection("Users").UpdateOne(context.Background(), bson.M{"userId": userId}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func DB_CountUsers() (int64, error) {
+	return dbConfigs.DATABASE.Collection("Users").CountDocuments(context.Background(), bson.M{})
+}
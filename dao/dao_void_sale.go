@@ -0,0 +1,125 @@
+package dao
+
+import (
+	"context"
+	"employee-crud/dbConfigs"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DB_VoidSale undoes a sale in a single transaction: the sale is marked voided with the reason,
+// every unit goes back to the batch its sale line recorded (or, for older sales, the ledger entries say) it was deducted from,
+// and the sale is taken back off its customer's points, spend and sale count
+// The restocking is recorded in the StockMovements ledger against the saleId
+// Returns mongo.ErrNoDocuments if the sale does not exist (archived sales cannot be voided)
+// and functions.ErrInvalidVoid if it was already voided or has returns
+func DB_VoidSale(saleId string, reason string, userId string) (*dto.Sale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ref := dto.StockMovementRef{
+		Type:          dto.MovementSaleVoid,
+		ReferenceType: dto.ReferenceSale,
+		ReferenceId:   saleId,
+		UserId:        userId,
+		Note:          reason,
+	}
+
+	var voided dto.Sale
+	err := runInTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		salesCollection := dbConfigs.DATABASE.Collection("Sales")
+
+		var sale dto.Sale
+		if err := salesCollection.FindOne(sessCtx, bson.M{"saleId": saleId}).Decode(&sale); err != nil {
+			return err
+		}
+		if err := functions.CheckSaleVoidable(&sale); err != nil {
+			return err
+		}
+
+		// Marking the sale makes a concurrent void or return against it conflict and retry
+		now := time.Now().UTC()
+		result, err := salesCollection.UpdateOne(sessCtx,
+			bson.M{"saleId": saleId, "voided": bson.M{"$ne": true}},
+			bson.M{"$set": bson.M{"voided": true, "voidReason": reason, "voidedBy": userId, "voidedAt": now, "updated_at": now}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return fmt.Errorf("%w: sale %s is already voided", functions.ErrInvalidVoid, saleId)
+		}
+
+		var movements []dto.StockMovement
+		if !functions.SaleHasAllocations(&sale) {
+			if movements, err = findSaleDeductions(sessCtx, saleId); err != nil {
+				return err
+			}
+		}
+		allocations := functions.SaleAllocationsByProduct(&sale, movements)
+
+		// A product can appear on several lines; its units go back in one update
+		quantities := make(map[string]int)
+		var productIds []string
+		for _, item := range sale.Items {
+			if _, seen := quantities[item.ProductID]; !seen {
+				productIds = append(productIds, item.ProductID)
+			}
+			quantities[item.ProductID] += item.Quantity
+		}
+
+		newBatchId := batchIdGenerator(sessCtx)
+		for _, productId := range productIds {
+			product, err := updateProductBatches(sessCtx, productId, ref, func(product *dto.Product) error {
+				return functions.ApplySaleVoid(product, quantities[productId], allocations[productId], functions.SaleLocation(&sale), newBatchId, now)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to restock product %s: %w", productId, err)
+			}
+			if err := syncSingleProductStock(sessCtx, product); err != nil {
+				return err
+			}
+		}
+
+		if err := reverseCustomerSale(sessCtx, &sale); err != nil {
+			return err
+		}
+
+		sale.Voided = true
+		sale.VoidReason = reason
+		sale.VoidedBy = userId
+		sale.VoidedAt = &now
+		sale.UpdatedAt = now
+		voided = sale
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &voided, nil
+}
+
+// findSaleDeductions returns the ledger entries of a sale's batch deductions, oldest first
+func findSaleDeductions(ctx context.Context, saleId string) ([]dto.StockMovement, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := dbConfigs.DATABASE.Collection("StockMovements").Find(ctx,
+		bson.M{"referenceType": dto.ReferenceSale, "referenceId": saleId, "type": dto.MovementSale},
+		opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var movements []dto.StockMovement
+	if err := cursor.All(ctx, &movements); err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
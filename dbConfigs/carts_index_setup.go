@@ -0,0 +1,33 @@
+package dbConfigs
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetupCartsIndexes makes cart ids unique and indexes the parked carts list per terminal
+// and the stock reservations summed on every cart change and checkout
+func SetupCartsIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := DATABASE.Collection("Carts").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "cartId", Value: 1}},
+			Options: options.Index().SetName("carts_cartId_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "terminalId", Value: 1}, {Key: "status", Value: 1}, {Key: "updated_at", Value: -1}},
+			Options: options.Index().SetName("carts_terminal_status_index"),
+		},
+		{
+			Keys:    bson.D{{Key: "reserveStock", Value: 1}, {Key: "status", Value: 1}, {Key: "reservedUntil", Value: 1}},
+			Options: options.Index().SetName("carts_reservation_index"),
+		},
+	})
+	return err
+}
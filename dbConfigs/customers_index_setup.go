@@ -0,0 +1,43 @@
+package dbConfigs
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetupCustomersIndexes makes customer ids and active customers' mobile numbers unique,
+// and indexes Sales by customer for purchase history
+func SetupCustomersIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	customerIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "customerId", Value: 1}},
+			Options: options.Index().SetName("customers_customerId_unique").SetUnique(true),
+		},
+		{
+			// Deleted customers are kept, so their mobile number can be registered again
+			Keys: bson.D{{Key: "mobileNumber", Value: 1}},
+			Options: options.Index().SetName("customers_mobileNumber_unique").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"deleted": false}),
+		},
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetName("customers_name_index"),
+		},
+	}
+	if _, err := DATABASE.Collection("Customers").Indexes().CreateMany(ctx, customerIndexes); err != nil {
+		return err
+	}
+
+	_, err := DATABASE.Collection("Sales").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "customerId", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("sales_customerId_createdAt_index").SetSparse(true),
+	})
+	return err
+}
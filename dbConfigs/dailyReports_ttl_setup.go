@@ -60,3 +60,25 @@ func SetupDailyReportsTTL() error {
 	_, err = collection.Indexes().CreateOne(ctx, monthIndexModel)
 	return err
 }
+
+// SetupReportRollupIndexes creates the unique index that keeps one rollup per period
+// Rollups have no TTL: they are the permanent sales history
+func SetupReportRollupIndexes() error {
+	collection := DATABASE.Collection("ReportRollups")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "period", Value: 1},
+			{Key: "year", Value: 1},
+			{Key: "month", Value: 1},
+		},
+		Options: options.Index().
+			SetName("reportRollups_period_unique").
+			SetUnique(true),
+	}
+
+	_, err := collection.Indexes().CreateOne(ctx, indexModel)
+	return err
+}
@@ -10,8 +10,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func ConnectMongoDB() *mongo.Client {
-	client, err := mongo.NewClient(options.Client().ApplyURI(DATABASE_URL))
+// ConnectMongoDB connects to the MongoDB deployment at uri and selects the named database
+func ConnectMongoDB(uri string, databaseName string) *mongo.Client {
+	client, err := mongo.NewClient(options.Client().ApplyURI(uri))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -25,7 +26,7 @@ func ConnectMongoDB() *mongo.Client {
 	fmt.Println("Connected to MongoDB")
 	CLIENT = client
 
-	DATABASE = client.Database(DATABASE_NAME)
+	DATABASE = client.Database(databaseName)
 	fmt.Println(DATABASE.Name())
 
 	return client
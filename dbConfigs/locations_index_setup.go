@@ -0,0 +1,44 @@
+package dbConfigs
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetupLocationsIndexes makes location ids unique and indexes the location of the stock entries, sales and
+// daily reports that listings and reports filter on
+func SetupLocationsIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	if _, err := DATABASE.Collection("Locations").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "locationId", Value: 1}},
+		Options: options.Index().SetName("locations_locationId_unique").SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	if _, err := DATABASE.Collection("Stocks").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "locationId", Value: 1}, {Key: "updated_at", Value: -1}},
+		Options: options.Index().SetName("stocks_location_index"),
+	}); err != nil {
+		return err
+	}
+
+	if _, err := DATABASE.Collection("Sales").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "locationId", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("sales_location_index"),
+	}); err != nil {
+		return err
+	}
+
+	_, err := DATABASE.Collection("DailyReports").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "locationId", Value: 1}, {Key: "reportDate", Value: 1}},
+		Options: options.Index().SetName("dailyReports_location_index"),
+	})
+	return err
+}
@@ -0,0 +1,28 @@
+package dbConfigs
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetupPromotionsIndexes makes promotion ids unique and indexes the running-promotions lookup done on every checkout
+func SetupPromotionsIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := DATABASE.Collection("Promotions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "promotionId", Value: 1}},
+			Options: options.Index().SetName("promotions_promotionId_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "active", Value: 1}, {Key: "deleted", Value: 1}, {Key: "priority", Value: -1}},
+			Options: options.Index().SetName("promotions_active_priority_index"),
+		},
+	})
+	return err
+}
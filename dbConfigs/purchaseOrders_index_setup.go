@@ -0,0 +1,38 @@
+package dbConfigs
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetupPurchaseOrdersIndexes makes purchase order ids unique, indexes the PO list per supplier and status,
+// and indexes the GRNs raised against each PO that are recounted whenever one of them is posted
+func SetupPurchaseOrdersIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := DATABASE.Collection("PurchaseOrders").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "poId", Value: 1}},
+			Options: options.Index().SetName("purchaseOrders_poId_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "supplierId", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("purchaseOrders_supplier_status_index"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = DATABASE.Collection("GRNs").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "purchaseOrderId", Value: 1}},
+		Options: options.Index().SetName("grns_purchaseOrderId_index").
+			SetPartialFilterExpression(bson.M{"purchaseOrderId": bson.M{"$exists": true}}),
+	})
+	return err
+}
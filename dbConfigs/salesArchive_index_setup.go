@@ -0,0 +1,39 @@
+package dbConfigs
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetupSalesArchiveIndexes creates the indexes used to find archived sales by day, date range, saleId and customerId
+func SetupSalesArchiveIndexes() error {
+	collection := DATABASE.Collection("SalesArchive")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "day", Value: 1}},
+			Options: options.Index().SetName("salesArchive_day_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "firstSaleAt", Value: 1}, {Key: "lastSaleAt", Value: 1}},
+			Options: options.Index().SetName("salesArchive_range_index"),
+		},
+		{
+			Keys:    bson.D{{Key: "saleIds", Value: 1}},
+			Options: options.Index().SetName("salesArchive_saleId_index"),
+		},
+		{
+			Keys:    bson.D{{Key: "customerIds", Value: 1}},
+			Options: options.Index().SetName("salesArchive_customerId_index"),
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	return err
+}
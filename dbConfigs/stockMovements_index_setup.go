@@ -0,0 +1,39 @@
+package dbConfigs
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetupStockMovementsIndexes creates the indexes used to filter the StockMovements ledger
+func SetupStockMovementsIndexes() error {
+	collection := DATABASE.Collection("StockMovements")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "productId", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("stockMovements_product_index"),
+		},
+		{
+			Keys:    bson.D{{Key: "batchId", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("stockMovements_batch_index"),
+		},
+		{
+			Keys:    bson.D{{Key: "type", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("stockMovements_type_index"),
+		},
+		{
+			Keys:    bson.D{{Key: "referenceId", Value: 1}},
+			Options: options.Index().SetName("stockMovements_reference_index"),
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	return err
+}
@@ -0,0 +1,29 @@
+package dbConfigs
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetupStockTakesIndexes makes stock take ids unique and indexes the stock takes by status for the listings
+// and the check for open stock takes counting the same products
+func SetupStockTakesIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := DATABASE.Collection("StockTakes").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "stockTakeId", Value: 1}},
+			Options: options.Index().SetName("stockTakes_stockTakeId_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("stockTakes_status_index"),
+		},
+	})
+	return err
+}
@@ -0,0 +1,38 @@
+package dbConfigs
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetupTaxClassesIndexes makes tax class ids unique and indexes the in-use checks made before deleting a class
+func SetupTaxClassesIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := DATABASE.Collection("TaxClasses").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "taxClassId", Value: 1}},
+		Options: options.Index().SetName("taxClasses_taxClassId_unique").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	for collection, name := range map[string]string{
+		"Products":   "products_taxClassId_index",
+		"Categories": "categories_taxClassId_index",
+	} {
+		_, err := DATABASE.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "taxClassId", Value: 1}},
+			Options: options.Index().SetName(name).SetSparse(true),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -10,31 +10,104 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// SetupSalesTTL creates a TTL index on the Sales collection
-// Sales will be automatically deleted 24 hours after the created_at timestamp
-func SetupSalesTTL() error {
-	collection := DATABASE.Collection("Sales")
+const (
+	salesTTLIndexName        = "sales_ttl_index"
+	salesArchiveTTLIndexName = "salesArchive_ttl_index"
+)
+
+// SetupSalesTTL makes the TTL indexes on Sales and SalesArchive match the configured retention
+// Sales (live or archived) are deleted retentionDays after they were made; 0 removes the indexes so sales are kept
+func SetupSalesTTL(retentionDays int) error {
+	expireAfterSeconds := int32(retentionDays * 24 * 3600)
+
+	if err := setupTTLIndex("Sales", salesTTLIndexName, "created_at", expireAfterSeconds); err != nil {
+		return err
+	}
+	if err := setupTTLIndex("SalesArchive", salesArchiveTTLIndexName, "lastSaleAt", expireAfterSeconds); err != nil {
+		return err
+	}
+
+	if retentionDays <= 0 {
+		log.Println("Sales retention disabled: sales records are kept")
+	} else {
+		log.Printf("Sales records will be automatically deleted %d days after creation", retentionDays)
+	}
+	return nil
+}
+
+// setupTTLIndex creates, replaces or drops a TTL index so it expires documents expireAfterSeconds after field
+// An existing index with a different expiry is dropped and recreated, since MongoDB cannot change it in place
+// expireAfterSeconds <= 0 only drops the index
+func setupTTLIndex(collectionName string, indexName string, field string, expireAfterSeconds int32) error {
+	collection := DATABASE.Collection(collectionName)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Create TTL index on created_at field
-	// expireAfterSeconds: 86400 = 24 hours (24 * 60 * 60 seconds)
+	existing, found, err := findIndex(ctx, collection, indexName)
+	if err != nil {
+		return err
+	}
+	if found {
+		if expireAfterSeconds > 0 && indexExpiry(existing) == int64(expireAfterSeconds) {
+			return nil
+		}
+		if _, err := collection.Indexes().DropOne(ctx, indexName); err != nil {
+			log.Printf("Error dropping TTL index %s: %v", indexName, err)
+			return err
+		}
+	}
+
+	if expireAfterSeconds <= 0 {
+		return nil
+	}
+
 	indexModel := mongo.IndexModel{
 		Keys: bson.D{
-			{Key: "created_at", Value: 1}, // 1 for ascending order
+			{Key: field, Value: 1},
 		},
 		Options: options.Index().
-			SetExpireAfterSeconds(86400). // 24 hours in seconds
-			SetName("sales_ttl_index"),
+			SetExpireAfterSeconds(expireAfterSeconds).
+			SetName(indexName),
 	}
 
-	indexName, err := collection.Indexes().CreateOne(ctx, indexModel)
-	if err != nil {
-		log.Printf("Error creating TTL index: %v", err)
+	if _, err := collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		log.Printf("Error creating TTL index %s: %v", indexName, err)
 		return err
 	}
 
-	log.Printf("Successfully created TTL index: %s on Sales collection", indexName)
-	log.Println("Sales records will be automatically deleted 24 hours after creation")
+	log.Printf("Successfully created TTL index: %s on %s collection", indexName, collectionName)
 	return nil
 }
+
+// findIndex returns the specification of the named index on the collection, if it exists
+func findIndex(ctx context.Context, collection *mongo.Collection, name string) (bson.M, bool, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var index bson.M
+		if err := cursor.Decode(&index); err != nil {
+			return nil, false, err
+		}
+		if index["name"] == name {
+			return index, true, nil
+		}
+	}
+	return nil, false, cursor.Err()
+}
+
+// indexExpiry returns an index's expireAfterSeconds, or -1 if it has none
+func indexExpiry(index bson.M) int64 {
+	switch v := index["expireAfterSeconds"].(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return -1
+}
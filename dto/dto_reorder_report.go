@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// Where a reorder point came from
+const (
+	ReorderFromProduct   = "product"   // Set on the product
+	ReorderFromSales     = "sales"     // Average daily sales over the supplier's lead time plus the safety days
+	ReorderFromThreshold = "threshold" // No sales to go on: the low stock threshold
+)
+
+// ReorderSuggestionsReport lists the products to reorder, grouped by the supplier they are bought from
+type ReorderSuggestionsReport struct {
+	GeneratedAt  time.Time              `json:"generatedAt"`
+	VelocityDays int                    `json:"velocityDays"` // Days of saved daily reports looked at
+	ReportDays   int                    `json:"reportDays"`   // Days among them that had a saved report
+	Suppliers    []ReorderSupplierGroup `json:"suppliers"`
+}
+
+// ReorderSupplierGroup is the suggestions for one supplier; products without a supplier are grouped under an empty SupplierId
+type ReorderSupplierGroup struct {
+	SupplierId    string              `json:"supplierId"`
+	SupplierName  string              `json:"supplierName"`
+	LeadTimeDays  int                 `json:"leadTimeDays"`
+	EstimatedCost Money               `json:"estimatedCost"`
+	Suggestions   []ReorderSuggestion `json:"suggestions"`
+}
+
+// ReorderSuggestion is a product whose stock and open purchase orders have fallen to its reorder point
+type ReorderSuggestion struct {
+	ProductId          string  `json:"productId"`
+	ProductName        string  `json:"productName"`
+	StockQty           int     `json:"stockQty"`
+	OnOrderQty         int     `json:"onOrderQty"` // Outstanding on purchase orders that are not closed, drafts included
+	AverageDailySales  float64 `json:"averageDailySales"`
+	ReorderPoint       int     `json:"reorderPoint"`
+	ReorderPointSource string  `json:"reorderPointSource"`
+	SuggestedQty       int     `json:"suggestedQty"`
+	UnitCost           Money   `json:"unitCost"`
+	EstimatedCost      Money   `json:"estimatedCost"`
+}
+
+// CreateReorderPurchaseOrdersRequest is the body of CreateReorderPurchaseOrders
+// Empty lists take every supplier with suggestions and every suggested product
+type CreateReorderPurchaseOrdersRequest struct {
+	SupplierIds []string `json:"supplierIds,omitempty"`
+	ProductIds  []string `json:"productIds,omitempty"`
+}
@@ -5,23 +5,66 @@ import "time"
 // DailySalesSummary represents the summary of sales for a specific date
 type DailySalesSummary struct {
 	ReportDate      time.Time            `json:"reportDate"`
+	LocationId      string               `json:"locationId,omitempty"` // Empty for the whole business
 	TotalSales      int                  `json:"totalSales"`
-	TotalRevenue    float64              `json:"totalRevenue"`
-	TotalDiscount   float64              `json:"totalDiscount"`
-	TotalTax        float64              `json:"totalTax"`
-	CashSales       int                  `json:"cashSales"`
-	CardSales       int                  `json:"cardSales"`
-	CashRevenue     float64              `json:"cashRevenue"`
-	CardRevenue     float64              `json:"cardRevenue"`
+	TotalRevenue    Money                `json:"totalRevenue"`
+	TotalDiscount   Money                `json:"totalDiscount"`
+	TotalTax        Money                `json:"totalTax"` // Including tax contained in tax-inclusive prices
+	TaxBreakdown    []TaxSummary         `json:"taxBreakdown"`
+	Tenders         []TenderSummary      `json:"tenders"`
 	ProductsSold    []ProductSoldSummary `json:"productsSold"`
 	TopSellingItems []ProductSoldSummary `json:"topSellingItems"`
+	VoidedSales     []VoidedSaleSummary  `json:"voidedSales"` // Not counted in any total above
+	Profit          GrossProfitReport    `json:"profit"`
+}
+
+// VoidedSaleSummary is a sale of the day that was voided, listed apart from the day's sales
+type VoidedSaleSummary struct {
+	SaleID   string    `bson:"saleId" json:"saleId"`
+	Total    Money     `bson:"total" json:"total"`
+	Reason   string    `bson:"reason" json:"reason"`
+	VoidedBy string    `bson:"voidedBy,omitempty" json:"voidedBy,omitempty"`
+	SoldAt   time.Time `bson:"soldAt" json:"soldAt"`
+	VoidedAt time.Time `bson:"voidedAt" json:"voidedAt"`
 }
 
 // ProductSoldSummary represents the summary of a product sold during the day
 type ProductSoldSummary struct {
-	ProductID   string  `json:"productId"`
-	ProductName string  `json:"productName"`
-	Quantity    int     `json:"quantity"`
-	UnitPrice   float64 `json:"unitPrice"`
-	TotalAmount float64 `json:"totalAmount"`
+	ProductID   string `json:"productId"`
+	ProductName string `json:"productName"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   Money  `json:"unitPrice"`
+	TotalAmount Money  `json:"totalAmount"`
+}
+
+// TenderSummary is the revenue taken with one tender type
+type TenderSummary struct {
+	Type   string `bson:"type" json:"type"`
+	Sales  int    `bson:"sales" json:"sales"` // Sales paid at least partly with this tender
+	Amount Money  `bson:"amount" json:"amount"`
+}
+
+// GrossProfitReport is the gross profit of a set of sales, costed at the batches each line was sold from
+// Revenue is net of promotions, the cashier's and loyalty discounts and tax; returns are not taken off, as in the sales totals
+type GrossProfitReport struct {
+	Revenue            Money           `bson:"revenue" json:"revenue"`
+	COGS               Money           `bson:"cogs" json:"cogs"`
+	GrossProfit        Money           `bson:"grossProfit" json:"grossProfit"`
+	MarginPercent      float64         `bson:"marginPercent" json:"marginPercent"`                               // Gross profit as a percentage of revenue
+	EstimatedCostLines int             `bson:"estimatedCostLines,omitempty" json:"estimatedCostLines,omitempty"` // Lines sold before batch allocations, costed at the product's current cost price
+	ByProduct          []ProfitSummary `bson:"byProduct" json:"byProduct"`
+	ByBrand            []ProfitSummary `bson:"byBrand" json:"byBrand"`
+	ByCategory         []ProfitSummary `bson:"byCategory" json:"byCategory"`
+	ByDay              []ProfitSummary `bson:"byDay,omitempty" json:"byDay,omitempty"` // Only for reports spanning several days
+}
+
+// ProfitSummary is the gross profit of the sale lines of one product, brand, category or day
+type ProfitSummary struct {
+	Key           string  `bson:"key" json:"key"` // Product, brand or category id, or the day as YYYY-MM-DD
+	Name          string  `bson:"name,omitempty" json:"name,omitempty"`
+	Quantity      int     `bson:"quantity" json:"quantity"`
+	Revenue       Money   `bson:"revenue" json:"revenue"`
+	COGS          Money   `bson:"cogs" json:"cogs"`
+	GrossProfit   Money   `bson:"grossProfit" json:"grossProfit"`
+	MarginPercent float64 `bson:"marginPercent" json:"marginPercent"`
 }
@@ -6,10 +6,12 @@ import (
 
 type Batch struct {
 	BatchId      string     `bson:"batchId" json:"batchId"`
+	BatchNumber  string     `bson:"batchNumber,omitempty" json:"batchNumber,omitempty"` // Supplier lot number, set when received through a GRN
+	LocationId   string     `bson:"locationId,omitempty" json:"locationId,omitempty"`   // Where the batch is held; empty is MainLocationId
 	StockQty     int        `bson:"stockQty" json:"stockQty"`
 	ExpiryDate   *time.Time `bson:"expiry_date,omitempty" json:"expiry_date,omitempty"`
-	CostPrice    float64    `bson:"costPrice" json:"costPrice"`
-	SellingPrice float64    `bson:"sellingPrice" json:"sellingPrice"`
+	CostPrice    Money      `bson:"costPrice" json:"costPrice"`
+	SellingPrice Money      `bson:"sellingPrice" json:"sellingPrice"`
 	CreatedAt    time.Time  `bson:"created_at" json:"created_at"`
 	UpdatedAt    time.Time  `bson:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,91 @@
+package dto
+
+import "time"
+
+// Cart statuses
+const (
+	CartOpen      = "open"      // Being rung up at its terminal; only open carts can be changed
+	CartParked    = "parked"    // Put on hold while the customer steps away, until it is resumed
+	CartConverted = "converted" // Turned into the sale in SaleID
+	CartCancelled = "cancelled"
+)
+
+// CartItem is a product and quantity in a draft cart; prices are worked out whenever the cart is priced
+type CartItem struct {
+	ProductID   string `bson:"productId" json:"productId"`
+	ProductName string `bson:"productName" json:"productName"`
+	Quantity    int    `bson:"quantity" json:"quantity"`
+}
+
+// Cart is a server-side draft sale held at a terminal
+// A cart that reserves stock keeps its quantities from other carts and sales until ReservedUntil;
+// the reservation is soft, stock is only deducted when the cart is converted to a sale
+type Cart struct {
+	CartID        string     `bson:"cartId" json:"cartId"`
+	TerminalID    string     `bson:"terminalId" json:"terminalId"`
+	LocationId    string     `bson:"locationId,omitempty" json:"locationId,omitempty"` // Store the cart's stock is reserved at
+	Status        string     `bson:"status" json:"status"`
+	CustomerID    string     `bson:"customerId,omitempty" json:"customerId,omitempty"`
+	CustomerName  string     `bson:"customerName,omitempty" json:"customerName,omitempty"`
+	MobileNumber  string     `bson:"mobileNumber,omitempty" json:"mobileNumber,omitempty"`
+	Items         []CartItem `bson:"items" json:"items"`
+	Note          string     `bson:"note,omitempty" json:"note,omitempty"` // Why it was parked, who it is for, ...
+	ReserveStock  bool       `bson:"reserveStock" json:"reserveStock"`
+	ReservedUntil *time.Time `bson:"reservedUntil,omitempty" json:"reservedUntil,omitempty"`
+	SaleID        string     `bson:"saleId,omitempty" json:"saleId,omitempty"`
+	CreatedBy     string     `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	ParkedAt      *time.Time `bson:"parkedAt,omitempty" json:"parkedAt,omitempty"`
+	CreatedAt     time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// CreateCartRequest is the body of CreateCart
+type CreateCartRequest struct {
+	TerminalID   string     `json:"terminalId"`
+	LocationId   string     `json:"locationId,omitempty"` // Defaults to the main location
+	CustomerID   string     `json:"customerId,omitempty"`
+	CustomerName string     `json:"customerName,omitempty"`
+	MobileNumber string     `json:"mobileNumber,omitempty"`
+	Items        []CartItem `json:"items,omitempty"`
+	ReserveStock bool       `json:"reserveStock,omitempty"`
+}
+
+// CartItemRequest is the body of AddCartItem, UpdateCartItem and RemoveCartItem
+// AddCartItem adds Quantity to the line, UpdateCartItem sets it (0 removes the line)
+type CartItemRequest struct {
+	CartID    string `json:"cartId"`
+	ProductID string `json:"productId"`
+	Quantity  int    `json:"quantity"`
+}
+
+// ParkCartRequest is the body of ParkCart
+type ParkCartRequest struct {
+	CartID string `json:"cartId"`
+	Note   string `json:"note,omitempty"`
+}
+
+// ResumeCartRequest is the body of ResumeCart; an empty TerminalID resumes the cart at the terminal that parked it
+type ResumeCartRequest struct {
+	CartID     string `json:"cartId"`
+	TerminalID string `json:"terminalId,omitempty"`
+}
+
+// ConvertCartRequest is the body of ConvertCartToSale: the payment side of CreateSaleRequest
+// The customer fields default to the cart's
+type ConvertCartRequest struct {
+	CartID       string   `json:"cartId"`
+	CustomerID   string   `json:"customerId,omitempty"`
+	CustomerName string   `json:"customerName,omitempty"`
+	MobileNumber string   `json:"mobileNumber,omitempty"`
+	Discount     float64  `json:"discount"`
+	DiscountType string   `json:"discountType"`
+	Tenders      []Tender `json:"tenders"`
+	RedeemPoints int      `json:"redeemPoints,omitempty"`
+	RedeemAs     string   `json:"redeemAs,omitempty"`
+}
+
+// CartResponse is a cart with its lines priced the way CreateSale will price them
+type CartResponse struct {
+	Cart    Cart                 `json:"cart"`
+	Summary OrderSummaryResponse `json:"summary"`
+}
@@ -5,10 +5,12 @@ import (
 )
 
 type Category struct {
-	CategoryId   string    `bson:"categoryId" json:"categoryId"`
-	Name         string    `bson:"name" json:"name"`
-	Deleted      bool      `json:"deleted" bson:"deleted"`
-	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
-	ProductCount int64     `bson:"-" json:"product_count,omitempty"` // Not stored in DB, computed at runtime
+	CategoryId      string           `bson:"categoryId" json:"categoryId"`
+	Name            string           `bson:"name" json:"name"`
+	TaxClassID      string           `bson:"taxClassId,omitempty" json:"taxClassId,omitempty"`           // Tax class of its products that have none of their own
+	StockThresholds *StockThresholds `bson:"stockThresholds,omitempty" json:"stockThresholds,omitempty"` // Stock thresholds of its products that have none of their own
+	Deleted         bool             `json:"deleted" bson:"deleted"`
+	CreatedAt       time.Time        `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time        `bson:"updated_at" json:"updated_at"`
+	ProductCount    int64            `bson:"-" json:"product_count,omitempty"` // Not stored in DB, computed at runtime
 }
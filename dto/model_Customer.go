@@ -2,10 +2,26 @@ package dto
 
 import "time"
 
+// Customer is a registered customer
+// LoyaltyPoints, LifetimeSpend, SaleCount and LastPurchaseAt are only changed by checkout, voids and returns
 type Customer struct {
-	CustomerID   string    `bson:"customerId" json:"customerId"`
-	Name         string    `bson:"name" json:"name"`
-	MobileNumber string    `bson:"mobileNumber" json:"mobileNumber"`
-	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+	CustomerID     string     `bson:"customerId" json:"customerId"`
+	Name           string     `bson:"name" json:"name"`
+	MobileNumber   string     `bson:"mobileNumber" json:"mobileNumber"` // Unique among active customers, digits and a leading + only
+	Email          string     `bson:"email,omitempty" json:"email,omitempty"`
+	LoyaltyPoints  int        `bson:"loyaltyPoints" json:"loyaltyPoints"` // Current balance
+	LifetimeSpend  Money      `bson:"lifetimeSpend" json:"lifetimeSpend"`
+	SaleCount      int        `bson:"saleCount" json:"saleCount"`
+	LastPurchaseAt *time.Time `bson:"lastPurchaseAt,omitempty" json:"lastPurchaseAt,omitempty"`
+	Deleted        bool       `bson:"deleted" json:"deleted"`
+	CreatedAt      time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// CustomerRequest is the body of CreateCustomer and UpdateCustomer (customerId is only used by the update)
+type CustomerRequest struct {
+	CustomerID   string `json:"customerId,omitempty"`
+	Name         string `json:"name"`
+	MobileNumber string `json:"mobileNumber"`
+	Email        string `json:"email,omitempty"`
 }
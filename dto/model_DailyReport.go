@@ -10,18 +10,25 @@ import (
 type DailyReportDocument struct {
 	ID              primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
 	ReportDate      time.Time            `bson:"reportDate" json:"reportDate"`
-	Month           int                  `bson:"month" json:"month"` // Month number (1-12)
-	Year            int                  `bson:"year" json:"year"`   // Year
+	LocationId      string               `bson:"locationId,omitempty" json:"locationId,omitempty"` // Empty for the whole business
+	Month           int                  `bson:"month" json:"month"`                               // Month number (1-12)
+	Year            int                  `bson:"year" json:"year"`                                 // Year
 	TotalSales      int                  `bson:"totalSales" json:"totalSales"`
-	TotalRevenue    float64              `bson:"totalRevenue" json:"totalRevenue"`
-	TotalDiscount   float64              `bson:"totalDiscount" json:"totalDiscount"`
-	TotalTax        float64              `bson:"totalTax" json:"totalTax"`
-	CashSales       int                  `bson:"cashSales" json:"cashSales"`
-	CardSales       int                  `bson:"cardSales" json:"cardSales"`
-	CashRevenue     float64              `bson:"cashRevenue" json:"cashRevenue"`
-	CardRevenue     float64              `bson:"cardRevenue" json:"cardRevenue"`
+	TotalRevenue    Money                `bson:"totalRevenue" json:"totalRevenue"`
+	TotalDiscount   Money                `bson:"totalDiscount" json:"totalDiscount"`
+	TotalTax        Money                `bson:"totalTax" json:"totalTax"`
+	TaxBreakdown    []TaxSummary         `bson:"taxBreakdown,omitempty" json:"taxBreakdown,omitempty"` // Absent on reports saved before tax classes, use functions.ReportTaxBreakdown
+	Tenders         []TenderSummary      `bson:"tenders" json:"tenders"`
 	ProductsSold    []ProductSoldSummary `bson:"productsSold" json:"productsSold"`
 	TopSellingItems []ProductSoldSummary `bson:"topSellingItems" json:"topSellingItems"`
+	VoidedSales     []VoidedSaleSummary  `bson:"voidedSales,omitempty" json:"voidedSales,omitempty"` // Not counted in the totals
+	Profit          *GrossProfitReport   `bson:"profit,omitempty" json:"profit,omitempty"`           // Absent on reports saved before profit reporting
 	CreatedAt       time.Time            `bson:"createdAt" json:"createdAt"`
 	ExpiresAt       time.Time            `bson:"expiresAt" json:"expiresAt"` // TTL for auto-deletion
+
+	// Payment split of reports saved before tenders; read only, use functions.ReportTenders
+	CashSales   int   `bson:"cashSales,omitempty" json:"cashSales,omitempty"`
+	CardSales   int   `bson:"cardSales,omitempty" json:"cardSales,omitempty"`
+	CashRevenue Money `bson:"cashRevenue,omitempty" json:"cashRevenue,omitempty"`
+	CardRevenue Money `bson:"cardRevenue,omitempty" json:"cardRevenue,omitempty"`
 }
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// MainLocationId is the location of every batch, sale, cart and GRN recorded without one,
+// so data from before locations keeps belonging to the first store
+const MainLocationId = "MAIN"
+
+// Location types
+const (
+	LocationStore     = "store"
+	LocationWarehouse = "warehouse"
+)
+
+// Location is a store or warehouse that holds its own batches
+// Sales and carts take stock from the batches at their location, GRNs receive into it
+type Location struct {
+	LocationId string    `bson:"locationId" json:"locationId"`
+	Name       string    `bson:"name" json:"name"`
+	Type       string    `bson:"type" json:"type"` // "store" or "warehouse"
+	Address    string    `bson:"address,omitempty" json:"address,omitempty"`
+	Phone      string    `bson:"phone,omitempty" json:"phone,omitempty"`
+	Status     string    `bson:"status" json:"status"` // "active" or "inactive"
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -5,20 +5,59 @@ import (
 )
 
 type Product struct {
-	ProductId     string     `bson:"productId" json:"productId"`
-	Name          string     `bson:"name" json:"name"`
-	Barcode       string     `bson:"barcode" json:"barcode"`
-	CategoryID    string     `bson:"categoryId" json:"categoryId"`
-	CategoryName  string     `bson:"categoryName,omitempty" json:"categoryName,omitempty"` // Populated via lookup
-	BrandID       string     `bson:"brandId" json:"brandId"`
-	BrandName     string     `bson:"brandName,omitempty" json:"brandName,omitempty"` // Populated via lookup
-	SubCategoryID string     `bson:"subCategoryId" json:"subCategoryId"`
-	CostPrice     float64    `bson:"costPrice" json:"costPrice"`
-	SellingPrice  float64    `bson:"sellingPrice" json:"sellingPrice"`
-	StockQty      int        `bson:"stockQty" json:"stockQty"`
-	ExpiryDate    *time.Time `bson:"expiry_date,omitempty" json:"expiry_date,omitempty"`
-	Batches       []Batch    `bson:"batches,omitempty" json:"batches,omitempty"`
-	Deleted       bool       `bson:"deleted" json:"deleted"`
-	CreatedAt     time.Time  `bson:"created_at" json:"created_at"`
-	UpdatedAt     time.Time  `bson:"updated_at" json:"updated_at"`
+	ProductId       string           `bson:"productId" json:"productId"`
+	Name            string           `bson:"name" json:"name"`
+	Barcode         string           `bson:"barcode" json:"barcode"`
+	CategoryID      string           `bson:"categoryId" json:"categoryId"`
+	CategoryName    string           `bson:"categoryName,omitempty" json:"categoryName,omitempty"` // Populated via lookup
+	BrandID         string           `bson:"brandId" json:"brandId"`
+	BrandName       string           `bson:"brandName,omitempty" json:"brandName,omitempty"` // Populated via lookup
+	SubCategoryID   string           `bson:"subCategoryId" json:"subCategoryId"`
+	TaxClassID      string           `bson:"taxClassId,omitempty" json:"taxClassId,omitempty"` // Overrides the category's tax class
+	CostPrice       Money            `bson:"costPrice" json:"costPrice"`
+	SellingPrice    Money            `bson:"sellingPrice" json:"sellingPrice"`
+	StockQty        int              `bson:"stockQty" json:"stockQty"`
+	ExpiryDate      *time.Time       `bson:"expiry_date,omitempty" json:"expiry_date,omitempty"`
+	ReorderPoint    int              `bson:"reorderPoint,omitempty" json:"reorderPoint,omitempty"`       // Reorder at or below this quantity; 0 works it out from sales
+	ReorderQty      int              `bson:"reorderQty,omitempty" json:"reorderQty,omitempty"`           // Quantity to order; 0 works it out from sales
+	StockThresholds *StockThresholds `bson:"stockThresholds,omitempty" json:"stockThresholds,omitempty"` // Overrides the category's stock thresholds
+	Batches         []Batch          `bson:"batches,omitempty" json:"batches,omitempty"`
+	Deleted         bool             `bson:"deleted" json:"deleted"`
+	Version         int64            `bson:"version" json:"version"` // Incremented on every batch update (optimistic locking)
+	CreatedAt       time.Time        `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time        `bson:"updated_at" json:"updated_at"`
+}
+
+// ProductSettingChanges are the tax class, reorder settings and stock thresholds of a product to update;
+// nil fields are left as they are
+// ClearStockThresholds drops the product's own thresholds, putting it back on its category's or the global ones
+type ProductSettingChanges struct {
+	TaxClassID           *string
+	ReorderPoint         *int
+	ReorderQty           *int
+	StockThresholds      *StockThresholds
+	ClearStockThresholds bool
+}
+
+// UpdateProductRequest is the body of UpdateProduct
+// The product's details are saved as sent; its tax class, reorder settings and stock thresholds only change when sent
+// Its own stock thresholds are only dropped when clearStockThresholds is true
+type UpdateProductRequest struct {
+	Product
+	TaxClassID           *string          `json:"taxClassId"`
+	ReorderPoint         *int             `json:"reorderPoint"`
+	ReorderQty           *int             `json:"reorderQty"`
+	StockThresholds      *StockThresholds `json:"stockThresholds"`
+	ClearStockThresholds bool             `json:"clearStockThresholds"`
+}
+
+// Settings returns the settings the request changes
+func (r UpdateProductRequest) Settings() ProductSettingChanges {
+	return ProductSettingChanges{
+		TaxClassID:           r.TaxClassID,
+		ReorderPoint:         r.ReorderPoint,
+		ReorderQty:           r.ReorderQty,
+		StockThresholds:      r.StockThresholds,
+		ClearStockThresholds: r.ClearStockThresholds,
+	}
 }
@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// Promotion types
+const (
+	PromotionPercentage = "percentage"  // Value percent off every matching unit
+	PromotionFixed      = "fixed"       // Value off every matching unit, or off the basket once for basket scope
+	PromotionBuyXGetY   = "buy_x_get_y" // For every BuyQty matching units, GetQty more are free (the cheapest)
+	PromotionBundle     = "bundle"      // Every BundleQty matching units cost BundlePrice together
+)
+
+// Promotion scopes: which sale lines a promotion applies to
+const (
+	PromotionScopeProduct     = "product"
+	PromotionScopeBrand       = "brand"
+	PromotionScopeCategory    = "category"
+	PromotionScopeSubCategory = "subcategory"
+	PromotionScopeBasket      = "basket" // Every line of the sale
+)
+
+// Promotion is a stored discount rule evaluated automatically at checkout
+// Promotions are tried highest Priority first and a sale line gets at most one line promotion;
+// basket promotions are applied after them to what is left of each line
+type Promotion struct {
+	PromotionID string   `bson:"promotionId" json:"promotionId"`
+	Name        string   `bson:"name" json:"name"`
+	Type        string   `bson:"type" json:"type"`
+	Scope       string   `bson:"scope" json:"scope"`
+	TargetIDs   []string `bson:"targetIds,omitempty" json:"targetIds,omitempty"` // Product, brand, category or subcategory ids; empty for basket scope
+
+	Value       float64 `bson:"value,omitempty" json:"value,omitempty"` // Percent or amount for percentage and fixed promotions
+	BuyQty      int     `bson:"buyQty,omitempty" json:"buyQty,omitempty"`
+	GetQty      int     `bson:"getQty,omitempty" json:"getQty,omitempty"`
+	BundleQty   int     `bson:"bundleQty,omitempty" json:"bundleQty,omitempty"`
+	BundlePrice Money   `bson:"bundlePrice,omitempty" json:"bundlePrice,omitempty"`
+
+	// Conditions
+	MinBasketValue Money      `bson:"minBasketValue,omitempty" json:"minBasketValue,omitempty"` // Sale subtotal before promotions
+	StartsAt       *time.Time `bson:"startsAt,omitempty" json:"startsAt,omitempty"`
+	EndsAt         *time.Time `bson:"endsAt,omitempty" json:"endsAt,omitempty"`
+	DaysOfWeek     []int      `bson:"daysOfWeek,omitempty" json:"daysOfWeek,omitempty"` // 0 = Sunday; empty means every day
+	StartTime      string     `bson:"startTime,omitempty" json:"startTime,omitempty"`   // Daily window in store time, "HH:MM"
+	EndTime        string     `bson:"endTime,omitempty" json:"endTime,omitempty"`       // Exclusive; before StartTime means the window ends the next day
+
+	Priority  int       `bson:"priority" json:"priority"`
+	Active    bool      `bson:"active" json:"active"`
+	Deleted   bool      `bson:"deleted" json:"deleted"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// PromotionRequest is the body of CreatePromotion and UpdatePromotion
+// active defaults to true on create and to the current value on update
+type PromotionRequest struct {
+	Promotion
+	Active *bool `json:"active"`
+}
+
+// AppliedPromotion is a promotion that discounted a sale or order summary and by how much in total
+type AppliedPromotion struct {
+	PromotionID string `json:"promotionId"`
+	Name        string `json:"name"`
+	Discount    Money  `json:"discount"`
+}
@@ -0,0 +1,115 @@
+package dto
+
+import "time"
+
+// Purchase order statuses
+// A PO is edited as a draft, approved by a manager and sent to the supplier; GRNs raised against it
+// move it to partially_received and, once every line is received in full, to closed
+// A manager can also close a PO short, after which no more GRNs are accepted against it
+const (
+	PODraft             = "draft"
+	POApproved          = "approved"
+	POSent              = "sent"
+	POPartiallyReceived = "partially_received"
+	POClosed            = "closed"
+)
+
+// PurchaseOrderItem is a product ordered from the supplier at the agreed unit cost
+// ReceivedQty and ReceivedCost add up the GRN lines posted against the PO for this product
+type PurchaseOrderItem struct {
+	ProductId    string `bson:"productId" json:"productId"`
+	ProductName  string `bson:"productName" json:"productName"`
+	OrderedQty   int    `bson:"orderedQty" json:"orderedQty"`
+	UnitCost     Money  `bson:"unitCost" json:"unitCost"`
+	TotalCost    Money  `bson:"totalCost" json:"totalCost"`
+	ReceivedQty  int    `bson:"receivedQty" json:"receivedQty"`
+	ReceivedCost Money  `bson:"receivedCost" json:"receivedCost"`
+}
+
+// PurchaseOrder is an order placed with a supplier that GRNs are received against
+type PurchaseOrder struct {
+	POId         string              `bson:"poId" json:"poId"`
+	PONumber     string              `bson:"poNumber" json:"poNumber"`
+	SupplierId   string              `bson:"supplierId" json:"supplierId"`
+	SupplierName string              `bson:"supplierName" json:"supplierName"`
+	Status       string              `bson:"status" json:"status"`
+	Items        []PurchaseOrderItem `bson:"items" json:"items"`
+	TotalAmount  Money               `bson:"totalAmount" json:"totalAmount"`
+	ExpectedDate *time.Time          `bson:"expectedDate,omitempty" json:"expectedDate,omitempty"`
+	Notes        string              `bson:"notes,omitempty" json:"notes,omitempty"`
+	GRNIds       []string            `bson:"grnIds" json:"grnIds"` // GRNs raised against the PO
+	CreatedBy    string              `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	ApprovedBy   string              `bson:"approvedBy,omitempty" json:"approvedBy,omitempty"`
+	ApprovedAt   *time.Time          `bson:"approvedAt,omitempty" json:"approvedAt,omitempty"`
+	SentAt       *time.Time          `bson:"sentAt,omitempty" json:"sentAt,omitempty"`
+	ClosedAt     *time.Time          `bson:"closedAt,omitempty" json:"closedAt,omitempty"`
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// PurchaseOrderLineRequest is one line of CreatePurchaseOrder and UpdatePurchaseOrder
+type PurchaseOrderLineRequest struct {
+	ProductId  string  `json:"productId"`
+	OrderedQty int     `json:"orderedQty"`
+	UnitCost   float64 `json:"unitCost"`
+}
+
+// PurchaseOrderRequest is the body of CreatePurchaseOrder, and of UpdatePurchaseOrder with POId set
+// Only draft POs can be updated; the supplier cannot be changed once the PO exists
+type PurchaseOrderRequest struct {
+	POId         string                     `json:"poId,omitempty"`
+	PONumber     string                     `json:"poNumber,omitempty"`
+	SupplierId   string                     `json:"supplierId"`
+	Items        []PurchaseOrderLineRequest `json:"items"`
+	ExpectedDate *time.Time                 `json:"expectedDate,omitempty"`
+	Notes        string                     `json:"notes,omitempty"`
+}
+
+// UpdatePurchaseOrderStatusRequest is the body of UpdatePurchaseOrderStatus
+// Only approved, sent and closed can be set by hand; partially_received follows from GRNs
+type UpdatePurchaseOrderStatusRequest struct {
+	POId   string `json:"poId"`
+	Status string `json:"status"`
+}
+
+// PurchaseOrderVarianceReport compares what was ordered on a PO with what its posted GRNs received
+type PurchaseOrderVarianceReport struct {
+	POId             string                      `json:"poId"`
+	PONumber         string                      `json:"poNumber"`
+	SupplierId       string                      `json:"supplierId"`
+	SupplierName     string                      `json:"supplierName"`
+	Status           string                      `json:"status"`
+	OrderedValue     Money                       `json:"orderedValue"`     // Ordered quantities at the agreed costs
+	ReceivedValue    Money                       `json:"receivedValue"`    // Received quantities at the GRN costs
+	PriceVariance    Money                       `json:"priceVariance"`    // Received value less the received quantities at the agreed costs
+	QuantityVariance int                         `json:"quantityVariance"` // Units received less units ordered
+	Lines            []PurchaseOrderVarianceLine `json:"lines"`
+}
+
+// PurchaseOrderVarianceLine is the variance of one product; Ordered is false for products received but not on the PO
+// QuantityVariance is received less ordered, so a short delivery is negative
+// PriceVariance is what the received units cost less what they would have cost at the agreed unit cost,
+// so paying over the agreed price is positive
+type PurchaseOrderVarianceLine struct {
+	ProductId        string                         `json:"productId"`
+	ProductName      string                         `json:"productName"`
+	Ordered          bool                           `json:"ordered"`
+	OrderedQty       int                            `json:"orderedQty"`
+	ReceivedQty      int                            `json:"receivedQty"`
+	OutstandingQty   int                            `json:"outstandingQty"`
+	QuantityVariance int                            `json:"quantityVariance"`
+	AgreedUnitCost   Money                          `json:"agreedUnitCost"`
+	ReceivedCost     Money                          `json:"receivedCost"`
+	AverageUnitCost  Money                          `json:"averageUnitCost"`
+	PriceVariance    Money                          `json:"priceVariance"`
+	Receipts         []PurchaseOrderVarianceReceipt `json:"receipts"`
+}
+
+// PurchaseOrderVarianceReceipt is a posted GRN line counted towards a PO line
+type PurchaseOrderVarianceReceipt struct {
+	GRNId        string    `json:"grnId"`
+	GRNNumber    string    `json:"grnNumber"`
+	ReceivedDate time.Time `json:"receivedDate"`
+	ReceivedQty  int       `json:"receivedQty"`
+	UnitCost     Money     `json:"unitCost"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+// Receipt output formats
+const (
+	ReceiptFormatText   = "text"   // Plain text at the paper's character width
+	ReceiptFormatESCPOS = "escpos" // ESC/POS byte stream for thermal printers
+	ReceiptFormatPDF    = "pdf"    // PDF the width of the paper roll
+)
+
+// Receipt is a sale laid out for printing, whatever the output format
+type Receipt struct {
+	StoreName string          `json:"storeName"`
+	Header    []string        `json:"header,omitempty"`
+	SaleID    string          `json:"saleId"`
+	SoldAt    time.Time       `json:"soldAt"` // In the business time zone
+	Customer  string          `json:"customer,omitempty"`
+	Lines     []ReceiptLine   `json:"lines"`
+	Totals    []ReceiptAmount `json:"totals"` // Subtotal, discounts and tax, in the order they apply
+	Total     Money           `json:"total"`
+	Tenders   []ReceiptAmount `json:"tenders"` // Cash is shown as handed over
+	Change    Money           `json:"change,omitempty"`
+	Points    []string        `json:"points,omitempty"` // Loyalty points earned and redeemed
+	Voided    bool            `json:"voided,omitempty"`
+	Footer    []string        `json:"footer,omitempty"`
+}
+
+// ReceiptLine is one sale line on a receipt
+type ReceiptLine struct {
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice Money  `json:"unitPrice"`
+	Amount    Money  `json:"amount"`             // Quantity x UnitPrice
+	Discount  Money  `json:"discount,omitempty"` // Promotion discount on the line
+}
+
+// ReceiptAmount is a labelled amount on a receipt
+type ReceiptAmount struct {
+	Label  string `json:"label"`
+	Amount Money  `json:"amount"`
+}
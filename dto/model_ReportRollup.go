@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Report rollup periods
+const (
+	RollupMonthly = "monthly"
+	RollupYearly  = "yearly"
+)
+
+// ReportRollup is a permanent monthly or yearly sales summary
+// Monthly rollups are built from the saved daily reports before those expire, yearly rollups from the monthly ones
+type ReportRollup struct {
+	ID              primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Period          string               `bson:"period" json:"period"` // "monthly" or "yearly"
+	Year            int                  `bson:"year" json:"year"`
+	Month           int                  `bson:"month" json:"month"` // 1-12, 0 for yearly rollups
+	PeriodStart     time.Time            `bson:"periodStart" json:"periodStart"`
+	PeriodEnd       time.Time            `bson:"periodEnd" json:"periodEnd"`         // Exclusive
+	DaysCovered     int                  `bson:"daysCovered" json:"daysCovered"`     // Number of daily reports included
+	MonthsCovered   int                  `bson:"monthsCovered" json:"monthsCovered"` // Number of monthly rollups included (yearly only)
+	TotalSales      int                  `bson:"totalSales" json:"totalSales"`
+	TotalRevenue    Money                `bson:"totalRevenue" json:"totalRevenue"`
+	TotalDiscount   Money                `bson:"totalDiscount" json:"totalDiscount"`
+	TotalTax        Money                `bson:"totalTax" json:"totalTax"`
+	TaxBreakdown    []TaxSummary         `bson:"taxBreakdown" json:"taxBreakdown"`
+	Tenders         []TenderSummary      `bson:"tenders" json:"tenders"`
+	ProductsSold    []ProductSoldSummary `bson:"productsSold" json:"productsSold"`
+	TopSellingItems []ProductSoldSummary `bson:"topSellingItems" json:"topSellingItems"`
+	CreatedAt       time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// RollupComparison is one rollup in a comparison, with the change from the period before it
+// The changes are absent for the first period, which has nothing to compare against
+type RollupComparison struct {
+	Rollup           ReportRollup `json:"rollup"`
+	RevenueChange    *Money       `json:"revenueChange,omitempty"`
+	RevenueChangePct *float64     `json:"revenueChangePct,omitempty"` // Also absent when the previous period had no revenue
+	SalesCountChange *int         `json:"salesCountChange,omitempty"`
+}
@@ -3,54 +3,131 @@ package dto
 import "time"
 
 type SaleItem struct {
-	ProductID   string  `bson:"productId" json:"productId"`
-	ProductName string  `bson:"productName" json:"productName"`
-	Quantity    int     `bson:"quantity" json:"quantity"`
-	UnitPrice   float64 `bson:"unitPrice" json:"unitPrice"`
-	TotalPrice  float64 `bson:"totalPrice" json:"totalPrice"`
+	ProductID    string   `bson:"productId" json:"productId"`
+	ProductName  string   `bson:"productName" json:"productName"`
+	Quantity     int      `bson:"quantity" json:"quantity"`
+	UnitPrice    Money    `bson:"unitPrice" json:"unitPrice"`
+	TotalPrice   Money    `bson:"totalPrice" json:"totalPrice"`                         // Quantity x UnitPrice, before promotions
+	Discount     Money    `bson:"discount,omitempty" json:"discount,omitempty"`         // Promotion discount on this line
+	PromotionIDs []string `bson:"promotionIds,omitempty" json:"promotionIds,omitempty"` // Promotions that gave the discount
+	TaxClassID   string   `bson:"taxClassId,omitempty" json:"taxClassId,omitempty"`
+	TaxRate      float64  `bson:"taxRate,omitempty" json:"taxRate,omitempty"`
+	TaxInclusive bool     `bson:"taxInclusive,omitempty" json:"taxInclusive,omitempty"` // Tax is part of TotalPrice rather than added to it
+	Tax          Money    `bson:"tax,omitempty" json:"tax,omitempty"`                   // On the line after its promotion discount
+	ReturnedQty  int      `bson:"returnedQty,omitempty" json:"returnedQty,omitempty"`   // Units already returned against this line
+
+	// Batches the line's units were deducted from, earliest expiry first; absent on sales made before allocations
+	Batches []BatchAllocation `bson:"batches,omitempty" json:"batches,omitempty"`
+}
+
+// BatchAllocation is the part of a sale line taken from one batch, with the batch's cost and expiry at the time
+// BatchID is empty for products without batches
+type BatchAllocation struct {
+	BatchID      string     `bson:"batchId" json:"batchId"`
+	BatchNumber  string     `bson:"batchNumber,omitempty" json:"batchNumber,omitempty"`
+	Quantity     int        `bson:"quantity" json:"quantity"`
+	CostPrice    Money      `bson:"costPrice" json:"costPrice"`
+	SellingPrice Money      `bson:"sellingPrice" json:"sellingPrice"`
+	ExpiryDate   *time.Time `bson:"expiry_date,omitempty" json:"expiry_date,omitempty"`
+}
+
+// Tender types accepted at checkout
+const (
+	TenderCash         = "cash"
+	TenderCard         = "card"
+	TenderBankTransfer = "bank_transfer"
+	TenderVoucher      = "voucher"
+	TenderStoreCredit  = "store_credit"
+
+	// TenderLoyaltyPoints is added by checkout for points redeemed as a tender; clients cannot send it directly
+	TenderLoyaltyPoints = "loyalty_points"
+)
+
+// How redeemed loyalty points are applied to a sale
+const (
+	RedeemAsDiscount = "discount"
+	RedeemAsTender   = "tender"
+)
+
+// PaymentSplit is the PaymentMethod of a sale paid with more than one tender type
+const PaymentSplit = "split"
+
+// TenderTypes lists every tender type in the order reports show them
+var TenderTypes = []string{TenderCash, TenderCard, TenderBankTransfer, TenderVoucher, TenderStoreCredit, TenderLoyaltyPoints}
+
+// Tender is one payment towards a sale
+// On a saved sale Amount is what was applied to the total, so cash is net of change and the tenders add up to Total
+type Tender struct {
+	Type      string `bson:"type" json:"type"`
+	Amount    Money  `bson:"amount" json:"amount"`
+	Reference string `bson:"reference,omitempty" json:"reference,omitempty"` // Card approval code, transfer reference, voucher number, ...
 }
 
 type Sale struct {
-	SaleID         string     `bson:"saleId" json:"saleId"`
-	CustomerName   string     `bson:"customerName,omitempty" json:"customerName,omitempty"`
-	MobileNumber   string     `bson:"mobileNumber,omitempty" json:"mobileNumber,omitempty"`
-	Items          []SaleItem `bson:"items" json:"items"`
-	Subtotal       float64    `bson:"subtotal" json:"subtotal"`
-	Tax            float64    `bson:"tax" json:"tax"`
-	TaxPercentage  float64    `bson:"taxPercentage" json:"taxPercentage"`
-	Discount       float64    `bson:"discount" json:"discount"`
-	DiscountType   string     `bson:"discountType" json:"discountType"` // "percentage" or "fixed"
-	Total          float64    `bson:"total" json:"total"`
-	PaymentMethod  string     `bson:"paymentMethod" json:"paymentMethod"` // "cash" or "card"
-	AmountReceived float64    `bson:"amountReceived,omitempty" json:"amountReceived,omitempty"`
-	Change         float64    `bson:"change,omitempty" json:"change,omitempty"`
-	CreatedAt      time.Time  `bson:"created_at" json:"created_at"`
-	UpdatedAt      time.Time  `bson:"updated_at" json:"updated_at"`
+	SaleID            string       `bson:"saleId" json:"saleId"`
+	LocationId        string       `bson:"locationId,omitempty" json:"locationId,omitempty"` // Store the sale was made at; empty is MainLocationId
+	CustomerID        string       `bson:"customerId,omitempty" json:"customerId,omitempty"` // Registered customer, if any
+	CustomerName      string       `bson:"customerName,omitempty" json:"customerName,omitempty"`
+	MobileNumber      string       `bson:"mobileNumber,omitempty" json:"mobileNumber,omitempty"`
+	Items             []SaleItem   `bson:"items" json:"items"`
+	Subtotal          Money        `bson:"subtotal" json:"subtotal"`                                       // Before promotions
+	PromotionDiscount Money        `bson:"promotionDiscount,omitempty" json:"promotionDiscount,omitempty"` // Sum of the line discounts
+	Tax               Money        `bson:"tax" json:"tax"`                                                 // Added on top of tax-exclusive prices
+	IncludedTax       Money        `bson:"includedTax,omitempty" json:"includedTax,omitempty"`             // Contained in tax-inclusive prices
+	TaxBreakdown      []TaxSummary `bson:"taxBreakdown,omitempty" json:"taxBreakdown,omitempty"`
+	TaxPercentage     float64      `bson:"taxPercentage,omitempty" json:"taxPercentage,omitempty"` // Bill-level rate of sales made before tax classes
+	Discount          Money        `bson:"discount" json:"discount"`                               // Entered by the cashier, on the subtotal after promotions
+	DiscountType      string       `bson:"discountType" json:"discountType"`                       // "percentage" or "fixed"
+	Total             Money        `bson:"total" json:"total"`
+	LoyaltyDiscount   Money        `bson:"loyaltyDiscount,omitempty" json:"loyaltyDiscount,omitempty"` // Points redeemed as a discount, already taken off Total
+	PointsRedeemed    int          `bson:"pointsRedeemed,omitempty" json:"pointsRedeemed,omitempty"`
+	PointsEarned      int          `bson:"pointsEarned,omitempty" json:"pointsEarned,omitempty"`
+	Tenders           []Tender     `bson:"tenders,omitempty" json:"tenders,omitempty"`               // Absent on sales made before split payments
+	PaymentMethod     string       `bson:"paymentMethod" json:"paymentMethod"`                       // The single tender type, or "split"
+	AmountReceived    Money        `bson:"amountReceived,omitempty" json:"amountReceived,omitempty"` // Cash handed over
+	Change            Money        `bson:"change,omitempty" json:"change,omitempty"`                 // Given from cash only
+	Voided            bool         `bson:"voided,omitempty" json:"voided,omitempty"`                 // Undone by a manager; left out of sales totals
+	VoidReason        string       `bson:"voidReason,omitempty" json:"voidReason,omitempty"`
+	VoidedBy          string       `bson:"voidedBy,omitempty" json:"voidedBy,omitempty"`
+	VoidedAt          *time.Time   `bson:"voidedAt,omitempty" json:"voidedAt,omitempty"`
+	CreatedAt         time.Time    `bson:"created_at" json:"created_at"`
+	UpdatedAt         time.Time    `bson:"updated_at" json:"updated_at"`
 }
 
 // Request DTOs
 type CreateSaleRequest struct {
-	CustomerName   string     `json:"customerName,omitempty"`
-	MobileNumber   string     `json:"mobileNumber,omitempty"`
-	Items          []SaleItem `json:"items" binding:"required"`
-	Tax            float64    `json:"tax"`
-	TaxPercentage  float64    `json:"taxPercentage"`
-	Discount       float64    `json:"discount"`
-	DiscountType   string     `json:"discountType"`                     // "percentage" or "fixed"
-	PaymentMethod  string     `json:"paymentMethod" binding:"required"` // "cash" or "card"
-	AmountReceived float64    `json:"amountReceived,omitempty"`
+	LocationId   string     `json:"locationId,omitempty"` // Store selling the items; defaults to the main location
+	CustomerID   string     `json:"customerId,omitempty"` // Or leave empty and give mobileNumber to link a registered customer
+	CustomerName string     `json:"customerName,omitempty"`
+	MobileNumber string     `json:"mobileNumber,omitempty"`
+	Items        []SaleItem `json:"items" binding:"required"` // Taxed per line by each product's tax class
+	Discount     float64    `json:"discount"`
+	DiscountType string     `json:"discountType"`               // "percentage" or "fixed"
+	Tenders      []Tender   `json:"tenders" binding:"required"` // Cash amounts are what was handed over; change is given from cash
+	RedeemPoints int        `json:"redeemPoints,omitempty"`     // Loyalty points to spend, needs a registered customer
+	RedeemAs     string     `json:"redeemAs,omitempty"`         // "discount" (default) or "tender"
+}
+
+// VoidSaleRequest is the body of VoidSale
+type VoidSaleRequest struct {
+	SaleID string `json:"saleId"`
+	Reason string `json:"reason"`
 }
 
 type CalculateOrderSummaryRequest struct {
-	Items         []SaleItem `json:"items" binding:"required"`
-	TaxPercentage float64    `json:"taxPercentage"`
-	Discount      float64    `json:"discount"`
-	DiscountType  string     `json:"discountType"` // "percentage" or "fixed"
+	Items        []SaleItem `json:"items" binding:"required"`
+	Discount     float64    `json:"discount"`
+	DiscountType string     `json:"discountType"` // "percentage" or "fixed"
 }
 
 type OrderSummaryResponse struct {
-	Subtotal float64 `json:"subtotal"`
-	Tax      float64 `json:"tax"`
-	Discount float64 `json:"discount"`
-	Total    float64 `json:"total"`
+	Items             []SaleItem         `json:"items"`
+	Subtotal          Money              `json:"subtotal"`
+	PromotionDiscount Money              `json:"promotionDiscount"`
+	Promotions        []AppliedPromotion `json:"promotions"`
+	Tax               Money              `json:"tax"`
+	IncludedTax       Money              `json:"includedTax"`
+	TaxBreakdown      []TaxSummary       `json:"taxBreakdown"`
+	Discount          Money              `json:"discount"`
+	Total             Money              `json:"total"`
 }
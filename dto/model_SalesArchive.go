@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// SalesArchive holds one business day of archived sales, compressed into Data
+// The summary fields stay queryable so reports can find the days they need without decompressing everything
+type SalesArchive struct {
+	Day          time.Time `bson:"day" json:"day"` // Start of the business day the sales belong to
+	SaleIds      []string  `bson:"saleIds" json:"saleIds"`
+	CustomerIds  []string  `bson:"customerIds,omitempty" json:"customerIds,omitempty"` // Registered customers with a sale that day
+	SaleCount    int       `bson:"saleCount" json:"saleCount"`
+	TotalRevenue Money     `bson:"totalRevenue" json:"totalRevenue"` // Voided sales excluded
+	FirstSaleAt  time.Time `bson:"firstSaleAt" json:"firstSaleAt"`
+	LastSaleAt   time.Time `bson:"lastSaleAt" json:"lastSaleAt"`
+	Encoding     string    `bson:"encoding" json:"encoding"` // How Data is encoded, see functions.SalesArchiveEncoding
+	Data         []byte    `bson:"data" json:"-"`
+	ArchivedAt   time.Time `bson:"archivedAt" json:"archivedAt"`
+}
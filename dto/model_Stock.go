@@ -6,10 +6,26 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Stock statuses
+const (
+	StockStatusLow     = "Low Stock"
+	StockStatusAverage = "Average Stock"
+	StockStatusGood    = "Good Stock"
+)
+
+// Where the stock thresholds of a product come from
+// A product's own thresholds override its category's, which override the global ones
+const (
+	StockThresholdsFromProduct  = "product"
+	StockThresholdsFromCategory = "category"
+	StockThresholdsFromGlobal   = "global"
+)
+
 type Stock struct {
 	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	ProductId  string             `bson:"productId" json:"productId"`
 	BatchId    string             `bson:"batchId,omitempty" json:"batchId,omitempty"`
+	LocationId string             `bson:"locationId,omitempty" json:"locationId,omitempty"`
 	Name       string             `bson:"name" json:"name"`
 	StockQty   int                `bson:"stockQty" json:"stockQty"`
 	Status     string             `bson:"-" json:"status"` // Not stored in DB, calculated dynamically
@@ -18,16 +34,32 @@ type Stock struct {
 	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
-// CalculateStatus calculates the stock status based on quantity
-// - Low Stock: quantity < 10
-// - Average Stock: quantity >= 10 and < 25
-// - Good Stock: quantity >= 25
-func (s *Stock) CalculateStatus() {
-	if s.StockQty < 10 {
-		s.Status = "Low Stock"
-	} else if s.StockQty >= 10 && s.StockQty < 25 {
-		s.Status = "Average Stock"
-	} else {
-		s.Status = "Good Stock"
+// StockThresholds split stock quantities into statuses
+// A quantity below LowThreshold is low, below AverageThreshold is average, otherwise good
+type StockThresholds struct {
+	LowThreshold     int `bson:"lowThreshold" json:"lowThreshold"`
+	AverageThreshold int `bson:"averageThreshold" json:"averageThreshold"`
+}
+
+// Valid reports whether the low threshold is positive and below the average threshold
+func (t StockThresholds) Valid() bool {
+	return t.LowThreshold > 0 && t.AverageThreshold > t.LowThreshold
+}
+
+// Status returns the stock status of a quantity
+func (t StockThresholds) Status(qty int) string {
+	if qty < t.LowThreshold {
+		return StockStatusLow
+	} else if qty < t.AverageThreshold {
+		return StockStatusAverage
 	}
+	return StockStatusGood
+}
+
+// CalculateStatus calculates the stock status from the quantity and the thresholds of its product
+// - Low Stock: quantity < low threshold (default 10)
+// - Average Stock: quantity >= low and < average threshold (default 25)
+// - Good Stock: quantity >= average threshold
+func (s *Stock) CalculateStatus(thresholds StockThresholds) {
+	s.Status = thresholds.Status(s.StockQty)
 }
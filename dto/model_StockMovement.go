@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// Stock movement types
+const (
+	MovementSale            = "sale"
+	MovementGRNReceipt      = "grn_receipt"
+	MovementStockAdd        = "stock_add"
+	MovementStockRemove     = "stock_remove"
+	MovementBatchAdjustment = "batch_adjustment"
+	MovementBatchEdit       = "batch_edit"
+	MovementBatchDelete     = "batch_delete"
+	MovementProductCreate   = "product_create"
+	MovementReturn          = "return"
+	MovementSaleVoid        = "sale_void"
+	MovementStockTake       = "stock_take"
+)
+
+// Reference document types a movement can point to
+const (
+	ReferenceSale      = "sale"
+	ReferenceGRN       = "grn"
+	ReferenceReturn    = "return"
+	ReferenceStockTake = "stock_take"
+)
+
+// StockMovement is one ledger entry recording why a batch's quantity changed
+type StockMovement struct {
+	MovementId          string     `bson:"movementId" json:"movementId"`
+	ProductId           string     `bson:"productId" json:"productId"`
+	ProductName         string     `bson:"productName" json:"productName"`
+	BatchId             string     `bson:"batchId" json:"batchId"` // Empty for legacy products without batches
+	LocationId          string     `bson:"locationId,omitempty" json:"locationId,omitempty"`
+	Type                string     `bson:"type" json:"type"`
+	Delta               int        `bson:"delta" json:"delta"`                             // Signed quantity change
+	BalanceAfter        int        `bson:"balanceAfter" json:"balanceAfter"`               // Batch quantity after the movement
+	ProductBalanceAfter int        `bson:"productBalanceAfter" json:"productBalanceAfter"` // Product total after the movement
+	CostPrice           Money      `bson:"costPrice" json:"costPrice"`                     // Batch cost price at the time of the movement
+	SellingPrice        Money      `bson:"sellingPrice" json:"sellingPrice"`
+	ExpiryDate          *time.Time `bson:"expiry_date,omitempty" json:"expiry_date,omitempty"`
+	ReferenceType       string     `bson:"referenceType,omitempty" json:"referenceType,omitempty"`
+	ReferenceId         string     `bson:"referenceId,omitempty" json:"referenceId,omitempty"` // saleId, grnId, returnId or stockTakeId
+	UserId              string     `bson:"userId,omitempty" json:"userId,omitempty"`
+	Note                string     `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt           time.Time  `bson:"created_at" json:"created_at"`
+}
+
+// StockMovementRef describes the reason for a stock change, passed down to the batch DAOs
+type StockMovementRef struct {
+	Type          string
+	ReferenceType string
+	ReferenceId   string
+	UserId        string
+	Note          string
+}
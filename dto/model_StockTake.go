@@ -0,0 +1,85 @@
+package dto
+
+import "time"
+
+// Stock-take statuses
+// A stock take is counted while open; approving it posts the variances into the batches, cancelling drops it
+const (
+	StockTakeOpen      = "open"
+	StockTakeApproved  = "approved"
+	StockTakeCancelled = "cancelled"
+)
+
+// Stock-take scopes
+const (
+	StockTakeAll      = "all"
+	StockTakeCategory = "category"
+	StockTakeBrand    = "brand"
+)
+
+// StockTakeLine is one batch to count, or a legacy product without batches (BatchId empty)
+// ExpectedQty is frozen when the stock take starts; sales and receipts while counting are kept on approval,
+// as only the variance is posted
+type StockTakeLine struct {
+	ProductId     string     `bson:"productId" json:"productId"`
+	ProductName   string     `bson:"productName" json:"productName"`
+	Barcode       string     `bson:"barcode,omitempty" json:"barcode,omitempty"`
+	BatchId       string     `bson:"batchId" json:"batchId"`
+	LocationId    string     `bson:"locationId" json:"locationId"`
+	ExpiryDate    *time.Time `bson:"expiry_date,omitempty" json:"expiry_date,omitempty"`
+	UnitCost      Money      `bson:"unitCost" json:"unitCost"`
+	ExpectedQty   int        `bson:"expectedQty" json:"expectedQty"`
+	CountedQty    *int       `bson:"countedQty,omitempty" json:"countedQty,omitempty"` // Not set until the line is counted
+	VarianceQty   int        `bson:"varianceQty" json:"varianceQty"`                   // Counted less expected, 0 until counted
+	VarianceValue Money      `bson:"varianceValue" json:"varianceValue"`               // VarianceQty at UnitCost
+	CountedBy     string     `bson:"countedBy,omitempty" json:"countedBy,omitempty"`
+	CountedAt     *time.Time `bson:"countedAt,omitempty" json:"countedAt,omitempty"`
+	PostedQty     int        `bson:"postedQty,omitempty" json:"postedQty,omitempty"` // Adjustment posted on approval
+}
+
+// StockTake is a physical count of the products in a scope, compared with the quantities frozen at its start
+type StockTake struct {
+	StockTakeId   string          `bson:"stockTakeId" json:"stockTakeId"`
+	Scope         string          `bson:"scope" json:"scope"`
+	ScopeId       string          `bson:"scopeId,omitempty" json:"scopeId,omitempty"`       // categoryId or brandId
+	LocationId    string          `bson:"locationId,omitempty" json:"locationId,omitempty"` // Empty counts every location
+	Status        string          `bson:"status" json:"status"`
+	Lines         []StockTakeLine `bson:"lines" json:"lines"`
+	CountedLines  int             `bson:"countedLines" json:"countedLines"`
+	ExpectedValue Money           `bson:"expectedValue" json:"expectedValue"` // Expected quantities at cost
+	VarianceValue Money           `bson:"varianceValue" json:"varianceValue"` // Net variance of the counted lines at cost
+	ShortageValue Money           `bson:"shortageValue" json:"shortageValue"` // Counted lines below expected, as a positive value
+	SurplusValue  Money           `bson:"surplusValue" json:"surplusValue"`   // Counted lines above expected
+	Notes         string          `bson:"notes,omitempty" json:"notes,omitempty"`
+	CreatedBy     string          `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	ApprovedBy    string          `bson:"approvedBy,omitempty" json:"approvedBy,omitempty"`
+	ApprovedAt    *time.Time      `bson:"approvedAt,omitempty" json:"approvedAt,omitempty"`
+	CancelledAt   *time.Time      `bson:"cancelledAt,omitempty" json:"cancelledAt,omitempty"`
+	CreatedAt     time.Time       `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time       `bson:"updated_at" json:"updated_at"`
+}
+
+// CreateStockTakeRequest is the body of CreateStockTake; ScopeId is required for the category and brand scopes
+type CreateStockTakeRequest struct {
+	Scope      string `json:"scope"`
+	ScopeId    string `json:"scopeId,omitempty"`
+	LocationId string `json:"locationId,omitempty"` // Count one location only
+	Notes      string `json:"notes,omitempty"`
+}
+
+// StockTakeCount is one count: the line is found by productId or barcode, and batchId when the product has
+// several batches in the stock take
+// Add adds Quantity to the line's count, as for each barcode scan; otherwise Quantity replaces it
+type StockTakeCount struct {
+	ProductId string `json:"productId,omitempty"`
+	Barcode   string `json:"barcode,omitempty"`
+	BatchId   string `json:"batchId,omitempty"`
+	Quantity  int    `json:"quantity"`
+	Add       bool   `json:"add,omitempty"`
+}
+
+// RecordStockTakeCountsRequest is the body of RecordStockTakeCounts
+type RecordStockTakeCountsRequest struct {
+	StockTakeId string           `json:"stockTakeId"`
+	Counts      []StockTakeCount `json:"counts"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// StockWriteOff records goods that left sellable stock without a sale, e.g. damaged returns
+type StockWriteOff struct {
+	WriteOffId    string    `bson:"writeOffId" json:"writeOffId"`
+	ProductId     string    `bson:"productId" json:"productId"`
+	ProductName   string    `bson:"productName" json:"productName"`
+	Quantity      int       `bson:"quantity" json:"quantity"`
+	CostPrice     Money     `bson:"costPrice" json:"costPrice"`
+	TotalCost     Money     `bson:"totalCost" json:"totalCost"`
+	Reason        string    `bson:"reason" json:"reason"`
+	ReferenceType string    `bson:"referenceType,omitempty" json:"referenceType,omitempty"`
+	ReferenceId   string    `bson:"referenceId,omitempty" json:"referenceId,omitempty"`
+	UserId        string    `bson:"userId,omitempty" json:"userId,omitempty"`
+	CreatedAt     time.Time `bson:"created_at" json:"created_at"`
+}
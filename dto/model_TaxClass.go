@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// TaxClass is a tax rate that products are assigned to, directly or through their category
+// A product's own class wins over its category's; products with neither are not taxed
+type TaxClass struct {
+	TaxClassID string    `bson:"taxClassId" json:"taxClassId"`
+	Name       string    `bson:"name" json:"name"`
+	Rate       float64   `bson:"rate" json:"rate"`           // Percent, 0 for exempt or zero-rated classes
+	Inclusive  bool      `bson:"inclusive" json:"inclusive"` // Selling prices of the class already include the tax
+	Deleted    bool      `bson:"deleted" json:"deleted"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// TaxSummary is the tax charged at one rate of one tax class
+// TaxableAmount excludes the tax, also for tax-inclusive classes
+type TaxSummary struct {
+	TaxClassID    string  `bson:"taxClassId" json:"taxClassId"` // Empty for the bill-level tax of sales made before tax classes
+	Name          string  `bson:"name" json:"name"`
+	Rate          float64 `bson:"rate" json:"rate"`
+	Inclusive     bool    `bson:"inclusive" json:"inclusive"`
+	TaxableAmount Money   `bson:"taxableAmount" json:"taxableAmount"`
+	Tax           Money   `bson:"tax" json:"tax"`
+}
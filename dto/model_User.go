@@ -0,0 +1,64 @@
+package dto
+
+import "time"
+
+// User roles
+const (
+	RoleCashier     = "cashier"
+	RoleStockKeeper = "stock_keeper"
+	RoleManager     = "manager"
+	RoleAdmin       = "admin"
+)
+
+type User struct {
+	UserId       string    `bson:"userId" json:"userId"`
+	Username     string    `bson:"username" json:"username"`
+	FullName     string    `bson:"fullName,omitempty" json:"fullName,omitempty"`
+	PasswordHash string    `bson:"passwordHash" json:"-"` // bcrypt hash, never returned to clients
+	Role         string    `bson:"role" json:"role"`
+	Active       bool      `bson:"active" json:"active"`
+	TokenVersion int       `bson:"tokenVersion" json:"-"` // Bumped on password changes to revoke the tokens issued before
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// UserChanges are the fields of a user to update; nil fields are left as they are
+// A new PasswordHash also bumps TokenVersion, so tokens issued with the old password stop working
+type UserChanges struct {
+	FullName     *string
+	Role         *string
+	Active       *bool
+	PasswordHash *string
+}
+
+// Request DTOs
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	User      User      `json:"user"`
+}
+
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	FullName string `json:"fullName,omitempty"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+type UpdateUserRequest struct {
+	UserId   string  `json:"userId"`
+	FullName *string `json:"fullName,omitempty"`
+	Password *string `json:"password,omitempty"`
+	Role     *string `json:"role,omitempty"`
+	Active   *bool   `json:"active,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
@@ -0,0 +1,244 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// Money is an amount in cents, so adding and subtracting amounts is exact
+//
+// Rounding rules: anything finer than a cent (a float, a decimal with more places, a percentage or a share
+// of an amount) is rounded to the nearest cent, halves away from zero. Amounts are stored in MongoDB as
+// Decimal128 so aggregation pipelines stay exact too, and are written to JSON as numbers with two decimals.
+// Doubles and integers written before the migration to Decimal128 are still read.
+type Money int64
+
+// MoneyFromFloat converts a float amount such as 12.5 to Money, rounding to the nearest cent
+func MoneyFromFloat(amount float64) Money {
+	// Go through the shortest decimal representation so 0.285 is 28.5 cents, not 28.4999... cents
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0
+	}
+	r := ratFromFloat(amount)
+	m, err := moneyFromRat(r.Mul(r, big.NewRat(100, 1)))
+	if err != nil {
+		return 0
+	}
+	return m
+}
+
+// ParseMoney reads a decimal amount such as "1234.5" or "-0.125", rounding to the nearest cent
+func ParseMoney(amount string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return 0, fmt.Errorf("invalid money amount %q", amount)
+	}
+	return moneyFromRat(r.Mul(r, big.NewRat(100, 1)))
+}
+
+// moneyFromRat rounds an amount in cents to a whole cent, halves away from zero
+func moneyFromRat(cents *big.Rat) (Money, error) {
+	num := new(big.Int).Set(cents.Num())
+	den := cents.Denom()
+
+	// Add half the denominator (away from zero) and truncate
+	half := new(big.Int).Set(den)
+	if num.Sign() < 0 {
+		num.Mul(num, big.NewInt(2)).Sub(num, half)
+	} else {
+		num.Mul(num, big.NewInt(2)).Add(num, half)
+	}
+	num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+
+	if !num.IsInt64() {
+		return 0, errors.New("money amount out of range")
+	}
+	return Money(num.Int64()), nil
+}
+
+// Float returns the amount in major units, for display and ratios only
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// String formats the amount with two decimals, e.g. "1234.50"
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Times returns the amount multiplied by a quantity
+func (m Money) Times(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// Percent returns rate percent of the amount, rounded to the nearest cent
+func (m Money) Percent(rate float64) Money {
+	r := ratFromFloat(rate)
+	r.Mul(r, new(big.Rat).SetInt64(int64(m)))
+	r.Quo(r, big.NewRat(100, 1))
+	result, err := moneyFromRat(r)
+	if err != nil {
+		return 0
+	}
+	return result
+}
+
+// IncludedPercent returns the rate percent tax contained in an amount that already includes it,
+// amount x rate / (100 + rate), rounded to the nearest cent
+func (m Money) IncludedPercent(rate float64) Money {
+	r := ratFromFloat(rate)
+	gross := new(big.Rat).Add(r, big.NewRat(100, 1))
+	if gross.Sign() == 0 {
+		return 0
+	}
+	r.Mul(r, new(big.Rat).SetInt64(int64(m)))
+	r.Quo(r, gross)
+	result, err := moneyFromRat(r)
+	if err != nil {
+		return 0
+	}
+	return result
+}
+
+// ratFromFloat returns the shortest decimal that reads back as f, so a rate of 0.1 is exactly one tenth
+func ratFromFloat(f float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// Share returns the amount multiplied by part/whole, rounded to the nearest cent; zero if whole is zero
+// e.g. a line's share of a bill discount is discount.Share(lineValue, subtotal)
+func (m Money) Share(part Money, whole Money) Money {
+	if whole == 0 {
+		return 0
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(int64(part)), big.NewInt(int64(whole)))
+	r.Mul(r, new(big.Rat).SetInt64(int64(m)))
+	result, err := moneyFromRat(r)
+	if err != nil {
+		return 0
+	}
+	return result
+}
+
+// PercentOf returns the amount as a percentage of whole, rounded to two decimals; zero if whole is zero
+// e.g. a revenue change as a percentage of the previous revenue is change.PercentOf(previous)
+func (m Money) PercentOf(whole Money) float64 {
+	if whole == 0 {
+		return 0
+	}
+	// Hundredths of a percent, rounded like cents
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(10000)), big.NewInt(int64(whole)))
+	hundredths, err := moneyFromRat(r)
+	if err != nil {
+		return 0
+	}
+	return float64(hundredths) / 100
+}
+
+// Div returns the amount divided by n, rounded to the nearest cent; zero if n is zero
+func (m Money) Div(n int) Money {
+	if n == 0 {
+		return 0
+	}
+	result, err := moneyFromRat(big.NewRat(int64(m), int64(n)))
+	if err != nil {
+		return 0
+	}
+	return result
+}
+
+// Min returns the smaller of two amounts
+func (m Money) Min(other Money) Money {
+	if other < m {
+		return other
+	}
+	return m
+}
+
+// MarshalJSON writes the amount as a number with two decimals
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a number or a numeric string, rounding to the nearest cent
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalText reads a decimal amount such as "12.5", so YAML config files can hold amounts
+func (m *Money) UnmarshalText(text []byte) error {
+	parsed, err := ParseMoney(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Decimal128 returns the amount as a BSON decimal with two decimal places
+func (m Money) Decimal128() primitive.Decimal128 {
+	d, _ := primitive.ParseDecimal128(m.String())
+	return d
+}
+
+// MarshalBSONValue stores the amount as Decimal128
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bsontype.Decimal128, bsoncore.AppendDecimal128(nil, m.Decimal128()), nil
+}
+
+// UnmarshalBSONValue reads Decimal128, and the doubles and integers of documents not yet migrated
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bsoncore.Value{Type: t, Data: data}
+	switch t {
+	case bsontype.Decimal128:
+		parsed, err := ParseMoney(value.Decimal128().String())
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case bsontype.Double:
+		*m = MoneyFromFloat(value.Double())
+	case bsontype.Int32:
+		*m = Money(value.Int32()) * 100
+	case bsontype.Int64:
+		*m = Money(value.Int64()) * 100
+	case bsontype.Null, bsontype.Undefined:
+		*m = 0
+	default:
+		return fmt.Errorf("cannot read money from BSON %s", t)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMoneyRounding(t *testing.T) {
+	cases := map[float64]Money{
+		0.1 + 0.2: 30,
+		1.005:     101, // 1.00499999... as a float, rounded from its shortest decimal form
+		2.675:     268,
+		-0.125:    -13,
+		1234.5:    123450,
+		0.004:     0,
+	}
+	for amount, want := range cases {
+		if got := MoneyFromFloat(amount); got != want {
+			t.Errorf("MoneyFromFloat(%v) = %d, want %d", amount, got, want)
+		}
+	}
+
+	if got := Money(1000).Percent(12.5); got != 125 {
+		t.Errorf("12.5%% of 10.00 = %s, want 1.25", got)
+	}
+	if got := Money(5).Percent(50); got != 3 {
+		t.Errorf("50%% of 0.05 = %s, want 0.03 (half a cent rounds up)", got)
+	}
+	if got := Money(11800).IncludedPercent(18); got != 1800 {
+		t.Errorf("18%% tax in 118.00 = %s, want 18.00", got)
+	}
+	if got := Money(1000).Share(1, 3); got != 333 {
+		t.Errorf("a third of 10.00 = %s, want 3.33", got)
+	}
+	if got := Money(-1000).Div(3); got != -333 {
+		t.Errorf("-10.00 / 3 = %s, want -3.33", got)
+	}
+
+	// The classic float drift: ten 0.10 amounts add up to exactly 1.00
+	var total Money
+	for i := 0; i < 10; i++ {
+		total += MoneyFromFloat(0.1)
+	}
+	if total != 100 {
+		t.Errorf("ten 0.10 amounts = %s, want 1.00", total)
+	}
+}
+
+func TestMoneyPercentOf(t *testing.T) {
+	cases := []struct {
+		amount, whole Money
+		want          float64
+	}{
+		{amount: 25000, whole: 100000, want: 25},
+		{amount: 100, whole: 300, want: 33.33},
+		{amount: -200, whole: 300, want: -66.67},
+		{amount: 500, whole: 0, want: 0},
+	}
+	for _, tc := range cases {
+		if got := tc.amount.PercentOf(tc.whole); got != tc.want {
+			t.Errorf("%s.PercentOf(%s) = %v, want %v", tc.amount, tc.whole, got, tc.want)
+		}
+	}
+}
+
+func TestMoneyText(t *testing.T) {
+	var m Money
+	if err := m.UnmarshalText([]byte("12.345")); err != nil || m != 1235 {
+		t.Fatalf("expected 12.35, got %s, %v", m, err)
+	}
+	if err := m.UnmarshalText([]byte("twelve")); err == nil {
+		t.Fatal("expected an error for a non-numeric amount")
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var body struct {
+		Price Money `json:"price"`
+		Cost  Money `json:"cost"`
+	}
+	if err := json.Unmarshal([]byte(`{"price": 19.999, "cost": "7.5"}`), &body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body.Price != 2000 || body.Cost != 750 {
+		t.Fatalf("expected 20.00 and 7.50, got %s and %s", body.Price, body.Cost)
+	}
+
+	out, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != `{"price":20.00,"cost":7.50}` {
+		t.Fatalf("unexpected JSON %s", out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"price": "abc"}`), &body); err == nil {
+		t.Fatal("expected an error for a non-numeric amount")
+	}
+}
+
+func TestMoneyBSON(t *testing.T) {
+	type priced struct {
+		Price Money `bson:"price"`
+	}
+
+	raw, err := bson.Marshal(priced{Price: 123456})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var stored bson.M
+	if err := bson.Unmarshal(raw, &stored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d, ok := stored["price"].(primitive.Decimal128); !ok || d.String() != "1234.56" {
+		t.Fatalf("expected Decimal128 1234.56, got %T %v", stored["price"], stored["price"])
+	}
+
+	// Documents written before the migration hold doubles or whole numbers
+	exponent, _ := primitive.ParseDecimal128("1.999E+1")
+	legacy := []bson.M{{"price": 19.99}, {"price": int32(20)}, {"price": int64(21)}, {"price": exponent}}
+	want := []Money{1999, 2000, 2100, 1999}
+	for i, doc := range legacy {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var decoded priced
+		if err := bson.Unmarshal(raw, &decoded); err != nil {
+			t.Fatalf("decoding %v: %v", doc, err)
+		}
+		if decoded.Price != want[i] {
+			t.Errorf("decoding %v: got %s, want %s", doc, decoded.Price, want[i])
+		}
+	}
+}
@@ -1,17 +1,39 @@
 package dto
 
+// Return item conditions
+const (
+	ReturnConditionResellable = "resellable"
+	ReturnConditionDamaged    = "damaged"
+)
+
 type ReturnProduct struct {
-	ProductID string  `json:"productId"`
-	Amount    float64 `json:"amount"`
-	Reason    string  `json:"reason"` // Damaged, Defective, Unwanted, Wrong Item
+	ProductID string `json:"productId"`
+	Amount    Money  `json:"amount"` // Refund amount for this line
+	Reason    string `json:"reason"` // Damaged, Defective, Unwanted, Wrong Item
+
+	// Sale-linked returns
+	ProductName   string `json:"productName,omitempty"`
+	Quantity      int    `json:"quantity,omitempty"`
+	LineIndex     *int   `json:"lineIndex,omitempty"`     // Sale line returned from; spread over the product's lines when empty
+	UnitPrice     Money  `json:"unitPrice,omitempty"`     // Unit price on the original sale
+	DiscountShare Money  `json:"discountShare,omitempty"` // Part of the sale discount attributed to this line
+	LoyaltyShare  Money  `json:"loyaltyShare,omitempty"`  // Part of the points redeemed as a discount attributed to this line
+	TaxShare      Money  `json:"taxShare,omitempty"`      // Part of the sale tax attributed to this line
+	Condition     string `json:"condition,omitempty"`     // "resellable" or "damaged"
+	Restock       bool   `json:"restock,omitempty"`       // Put resellable items back into stock
+	BatchID       string `json:"batchId,omitempty"`       // Batch to restock into; chosen automatically when empty
+	WriteOffID    string `json:"writeOffId,omitempty"`    // Set for damaged items
 }
 
 type ReturnDTO struct {
 	ID                 string          `json:"id"`
+	SaleID             string          `json:"saleId,omitempty"`
 	CustomerName       string          `json:"customerName"`
 	ContactNumber      string          `json:"contactNumber"`
 	OriginalBillNumber string          `json:"originalBillNumber,omitempty"`
 	Products           []ReturnProduct `json:"products"`
+	TotalRefund        Money           `json:"totalRefund,omitempty"`
 	AdditionalNotes    string          `json:"additionalNotes,omitempty"`
+	ProcessedBy        string          `json:"processedBy,omitempty"`
 	CreatedAt          string          `json:"createdAt"`
 }
@@ -0,0 +1,104 @@
+package functions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"employee-crud/dto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned when a token is malformed, has a bad signature or has expired
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// tokenHeader is the fixed JWT header for HS256 signed tokens
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// TokenClaims is the payload carried in an auth token
+type TokenClaims struct {
+	UserId    string `json:"sub"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	Version   int    `json:"ver"` // The user's token version when it was issued
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// SignToken issues an HS256 signed JWT for the user, valid for ttl
+func SignToken(user *dto.User, secret []byte, ttl time.Duration, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(ttl)
+	payload, err := json.Marshal(TokenClaims{
+		UserId:    user.UserId,
+		Username:  user.Username,
+		Role:      user.Role,
+		Version:   user.TokenVersion,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + signTokenPart(unsigned, secret), expiresAt, nil
+}
+
+// ParseToken verifies the token's signature and expiry and returns its claims
+func ParseToken(token string, secret []byte, now time.Time) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return nil, ErrInvalidToken
+	}
+
+	expected := signTokenPart(parts[0]+"."+parts[1], secret)
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims TokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.UserId == "" || now.Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
+// IsValidRole reports whether role is one of the known user roles
+func IsValidRole(role string) bool {
+	switch role {
+	case dto.RoleCashier, dto.RoleStockKeeper, dto.RoleManager, dto.RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// RoleAllowed reports whether role may access a route restricted to allowed
+// Admins may access every route
+func RoleAllowed(role string, allowed []string) bool {
+	if role == dto.RoleAdmin {
+		return true
+	}
+	for _, r := range allowed {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func signTokenPart(unsigned string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,382 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// The Apply* functions below are the batch mutations shared by every product repository
+// They only change the product in memory; persisting it (and the ledger entries) is up to the caller
+// newBatchId is only called when a new batch is actually created
+
+// DatesMatch reports whether two expiry dates fall on the same day (two missing dates match)
+func DatesMatch(date1 *time.Time, date2 *time.Time) bool {
+	if date1 == nil && date2 == nil {
+		return true
+	}
+	if date1 == nil || date2 == nil {
+		return false
+	}
+	return date1.Format("2006-01-02") == date2.Format("2006-01-02")
+}
+
+// LaterExpiry reports whether date1 expires after date2, treating no expiry as the latest
+func LaterExpiry(date1 *time.Time, date2 *time.Time) bool {
+	if date1 == nil {
+		return date2 != nil
+	}
+	if date2 == nil {
+		return false
+	}
+	return date1.After(*date2)
+}
+
+// TotalBatchStock returns the sum of all batch quantities
+func TotalBatchStock(batches []dto.Batch) int {
+	total := 0
+	for _, batch := range batches {
+		total += batch.StockQty
+	}
+	return total
+}
+
+// CurrentSellingPrice returns the selling price for a newly received batch
+// It uses the most recently created batch's price, falling back to the product price
+func CurrentSellingPrice(product *dto.Product) dto.Money {
+	var latest *dto.Batch
+	for i := range product.Batches {
+		if latest == nil || product.Batches[i].CreatedAt.After(latest.CreatedAt) {
+			latest = &product.Batches[i]
+		}
+	}
+	if latest != nil && latest.SellingPrice > 0 {
+		return latest.SellingPrice
+	}
+	return product.SellingPrice
+}
+
+// ApplyAddStock adds stock to the batch at the location with the same expiry date, or creates a new batch there
+// Prices are only updated when given (> 0). Returns the batch that received the stock
+func ApplyAddStock(product *dto.Product, locationId string, stockQty int, expiryDate *time.Time, costPrice dto.Money, sellingPrice dto.Money, newBatchId func() (string, error), now time.Time) (string, error) {
+	locationId = NormalizeLocationId(locationId)
+
+	// Look for a batch at the location with matching expiry date
+	for i := range product.Batches {
+		if BatchLocation(&product.Batches[i]) == locationId && DatesMatch(product.Batches[i].ExpiryDate, expiryDate) {
+			// Found matching batch - add stock to it
+			product.Batches[i].StockQty += stockQty
+			product.Batches[i].UpdatedAt = now
+			// Update prices if provided and different
+			if costPrice > 0 {
+				product.Batches[i].CostPrice = costPrice
+			}
+			if sellingPrice > 0 {
+				product.Batches[i].SellingPrice = sellingPrice
+			}
+			product.StockQty = TotalBatchStock(product.Batches)
+			return product.Batches[i].BatchId, nil
+		}
+	}
+
+	// No matching expiry date (or no batches yet) - create new batch
+	batchId, err := newBatchId()
+	if err != nil {
+		return "", err
+	}
+
+	product.Batches = append(product.Batches, dto.Batch{
+		BatchId:      batchId,
+		LocationId:   locationId,
+		StockQty:     stockQty,
+		ExpiryDate:   expiryDate,
+		CostPrice:    costPrice,
+		SellingPrice: sellingPrice,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	product.StockQty = TotalBatchStock(product.Batches)
+	return batchId, nil
+}
+
+// ApplyEditBatchStock sets a batch's quantity; a batch set to 0 is removed
+func ApplyEditBatchStock(product *dto.Product, batchId string, newStockQty int, now time.Time) error {
+	if newStockQty < 0 {
+		return fmt.Errorf("stock quantity cannot be negative")
+	}
+
+	batch := findBatch(product, batchId)
+	if batch == nil {
+		return fmt.Errorf("batch not found: %s", batchId)
+	}
+	batch.StockQty = newStockQty
+	batch.UpdatedAt = now
+
+	removeEmptyBatches(product)
+	return nil
+}
+
+// ApplyEditBatchDetails updates a batch's expiry date and prices (only the ones given)
+func ApplyEditBatchDetails(product *dto.Product, batchId string, expiryDate *time.Time, costPrice dto.Money, sellingPrice dto.Money, now time.Time) error {
+	batch := findBatch(product, batchId)
+	if batch == nil {
+		return fmt.Errorf("batch not found: %s", batchId)
+	}
+
+	if expiryDate != nil {
+		batch.ExpiryDate = expiryDate
+	}
+	if costPrice > 0 {
+		batch.CostPrice = costPrice
+	}
+	if sellingPrice > 0 {
+		batch.SellingPrice = sellingPrice
+	}
+	batch.UpdatedAt = now
+	return nil
+}
+
+// ApplyRemoveStockFromBatch reduces a batch's quantity; a batch that reaches 0 is removed
+func ApplyRemoveStockFromBatch(product *dto.Product, batchId string, quantityToRemove int, now time.Time) error {
+	if quantityToRemove <= 0 {
+		return fmt.Errorf("quantity to remove must be greater than 0")
+	}
+
+	batch := findBatch(product, batchId)
+	if batch == nil {
+		return fmt.Errorf("batch not found: %s", batchId)
+	}
+	if batch.StockQty < quantityToRemove {
+		return fmt.Errorf("insufficient stock in batch %s: requested %d, available %d",
+			batchId, quantityToRemove, batch.StockQty)
+	}
+	batch.StockQty -= quantityToRemove
+	batch.UpdatedAt = now
+
+	removeEmptyBatches(product)
+	return nil
+}
+
+// ApplyDeleteBatch removes a batch completely
+func ApplyDeleteBatch(product *dto.Product, batchId string) error {
+	batchFound := false
+	var updatedBatches []dto.Batch
+
+	for _, batch := range product.Batches {
+		if batch.BatchId == batchId {
+			batchFound = true
+			// Skip this batch (delete it)
+			continue
+		}
+		updatedBatches = append(updatedBatches, batch)
+	}
+
+	if !batchFound {
+		return fmt.Errorf("batch not found: %s", batchId)
+	}
+
+	product.Batches = updatedBatches
+	product.StockQty = TotalBatchStock(updatedBatches)
+	return nil
+}
+
+// ApplySaleDeduction deducts units sold at a location from the product and returns the batches they were taken from
+// Only the batches held at the location are deducted, FEFO; legacy products without batches use their single
+// stockQty, which is held at the main location
+func ApplySaleDeduction(product *dto.Product, locationId string, quantitySold int, now time.Time) ([]dto.BatchAllocation, error) {
+	locationId = NormalizeLocationId(locationId)
+	if len(product.Batches) > 0 {
+		var here, elsewhere []dto.Batch
+		for _, batch := range product.Batches {
+			if BatchLocation(&batch) == locationId {
+				here = append(here, batch)
+			} else {
+				elsewhere = append(elsewhere, batch)
+			}
+		}
+		updatedBatches, err := DeductBatchesFEFO(here, quantitySold, now)
+		if err != nil {
+			return nil, fmt.Errorf("product %s at %s: %w", product.ProductId, locationId, err)
+		}
+		allocations := batchAllocations(here, updatedBatches)
+		product.Batches = append(elsewhere, updatedBatches...)
+		product.StockQty = TotalBatchStock(product.Batches)
+		return allocations, nil
+	}
+
+	// Legacy: product without batches
+	if locationId != dto.MainLocationId {
+		return nil, fmt.Errorf("product %s at %s: %w: requested %d, available 0",
+			product.ProductId, locationId, ErrInsufficientStock, quantitySold)
+	}
+	if product.StockQty < quantitySold {
+		return nil, fmt.Errorf("product %s: %w: requested %d, available %d",
+			product.ProductId, ErrInsufficientStock, quantitySold, product.StockQty)
+	}
+	product.StockQty -= quantitySold
+	return []dto.BatchAllocation{{
+		Quantity:     quantitySold,
+		CostPrice:    product.CostPrice,
+		SellingPrice: product.SellingPrice,
+		ExpiryDate:   product.ExpiryDate,
+	}}, nil
+}
+
+// batchAllocations compares batches before and after a FEFO deduction and returns what was taken from each,
+// earliest expiry first
+func batchAllocations(before []dto.Batch, after []dto.Batch) []dto.BatchAllocation {
+	remaining := make(map[string]int, len(after))
+	for _, batch := range after {
+		remaining[batch.BatchId] = batch.StockQty
+	}
+
+	var allocations []dto.BatchAllocation
+	for _, batch := range before {
+		taken := batch.StockQty - remaining[batch.BatchId]
+		if taken <= 0 {
+			continue
+		}
+		allocations = append(allocations, dto.BatchAllocation{
+			BatchID:      batch.BatchId,
+			BatchNumber:  batch.BatchNumber,
+			Quantity:     taken,
+			CostPrice:    batch.CostPrice,
+			SellingPrice: batch.SellingPrice,
+			ExpiryDate:   batch.ExpiryDate,
+		})
+	}
+	sort.SliceStable(allocations, func(i, j int) bool {
+		return LaterExpiry(allocations[j].ExpiryDate, allocations[i].ExpiryDate)
+	})
+	return allocations
+}
+
+// ApplyGRNItem receives a GRN line into the product's batches at a location and returns the batch it went into
+// A batch is only topped up if it is the same lot at the same location: same expiry, batch number and unit cost
+func ApplyGRNItem(product *dto.Product, item *dto.GRNItem, locationId string, newBatchId func() (string, error), now time.Time) (string, error) {
+	locationId = NormalizeLocationId(locationId)
+	for i := range product.Batches {
+		batch := &product.Batches[i]
+		if BatchLocation(batch) == locationId &&
+			DatesMatch(batch.ExpiryDate, item.ExpiryDate) &&
+			batch.BatchNumber == item.BatchNumber &&
+			batch.CostPrice == item.UnitCost {
+			batch.StockQty += item.ReceivedQty
+			batch.UpdatedAt = now
+			product.StockQty = TotalBatchStock(product.Batches)
+			return batch.BatchId, nil
+		}
+	}
+
+	batchId, err := newBatchId()
+	if err != nil {
+		return "", err
+	}
+
+	product.Batches = append(product.Batches, dto.Batch{
+		BatchId:      batchId,
+		BatchNumber:  item.BatchNumber,
+		LocationId:   locationId,
+		StockQty:     item.ReceivedQty,
+		ExpiryDate:   item.ExpiryDate,
+		CostPrice:    item.UnitCost,
+		SellingPrice: CurrentSellingPrice(product),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	product.StockQty = TotalBatchStock(product.Batches)
+	return batchId, nil
+}
+
+// ApplyReturnRestock puts returned units back into stock at a location and returns the batch they went into
+// It uses the requested batch, otherwise the last batch in soldFrom (the sale line's allocations) still held at
+// the location, otherwise the batch at the location with the latest expiry, otherwise a new batch there at the
+// product's current prices
+// Legacy products without batches just get their stockQty increased (empty batch id) at the main location
+func ApplyReturnRestock(product *dto.Product, quantity int, batchId string, soldFrom []dto.BatchAllocation, locationId string, newBatchId func() (string, error), now time.Time) (string, error) {
+	locationId = NormalizeLocationId(locationId)
+	if batchId == "" {
+		batchId = soldFromBatch(product, soldFrom, locationId)
+	}
+	if len(product.Batches) == 0 && product.StockQty > 0 && batchId == "" && locationId == dto.MainLocationId {
+		product.StockQty += quantity
+		return "", nil
+	}
+
+	target := -1
+	for i := range product.Batches {
+		if batchId != "" {
+			if product.Batches[i].BatchId == batchId {
+				target = i
+				break
+			}
+			continue
+		}
+		if BatchLocation(&product.Batches[i]) != locationId {
+			continue
+		}
+		if target == -1 || LaterExpiry(product.Batches[i].ExpiryDate, product.Batches[target].ExpiryDate) {
+			target = i
+		}
+	}
+
+	if batchId != "" && target == -1 {
+		return "", fmt.Errorf("%w: batch %s not found on product %s", ErrInvalidReturn, batchId, product.ProductId)
+	}
+
+	if target >= 0 {
+		product.Batches[target].StockQty += quantity
+		product.Batches[target].UpdatedAt = now
+		product.StockQty = TotalBatchStock(product.Batches)
+		return product.Batches[target].BatchId, nil
+	}
+
+	id, err := newBatchId()
+	if err != nil {
+		return "", err
+	}
+	product.Batches = append(product.Batches, dto.Batch{
+		BatchId:      id,
+		LocationId:   locationId,
+		StockQty:     quantity,
+		CostPrice:    product.CostPrice,
+		SellingPrice: product.SellingPrice,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	product.StockQty = TotalBatchStock(product.Batches)
+	return id, nil
+}
+
+func findBatch(product *dto.Product, batchId string) *dto.Batch {
+	for i := range product.Batches {
+		if product.Batches[i].BatchId == batchId {
+			return &product.Batches[i]
+		}
+	}
+	return nil
+}
+
+func removeEmptyBatches(product *dto.Product) {
+	var updatedBatches []dto.Batch
+	for _, batch := range product.Batches {
+		if batch.StockQty > 0 {
+			updatedBatches = append(updatedBatches, batch)
+		}
+	}
+	product.Batches = updatedBatches
+	product.StockQty = TotalBatchStock(updatedBatches)
+}
+
+// soldFromBatch returns the last of the allocated batches that the product still holds at the location
+func soldFromBatch(product *dto.Product, soldFrom []dto.BatchAllocation, locationId string) string {
+	for i := len(soldFrom) - 1; i >= 0; i-- {
+		for j := range product.Batches {
+			if product.Batches[j].BatchId == soldFrom[i].BatchID && BatchLocation(&product.Batches[j]) == locationId {
+				return soldFrom[i].BatchID
+			}
+		}
+	}
+	return ""
+}
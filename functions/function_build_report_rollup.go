@@ -0,0 +1,136 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"sort"
+	"time"
+)
+
+// topSellingCount is how many products a rollup lists as top selling, the same as the daily summary
+const topSellingCount = 10
+
+// BuildMonthlyRollup sums the saved daily reports of one month into a monthly rollup
+func BuildMonthlyRollup(year int, month int, reports []dto.DailyReportDocument, loc *time.Location, now time.Time) *dto.ReportRollup {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	rollup := &dto.ReportRollup{
+		Period:      dto.RollupMonthly,
+		Year:        year,
+		Month:       month,
+		PeriodStart: start,
+		PeriodEnd:   start.AddDate(0, 1, 0),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	products := make(map[string]*dto.ProductSoldSummary)
+	for _, report := range reports {
+		rollup.DaysCovered++
+		rollup.TotalSales += report.TotalSales
+		rollup.TotalRevenue += report.TotalRevenue
+		rollup.TotalDiscount += report.TotalDiscount
+		rollup.TotalTax += report.TotalTax
+		rollup.TaxBreakdown = AddTaxSummaries(rollup.TaxBreakdown, ReportTaxBreakdown(&report))
+		rollup.Tenders = AddTenderSummaries(rollup.Tenders, ReportTenders(&report))
+		addProductsSold(products, report.ProductsSold)
+	}
+
+	finishRollup(rollup, products)
+	return rollup
+}
+
+// BuildYearlyRollup sums the monthly rollups of one year into a yearly rollup
+func BuildYearlyRollup(year int, months []dto.ReportRollup, loc *time.Location, now time.Time) *dto.ReportRollup {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	rollup := &dto.ReportRollup{
+		Period:      dto.RollupYearly,
+		Year:        year,
+		PeriodStart: start,
+		PeriodEnd:   start.AddDate(1, 0, 0),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	products := make(map[string]*dto.ProductSoldSummary)
+	for _, month := range months {
+		rollup.MonthsCovered++
+		rollup.DaysCovered += month.DaysCovered
+		rollup.TotalSales += month.TotalSales
+		rollup.TotalRevenue += month.TotalRevenue
+		rollup.TotalDiscount += month.TotalDiscount
+		rollup.TotalTax += month.TotalTax
+		rollup.TaxBreakdown = AddTaxSummaries(rollup.TaxBreakdown, month.TaxBreakdown)
+		rollup.Tenders = AddTenderSummaries(rollup.Tenders, month.Tenders)
+		addProductsSold(products, month.ProductsSold)
+	}
+
+	finishRollup(rollup, products)
+	return rollup
+}
+
+// CompareRollups pairs each rollup with its change from the one before it
+// rollups must already be in period order
+func CompareRollups(rollups []dto.ReportRollup) []dto.RollupComparison {
+	comparisons := make([]dto.RollupComparison, 0, len(rollups))
+	for i, rollup := range rollups {
+		comparison := dto.RollupComparison{Rollup: rollup}
+		if i > 0 {
+			previous := rollups[i-1]
+			revenueChange := rollup.TotalRevenue - previous.TotalRevenue
+			salesChange := rollup.TotalSales - previous.TotalSales
+			comparison.RevenueChange = &revenueChange
+			comparison.SalesCountChange = &salesChange
+			if previous.TotalRevenue != 0 {
+				pct := revenueChange.PercentOf(previous.TotalRevenue)
+				comparison.RevenueChangePct = &pct
+			}
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons
+}
+
+// addProductsSold merges per-product quantities and revenue into products
+func addProductsSold(products map[string]*dto.ProductSoldSummary, sold []dto.ProductSoldSummary) {
+	for _, item := range sold {
+		if existing, exists := products[item.ProductID]; exists {
+			existing.Quantity += item.Quantity
+			existing.TotalAmount += item.TotalAmount
+			continue
+		}
+		copied := item
+		products[item.ProductID] = &copied
+	}
+}
+
+// finishRollup fills in the product lists
+// A product's UnitPrice becomes its average selling price over the period
+func finishRollup(rollup *dto.ReportRollup, products map[string]*dto.ProductSoldSummary) {
+	if rollup.TaxBreakdown == nil {
+		rollup.TaxBreakdown = []dto.TaxSummary{}
+	}
+	if rollup.Tenders == nil {
+		rollup.Tenders = []dto.TenderSummary{}
+	}
+
+	rollup.ProductsSold = make([]dto.ProductSoldSummary, 0, len(products))
+	for _, product := range products {
+		if product.Quantity > 0 {
+			product.UnitPrice = product.TotalAmount.Div(product.Quantity)
+		}
+		rollup.ProductsSold = append(rollup.ProductsSold, *product)
+	}
+
+	// Sort products by total amount (descending), then by id so the order is stable
+	sort.Slice(rollup.ProductsSold, func(i, j int) bool {
+		if rollup.ProductsSold[i].TotalAmount != rollup.ProductsSold[j].TotalAmount {
+			return rollup.ProductsSold[i].TotalAmount > rollup.ProductsSold[j].TotalAmount
+		}
+		return rollup.ProductsSold[i].ProductID < rollup.ProductsSold[j].ProductID
+	})
+
+	topCount := topSellingCount
+	if len(rollup.ProductsSold) < topCount {
+		topCount = len(rollup.ProductsSold)
+	}
+	rollup.TopSellingItems = rollup.ProductsSold[:topCount]
+}
@@ -0,0 +1,75 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"testing"
+	"time"
+)
+
+func TestBuildMonthlyRollupSumsDailyReports(t *testing.T) {
+	loc := time.UTC
+	reports := []dto.DailyReportDocument{
+		{
+			TotalSales: 2, TotalRevenue: dto.MoneyFromFloat(300), TotalDiscount: dto.MoneyFromFloat(10), TotalTax: dto.MoneyFromFloat(5),
+			CashSales: 1, CashRevenue: dto.MoneyFromFloat(100), CardSales: 1, CardRevenue: dto.MoneyFromFloat(200),
+			ProductsSold: []dto.ProductSoldSummary{
+				{ProductID: "PRD-001", ProductName: "Tea", Quantity: 2, UnitPrice: dto.MoneyFromFloat(50), TotalAmount: dto.MoneyFromFloat(100)},
+				{ProductID: "PRD-002", ProductName: "Milk", Quantity: 1, UnitPrice: dto.MoneyFromFloat(200), TotalAmount: dto.MoneyFromFloat(200)},
+			},
+		},
+		{
+			TotalSales: 1, TotalRevenue: dto.MoneyFromFloat(120),
+			Tenders: []dto.TenderSummary{{Type: dto.TenderCash, Sales: 1, Amount: dto.MoneyFromFloat(120)}},
+			ProductsSold: []dto.ProductSoldSummary{
+				{ProductID: "PRD-001", ProductName: "Tea", Quantity: 2, UnitPrice: dto.MoneyFromFloat(60), TotalAmount: dto.MoneyFromFloat(120)},
+			},
+		},
+	}
+
+	rollup := BuildMonthlyRollup(2026, 2, reports, loc, time.Now())
+
+	if rollup.Period != dto.RollupMonthly || rollup.DaysCovered != 2 {
+		t.Fatalf("expected a monthly rollup of 2 days, got %s of %d", rollup.Period, rollup.DaysCovered)
+	}
+	if !rollup.PeriodEnd.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, loc)) {
+		t.Fatalf("expected the period to end on March 1st, got %v", rollup.PeriodEnd)
+	}
+	if rollup.TotalSales != 3 || rollup.TotalRevenue != dto.MoneyFromFloat(420) {
+		t.Fatalf("unexpected totals %+v", rollup)
+	}
+	// The first report was saved before tenders and only has the legacy cash/card split
+	wantTenders := []dto.TenderSummary{{Type: dto.TenderCash, Sales: 2, Amount: dto.MoneyFromFloat(220)}, {Type: dto.TenderCard, Sales: 1, Amount: dto.MoneyFromFloat(200)}}
+	if len(rollup.Tenders) != len(wantTenders) || rollup.Tenders[0] != wantTenders[0] || rollup.Tenders[1] != wantTenders[1] {
+		t.Fatalf("expected tenders %+v, got %+v", wantTenders, rollup.Tenders)
+	}
+	if len(rollup.ProductsSold) != 2 {
+		t.Fatalf("expected 2 products, got %d", len(rollup.ProductsSold))
+	}
+	tea := rollup.ProductsSold[0]
+	if tea.ProductID != "PRD-001" || tea.Quantity != 4 || tea.TotalAmount != dto.MoneyFromFloat(220) || tea.UnitPrice != dto.MoneyFromFloat(55) {
+		t.Fatalf("expected Tea first with 4 units worth 220 at 55 average, got %+v", tea)
+	}
+}
+
+func TestBuildYearlyRollupAndCompare(t *testing.T) {
+	loc := time.UTC
+	january := BuildMonthlyRollup(2026, 1, []dto.DailyReportDocument{{TotalSales: 4, TotalRevenue: dto.MoneyFromFloat(400)}}, loc, time.Now())
+	february := BuildMonthlyRollup(2026, 2, []dto.DailyReportDocument{{TotalSales: 5, TotalRevenue: dto.MoneyFromFloat(500)}}, loc, time.Now())
+	empty := BuildMonthlyRollup(2026, 3, nil, loc, time.Now())
+
+	year := BuildYearlyRollup(2026, []dto.ReportRollup{*january, *february}, loc, time.Now())
+	if year.Period != dto.RollupYearly || year.MonthsCovered != 2 || year.TotalRevenue != dto.MoneyFromFloat(900) || year.TotalSales != 9 {
+		t.Fatalf("unexpected yearly rollup %+v", year)
+	}
+
+	comparisons := CompareRollups([]dto.ReportRollup{*january, *february, *empty})
+	if comparisons[0].RevenueChange != nil {
+		t.Fatal("the first period has nothing to compare against")
+	}
+	if *comparisons[1].RevenueChange != dto.MoneyFromFloat(100) || *comparisons[1].RevenueChangePct != 25 || *comparisons[1].SalesCountChange != 1 {
+		t.Fatalf("unexpected February change %+v", comparisons[1])
+	}
+	if *comparisons[2].RevenueChangePct != -100 {
+		t.Fatalf("expected a 100%% drop for March, got %v", *comparisons[2].RevenueChangePct)
+	}
+}
@@ -0,0 +1,90 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BuildStockMovements compares a product before and after a stock change and returns
+// one ledger entry for every batch whose quantity changed
+// Legacy products without batches are treated as a single batch with an empty batchId
+func BuildStockMovements(before *dto.Product, after *dto.Product, ref dto.StockMovementRef, now time.Time) []dto.StockMovement {
+	beforeBatches := batchesOrLegacy(before)
+	afterBatches := batchesOrLegacy(after)
+
+	afterById := make(map[string]dto.Batch, len(afterBatches))
+	for _, batch := range afterBatches {
+		afterById[batch.BatchId] = batch
+	}
+
+	var movements []dto.StockMovement
+	seen := make(map[string]bool, len(beforeBatches))
+
+	// Batches that existed before: changed or removed
+	for _, old := range beforeBatches {
+		seen[old.BatchId] = true
+		current, exists := afterById[old.BatchId]
+		balance := 0
+		snapshot := old
+		if exists {
+			balance = current.StockQty
+			snapshot = current
+		}
+		if delta := balance - old.StockQty; delta != 0 {
+			movements = append(movements, newStockMovement(after, snapshot, delta, balance, ref, now))
+		}
+	}
+
+	// Batches that were created by the change
+	for _, current := range afterBatches {
+		if seen[current.BatchId] || current.StockQty == 0 {
+			continue
+		}
+		movements = append(movements, newStockMovement(after, current, current.StockQty, current.StockQty, ref, now))
+	}
+
+	return movements
+}
+
+// batchesOrLegacy returns the product's batches, or a pseudo batch holding the stockQty of a legacy product
+func batchesOrLegacy(product *dto.Product) []dto.Batch {
+	if product == nil {
+		return nil
+	}
+	if len(product.Batches) > 0 {
+		return product.Batches
+	}
+	if product.StockQty == 0 {
+		return nil
+	}
+	return []dto.Batch{{
+		StockQty:     product.StockQty,
+		ExpiryDate:   product.ExpiryDate,
+		CostPrice:    product.CostPrice,
+		SellingPrice: product.SellingPrice,
+	}}
+}
+
+func newStockMovement(product *dto.Product, batch dto.Batch, delta int, balance int, ref dto.StockMovementRef, now time.Time) dto.StockMovement {
+	return dto.StockMovement{
+		MovementId:          uuid.New().String(),
+		ProductId:           product.ProductId,
+		ProductName:         product.Name,
+		BatchId:             batch.BatchId,
+		LocationId:          BatchLocation(&batch),
+		Type:                ref.Type,
+		Delta:               delta,
+		BalanceAfter:        balance,
+		ProductBalanceAfter: product.StockQty,
+		CostPrice:           batch.CostPrice,
+		SellingPrice:        batch.SellingPrice,
+		ExpiryDate:          batch.ExpiryDate,
+		ReferenceType:       ref.ReferenceType,
+		ReferenceId:         ref.ReferenceId,
+		UserId:              ref.UserId,
+		Note:                ref.Note,
+		CreatedAt:           now,
+	}
+}
@@ -0,0 +1,62 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"time"
+)
+
+// SetCartLine sets the quantity of a product's line in a cart, adding the line if it is new
+// A quantity of 0 or less removes the line; the other lines keep their order
+func SetCartLine(items []dto.CartItem, productId string, productName string, quantity int) []dto.CartItem {
+	for i := range items {
+		if items[i].ProductID != productId {
+			continue
+		}
+		if quantity <= 0 {
+			return append(items[:i:i], items[i+1:]...)
+		}
+		items[i].Quantity = quantity
+		items[i].ProductName = productName
+		return items
+	}
+	if quantity <= 0 {
+		return items
+	}
+	return append(items, dto.CartItem{ProductID: productId, ProductName: productName, Quantity: quantity})
+}
+
+// CartLineQuantity returns the quantity of a product in a cart, 0 if it has no line
+func CartLineQuantity(items []dto.CartItem, productId string) int {
+	for _, item := range items {
+		if item.ProductID == productId {
+			return item.Quantity
+		}
+	}
+	return 0
+}
+
+// CartSaleItems turns cart lines into unpriced sale lines, ready for the order summary and checkout
+func CartSaleItems(items []dto.CartItem) []dto.SaleItem {
+	saleItems := make([]dto.SaleItem, 0, len(items))
+	for _, item := range items {
+		saleItems = append(saleItems, dto.SaleItem{ProductID: item.ProductID, ProductName: item.ProductName, Quantity: item.Quantity})
+	}
+	return saleItems
+}
+
+// CartHoldsReservation reports whether a cart's lines are still held back from other carts and sales at now:
+// it reserves stock, is open or parked and its reservation has not run out
+func CartHoldsReservation(cart *dto.Cart, now time.Time) bool {
+	if !cart.ReserveStock || cart.ReservedUntil == nil || !now.Before(*cart.ReservedUntil) {
+		return false
+	}
+	return cart.Status == dto.CartOpen || cart.Status == dto.CartParked
+}
+
+// ConvertCart marks a cart as turned into the sale saleId and releases its reservation
+func ConvertCart(cart *dto.Cart, saleId string, now time.Time) {
+	cart.Status = dto.CartConverted
+	cart.SaleID = saleId
+	cart.ReservedUntil = nil
+	cart.UpdatedAt = now
+}
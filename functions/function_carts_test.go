@@ -0,0 +1,54 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"testing"
+	"time"
+)
+
+func TestSetCartLine(t *testing.T) {
+	var items []dto.CartItem
+	items = SetCartLine(items, "PRD-001", "Rice", 2)
+	items = SetCartLine(items, "PRD-002", "Milk", 1)
+	items = SetCartLine(items, "PRD-001", "Rice", 5)
+	if len(items) != 2 || items[0].ProductID != "PRD-001" || items[0].Quantity != 5 {
+		t.Fatalf("expected PRD-001 x5 then PRD-002, got %+v", items)
+	}
+
+	items = SetCartLine(items, "PRD-001", "Rice", 0)
+	if len(items) != 1 || items[0].ProductID != "PRD-002" {
+		t.Fatalf("expected only PRD-002 left, got %+v", items)
+	}
+
+	items = SetCartLine(items, "PRD-003", "Bread", 0)
+	if len(items) != 1 {
+		t.Fatalf("removing a product without a line should change nothing, got %+v", items)
+	}
+	if CartLineQuantity(items, "PRD-002") != 1 || CartLineQuantity(items, "PRD-001") != 0 {
+		t.Fatalf("unexpected line quantities in %+v", items)
+	}
+}
+
+func TestCartHoldsReservation(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	until := now.Add(10 * time.Minute)
+
+	cart := dto.Cart{Status: dto.CartParked, ReserveStock: true, ReservedUntil: &until}
+	if !CartHoldsReservation(&cart, now) {
+		t.Error("a parked cart inside its reservation should hold stock")
+	}
+	if CartHoldsReservation(&cart, until) {
+		t.Error("the reservation should run out at ReservedUntil")
+	}
+
+	cart.Status = dto.CartConverted
+	if CartHoldsReservation(&cart, now) {
+		t.Error("a converted cart should not hold stock")
+	}
+
+	cart.Status = dto.CartOpen
+	cart.ReserveStock = false
+	if CartHoldsReservation(&cart, now) {
+		t.Error("a cart that does not reserve stock should not hold stock")
+	}
+}
@@ -0,0 +1,108 @@
+package functions
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedBarcode is returned for text a Code 128 barcode cannot carry
+var ErrUnsupportedBarcode = errors.New("unsupported barcode text")
+
+// code128Patterns are the bar and space widths, in modules, of Code 128 symbol values 0 to 106 (106 is stop)
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Code 128 control values
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// Code128 encodes printable ASCII text as a Code 128 barcode and returns its bar and space widths in modules,
+// starting with a bar; quiet zones are left to the renderer
+// Runs of digits are packed two to a symbol (subset C) to keep the barcode short
+func Code128(text string) ([]int, error) {
+	symbols, err := code128Symbols(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var widths []int
+	for _, symbol := range symbols {
+		for _, width := range code128Patterns[symbol] {
+			widths = append(widths, int(width-'0'))
+		}
+	}
+	return widths, nil
+}
+
+// code128Symbols returns the symbol values of text: start, data, check symbol and stop
+func code128Symbols(text string) ([]int, error) {
+	if text == "" {
+		return nil, fmt.Errorf("%w: nothing to encode", ErrUnsupportedBarcode)
+	}
+	for _, r := range text {
+		if r < 32 || r > 126 {
+			return nil, fmt.Errorf("%w: %q is not printable ASCII", ErrUnsupportedBarcode, r)
+		}
+	}
+
+	var symbols []int
+	subsetC := digitRun(text, 0) >= 4 && digitRun(text, 0)%2 == 0
+	if subsetC {
+		symbols = append(symbols, code128StartC)
+	} else {
+		symbols = append(symbols, code128StartB)
+	}
+
+	for i := 0; i < len(text); {
+		if subsetC {
+			if digitRun(text, i) >= 2 {
+				symbols = append(symbols, int(text[i]-'0')*10+int(text[i+1]-'0'))
+				i += 2
+				continue
+			}
+			symbols = append(symbols, code128CodeB)
+			subsetC = false
+		}
+
+		// Switch to subset C for an even run of six digits, or four that end the text
+		run := digitRun(text, i)
+		if run%2 == 0 && (run >= 6 || (run >= 4 && i+run == len(text))) {
+			symbols = append(symbols, code128CodeC)
+			subsetC = true
+			continue
+		}
+		symbols = append(symbols, int(text[i])-32)
+		i++
+	}
+
+	check := symbols[0]
+	for i, symbol := range symbols[1:] {
+		check += (i + 1) * symbol
+	}
+	return append(symbols, check%103, code128Stop), nil
+}
+
+// digitRun returns the number of consecutive digits in text from index start
+func digitRun(text string, start int) int {
+	n := 0
+	for start+n < len(text) && text[start+n] >= '0' && text[start+n] <= '9' {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,59 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrInsufficientStock is returned when a deduction asks for more units than the product holds
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// DeductBatchesFEFO deducts quantity from batches using FEFO (First Expired First Out)
+// Batches without an expiry date are used last and batches that reach zero are removed
+// The input slice is not modified
+func DeductBatchesFEFO(batches []dto.Batch, quantity int, now time.Time) ([]dto.Batch, error) {
+	sorted := make([]dto.Batch, len(batches))
+	copy(sorted, batches)
+
+	// Sort batches by expiry date (earliest first), nil expiry dates go to the end
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].ExpiryDate == nil {
+			return false
+		}
+		if sorted[j].ExpiryDate == nil {
+			return true
+		}
+		return sorted[i].ExpiryDate.Before(*sorted[j].ExpiryDate)
+	})
+
+	remainingQty := quantity
+	var updatedBatches []dto.Batch
+	for _, batch := range sorted {
+		if remainingQty <= 0 {
+			updatedBatches = append(updatedBatches, batch)
+			continue
+		}
+
+		if batch.StockQty >= remainingQty {
+			// This batch has enough stock
+			batch.StockQty -= remainingQty
+			batch.UpdatedAt = now
+			remainingQty = 0
+			if batch.StockQty > 0 {
+				updatedBatches = append(updatedBatches, batch)
+			}
+		} else {
+			// This batch doesn't have enough stock, use all of it
+			remainingQty -= batch.StockQty
+		}
+	}
+
+	if remainingQty > 0 {
+		return nil, fmt.Errorf("%w: requested %d, available %d", ErrInsufficientStock, quantity, quantity-remainingQty)
+	}
+
+	return updatedBatches, nil
+}
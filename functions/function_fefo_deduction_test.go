@@ -0,0 +1,47 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeductBatchesFEFOUsesEarliestExpiryFirst(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	early := now.AddDate(0, 1, 0)
+	late := now.AddDate(0, 6, 0)
+
+	batches := []dto.Batch{
+		{BatchId: "BATCH-LATE", StockQty: 5, ExpiryDate: &late},
+		{BatchId: "BATCH-NONE", StockQty: 5},
+		{BatchId: "BATCH-EARLY", StockQty: 3, ExpiryDate: &early},
+	}
+
+	updated, err := DeductBatchesFEFO(batches, 4, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(updated) != 2 {
+		t.Fatalf("expected the early batch to be emptied and removed, got %d batches", len(updated))
+	}
+	if updated[0].BatchId != "BATCH-LATE" || updated[0].StockQty != 4 {
+		t.Fatalf("expected BATCH-LATE to hold 4 units, got %s with %d", updated[0].BatchId, updated[0].StockQty)
+	}
+	if updated[1].BatchId != "BATCH-NONE" || updated[1].StockQty != 5 {
+		t.Fatalf("expected batch without expiry to be untouched, got %s with %d", updated[1].BatchId, updated[1].StockQty)
+	}
+	if batches[2].StockQty != 3 {
+		t.Fatal("input batches must not be modified")
+	}
+}
+
+func TestDeductBatchesFEFOInsufficientStock(t *testing.T) {
+	batches := []dto.Batch{{BatchId: "BATCH-001", StockQty: 1}}
+
+	_, err := DeductBatchesFEFO(batches, 2, time.Now())
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("expected ErrInsufficientStock, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"sort"
+	"time"
+)
+
+// SaleLineRevenues returns what each line of a sale brought in, net of its promotion discount and of tax
+// The cashier's and loyalty discounts are on the whole bill; they are shared across the lines by value
+// and the shares add up to them exactly
+func SaleLineRevenues(sale *dto.Sale) []dto.Money {
+	revenues := make([]dto.Money, len(sale.Items))
+	var net dto.Money
+	for i, item := range sale.Items {
+		revenues[i] = item.TotalPrice - item.Discount
+		if item.TaxInclusive {
+			revenues[i] -= item.Tax
+		}
+		net += revenues[i]
+	}
+
+	billDiscount := sale.Discount + sale.LoyaltyDiscount
+	remaining := billDiscount
+	for i := range revenues {
+		share := billDiscount.Share(revenues[i], net)
+		if i == len(revenues)-1 {
+			share = remaining
+		}
+		revenues[i] -= share
+		remaining -= share
+	}
+	return revenues
+}
+
+// SaleLineCost returns the cost of goods of a sale line from the batches it was sold from
+// Lines sold before allocations were recorded are costed at the product's current cost price
+// and reported as estimated; a product that no longer exists costs nothing
+func SaleLineCost(item *dto.SaleItem, product *dto.Product) (cost dto.Money, estimated bool) {
+	if len(item.Batches) == 0 {
+		if product == nil {
+			return 0, true
+		}
+		return product.CostPrice.Times(item.Quantity), true
+	}
+	for _, allocation := range item.Batches {
+		cost += allocation.CostPrice.Times(allocation.Quantity)
+	}
+	return cost, false
+}
+
+// ProfitMargin returns gross profit as a percentage of revenue, rounded to two decimals; zero without revenue
+func ProfitMargin(revenue dto.Money, grossProfit dto.Money) float64 {
+	return grossProfit.PercentOf(revenue)
+}
+
+// SummarizeProfit works out the gross profit of sales by product, brand, category and, when loc is given,
+// business day; voided sales are left out
+// products holds the sold products by id with their brand and category names; a brand or category
+// without a name is shown by its id, and lines without a brand or category are grouped under "unknown"
+func SummarizeProfit(sales []dto.Sale, products map[string]*dto.Product, loc *time.Location) dto.GrossProfitReport {
+	byProduct := make(map[string]*dto.ProfitSummary)
+	byBrand := make(map[string]*dto.ProfitSummary)
+	byCategory := make(map[string]*dto.ProfitSummary)
+	byDay := make(map[string]*dto.ProfitSummary)
+
+	var report dto.GrossProfitReport
+	for _, sale := range sales {
+		if sale.Voided {
+			continue
+		}
+
+		day := ""
+		if loc != nil {
+			day = BusinessDay(sale.CreatedAt, loc).Format("2006-01-02")
+		}
+
+		revenues := SaleLineRevenues(&sale)
+		for i := range sale.Items {
+			item := &sale.Items[i]
+			product := products[item.ProductID]
+			cost, estimated := SaleLineCost(item, product)
+			if estimated {
+				report.EstimatedCostLines++
+			}
+
+			report.Revenue += revenues[i]
+			report.COGS += cost
+
+			var brandId, brandName, categoryId, categoryName string
+			if product != nil {
+				brandId, brandName = product.BrandID, product.BrandName
+				categoryId, categoryName = product.CategoryID, product.CategoryName
+			}
+			addProfit(byProduct, item.ProductID, item.ProductName, item.Quantity, revenues[i], cost)
+			addProfit(byBrand, brandId, brandName, item.Quantity, revenues[i], cost)
+			addProfit(byCategory, categoryId, categoryName, item.Quantity, revenues[i], cost)
+			if loc != nil {
+				addProfit(byDay, day, "", item.Quantity, revenues[i], cost)
+			}
+		}
+	}
+
+	report.GrossProfit = report.Revenue - report.COGS
+	report.MarginPercent = ProfitMargin(report.Revenue, report.GrossProfit)
+	report.ByProduct = sortedProfit(byProduct)
+	report.ByBrand = sortedProfit(byBrand)
+	report.ByCategory = sortedProfit(byCategory)
+	if loc != nil {
+		report.ByDay = sortedProfit(byDay)
+		// Days read best in date order
+		sort.Slice(report.ByDay, func(i, j int) bool {
+			return report.ByDay[i].Key < report.ByDay[j].Key
+		})
+	}
+	return report
+}
+
+func addProfit(groups map[string]*dto.ProfitSummary, key string, name string, quantity int, revenue dto.Money, cost dto.Money) {
+	if key == "" {
+		key = "unknown"
+	}
+	group, exists := groups[key]
+	if !exists {
+		group = &dto.ProfitSummary{Key: key, Name: name}
+		groups[key] = group
+	}
+	if group.Name == "" {
+		group.Name = name
+	}
+	group.Quantity += quantity
+	group.Revenue += revenue
+	group.COGS += cost
+}
+
+// sortedProfit finishes the groups' profit and margin and returns them by gross profit, highest first
+func sortedProfit(groups map[string]*dto.ProfitSummary) []dto.ProfitSummary {
+	summaries := make([]dto.ProfitSummary, 0, len(groups))
+	for _, group := range groups {
+		group.GrossProfit = group.Revenue - group.COGS
+		group.MarginPercent = ProfitMargin(group.Revenue, group.GrossProfit)
+		if group.Name == "" {
+			group.Name = group.Key
+		}
+		summaries = append(summaries, *group)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].GrossProfit != summaries[j].GrossProfit {
+			return summaries[i].GrossProfit > summaries[j].GrossProfit
+		}
+		return summaries[i].Key < summaries[j].Key
+	})
+	return summaries
+}
@@ -0,0 +1,75 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"testing"
+	"time"
+)
+
+func TestSaleLineRevenuesShareBillDiscounts(t *testing.T) {
+	sale := &dto.Sale{
+		Items: []dto.SaleItem{
+			{ProductID: "PRD-001", Quantity: 1, TotalPrice: dto.MoneyFromFloat(100)},
+			// Tax-inclusive line with a promotion: 236 - 36 = 200, of which 30 is tax
+			{ProductID: "PRD-002", Quantity: 2, TotalPrice: dto.MoneyFromFloat(236), Discount: dto.MoneyFromFloat(36), TaxInclusive: true, Tax: dto.MoneyFromFloat(30)},
+		},
+		Discount:        dto.MoneyFromFloat(20),
+		LoyaltyDiscount: dto.MoneyFromFloat(7),
+	}
+
+	revenues := SaleLineRevenues(sale)
+	// 27 of bill discounts over 100 and 170: 10 and 17
+	if revenues[0] != dto.MoneyFromFloat(90) || revenues[1] != dto.MoneyFromFloat(153) {
+		t.Fatalf("expected 90 and 153, got %v and %v", revenues[0], revenues[1])
+	}
+}
+
+func TestSummarizeProfitByBatchCost(t *testing.T) {
+	loc := time.UTC
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, loc)
+	products := map[string]*dto.Product{
+		"PRD-001": {ProductId: "PRD-001", BrandID: "BRD-001", BrandName: "Acme", CategoryID: "CAT-001", CategoryName: "Snacks", CostPrice: dto.MoneyFromFloat(65)},
+		"PRD-002": {ProductId: "PRD-002", BrandID: "BRD-001", BrandName: "Acme", CategoryID: "CAT-002", CostPrice: dto.MoneyFromFloat(30)},
+	}
+	sales := []dto.Sale{
+		{SaleID: "SALE-1", CreatedAt: day, Items: []dto.SaleItem{{
+			ProductID: "PRD-001", ProductName: "Chips", Quantity: 3, TotalPrice: dto.MoneyFromFloat(300),
+			Batches: []dto.BatchAllocation{
+				{BatchID: "BATCH-1", Quantity: 2, CostPrice: dto.MoneyFromFloat(50)},
+				{BatchID: "BATCH-2", Quantity: 1, CostPrice: dto.MoneyFromFloat(70)},
+			},
+		}}},
+		// Sold before allocations were recorded: costed at the current cost price
+		{SaleID: "SALE-2", CreatedAt: day.AddDate(0, 0, 1), Items: []dto.SaleItem{{
+			ProductID: "PRD-002", ProductName: "Soda", Quantity: 2, TotalPrice: dto.MoneyFromFloat(100),
+		}}},
+		{SaleID: "SALE-3", CreatedAt: day, Voided: true, Items: []dto.SaleItem{{
+			ProductID: "PRD-001", Quantity: 10, TotalPrice: dto.MoneyFromFloat(1000),
+		}}},
+	}
+
+	report := SummarizeProfit(sales, products, loc)
+	if report.Revenue != dto.MoneyFromFloat(400) || report.COGS != dto.MoneyFromFloat(230) || report.GrossProfit != dto.MoneyFromFloat(170) {
+		t.Fatalf("expected 400 revenue, 230 COGS and 170 profit, got %v, %v and %v", report.Revenue, report.COGS, report.GrossProfit)
+	}
+	if report.MarginPercent != 42.5 || report.EstimatedCostLines != 1 {
+		t.Fatalf("expected a 42.5%% margin with one estimated line, got %v%% and %d", report.MarginPercent, report.EstimatedCostLines)
+	}
+
+	if len(report.ByProduct) != 2 || report.ByProduct[0].Key != "PRD-001" || report.ByProduct[0].GrossProfit != dto.MoneyFromFloat(130) {
+		t.Fatalf("expected PRD-001 first with 130 profit, got %+v", report.ByProduct)
+	}
+	if len(report.ByBrand) != 1 || report.ByBrand[0].Name != "Acme" || report.ByBrand[0].COGS != dto.MoneyFromFloat(230) {
+		t.Fatalf("expected both products under Acme, got %+v", report.ByBrand)
+	}
+	if len(report.ByCategory) != 2 || report.ByCategory[1].Key != "CAT-002" || report.ByCategory[1].Name != "CAT-002" {
+		t.Fatalf("expected the unnamed category shown by its id, got %+v", report.ByCategory)
+	}
+	if len(report.ByDay) != 2 || report.ByDay[0].Key != "2026-03-10" || report.ByDay[1].MarginPercent != 40 {
+		t.Fatalf("expected two days in date order, got %+v", report.ByDay)
+	}
+
+	if daily := SummarizeProfit(sales, products, nil); daily.ByDay != nil {
+		t.Fatalf("expected no day breakdown without a location, got %+v", daily.ByDay)
+	}
+}
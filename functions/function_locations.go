@@ -0,0 +1,86 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidLocation is returned for a location without a name or with an unknown type or status
+var ErrInvalidLocation = errors.New("invalid location")
+
+// NormalizeLocationId returns the location a record belongs to; an empty id is the main location
+func NormalizeLocationId(locationId string) string {
+	if locationId == "" {
+		return dto.MainLocationId
+	}
+	return locationId
+}
+
+// BatchLocation returns the location a batch is held at
+func BatchLocation(batch *dto.Batch) string {
+	return NormalizeLocationId(batch.LocationId)
+}
+
+// SaleLocation returns the location a sale was made at
+func SaleLocation(sale *dto.Sale) string {
+	return NormalizeLocationId(sale.LocationId)
+}
+
+// LocationStock returns how much of a product is held at a location
+// A legacy product without batches is held at the main location
+func LocationStock(product *dto.Product, locationId string) int {
+	locationId = NormalizeLocationId(locationId)
+	if len(product.Batches) == 0 {
+		if locationId == dto.MainLocationId {
+			return product.StockQty
+		}
+		return 0
+	}
+
+	total := 0
+	for i := range product.Batches {
+		if BatchLocation(&product.Batches[i]) == locationId {
+			total += product.Batches[i].StockQty
+		}
+	}
+	return total
+}
+
+// SalesAtLocation returns the sales made at a location; an empty locationId returns every sale
+func SalesAtLocation(sales []dto.Sale, locationId string) []dto.Sale {
+	if locationId == "" {
+		return sales
+	}
+	var matching []dto.Sale
+	for i := range sales {
+		if SaleLocation(&sales[i]) == locationId {
+			matching = append(matching, sales[i])
+		}
+	}
+	return matching
+}
+
+// ValidateLocation trims a location's name and checks its type and status, defaulting them to store and active
+func ValidateLocation(location *dto.Location) error {
+	location.Name = strings.TrimSpace(location.Name)
+	if location.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidLocation)
+	}
+	switch location.Type {
+	case "":
+		location.Type = dto.LocationStore
+	case dto.LocationStore, dto.LocationWarehouse:
+	default:
+		return fmt.Errorf("%w: type must be store or warehouse", ErrInvalidLocation)
+	}
+	switch location.Status {
+	case "":
+		location.Status = "active"
+	case "active", "inactive":
+	default:
+		return fmt.Errorf("%w: status must be active or inactive", ErrInvalidLocation)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApplySaleDeductionTakesOnlyFromTheSaleLocation(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	soon := now.AddDate(0, 0, 5)
+	later := now.AddDate(0, 2, 0)
+
+	// The warehouse holds the earliest expiry, but a sale at the main store must not touch it
+	product := &dto.Product{
+		ProductId: "PRD-001",
+		Batches: []dto.Batch{
+			{BatchId: "BATCH-WH", LocationId: "LOC-001", StockQty: 50, ExpiryDate: &soon},
+			{BatchId: "BATCH-MAIN", StockQty: 4, ExpiryDate: &later},
+		},
+	}
+	product.StockQty = TotalBatchStock(product.Batches)
+
+	allocations, err := ApplySaleDeduction(product, "", 3, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(allocations) != 1 || allocations[0].BatchID != "BATCH-MAIN" || allocations[0].Quantity != 3 {
+		t.Fatalf("expected 3 from BATCH-MAIN, got %+v", allocations)
+	}
+	if LocationStock(product, dto.MainLocationId) != 1 || LocationStock(product, "LOC-001") != 50 || product.StockQty != 51 {
+		t.Fatalf("expected 1 at main and 50 at the warehouse, got %+v", product.Batches)
+	}
+
+	if _, err := ApplySaleDeduction(product, dto.MainLocationId, 2, now); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("expected ErrInsufficientStock with the warehouse stock left out, got %v", err)
+	}
+}
+
+func TestLegacyProductIsHeldAtTheMainLocation(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	product := &dto.Product{ProductId: "PRD-OLD", StockQty: 8}
+
+	if LocationStock(product, "") != 8 || LocationStock(product, "LOC-001") != 0 {
+		t.Fatalf("expected the legacy stock at main only")
+	}
+	if _, err := ApplySaleDeduction(product, "LOC-001", 1, now); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("expected ErrInsufficientStock at another location, got %v", err)
+	}
+}
+
+func TestApplyAddStockKeepsLocationsApart(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	expiry := now.AddDate(0, 6, 0)
+	product := &dto.Product{
+		ProductId: "PRD-001",
+		Batches:   []dto.Batch{{BatchId: "BATCH-MAIN", StockQty: 5, ExpiryDate: &expiry}},
+		StockQty:  5,
+	}
+	newBatchId := func() (string, error) { return "BATCH-NEW", nil }
+
+	// Same expiry at another location is a batch of its own
+	batchId, err := ApplyAddStock(product, "LOC-001", 7, &expiry, 0, 0, newBatchId, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if batchId != "BATCH-NEW" || len(product.Batches) != 2 || product.Batches[1].LocationId != "LOC-001" || product.StockQty != 12 {
+		t.Fatalf("expected a new batch at LOC-001, got %s in %+v", batchId, product.Batches)
+	}
+
+	// At the main location it tops up the existing batch
+	if batchId, _ = ApplyAddStock(product, "", 2, &expiry, 0, 0, newBatchId, now); batchId != "BATCH-MAIN" {
+		t.Fatalf("expected BATCH-MAIN topped up, got %s", batchId)
+	}
+	if LocationStock(product, dto.MainLocationId) != 7 || LocationStock(product, "LOC-001") != 7 {
+		t.Fatalf("expected 7 at each location, got %+v", product.Batches)
+	}
+}
+
+func TestSalesAtLocationCountsUntaggedSalesAtMain(t *testing.T) {
+	sales := []dto.Sale{
+		{SaleID: "S1"},
+		{SaleID: "S2", LocationId: dto.MainLocationId},
+		{SaleID: "S3", LocationId: "LOC-001"},
+	}
+
+	if got := SalesAtLocation(sales, ""); len(got) != 3 {
+		t.Fatalf("expected every sale without a location filter, got %d", len(got))
+	}
+	if got := SalesAtLocation(sales, dto.MainLocationId); len(got) != 2 || got[0].SaleID != "S1" || got[1].SaleID != "S2" {
+		t.Fatalf("expected S1 and S2 at main, got %+v", got)
+	}
+	if got := SalesAtLocation(sales, "LOC-001"); len(got) != 1 || got[0].SaleID != "S3" {
+		t.Fatalf("expected S3 at LOC-001, got %+v", got)
+	}
+}
+
+func TestValidateLocation(t *testing.T) {
+	location := dto.Location{Name: "  Branch  "}
+	if err := ValidateLocation(&location); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if location.Name != "Branch" || location.Type != dto.LocationStore || location.Status != "active" {
+		t.Fatalf("expected a trimmed active store, got %+v", location)
+	}
+
+	for _, bad := range []dto.Location{{Name: " "}, {Name: "Depot", Type: "shed"}, {Name: "Depot", Status: "closed"}} {
+		if err := ValidateLocation(&bad); !errors.Is(err, ErrInvalidLocation) {
+			t.Fatalf("expected ErrInvalidLocation for %+v, got %v", bad, err)
+		}
+	}
+}
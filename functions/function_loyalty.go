@@ -0,0 +1,69 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInsufficientPoints is returned when a customer redeems more loyalty points than they hold
+	ErrInsufficientPoints = errors.New("insufficient loyalty points")
+	// ErrInvalidRedemption is returned when a loyalty redemption breaks the points rule
+	ErrInvalidRedemption = errors.New("invalid loyalty redemption")
+)
+
+// PointsEarned returns the loyalty points earned on amount: one per earnPerAmount, rounded down
+// An earnPerAmount of 0 disables earning
+func PointsEarned(amount dto.Money, earnPerAmount dto.Money) int {
+	if earnPerAmount <= 0 || amount <= 0 {
+		return 0
+	}
+	return int(amount / earnPerAmount)
+}
+
+// RedemptionValue checks that points can be redeemed against a balance and returns what they are worth
+// The value may not exceed maxValue, the amount still due on the sale
+func RedemptionValue(points int, balance int, pointValue dto.Money, minPoints int, maxValue dto.Money) (dto.Money, error) {
+	if points <= 0 {
+		return 0, fmt.Errorf("%w: points to redeem must be positive", ErrInvalidRedemption)
+	}
+	if pointValue <= 0 {
+		return 0, fmt.Errorf("%w: redeeming points is disabled", ErrInvalidRedemption)
+	}
+	if points < minPoints {
+		return 0, fmt.Errorf("%w: at least %d points must be redeemed", ErrInvalidRedemption, minPoints)
+	}
+	if points > balance {
+		return 0, fmt.Errorf("%w: %d requested, %d available", ErrInsufficientPoints, points, balance)
+	}
+
+	value := pointValue.Times(points)
+	if value > maxValue {
+		return 0, fmt.Errorf("%w: %d points are worth %s, more than the %s due", ErrInvalidRedemption, points, value, maxValue)
+	}
+	return value, nil
+}
+
+// NormalizeMobile strips spaces, dashes, dots and brackets from a mobile number so lookups match however it was typed
+// Returns an empty string if anything other than digits and a leading + remains
+func NormalizeMobile(mobileNumber string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(mobileNumber) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return ""
+		}
+	}
+	normalized := b.String()
+	if strings.TrimPrefix(normalized, "+") == "" {
+		return ""
+	}
+	return normalized
+}
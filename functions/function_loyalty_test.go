@@ -0,0 +1,58 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+)
+
+func TestPointsEarnedRoundsDown(t *testing.T) {
+	cases := []struct {
+		amount        dto.Money
+		earnPerAmount dto.Money
+		want          int
+	}{
+		{amount: dto.MoneyFromFloat(250), earnPerAmount: dto.MoneyFromFloat(100), want: 2},
+		{amount: dto.MoneyFromFloat(299.99999999), earnPerAmount: dto.MoneyFromFloat(100), want: 3},
+		{amount: dto.MoneyFromFloat(299.99), earnPerAmount: dto.MoneyFromFloat(100), want: 2},
+		{amount: dto.MoneyFromFloat(99), earnPerAmount: dto.MoneyFromFloat(100), want: 0},
+		{amount: dto.MoneyFromFloat(500), earnPerAmount: dto.MoneyFromFloat(0), want: 0},
+	}
+	for _, tc := range cases {
+		if got := PointsEarned(tc.amount, tc.earnPerAmount); got != tc.want {
+			t.Errorf("PointsEarned(%v, %v) = %d, want %d", tc.amount, tc.earnPerAmount, got, tc.want)
+		}
+	}
+}
+
+func TestRedemptionValue(t *testing.T) {
+	value, err := RedemptionValue(50, 80, dto.MoneyFromFloat(2), 10, dto.MoneyFromFloat(500))
+	if err != nil || value != dto.MoneyFromFloat(100) {
+		t.Fatalf("expected 50 points worth 100, got %v, %v", value, err)
+	}
+
+	if _, err := RedemptionValue(100, 80, dto.MoneyFromFloat(2), 10, dto.MoneyFromFloat(500)); !errors.Is(err, ErrInsufficientPoints) {
+		t.Errorf("over the balance: expected ErrInsufficientPoints, got %v", err)
+	}
+	if _, err := RedemptionValue(5, 80, dto.MoneyFromFloat(2), 10, dto.MoneyFromFloat(500)); !errors.Is(err, ErrInvalidRedemption) {
+		t.Errorf("under the minimum: expected ErrInvalidRedemption, got %v", err)
+	}
+	if _, err := RedemptionValue(50, 80, dto.MoneyFromFloat(2), 10, dto.MoneyFromFloat(60)); !errors.Is(err, ErrInvalidRedemption) {
+		t.Errorf("worth more than due: expected ErrInvalidRedemption, got %v", err)
+	}
+}
+
+func TestNormalizeMobile(t *testing.T) {
+	cases := map[string]string{
+		"077 123-4567":   "0771234567",
+		"+94 (77) 12.34": "+94771234",
+		"077x1234":       "",
+		"+":              "",
+		"07+7":           "",
+	}
+	for in, want := range cases {
+		if got := NormalizeMobile(in); got != want {
+			t.Errorf("NormalizeMobile(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
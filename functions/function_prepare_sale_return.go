@@ -0,0 +1,221 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidReturn is returned when a return does not match what was sold on the original sale
+var ErrInvalidReturn = errors.New("invalid return")
+
+// PrepareSaleReturn validates the returned lines against the sale and prices them
+// A line may name the sale line it returns with LineIndex; otherwise its quantity is spread over the sale lines of
+// the product in order. Either way each sale line gives back at most what it sold minus what was already returned,
+// and a returned line that spans several sale lines is split so that every line is priced from its own sale line
+// Each line is refunded at the sale's unit price net of promotions, less its share of the bill discount and of the
+// points redeemed as a discount, plus the tax added to it. The discount and points shares are proportional to the
+// line's value in the sale subtotal after promotions, so value paid with points is never refunded as money;
+// the tax is the line's own, or for sales made before tax classes a share of the bill-level tax like the discount
+// On success the priced lines and the total refund are returned and sale.Items[].ReturnedQty is increased
+func PrepareSaleReturn(sale *dto.Sale, lines []dto.ReturnProduct) ([]dto.ReturnProduct, dto.Money, error) {
+	if len(lines) == 0 {
+		return nil, 0, fmt.Errorf("%w: no products to return", ErrInvalidReturn)
+	}
+	if sale.Voided {
+		return nil, 0, fmt.Errorf("%w: sale %s was voided", ErrInvalidReturn, sale.SaleID)
+	}
+
+	// What each sale line can still give back
+	remaining := make([]int, len(sale.Items))
+	for i, item := range sale.Items {
+		remaining[i] = item.Quantity - item.ReturnedQty
+	}
+
+	for i := range lines {
+		line := &lines[i]
+		if line.Quantity <= 0 {
+			return nil, 0, fmt.Errorf("%w: quantity for product %s must be greater than 0", ErrInvalidReturn, line.ProductID)
+		}
+		if line.Condition == "" {
+			line.Condition = dto.ReturnConditionResellable
+		}
+		if line.Condition != dto.ReturnConditionResellable && line.Condition != dto.ReturnConditionDamaged {
+			return nil, 0, fmt.Errorf("%w: condition must be '%s' or '%s'", ErrInvalidReturn, dto.ReturnConditionResellable, dto.ReturnConditionDamaged)
+		}
+	}
+
+	// Lines naming their sale line are taken first, so spreading the others cannot use up what they asked for
+	taken := make([][]int, len(lines)) // quantity taken from each sale line, per returned line
+	for i := range lines {
+		line := &lines[i]
+		if line.LineIndex == nil {
+			continue
+		}
+		index := *line.LineIndex
+		if index < 0 || index >= len(sale.Items) {
+			return nil, 0, fmt.Errorf("%w: sale %s has no line %d", ErrInvalidReturn, sale.SaleID, index)
+		}
+		if line.ProductID == "" {
+			line.ProductID = sale.Items[index].ProductID
+		}
+		if line.ProductID != sale.Items[index].ProductID {
+			return nil, 0, fmt.Errorf("%w: line %d of sale %s is not product %s", ErrInvalidReturn, index, sale.SaleID, line.ProductID)
+		}
+		if line.Quantity > remaining[index] {
+			return nil, 0, fmt.Errorf("%w: returning %d of line %d but only %d can still be returned",
+				ErrInvalidReturn, line.Quantity, index, remaining[index])
+		}
+		taken[i] = make([]int, len(sale.Items))
+		taken[i][index] = line.Quantity
+		remaining[index] -= line.Quantity
+	}
+
+	for i := range lines {
+		line := &lines[i]
+		if line.LineIndex != nil {
+			continue
+		}
+		available, sold := 0, false
+		for j := range sale.Items {
+			if sale.Items[j].ProductID == line.ProductID {
+				available += remaining[j]
+				sold = true
+			}
+		}
+		if !sold {
+			return nil, 0, fmt.Errorf("%w: product %s was not sold on sale %s", ErrInvalidReturn, line.ProductID, sale.SaleID)
+		}
+		if line.Quantity > available {
+			return nil, 0, fmt.Errorf("%w: returning %d of product %s but only %d can still be returned",
+				ErrInvalidReturn, line.Quantity, line.ProductID, available)
+		}
+
+		taken[i] = make([]int, len(sale.Items))
+		qty := line.Quantity
+		for j := range sale.Items {
+			if qty == 0 {
+				break
+			}
+			if sale.Items[j].ProductID != line.ProductID || remaining[j] == 0 {
+				continue
+			}
+			take := remaining[j]
+			if take > qty {
+				take = qty
+			}
+			taken[i][j] = take
+			remaining[j] -= take
+			qty -= take
+		}
+	}
+
+	var priced []dto.ReturnProduct
+	var totalRefund dto.Money
+	netSubtotal := sale.Subtotal - sale.PromotionDiscount
+	for i := range lines {
+		for j, quantity := range taken[i] {
+			if quantity == 0 {
+				continue
+			}
+			item := &sale.Items[j]
+			line := lines[i]
+			line.LineIndex = &j
+			line.Quantity = quantity
+			priceReturnLine(sale, item, &line, netSubtotal)
+
+			item.ReturnedQty += quantity
+			totalRefund += line.Amount
+			priced = append(priced, line)
+		}
+	}
+
+	return priced, totalRefund, nil
+}
+
+// priceReturnLine fills in the name, unit price, discount, points and tax shares and refund of a returned line
+// taken from one sale line
+func priceReturnLine(sale *dto.Sale, item *dto.SaleItem, line *dto.ReturnProduct, netSubtotal dto.Money) {
+	lineValue := netLineValue(item, line.Quantity)
+
+	line.ProductName = item.ProductName
+	line.UnitPrice = item.UnitPrice
+	line.DiscountShare = 0
+	line.LoyaltyShare = 0
+	line.TaxShare = 0
+	if netSubtotal > 0 {
+		line.DiscountShare = sale.Discount.Share(lineValue, netSubtotal)
+		line.LoyaltyShare = sale.LoyaltyDiscount.Share(lineValue, netSubtotal)
+		line.TaxShare = sale.Tax.Share(lineValue, netSubtotal)
+	}
+	if len(sale.TaxBreakdown) > 0 {
+		// Taxed per line: refund the line's own added tax; inclusive tax is already in lineValue
+		line.TaxShare = 0
+		if !item.TaxInclusive && item.Quantity > 0 {
+			line.TaxShare = item.Tax.Times(line.Quantity).Div(item.Quantity)
+		}
+	}
+	line.Amount = lineValue - line.DiscountShare - line.LoyaltyShare + line.TaxShare
+}
+
+// ReturnReversal is what a return takes back off the customer its sale was booked on
+type ReturnReversal struct {
+	Spend          dto.Money // Taken off lifetime spend
+	PointsEarned   int       // Taken back off the balance
+	PointsRedeemed int       // Given back, as the refund leaves out what they paid for
+	SaleCount      int       // Taken off the sale count
+}
+
+// ReturnedSaleValue is the value after promotions of everything already returned on the sale
+// Pass it to SaleReturnReversal as taken before PrepareSaleReturn records a new return
+func ReturnedSaleValue(sale *dto.Sale) dto.Money {
+	var value dto.Money
+	for i := range sale.Items {
+		value += netLineValue(&sale.Items[i], sale.Items[i].ReturnedQty)
+	}
+	return value
+}
+
+// SaleReturnReversal works out what a return takes back off the sale's customer, the pro-rata version of a void
+// Lifetime spend falls by the refund. Points earned are taken back, and points redeemed as a discount given back,
+// in proportion to the sale's value returned so far (returnedBefore is ReturnedSaleValue before the return),
+// so a sale returned in several parts ends up reversed exactly like a void. The sale stops counting towards the
+// customer's sale count once every unit has been returned
+func SaleReturnReversal(sale *dto.Sale, returnedBefore dto.Money, refund dto.Money) ReturnReversal {
+	reversal := ReturnReversal{Spend: refund}
+
+	var soldValue dto.Money
+	fullyReturned := true
+	for i := range sale.Items {
+		item := &sale.Items[i]
+		soldValue += netLineValue(item, item.Quantity)
+		if item.ReturnedQty < item.Quantity {
+			fullyReturned = false
+		}
+	}
+	if fullyReturned {
+		reversal.SaleCount = 1
+	}
+	if soldValue <= 0 {
+		return reversal
+	}
+
+	returnedAfter := ReturnedSaleValue(sale)
+	pointsShare := func(points int, value dto.Money) int {
+		return int(math.Round(float64(points) * float64(value) / float64(soldValue)))
+	}
+	reversal.PointsEarned = pointsShare(sale.PointsEarned, returnedAfter) - pointsShare(sale.PointsEarned, returnedBefore)
+	if sale.LoyaltyDiscount > 0 {
+		reversal.PointsRedeemed = pointsShare(sale.PointsRedeemed, returnedAfter) - pointsShare(sale.PointsRedeemed, returnedBefore)
+	}
+	return reversal
+}
+
+// netLineValue is what quantity units of a sale line cost after its promotion discount
+func netLineValue(item *dto.SaleItem, quantity int) dto.Money {
+	if item.Discount == 0 || item.Quantity == 0 {
+		return item.UnitPrice.Times(quantity)
+	}
+	return (item.TotalPrice - item.Discount).Times(quantity).Div(item.Quantity)
+}
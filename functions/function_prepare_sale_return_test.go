@@ -0,0 +1,132 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPrepareSaleReturnLeavesOutPointsRedeemedAsDiscount(t *testing.T) {
+	// 400 of goods, 20 points redeemed for 40 off, 3 points earned on the 360 paid
+	sale := &dto.Sale{
+		SaleID: "SALE-1",
+		Items: []dto.SaleItem{
+			{ProductID: "PRD-001", Quantity: 2, UnitPrice: dto.MoneyFromFloat(100), TotalPrice: dto.MoneyFromFloat(200)},
+			{ProductID: "PRD-002", Quantity: 1, UnitPrice: dto.MoneyFromFloat(200), TotalPrice: dto.MoneyFromFloat(200)},
+		},
+		Subtotal:        dto.MoneyFromFloat(400),
+		LoyaltyDiscount: dto.MoneyFromFloat(40),
+		Total:           dto.MoneyFromFloat(360),
+		PointsRedeemed:  20,
+		PointsEarned:    3,
+	}
+
+	before := ReturnedSaleValue(sale)
+	lines := []dto.ReturnProduct{{ProductID: "PRD-001", Quantity: 2}}
+	priced, refund, err := PrepareSaleReturn(sale, lines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refund != dto.MoneyFromFloat(180) || priced[0].LoyaltyShare != dto.MoneyFromFloat(20) {
+		t.Fatalf("expected 180 refunded with 20 of points left out, got %v and %+v", refund, priced[0])
+	}
+	reversal := SaleReturnReversal(sale, before, refund)
+	if reversal != (ReturnReversal{Spend: dto.MoneyFromFloat(180), PointsEarned: 2, PointsRedeemed: 10}) {
+		t.Fatalf("expected half the points reversed and the sale still counted, got %+v", reversal)
+	}
+
+	// Returning the rest reverses exactly what the sale booked
+	before = ReturnedSaleValue(sale)
+	lines = []dto.ReturnProduct{{ProductID: "PRD-002", Quantity: 1}}
+	if _, refund, err = PrepareSaleReturn(sale, lines); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reversal = SaleReturnReversal(sale, before, refund)
+	if reversal != (ReturnReversal{Spend: dto.MoneyFromFloat(180), PointsEarned: 1, PointsRedeemed: 10, SaleCount: 1}) {
+		t.Fatalf("expected the remaining points and the sale count reversed, got %+v", reversal)
+	}
+}
+
+func TestSaleReturnReversalKeepsPointsPaidAsATender(t *testing.T) {
+	// Points tendered as payment are refunded with the rest of the money, so they are not given back as well
+	sale := &dto.Sale{
+		SaleID:         "SALE-2",
+		Items:          []dto.SaleItem{{ProductID: "PRD-001", Quantity: 1, UnitPrice: dto.MoneyFromFloat(100), TotalPrice: dto.MoneyFromFloat(100)}},
+		Subtotal:       dto.MoneyFromFloat(100),
+		Total:          dto.MoneyFromFloat(100),
+		PointsRedeemed: 10,
+	}
+
+	lines := []dto.ReturnProduct{{ProductID: "PRD-001", Quantity: 1}}
+	_, refund, err := PrepareSaleReturn(sale, lines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reversal := SaleReturnReversal(sale, 0, refund); reversal.PointsRedeemed != 0 || reversal.Spend != dto.MoneyFromFloat(100) {
+		t.Fatalf("expected only the spend reversed, got %+v", reversal)
+	}
+}
+
+func TestPrepareSaleReturnPricesEachSaleLineOfAProduct(t *testing.T) {
+	// The same product sold on two lines: 2 at full price, then 2 with 50 off the line
+	sale := &dto.Sale{
+		SaleID: "SALE-3",
+		Items: []dto.SaleItem{
+			{ProductID: "PRD-001", Quantity: 2, UnitPrice: dto.MoneyFromFloat(100), TotalPrice: dto.MoneyFromFloat(200)},
+			{ProductID: "PRD-001", Quantity: 2, UnitPrice: dto.MoneyFromFloat(100), TotalPrice: dto.MoneyFromFloat(200), Discount: dto.MoneyFromFloat(50)},
+		},
+		Subtotal:          dto.MoneyFromFloat(400),
+		PromotionDiscount: dto.MoneyFromFloat(50),
+		Total:             dto.MoneyFromFloat(350),
+	}
+
+	// Naming the discounted line refunds what was paid on it
+	second := 1
+	priced, refund, err := PrepareSaleReturn(sale, []dto.ReturnProduct{{Quantity: 1, LineIndex: &second}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refund != dto.MoneyFromFloat(75) || priced[0].ProductID != "PRD-001" {
+		t.Fatalf("expected 75 back for one discounted unit, got %v and %+v", refund, priced)
+	}
+
+	// Without a line the quantity is spread in order and split per sale line
+	priced, refund, err = PrepareSaleReturn(sale, []dto.ReturnProduct{{ProductID: "PRD-001", Quantity: 3}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refund != dto.MoneyFromFloat(275) || len(priced) != 2 || priced[0].Quantity != 2 || *priced[1].LineIndex != 1 {
+		t.Fatalf("expected 2 at 100 and 1 at 75, got %v and %+v", refund, priced)
+	}
+	if sale.Items[0].ReturnedQty != 2 || sale.Items[1].ReturnedQty != 2 {
+		t.Fatalf("expected both lines fully returned, got %+v", sale.Items)
+	}
+
+	if _, _, err := PrepareSaleReturn(sale, []dto.ReturnProduct{{Quantity: 1, LineIndex: &second}}); !errors.Is(err, ErrInvalidReturn) {
+		t.Fatalf("expected ErrInvalidReturn past the line's sold quantity, got %v", err)
+	}
+}
+
+func TestApplyReturnRestockPrefersTheBatchTheLineWasSoldFrom(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	soon, later := now.AddDate(0, 1, 0), now.AddDate(0, 6, 0)
+	product := &dto.Product{
+		ProductId: "PRD-001",
+		Batches: []dto.Batch{
+			{BatchId: "BATCH-OLD", StockQty: 1, ExpiryDate: &soon},
+			{BatchId: "BATCH-NEW", StockQty: 1, ExpiryDate: &later},
+		},
+	}
+	newBatchId := func() (string, error) { return "BATCH-X", nil }
+
+	soldFrom := []dto.BatchAllocation{{BatchID: "BATCH-GONE", Quantity: 1}, {BatchID: "BATCH-OLD", Quantity: 1}}
+	if batchId, err := ApplyReturnRestock(product, 1, "", soldFrom, "", newBatchId, now); err != nil || batchId != "BATCH-OLD" {
+		t.Fatalf("expected BATCH-OLD, got %s, %v", batchId, err)
+	}
+
+	// Without allocations still on the product it falls back to the latest expiry
+	if batchId, _ := ApplyReturnRestock(product, 1, "", soldFrom[:1], "", newBatchId, now); batchId != "BATCH-NEW" {
+		t.Fatalf("expected BATCH-NEW, got %s", batchId)
+	}
+}
@@ -0,0 +1,344 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrInvalidPromotion is returned when a promotion's rule or conditions are incomplete or inconsistent
+var ErrInvalidPromotion = errors.New("invalid promotion")
+
+// ValidatePromotion checks that a promotion has everything its type and scope need
+func ValidatePromotion(p *dto.Promotion) error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	}
+
+	switch p.Type {
+	case dto.PromotionPercentage:
+		if p.Value <= 0 || p.Value > 100 {
+			return fmt.Errorf("%w: a percentage must be more than 0 and at most 100", ErrInvalidPromotion)
+		}
+	case dto.PromotionFixed:
+		if p.Value <= 0 {
+			return fmt.Errorf("%w: a fixed discount must be more than 0", ErrInvalidPromotion)
+		}
+	case dto.PromotionBuyXGetY:
+		if p.BuyQty <= 0 || p.GetQty <= 0 {
+			return fmt.Errorf("%w: buyQty and getQty must be more than 0", ErrInvalidPromotion)
+		}
+	case dto.PromotionBundle:
+		if p.BundleQty < 2 || p.BundlePrice <= 0 {
+			return fmt.Errorf("%w: a bundle needs bundleQty of at least 2 and a bundlePrice", ErrInvalidPromotion)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type '%s'", ErrInvalidPromotion, p.Type)
+	}
+
+	switch p.Scope {
+	case dto.PromotionScopeBasket:
+		if len(p.TargetIDs) > 0 {
+			return fmt.Errorf("%w: a basket promotion has no targetIds", ErrInvalidPromotion)
+		}
+	case dto.PromotionScopeProduct, dto.PromotionScopeBrand, dto.PromotionScopeCategory, dto.PromotionScopeSubCategory:
+		if len(p.TargetIDs) == 0 {
+			return fmt.Errorf("%w: targetIds are required for %s scope", ErrInvalidPromotion, p.Scope)
+		}
+	default:
+		return fmt.Errorf("%w: unknown scope '%s'", ErrInvalidPromotion, p.Scope)
+	}
+
+	if p.MinBasketValue < 0 {
+		return fmt.Errorf("%w: minBasketValue cannot be negative", ErrInvalidPromotion)
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return fmt.Errorf("%w: endsAt must be after startsAt", ErrInvalidPromotion)
+	}
+	for _, day := range p.DaysOfWeek {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("%w: daysOfWeek must be 0 (Sunday) to 6 (Saturday)", ErrInvalidPromotion)
+		}
+	}
+	if (p.StartTime == "") != (p.EndTime == "") {
+		return fmt.Errorf("%w: startTime and endTime must be given together", ErrInvalidPromotion)
+	}
+	if p.StartTime != "" {
+		start, err := parseClock(p.StartTime)
+		if err != nil {
+			return err
+		}
+		end, err := parseClock(p.EndTime)
+		if err != nil {
+			return err
+		}
+		if start == end {
+			return fmt.Errorf("%w: startTime and endTime cannot be the same", ErrInvalidPromotion)
+		}
+	}
+	return nil
+}
+
+// parseClock returns the minutes after midnight of an "HH:MM" time
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("%w: '%s' is not an HH:MM time", ErrInvalidPromotion, clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// PromotionRunsAt reports whether a promotion applies at now: active, within its dates, on one of its days and
+// inside its daily time window. now must be in store time, since days and times are compared as they are
+func PromotionRunsAt(p *dto.Promotion, now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+
+	if len(p.DaysOfWeek) > 0 {
+		onDay := false
+		for _, day := range p.DaysOfWeek {
+			if time.Weekday(day) == now.Weekday() {
+				onDay = true
+				break
+			}
+		}
+		if !onDay {
+			return false
+		}
+	}
+
+	if p.StartTime != "" && p.EndTime != "" {
+		start, err := parseClock(p.StartTime)
+		if err != nil {
+			return false
+		}
+		end, err := parseClock(p.EndTime)
+		if err != nil {
+			return false
+		}
+		minute := now.Hour()*60 + now.Minute()
+		if start < end {
+			return minute >= start && minute < end
+		}
+		// Overnight window such as 22:00 to 02:00
+		return minute >= start || minute < end
+	}
+	return true
+}
+
+// ApplyPromotions discounts priced sale lines with the promotions that run at now (in store time)
+// products holds the product of each line by id and is used for brand and category scopes
+// Line promotions go highest priority first, each line taking at most one; basket promotions then discount
+// what is left of every line. Each item's Discount and PromotionIDs are overwritten.
+// Returns the promotions that gave a discount and the total discount
+func ApplyPromotions(items []dto.SaleItem, products map[string]*dto.Product, promotions []dto.Promotion, now time.Time) ([]dto.AppliedPromotion, dto.Money) {
+	var subtotal dto.Money
+	for i := range items {
+		items[i].Discount = 0
+		items[i].PromotionIDs = nil
+		subtotal += items[i].TotalPrice
+	}
+
+	ordered := append([]dto.Promotion(nil), promotions...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority > ordered[j].Priority
+		}
+		return ordered[i].PromotionID < ordered[j].PromotionID
+	})
+
+	var applied []dto.AppliedPromotion
+	var total dto.Money
+	taken := make([]bool, len(items))
+	for _, basketPass := range []bool{false, true} {
+		for i := range ordered {
+			p := &ordered[i]
+			if (p.Scope == dto.PromotionScopeBasket) != basketPass || !PromotionRunsAt(p, now) || subtotal < p.MinBasketValue {
+				continue
+			}
+
+			var lines []int
+			for j := range items {
+				if basketPass || (!taken[j] && promotionMatches(p, &items[j], products[items[j].ProductID])) {
+					lines = append(lines, j)
+				}
+			}
+
+			discounts := promotionDiscounts(p, items, lines)
+			var given dto.Money
+			for k, j := range lines {
+				if discounts[k] <= 0 {
+					continue
+				}
+				items[j].Discount += discounts[k]
+				items[j].PromotionIDs = append(items[j].PromotionIDs, p.PromotionID)
+				taken[j] = true
+				given += discounts[k]
+			}
+			if given > 0 {
+				applied = append(applied, dto.AppliedPromotion{PromotionID: p.PromotionID, Name: p.Name, Discount: given})
+				total += given
+			}
+		}
+	}
+	return applied, total
+}
+
+// promotionMatches reports whether a line promotion targets the item's product, brand, category or subcategory
+func promotionMatches(p *dto.Promotion, item *dto.SaleItem, product *dto.Product) bool {
+	var id string
+	switch p.Scope {
+	case dto.PromotionScopeProduct:
+		id = item.ProductID
+	case dto.PromotionScopeBrand, dto.PromotionScopeCategory, dto.PromotionScopeSubCategory:
+		if product == nil {
+			return false
+		}
+		id = map[string]string{
+			dto.PromotionScopeBrand:       product.BrandID,
+			dto.PromotionScopeCategory:    product.CategoryID,
+			dto.PromotionScopeSubCategory: product.SubCategoryID,
+		}[p.Scope]
+	default:
+		return false
+	}
+
+	for _, target := range p.TargetIDs {
+		if target == id && id != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// saleUnit is one unit of a sale line at what is still left to pay for it
+type saleUnit struct {
+	line  int
+	price dto.Money
+}
+
+// promotionDiscounts returns the discount a promotion gives each of lines (indexes into items),
+// worked out on what is left of each line after earlier promotions
+func promotionDiscounts(p *dto.Promotion, items []dto.SaleItem, lines []int) []dto.Money {
+	discounts := make([]dto.Money, len(lines))
+	remaining := make([]dto.Money, len(lines))
+	var remainingTotal dto.Money
+	for k, j := range lines {
+		remaining[k] = items[j].TotalPrice - items[j].Discount
+		if remaining[k] < 0 {
+			remaining[k] = 0
+		}
+		remainingTotal += remaining[k]
+	}
+	if remainingTotal <= 0 {
+		return discounts
+	}
+
+	switch p.Type {
+	case dto.PromotionPercentage:
+		for k := range lines {
+			discounts[k] = remaining[k].Percent(p.Value)
+		}
+
+	case dto.PromotionFixed:
+		if p.Scope == dto.PromotionScopeBasket {
+			// Once off the basket, shared out in proportion to what is left of each line
+			amount := dto.MoneyFromFloat(p.Value).Min(remainingTotal)
+			shareLines(discounts, remaining, remainingTotal, amount)
+			break
+		}
+		for k, j := range lines {
+			if items[j].Quantity <= 0 {
+				continue
+			}
+			discounts[k] = dto.MoneyFromFloat(p.Value).Times(items[j].Quantity)
+		}
+
+	case dto.PromotionBuyXGetY, dto.PromotionBundle:
+		// Group the units most expensive first, so the free or bundled units favour the customer
+		var units []saleUnit
+		for k, j := range lines {
+			units = append(units, splitUnits(k, remaining[k], items[j].Quantity)...)
+		}
+		sort.SliceStable(units, func(a, b int) bool { return units[a].price > units[b].price })
+
+		size := p.BundleQty
+		if p.Type == dto.PromotionBuyXGetY {
+			size = p.BuyQty + p.GetQty
+		}
+		for start := 0; start+size <= len(units); start += size {
+			group := units[start : start+size]
+			if p.Type == dto.PromotionBuyXGetY {
+				// The last GetQty units of each group are the cheapest ones, and free
+				for _, unit := range group[p.BuyQty:] {
+					discounts[unit.line] += unit.price
+				}
+				continue
+			}
+
+			var groupTotal dto.Money
+			prices := make([]dto.Money, len(group))
+			for u, unit := range group {
+				groupTotal += unit.price
+				prices[u] = unit.price
+			}
+			if groupTotal <= p.BundlePrice {
+				continue
+			}
+			savings := make([]dto.Money, len(group))
+			shareLines(savings, prices, groupTotal, groupTotal-p.BundlePrice)
+			for u, unit := range group {
+				discounts[unit.line] += savings[u]
+			}
+		}
+	}
+
+	for k := range discounts {
+		discounts[k] = discounts[k].Min(remaining[k])
+	}
+	return discounts
+}
+
+// splitUnits splits what is left of a sale line into its units, giving the odd cents to the first units
+// so the unit prices add up to the line exactly
+func splitUnits(line int, remaining dto.Money, quantity int) []saleUnit {
+	if quantity <= 0 {
+		return nil
+	}
+	units := make([]saleUnit, quantity)
+	base := remaining / dto.Money(quantity)
+	extra := int(remaining % dto.Money(quantity))
+	for q := range units {
+		units[q] = saleUnit{line: line, price: base}
+		if q < extra {
+			units[q].price++
+		}
+	}
+	return units
+}
+
+// shareLines splits amount over the lines in proportion to weights, putting the rounding difference on the last line
+func shareLines(shares []dto.Money, weights []dto.Money, weightTotal dto.Money, amount dto.Money) {
+	var given dto.Money
+	last := -1
+	for k := range shares {
+		if weights[k] <= 0 {
+			continue
+		}
+		shares[k] = amount.Share(weights[k], weightTotal)
+		given += shares[k]
+		last = k
+	}
+	if last >= 0 {
+		shares[last] += amount - given
+	}
+}
@@ -0,0 +1,138 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+	"time"
+)
+
+func saleLine(productId string, quantity int, unitPrice float64) dto.SaleItem {
+	price := dto.MoneyFromFloat(unitPrice)
+	return dto.SaleItem{ProductID: productId, Quantity: quantity, UnitPrice: price, TotalPrice: price.Times(quantity)}
+}
+
+func TestApplyPromotionsLineRules(t *testing.T) {
+	now := time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+	products := map[string]*dto.Product{
+		"PRD-001": {ProductId: "PRD-001", BrandID: "BRD-001"},
+		"PRD-002": {ProductId: "PRD-002", CategoryID: "CAT-001"},
+		"PRD-003": {ProductId: "PRD-003", SubCategoryID: "SUB-001"},
+	}
+	items := []dto.SaleItem{
+		saleLine("PRD-001", 2, 100),
+		saleLine("PRD-002", 3, 50),
+		saleLine("PRD-003", 5, 20),
+	}
+	promotions := []dto.Promotion{
+		{PromotionID: "PRM-001", Name: "Brand 10%", Type: dto.PromotionPercentage, Scope: dto.PromotionScopeBrand, TargetIDs: []string{"BRD-001"}, Value: 10, Active: true},
+		// Lower priority, so PRD-001 keeps the brand discount
+		{PromotionID: "PRM-002", Name: "Product 30 off", Type: dto.PromotionFixed, Scope: dto.PromotionScopeProduct, TargetIDs: []string{"PRD-001"}, Value: 30, Priority: -1, Active: true},
+		{PromotionID: "PRM-003", Name: "Buy 2 get 1", Type: dto.PromotionBuyXGetY, Scope: dto.PromotionScopeCategory, TargetIDs: []string{"CAT-001"}, BuyQty: 2, GetQty: 1, Active: true},
+		{PromotionID: "PRM-004", Name: "2 for 30", Type: dto.PromotionBundle, Scope: dto.PromotionScopeSubCategory, TargetIDs: []string{"SUB-001"}, BundleQty: 2, BundlePrice: dto.MoneyFromFloat(30), Active: true},
+	}
+
+	applied, total := ApplyPromotions(items, products, promotions, now)
+
+	// 10% of 200, one of three free at 50, two bundles of 2 save 10 each
+	wantDiscounts := []float64{20, 50, 20}
+	for i, want := range wantDiscounts {
+		if items[i].Discount != dto.MoneyFromFloat(want) {
+			t.Errorf("line %d: discount %v, want %v", i, items[i].Discount, want)
+		}
+	}
+	if items[0].PromotionIDs[0] != "PRM-001" || len(items[0].PromotionIDs) != 1 {
+		t.Errorf("line 0: promotions %v, want only PRM-001", items[0].PromotionIDs)
+	}
+	if total != dto.MoneyFromFloat(90) || len(applied) != 3 {
+		t.Fatalf("expected 90 off from 3 promotions, got %v from %+v", total, applied)
+	}
+}
+
+func TestApplyPromotionsBasketAfterLines(t *testing.T) {
+	now := time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+	items := []dto.SaleItem{saleLine("PRD-001", 1, 300), saleLine("PRD-002", 1, 100)}
+	promotions := []dto.Promotion{
+		{PromotionID: "PRM-001", Name: "Spend 400 save 50", Type: dto.PromotionFixed, Scope: dto.PromotionScopeBasket, Value: 50, MinBasketValue: dto.MoneyFromFloat(400), Priority: 10, Active: true},
+		{PromotionID: "PRM-002", Name: "100 off", Type: dto.PromotionFixed, Scope: dto.PromotionScopeProduct, TargetIDs: []string{"PRD-001"}, Value: 100, Active: true},
+	}
+
+	_, total := ApplyPromotions(items, nil, promotions, now)
+
+	// The basket discount is shared over what is left: 200 and 100
+	if items[0].Discount != dto.MoneyFromFloat(133.33) || items[1].Discount != dto.MoneyFromFloat(16.67) || total != dto.MoneyFromFloat(150) {
+		t.Fatalf("expected discounts 133.33 and 16.67 (150 total), got %v, %v and %v", items[0].Discount, items[1].Discount, total)
+	}
+
+	// Below the minimum basket value only the line promotion applies
+	items = items[1:]
+	if _, total := ApplyPromotions(items, nil, promotions, now); total != 0 {
+		t.Fatalf("expected no discount on a 100 basket, got %v", total)
+	}
+}
+
+func TestPromotionRunsAt(t *testing.T) {
+	starts := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	ends := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	happyHour := dto.Promotion{Active: true, StartsAt: &starts, EndsAt: &ends, DaysOfWeek: []int{3}, StartTime: "22:00", EndTime: "02:00"}
+
+	cases := map[time.Time]bool{
+		time.Date(2025, 6, 4, 23, 0, 0, 0, time.UTC): true,  // Wednesday, inside the window
+		time.Date(2025, 6, 4, 1, 30, 0, 0, time.UTC): true,  // Wednesday, after midnight
+		time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC): false, // Wednesday, outside the window
+		time.Date(2025, 6, 5, 23, 0, 0, 0, time.UTC): false, // Thursday
+		time.Date(2025, 7, 2, 23, 0, 0, 0, time.UTC): false, // After endsAt
+	}
+	for now, want := range cases {
+		if got := PromotionRunsAt(&happyHour, now); got != want {
+			t.Errorf("%s: got %v, want %v", now.Format(time.RFC1123), got, want)
+		}
+	}
+
+	happyHour.Active = false
+	if PromotionRunsAt(&happyHour, time.Date(2025, 6, 4, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("an inactive promotion must not run")
+	}
+}
+
+func TestValidatePromotion(t *testing.T) {
+	cases := map[string]dto.Promotion{
+		"no name":        {Type: dto.PromotionPercentage, Scope: dto.PromotionScopeBasket, Value: 10},
+		"over 100%":      {Name: "x", Type: dto.PromotionPercentage, Scope: dto.PromotionScopeBasket, Value: 120},
+		"no targets":     {Name: "x", Type: dto.PromotionFixed, Scope: dto.PromotionScopeBrand, Value: 10},
+		"bundle of one":  {Name: "x", Type: dto.PromotionBundle, Scope: dto.PromotionScopeBasket, BundleQty: 1, BundlePrice: dto.MoneyFromFloat(10)},
+		"bad time":       {Name: "x", Type: dto.PromotionFixed, Scope: dto.PromotionScopeBasket, Value: 10, StartTime: "25:00", EndTime: "10:00"},
+		"unknown type":   {Name: "x", Type: "mystery", Scope: dto.PromotionScopeBasket},
+		"only startTime": {Name: "x", Type: dto.PromotionFixed, Scope: dto.PromotionScopeBasket, Value: 10, StartTime: "10:00"},
+	}
+	for name, promotion := range cases {
+		if err := ValidatePromotion(&promotion); !errors.Is(err, ErrInvalidPromotion) {
+			t.Errorf("%s: expected ErrInvalidPromotion, got %v", name, err)
+		}
+	}
+
+	valid := dto.Promotion{Name: "Buy 2 get 1", Type: dto.PromotionBuyXGetY, Scope: dto.PromotionScopeProduct, TargetIDs: []string{"PRD-001"}, BuyQty: 2, GetQty: 1}
+	if err := ValidatePromotion(&valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPrepareSaleReturnRefundsNetOfPromotions(t *testing.T) {
+	// 3 units at 100 with one free, 10% tax on the 200 paid
+	sale := &dto.Sale{
+		SaleID:            "SALE-1",
+		Items:             []dto.SaleItem{{ProductID: "PRD-001", Quantity: 3, UnitPrice: dto.MoneyFromFloat(100), TotalPrice: dto.MoneyFromFloat(300), Discount: dto.MoneyFromFloat(100)}},
+		Subtotal:          dto.MoneyFromFloat(300),
+		PromotionDiscount: dto.MoneyFromFloat(100),
+		Tax:               dto.MoneyFromFloat(20),
+	}
+
+	lines := []dto.ReturnProduct{{ProductID: "PRD-001", Quantity: 3}}
+	_, refund, err := PrepareSaleReturn(sale, lines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refund != dto.MoneyFromFloat(220) {
+		t.Fatalf("expected the 220 paid to be refunded, got %v", refund)
+	}
+}
@@ -0,0 +1,287 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrPurchaseOrderClosedToGRNs is returned when a GRN is raised against a PO that is not approved, sent or partially received
+	ErrPurchaseOrderClosedToGRNs = errors.New("purchase order does not accept GRNs")
+	// ErrPurchaseOrderSupplierMismatch is returned when a GRN's supplier is not the PO's
+	ErrPurchaseOrderSupplierMismatch = errors.New("GRN supplier does not match the purchase order")
+	// ErrNothingOutstanding is returned when a GRN line is for a PO line that has been received in full
+	ErrNothingOutstanding = errors.New("nothing outstanding on the purchase order")
+)
+
+// PurchaseOrderStatusChangeAllowed reports whether a PO can be moved by hand from one status to another:
+// a draft is approved, an approved PO is sent, and any PO that is not closed can be closed
+// partially_received is never set by hand, GRNs set it
+func PurchaseOrderStatusChangeAllowed(from string, to string) bool {
+	switch to {
+	case dto.POApproved:
+		return from == dto.PODraft
+	case dto.POSent:
+		return from == dto.POApproved
+	case dto.POClosed:
+		return from != dto.POClosed
+	}
+	return false
+}
+
+// PurchaseOrderAcceptsGRNs reports whether goods can be received against a PO
+func PurchaseOrderAcceptsGRNs(po *dto.PurchaseOrder) bool {
+	return po.Status == dto.POApproved || po.Status == dto.POSent || po.Status == dto.POPartiallyReceived
+}
+
+// PurchaseOrderOutstanding returns the quantity of a PO line still to be received; over-deliveries leave nothing outstanding
+func PurchaseOrderOutstanding(item *dto.PurchaseOrderItem) int {
+	if item.ReceivedQty >= item.OrderedQty {
+		return 0
+	}
+	return item.OrderedQty - item.ReceivedQty
+}
+
+// PurchaseOrderTotal works out each line's cost and returns the PO total at the agreed unit costs
+func PurchaseOrderTotal(items []dto.PurchaseOrderItem) dto.Money {
+	var total dto.Money
+	for i := range items {
+		items[i].TotalCost = items[i].UnitCost.Times(items[i].OrderedQty)
+		total += items[i].TotalCost
+	}
+	return total
+}
+
+// PurchaseOrderAwaitingPosting sums per product what the PO's GRNs have received but not yet posted into stock
+func PurchaseOrderAwaitingPosting(po *dto.PurchaseOrder, grns []dto.GRN) map[string]int {
+	awaiting := map[string]int{}
+	for _, grn := range grns {
+		if grn.PurchaseOrderId != po.POId || grn.Deleted {
+			continue
+		}
+		for _, item := range grn.Items {
+			if item.PostedBatchId == "" && item.ReceivedQty > 0 {
+				awaiting[item.ProductId] += item.ReceivedQty
+			}
+		}
+	}
+	return awaiting
+}
+
+// PrefillGRNFromPurchaseOrder fills a GRN raised against a PO, given the GRNs already raised against it
+// A GRN without items gets one line per outstanding PO line, expected and received in full at the agreed cost
+// Lines given for products on the PO expect the outstanding quantity and default to the agreed cost and
+// the PO's product name; lines for products not on the PO are kept as given and reported as variances
+// Quantities already on GRNs waiting to be posted are not outstanding, so two GRNs never expect the same units
+func PrefillGRNFromPurchaseOrder(grn *dto.GRN, po *dto.PurchaseOrder, grns []dto.GRN) error {
+	if !PurchaseOrderAcceptsGRNs(po) {
+		return fmt.Errorf("%w: %s is %s", ErrPurchaseOrderClosedToGRNs, po.POId, po.Status)
+	}
+	if grn.SupplierId != "" && grn.SupplierId != po.SupplierId {
+		return fmt.Errorf("%w: %s", ErrPurchaseOrderSupplierMismatch, grn.SupplierId)
+	}
+	grn.SupplierId = po.SupplierId
+	if grn.SupplierName == "" {
+		grn.SupplierName = po.SupplierName
+	}
+	grn.PurchaseOrderId = po.POId
+
+	awaiting := PurchaseOrderAwaitingPosting(po, grns)
+	outstandingOf := func(line *dto.PurchaseOrderItem) int {
+		if outstanding := PurchaseOrderOutstanding(line) - awaiting[line.ProductId]; outstanding > 0 {
+			return outstanding
+		}
+		return 0
+	}
+
+	if len(grn.Items) == 0 {
+		for i := range po.Items {
+			outstanding := outstandingOf(&po.Items[i])
+			if outstanding == 0 {
+				continue
+			}
+			grn.Items = append(grn.Items, dto.GRNItem{
+				ProductId:   po.Items[i].ProductId,
+				ProductName: po.Items[i].ProductName,
+				ExpectedQty: outstanding,
+				ReceivedQty: outstanding,
+				UnitCost:    po.Items[i].UnitCost,
+			})
+		}
+		if len(grn.Items) == 0 {
+			return fmt.Errorf("%w: %s", ErrNothingOutstanding, po.POId)
+		}
+		return nil
+	}
+
+	for i := range grn.Items {
+		line := purchaseOrderLine(po, grn.Items[i].ProductId)
+		if line == nil {
+			continue
+		}
+		outstanding := outstandingOf(line)
+		if outstanding == 0 {
+			return fmt.Errorf("%w: %s", ErrNothingOutstanding, line.ProductId)
+		}
+		grn.Items[i].ExpectedQty = outstanding
+		if grn.Items[i].UnitCost == 0 {
+			grn.Items[i].UnitCost = line.UnitCost
+		}
+		if grn.Items[i].ProductName == "" {
+			grn.Items[i].ProductName = line.ProductName
+		}
+	}
+	return nil
+}
+
+// CheckGRNPosting is run as a GRN raised against a PO is posted: every line still to post for a product on the PO
+// must find something outstanding on it, so a second GRN for units already received is refused
+func CheckGRNPosting(grn *dto.GRN, po *dto.PurchaseOrder) error {
+	for _, item := range grn.Items {
+		if item.PostedBatchId != "" || item.ReceivedQty <= 0 {
+			continue
+		}
+		if line := purchaseOrderLine(po, item.ProductId); line != nil && PurchaseOrderOutstanding(line) == 0 {
+			return fmt.Errorf("%w: %s on %s", ErrNothingOutstanding, item.ProductId, po.POId)
+		}
+	}
+	return nil
+}
+
+func purchaseOrderLine(po *dto.PurchaseOrder, productId string) *dto.PurchaseOrderItem {
+	for i := range po.Items {
+		if po.Items[i].ProductId == productId {
+			return &po.Items[i]
+		}
+	}
+	return nil
+}
+
+// ReceivePurchaseOrder recounts what has been received on a PO from the GRNs raised against it
+// Only posted GRN lines count, so the outstanding quantities follow stock rather than paperwork
+// An open PO that has received anything becomes partially_received, and closed once every line is received in full
+// Recounting from every GRN makes this safe to run again after any GRN changes
+func ReceivePurchaseOrder(po *dto.PurchaseOrder, grns []dto.GRN, now time.Time) {
+	for i := range po.Items {
+		po.Items[i].ReceivedQty = 0
+		po.Items[i].ReceivedCost = 0
+	}
+
+	po.GRNIds = []string{}
+	received := false
+	for _, grn := range grns {
+		if grn.PurchaseOrderId != po.POId || grn.Deleted {
+			continue
+		}
+		po.GRNIds = append(po.GRNIds, grn.GRNId)
+		for _, item := range grn.Items {
+			if item.PostedQty <= 0 {
+				continue
+			}
+			received = true
+			if line := purchaseOrderLine(po, item.ProductId); line != nil {
+				line.ReceivedQty += item.PostedQty
+				line.ReceivedCost += item.UnitCost.Times(item.PostedQty)
+			}
+		}
+	}
+	sort.Strings(po.GRNIds)
+
+	if !received || !PurchaseOrderAcceptsGRNs(po) {
+		return
+	}
+	complete := true
+	for i := range po.Items {
+		if PurchaseOrderOutstanding(&po.Items[i]) > 0 {
+			complete = false
+			break
+		}
+	}
+	if complete {
+		po.Status = dto.POClosed
+		po.ClosedAt = &now
+	} else {
+		po.Status = dto.POPartiallyReceived
+	}
+}
+
+// PurchaseOrderVariance compares a PO's lines with the posted GRN lines raised against it
+// Products received but never ordered are listed after the PO lines, with nothing ordered
+func PurchaseOrderVariance(po *dto.PurchaseOrder, grns []dto.GRN) dto.PurchaseOrderVarianceReport {
+	report := dto.PurchaseOrderVarianceReport{
+		POId:         po.POId,
+		PONumber:     po.PONumber,
+		SupplierId:   po.SupplierId,
+		SupplierName: po.SupplierName,
+		Status:       po.Status,
+		Lines:        make([]dto.PurchaseOrderVarianceLine, 0, len(po.Items)),
+	}
+
+	lines := make(map[string]int, len(po.Items))
+	for _, item := range po.Items {
+		lines[item.ProductId] = len(report.Lines)
+		report.Lines = append(report.Lines, dto.PurchaseOrderVarianceLine{
+			ProductId:      item.ProductId,
+			ProductName:    item.ProductName,
+			Ordered:        true,
+			OrderedQty:     item.OrderedQty,
+			AgreedUnitCost: item.UnitCost,
+			Receipts:       []dto.PurchaseOrderVarianceReceipt{},
+		})
+	}
+
+	for _, grn := range grns {
+		if grn.PurchaseOrderId != po.POId || grn.Deleted {
+			continue
+		}
+		for _, item := range grn.Items {
+			if item.PostedQty <= 0 {
+				continue
+			}
+			index, exists := lines[item.ProductId]
+			if !exists {
+				index = len(report.Lines)
+				lines[item.ProductId] = index
+				report.Lines = append(report.Lines, dto.PurchaseOrderVarianceLine{
+					ProductId:   item.ProductId,
+					ProductName: item.ProductName,
+					Receipts:    []dto.PurchaseOrderVarianceReceipt{},
+				})
+			}
+			line := &report.Lines[index]
+			line.ReceivedQty += item.PostedQty
+			line.ReceivedCost += item.UnitCost.Times(item.PostedQty)
+			line.Receipts = append(line.Receipts, dto.PurchaseOrderVarianceReceipt{
+				GRNId:        grn.GRNId,
+				GRNNumber:    grn.GRNNumber,
+				ReceivedDate: grn.ReceivedDate,
+				ReceivedQty:  item.PostedQty,
+				UnitCost:     item.UnitCost,
+			})
+		}
+	}
+
+	for i := range report.Lines {
+		line := &report.Lines[i]
+		if line.OrderedQty > line.ReceivedQty {
+			line.OutstandingQty = line.OrderedQty - line.ReceivedQty
+		}
+		line.QuantityVariance = line.ReceivedQty - line.OrderedQty
+		if line.ReceivedQty > 0 {
+			line.AverageUnitCost = line.ReceivedCost.Div(line.ReceivedQty)
+		}
+		// Products that were not ordered have no agreed cost to vary from
+		if line.Ordered {
+			line.PriceVariance = line.ReceivedCost - line.AgreedUnitCost.Times(line.ReceivedQty)
+		}
+
+		report.OrderedValue += line.AgreedUnitCost.Times(line.OrderedQty)
+		report.ReceivedValue += line.ReceivedCost
+		report.PriceVariance += line.PriceVariance
+		report.QuantityVariance += line.QuantityVariance
+	}
+	return report
+}
@@ -0,0 +1,196 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testPurchaseOrder() dto.PurchaseOrder {
+	return dto.PurchaseOrder{
+		POId:       "PO-001",
+		SupplierId: "SUP-001",
+		Status:     dto.POSent,
+		Items: []dto.PurchaseOrderItem{
+			{ProductId: "PRD-001", ProductName: "Rice", OrderedQty: 10, UnitCost: dto.MoneyFromFloat(50)},
+			{ProductId: "PRD-002", ProductName: "Milk", OrderedQty: 4, UnitCost: dto.MoneyFromFloat(20)},
+		},
+	}
+}
+
+func TestPurchaseOrderStatusChangeAllowed(t *testing.T) {
+	cases := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{dto.PODraft, dto.POApproved, true},
+		{dto.PODraft, dto.POSent, false},
+		{dto.POApproved, dto.POSent, true},
+		{dto.POSent, dto.POApproved, false},
+		{dto.POPartiallyReceived, dto.POClosed, true},
+		{dto.POClosed, dto.POClosed, false},
+		{dto.POSent, dto.POPartiallyReceived, false},
+	}
+	for _, tc := range cases {
+		if got := PurchaseOrderStatusChangeAllowed(tc.from, tc.to); got != tc.allowed {
+			t.Errorf("%s -> %s: expected %v, got %v", tc.from, tc.to, tc.allowed, got)
+		}
+	}
+}
+
+func TestPrefillGRNFromPurchaseOrder(t *testing.T) {
+	po := testPurchaseOrder()
+	po.Items[1].ReceivedQty = 4
+
+	var grn dto.GRN
+	if err := PrefillGRNFromPurchaseOrder(&grn, &po, nil); err != nil {
+		t.Fatalf("prefill: %v", err)
+	}
+	if grn.SupplierId != "SUP-001" || grn.PurchaseOrderId != "PO-001" {
+		t.Fatalf("expected the PO's supplier and reference, got %+v", grn)
+	}
+	if len(grn.Items) != 1 || grn.Items[0].ProductId != "PRD-001" || grn.Items[0].ExpectedQty != 10 || grn.Items[0].UnitCost != dto.MoneyFromFloat(50) {
+		t.Fatalf("expected only the outstanding PRD-001 line at the agreed cost, got %+v", grn.Items)
+	}
+
+	given := dto.GRN{SupplierId: "SUP-001", Items: []dto.GRNItem{
+		{ProductId: "PRD-001", ExpectedQty: 99, ReceivedQty: 6, UnitCost: dto.MoneyFromFloat(52)},
+		{ProductId: "PRD-009", ExpectedQty: 2, ReceivedQty: 2, UnitCost: dto.MoneyFromFloat(5)},
+	}}
+	if err := PrefillGRNFromPurchaseOrder(&given, &po, nil); err != nil {
+		t.Fatalf("prefill given lines: %v", err)
+	}
+	if given.Items[0].ExpectedQty != 10 || given.Items[0].UnitCost != dto.MoneyFromFloat(52) || given.Items[0].ProductName != "Rice" {
+		t.Fatalf("expected the outstanding quantity with the invoiced cost kept, got %+v", given.Items[0])
+	}
+	if given.Items[1].ExpectedQty != 2 {
+		t.Fatalf("a line not on the PO should be kept as given, got %+v", given.Items[1])
+	}
+
+	received := dto.GRN{Items: []dto.GRNItem{{ProductId: "PRD-002", ReceivedQty: 1}}}
+	if err := PrefillGRNFromPurchaseOrder(&received, &po, nil); !errors.Is(err, ErrNothingOutstanding) {
+		t.Fatalf("expected ErrNothingOutstanding for a line received in full, got %v", err)
+	}
+	other := dto.GRN{SupplierId: "SUP-002"}
+	if err := PrefillGRNFromPurchaseOrder(&other, &po, nil); !errors.Is(err, ErrPurchaseOrderSupplierMismatch) {
+		t.Fatalf("expected ErrPurchaseOrderSupplierMismatch, got %v", err)
+	}
+
+	// Units on a GRN still waiting to be posted are not outstanding for the next one
+	pending := []dto.GRN{{GRNId: "GRN-001", PurchaseOrderId: "PO-001", Items: []dto.GRNItem{{ProductId: "PRD-001", ReceivedQty: 7}}}}
+	var next dto.GRN
+	if err := PrefillGRNFromPurchaseOrder(&next, &po, pending); err != nil {
+		t.Fatalf("prefill after a pending GRN: %v", err)
+	}
+	if len(next.Items) != 1 || next.Items[0].ExpectedQty != 3 {
+		t.Fatalf("expected 3 of PRD-001 left to expect, got %+v", next.Items)
+	}
+	pending[0].Items[0].ReceivedQty = 10
+	if err := PrefillGRNFromPurchaseOrder(&dto.GRN{}, &po, pending); !errors.Is(err, ErrNothingOutstanding) {
+		t.Fatalf("expected ErrNothingOutstanding once a pending GRN covers the rest, got %v", err)
+	}
+
+	po.Status = dto.PODraft
+	if err := PrefillGRNFromPurchaseOrder(&dto.GRN{}, &po, nil); !errors.Is(err, ErrPurchaseOrderClosedToGRNs) {
+		t.Fatalf("expected a draft PO to refuse GRNs, got %v", err)
+	}
+}
+
+func TestCheckGRNPosting(t *testing.T) {
+	po := testPurchaseOrder()
+	po.Items[1].ReceivedQty = 4
+
+	grn := dto.GRN{Items: []dto.GRNItem{{ProductId: "PRD-001", ReceivedQty: 12}, {ProductId: "PRD-009", ReceivedQty: 1}}}
+	if err := CheckGRNPosting(&grn, &po); err != nil {
+		t.Fatalf("expected an over-delivery against an outstanding line to post, got %v", err)
+	}
+	grn.Items = append(grn.Items, dto.GRNItem{ProductId: "PRD-002", ReceivedQty: 4})
+	if err := CheckGRNPosting(&grn, &po); !errors.Is(err, ErrNothingOutstanding) {
+		t.Fatalf("expected ErrNothingOutstanding for a line received in full, got %v", err)
+	}
+	grn.Items[2].PostedBatchId = "BATCH-001"
+	if err := CheckGRNPosting(&grn, &po); err != nil {
+		t.Fatalf("lines already posted are not checked again, got %v", err)
+	}
+}
+
+func TestReceivePurchaseOrder(t *testing.T) {
+	now := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	po := testPurchaseOrder()
+
+	grns := []dto.GRN{
+		{GRNId: "GRN-002", PurchaseOrderId: "PO-001", Items: []dto.GRNItem{
+			{ProductId: "PRD-001", ReceivedQty: 6, PostedQty: 6, UnitCost: dto.MoneyFromFloat(52)},
+			{ProductId: "PRD-002", ReceivedQty: 4, PostedQty: 4, UnitCost: dto.MoneyFromFloat(20)},
+		}},
+		// Pending: linked but not yet received into stock
+		{GRNId: "GRN-001", PurchaseOrderId: "PO-001", Items: []dto.GRNItem{
+			{ProductId: "PRD-001", ReceivedQty: 4, UnitCost: dto.MoneyFromFloat(50)},
+		}},
+		{GRNId: "GRN-003", PurchaseOrderId: "PO-002", Items: []dto.GRNItem{
+			{ProductId: "PRD-001", ReceivedQty: 4, PostedQty: 4, UnitCost: dto.MoneyFromFloat(50)},
+		}},
+	}
+
+	ReceivePurchaseOrder(&po, grns, now)
+	if po.Status != dto.POPartiallyReceived || po.ClosedAt != nil {
+		t.Fatalf("expected partially_received, got %s", po.Status)
+	}
+	if po.Items[0].ReceivedQty != 6 || po.Items[0].ReceivedCost != dto.MoneyFromFloat(312) || PurchaseOrderOutstanding(&po.Items[0]) != 4 {
+		t.Fatalf("expected 6 of PRD-001 received for 312, got %+v", po.Items[0])
+	}
+	if len(po.GRNIds) != 2 || po.GRNIds[0] != "GRN-001" || po.GRNIds[1] != "GRN-002" {
+		t.Fatalf("expected both GRNs of the PO linked, got %v", po.GRNIds)
+	}
+
+	// Posting the pending GRN completes the order; recounting again changes nothing
+	grns[1].Items[0].PostedQty = 4
+	ReceivePurchaseOrder(&po, grns, now)
+	ReceivePurchaseOrder(&po, grns, now)
+	if po.Status != dto.POClosed || po.ClosedAt == nil || po.Items[0].ReceivedQty != 10 {
+		t.Fatalf("expected the PO closed with 10 of PRD-001 received, got %s %+v", po.Status, po.Items[0])
+	}
+}
+
+func TestPurchaseOrderVariance(t *testing.T) {
+	po := testPurchaseOrder()
+	grns := []dto.GRN{
+		{GRNId: "GRN-001", PurchaseOrderId: "PO-001", Items: []dto.GRNItem{
+			{ProductId: "PRD-001", ProductName: "Rice", ReceivedQty: 6, PostedQty: 6, UnitCost: dto.MoneyFromFloat(52)},
+			{ProductId: "PRD-009", ProductName: "Salt", ReceivedQty: 2, PostedQty: 2, UnitCost: dto.MoneyFromFloat(5)},
+		}},
+		{GRNId: "GRN-002", PurchaseOrderId: "PO-001", Items: []dto.GRNItem{
+			{ProductId: "PRD-001", ReceivedQty: 5, PostedQty: 5, UnitCost: dto.MoneyFromFloat(50)},
+		}},
+	}
+
+	report := PurchaseOrderVariance(&po, grns)
+	if len(report.Lines) != 3 {
+		t.Fatalf("expected the two PO lines and the unordered product, got %+v", report.Lines)
+	}
+
+	rice := report.Lines[0]
+	if rice.ReceivedQty != 11 || rice.QuantityVariance != 1 || rice.OutstandingQty != 0 || len(rice.Receipts) != 2 {
+		t.Fatalf("expected 11 of 10 received over two GRNs, got %+v", rice)
+	}
+	// 6 x 52 + 5 x 50 = 562 against 11 x 50 = 550
+	if rice.ReceivedCost != dto.MoneyFromFloat(562) || rice.PriceVariance != dto.MoneyFromFloat(12) || rice.AverageUnitCost != dto.MoneyFromFloat(51.09) {
+		t.Fatalf("unexpected rice costs %+v", rice)
+	}
+
+	milk := report.Lines[1]
+	if milk.ReceivedQty != 0 || milk.OutstandingQty != 4 || milk.QuantityVariance != -4 || milk.PriceVariance != 0 {
+		t.Fatalf("expected milk still outstanding, got %+v", milk)
+	}
+
+	salt := report.Lines[2]
+	if salt.Ordered || salt.QuantityVariance != 2 || salt.PriceVariance != 0 {
+		t.Fatalf("expected salt received without being ordered, got %+v", salt)
+	}
+
+	if report.OrderedValue != dto.MoneyFromFloat(580) || report.ReceivedValue != dto.MoneyFromFloat(572) ||
+		report.PriceVariance != dto.MoneyFromFloat(12) || report.QuantityVariance != -1 {
+		t.Fatalf("unexpected totals %+v", report)
+	}
+}
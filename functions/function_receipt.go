@@ -0,0 +1,322 @@
+package functions
+
+import (
+	"bytes"
+	"employee-crud/dto"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReceiptColumns returns the characters per line of a receipt in the printer's standard font:
+// 32 on 58mm paper and 48 on 80mm paper
+func ReceiptColumns(paperWidthMM int) int {
+	if paperWidthMM == 58 {
+		return 32
+	}
+	return 48
+}
+
+// BuildReceipt lays out a sale for printing; the store name, header and footer are left to the caller
+func BuildReceipt(sale *dto.Sale, loc *time.Location) dto.Receipt {
+	receipt := dto.Receipt{
+		SaleID:   sale.SaleID,
+		SoldAt:   sale.CreatedAt.In(loc),
+		Customer: sale.CustomerName,
+		Lines:    make([]dto.ReceiptLine, 0, len(sale.Items)),
+		Totals:   []dto.ReceiptAmount{{Label: "Subtotal", Amount: sale.Subtotal}},
+		Total:    sale.Total,
+		Tenders:  []dto.ReceiptAmount{},
+		Change:   sale.Change,
+		Voided:   sale.Voided,
+	}
+
+	for _, item := range sale.Items {
+		receipt.Lines = append(receipt.Lines, dto.ReceiptLine{
+			Name:      item.ProductName,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Amount:    item.TotalPrice,
+			Discount:  item.Discount,
+		})
+	}
+
+	if sale.PromotionDiscount > 0 {
+		receipt.Totals = append(receipt.Totals, dto.ReceiptAmount{Label: "Promotions", Amount: -sale.PromotionDiscount})
+	}
+	for _, tax := range SaleTaxBreakdown(sale) {
+		if tax.Tax == 0 {
+			continue
+		}
+		label := tax.Name
+		if tax.TaxClassID == "" {
+			label = "Tax"
+		}
+		label += " " + strconv.FormatFloat(tax.Rate, 'f', -1, 64) + "%"
+		if tax.Inclusive {
+			label += " (incl.)"
+		}
+		receipt.Totals = append(receipt.Totals, dto.ReceiptAmount{Label: label, Amount: tax.Tax})
+	}
+	if sale.Discount > 0 {
+		receipt.Totals = append(receipt.Totals, dto.ReceiptAmount{Label: "Discount", Amount: -sale.Discount})
+	}
+	if sale.LoyaltyDiscount > 0 {
+		receipt.Totals = append(receipt.Totals, dto.ReceiptAmount{Label: "Points redeemed", Amount: -sale.LoyaltyDiscount})
+	}
+
+	// Cash is shown as handed over: the cash applied to the sale plus the change given from it
+	cashShown := false
+	for _, tender := range SaleTenders(sale) {
+		if tender.Type == dto.TenderCash {
+			if !cashShown {
+				receipt.Tenders = append(receipt.Tenders, dto.ReceiptAmount{Label: TenderLabel(dto.TenderCash), Amount: cashHandedOver(sale)})
+				cashShown = true
+			}
+			continue
+		}
+		label := TenderLabel(tender.Type)
+		if tender.Reference != "" && tender.Type != dto.TenderLoyaltyPoints {
+			label += " " + tender.Reference
+		}
+		receipt.Tenders = append(receipt.Tenders, dto.ReceiptAmount{Label: label, Amount: tender.Amount})
+	}
+	if !cashShown && sale.Change > 0 {
+		receipt.Tenders = append(receipt.Tenders, dto.ReceiptAmount{Label: TenderLabel(dto.TenderCash), Amount: sale.Change})
+	}
+
+	if sale.PointsRedeemed > 0 {
+		receipt.Points = append(receipt.Points, fmt.Sprintf("Points redeemed: %d", sale.PointsRedeemed))
+	}
+	if sale.PointsEarned > 0 {
+		receipt.Points = append(receipt.Points, fmt.Sprintf("Points earned: %d", sale.PointsEarned))
+	}
+	return receipt
+}
+
+// cashHandedOver is the cash a customer gave for a sale, before change
+func cashHandedOver(sale *dto.Sale) dto.Money {
+	cash := sale.Change
+	for _, tender := range SaleTenders(sale) {
+		if tender.Type == dto.TenderCash {
+			cash += tender.Amount
+		}
+	}
+	return cash
+}
+
+// ReceiptRow is one printed line of a receipt
+// Large rows are printed at double width, so they hold half as many characters
+type ReceiptRow struct {
+	Text    string
+	Center  bool
+	Bold    bool
+	Large   bool
+	Barcode bool // Where the saleId barcode goes; Text is empty
+}
+
+// ReceiptRows lays out a receipt as lines of at most columns characters
+func ReceiptRows(receipt *dto.Receipt, columns int) []ReceiptRow {
+	var rows []ReceiptRow
+	separator := ReceiptRow{Text: strings.Repeat("-", columns)}
+
+	for _, line := range wrapText(receipt.StoreName, columns/2) {
+		rows = append(rows, ReceiptRow{Text: line, Center: true, Bold: true, Large: true})
+	}
+	for _, header := range receipt.Header {
+		for _, line := range wrapText(header, columns) {
+			rows = append(rows, ReceiptRow{Text: line, Center: true})
+		}
+	}
+	if receipt.Voided {
+		rows = append(rows, ReceiptRow{Text: "VOID", Center: true, Bold: true, Large: true})
+	}
+
+	rows = append(rows, separator)
+	for _, line := range wrapText("Sale: "+receipt.SaleID, columns) {
+		rows = append(rows, ReceiptRow{Text: line})
+	}
+	rows = append(rows, ReceiptRow{Text: "Date: " + receipt.SoldAt.Format("2006-01-02 15:04")})
+	if receipt.Customer != "" {
+		for _, line := range wrapText("Customer: "+receipt.Customer, columns) {
+			rows = append(rows, ReceiptRow{Text: line})
+		}
+	}
+
+	rows = append(rows, separator)
+	for _, line := range receipt.Lines {
+		for _, text := range wrapText(line.Name, columns) {
+			rows = append(rows, ReceiptRow{Text: text})
+		}
+		rows = appendAmountRows(rows, fmt.Sprintf("  %d x %s", line.Quantity, line.UnitPrice), line.Amount, columns, false)
+		if line.Discount > 0 {
+			rows = appendAmountRows(rows, "  Promotion", -line.Discount, columns, false)
+		}
+	}
+
+	rows = append(rows, separator)
+	for _, total := range receipt.Totals {
+		rows = appendAmountRows(rows, total.Label, total.Amount, columns, false)
+	}
+	rows = appendAmountRows(rows, "TOTAL", receipt.Total, columns, true)
+	for _, tender := range receipt.Tenders {
+		rows = appendAmountRows(rows, tender.Label, tender.Amount, columns, false)
+	}
+	if receipt.Change > 0 {
+		rows = appendAmountRows(rows, "Change", receipt.Change, columns, false)
+	}
+	for _, points := range receipt.Points {
+		rows = append(rows, ReceiptRow{Text: points})
+	}
+
+	rows = append(rows, separator, ReceiptRow{Barcode: true})
+	for _, footer := range receipt.Footer {
+		for _, line := range wrapText(footer, columns) {
+			rows = append(rows, ReceiptRow{Text: line, Center: true})
+		}
+	}
+	return rows
+}
+
+// appendAmountRows adds a label with its amount right-aligned; a label too long to share the line gets its own
+func appendAmountRows(rows []ReceiptRow, label string, amount dto.Money, columns int, bold bool) []ReceiptRow {
+	label = printableASCII(label)
+	value := amount.String()
+	if len(label)+1+len(value) > columns {
+		for _, line := range wrapText(label, columns) {
+			rows = append(rows, ReceiptRow{Text: line, Bold: bold})
+		}
+		label = ""
+	}
+	return append(rows, ReceiptRow{Text: label + strings.Repeat(" ", columns-len(label)-len(value)) + value, Bold: bold})
+}
+
+// wrapText breaks text into lines of at most width characters at spaces, splitting longer words
+func wrapText(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(printableASCII(text)) {
+		for len(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, word[:width])
+			word = word[width:]
+		}
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// printableASCII replaces characters receipt printers cannot print with "?"
+func printableASCII(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' {
+			return ' '
+		}
+		if r < 32 || r > 126 {
+			return '?'
+		}
+		return r
+	}, text)
+}
+
+// ReceiptText renders a receipt as plain text, centring rows with spaces
+// Text has no barcode; the saleId is printed in full near the top
+func ReceiptText(receipt *dto.Receipt, columns int) string {
+	var text strings.Builder
+	for _, row := range ReceiptRows(receipt, columns) {
+		if row.Barcode {
+			continue
+		}
+		if row.Center {
+			text.WriteString(strings.Repeat(" ", (columns-len(row.Text))/2))
+		}
+		text.WriteString(row.Text)
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+// ESC/POS commands used for receipts
+var (
+	escposInit       = []byte{0x1B, 0x40}                               // ESC @
+	escposAlignLeft  = []byte{0x1B, 0x61, 0x00}                         // ESC a 0
+	escposAlignCtr   = []byte{0x1B, 0x61, 0x01}                         // ESC a 1
+	escposBoldOn     = []byte{0x1B, 0x45, 0x01}                         // ESC E 1
+	escposBoldOff    = []byte{0x1B, 0x45, 0x00}                         // ESC E 0
+	escposSizeLarge  = []byte{0x1D, 0x21, 0x11}                         // GS ! double width and height
+	escposSizeNormal = []byte{0x1D, 0x21, 0x00}                         // GS ! normal
+	escposFeedAndCut = []byte{0x1B, 0x64, 0x04, 0x1D, 0x56, 0x42, 0x00} // ESC d 4, GS V partial cut
+)
+
+// ReceiptESCPOS renders a receipt as an ESC/POS byte stream for a thermal printer of the given paper width
+// The saleId is printed as a QR code drawn by the printer (GS ( k): a Code 128 of a 36-character saleId
+// is wider than either paper roll at the printer's narrowest bar width
+func ReceiptESCPOS(receipt *dto.Receipt, paperWidthMM int) []byte {
+	var out bytes.Buffer
+	out.Write(escposInit)
+
+	for _, row := range ReceiptRows(receipt, ReceiptColumns(paperWidthMM)) {
+		if row.Barcode {
+			out.Write(escposAlignCtr)
+			writeESCPOSQRCode(&out, receipt.SaleID, paperWidthMM)
+			out.WriteByte('\n')
+			continue
+		}
+
+		if row.Center {
+			out.Write(escposAlignCtr)
+		} else {
+			out.Write(escposAlignLeft)
+		}
+		if row.Bold {
+			out.Write(escposBoldOn)
+		}
+		if row.Large {
+			out.Write(escposSizeLarge)
+		}
+		out.WriteString(row.Text)
+		out.WriteByte('\n')
+		if row.Large {
+			out.Write(escposSizeNormal)
+		}
+		if row.Bold {
+			out.Write(escposBoldOff)
+		}
+	}
+
+	out.Write(escposAlignLeft)
+	out.Write(escposFeedAndCut)
+	return out.Bytes()
+}
+
+// writeESCPOSQRCode writes the GS ( k commands that store and print text as a QR code (model 2, error correction M)
+func writeESCPOSQRCode(out *bytes.Buffer, text string, paperWidthMM int) {
+	moduleSize := byte(6)
+	if paperWidthMM == 58 {
+		moduleSize = 4
+	}
+	data := printableASCII(text)
+	storeLength := len(data) + 3
+
+	out.Write([]byte{0x1D, 0x28, 0x6B, 0x04, 0x00, 0x31, 0x41, 0x32, 0x00}) // Model 2
+	out.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x43, moduleSize}) // Module size in dots
+	out.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x45, 0x31})       // Error correction M
+	out.Write([]byte{0x1D, 0x28, 0x6B, byte(storeLength), byte(storeLength >> 8), 0x31, 0x50, 0x30})
+	out.WriteString(data)
+	out.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x51, 0x30}) // Print the stored symbol
+}
@@ -0,0 +1,130 @@
+package functions
+
+import (
+	"bytes"
+	"employee-crud/dto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func receiptSale() *dto.Sale {
+	return &dto.Sale{
+		SaleID:       "6f1c2a9e-0b7d-4c55-9a8e-3d2f1b0c4e7a",
+		CustomerName: "Nimal Perera",
+		Items: []dto.SaleItem{
+			{ProductName: "Anchor Full Cream Milk Powder 400g", Quantity: 2, UnitPrice: dto.MoneyFromFloat(100), TotalPrice: dto.MoneyFromFloat(200), Discount: dto.MoneyFromFloat(20)},
+			{ProductName: "Bread", Quantity: 1, UnitPrice: dto.MoneyFromFloat(50), TotalPrice: dto.MoneyFromFloat(50)},
+		},
+		Subtotal:          dto.MoneyFromFloat(250),
+		PromotionDiscount: dto.MoneyFromFloat(20),
+		Tax:               dto.MoneyFromFloat(23),
+		TaxBreakdown:      []dto.TaxSummary{{TaxClassID: "TAX-001", Name: "VAT", Rate: 10, Tax: dto.MoneyFromFloat(23)}},
+		Discount:          dto.MoneyFromFloat(3),
+		Total:             dto.MoneyFromFloat(250),
+		Tenders: []dto.Tender{
+			{Type: dto.TenderCard, Amount: dto.MoneyFromFloat(100), Reference: "A1234"},
+			{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(150)},
+		},
+		PaymentMethod:  dto.PaymentSplit,
+		AmountReceived: dto.MoneyFromFloat(200),
+		Change:         dto.MoneyFromFloat(50),
+		PointsEarned:   2,
+		CreatedAt:      time.Date(2026, 3, 10, 8, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestBuildReceipt(t *testing.T) {
+	receipt := BuildReceipt(receiptSale(), time.FixedZone("+0530", 5*3600+30*60))
+
+	if receipt.SoldAt.Format("15:04") != "14:00" {
+		t.Errorf("expected the sale time in the business zone, got %s", receipt.SoldAt.Format("15:04"))
+	}
+	wantTotals := []string{"Subtotal 250.00", "Promotions -20.00", "VAT 10% 23.00", "Discount -3.00"}
+	if len(receipt.Totals) != len(wantTotals) {
+		t.Fatalf("expected %d totals, got %+v", len(wantTotals), receipt.Totals)
+	}
+	for i, want := range wantTotals {
+		if got := receipt.Totals[i].Label + " " + receipt.Totals[i].Amount.String(); got != want {
+			t.Errorf("total %d: expected %q, got %q", i, want, got)
+		}
+	}
+	// Cash is shown as handed over, with the change taken from it
+	if len(receipt.Tenders) != 2 || receipt.Tenders[0].Label != "Card A1234" || receipt.Tenders[1].Amount != dto.MoneyFromFloat(200) {
+		t.Fatalf("expected the card and 200 cash, got %+v", receipt.Tenders)
+	}
+}
+
+func TestReceiptTextFitsThePaper(t *testing.T) {
+	receipt := BuildReceipt(receiptSale(), time.UTC)
+	receipt.StoreName = "Corner Store"
+	receipt.Footer = []string{"Thank you!"}
+
+	for _, width := range []int{58, 80} {
+		columns := ReceiptColumns(width)
+		text := ReceiptText(&receipt, columns)
+		for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+			if len(line) > columns {
+				t.Fatalf("%dmm: line longer than %d columns: %q", width, columns, line)
+			}
+		}
+		if !strings.Contains(text, "Anchor Full Cream") || !strings.Contains(text, "Change") || !strings.Contains(text, "Thank you!") {
+			t.Fatalf("%dmm: receipt is missing content:\n%s", width, text)
+		}
+	}
+
+	text := ReceiptText(&receipt, 48)
+	if !strings.Contains(text, "TOTAL"+strings.Repeat(" ", 48-len("TOTAL")-len("250.00"))+"250.00") {
+		t.Fatalf("expected the total right-aligned, got:\n%s", text)
+	}
+}
+
+func TestReceiptESCPOSPrintsTheSaleIdAsQRCode(t *testing.T) {
+	receipt := BuildReceipt(receiptSale(), time.UTC)
+	stream := ReceiptESCPOS(&receipt, 58)
+
+	if !bytes.HasPrefix(stream, []byte{0x1B, 0x40}) {
+		t.Fatalf("expected the stream to start with ESC @")
+	}
+	store := append([]byte{0x1D, 0x28, 0x6B, byte(len(receipt.SaleID) + 3), 0x00, 0x31, 0x50, 0x30}, receipt.SaleID...)
+	if !bytes.Contains(stream, store) {
+		t.Fatalf("expected the saleId stored as a QR code")
+	}
+	if !bytes.HasSuffix(stream, []byte{0x1D, 0x56, 0x42, 0x00}) {
+		t.Fatalf("expected the stream to end with a paper cut")
+	}
+}
+
+func TestCode128(t *testing.T) {
+	for value, pattern := range code128Patterns {
+		sum := 0
+		for _, width := range pattern {
+			sum += int(width - '0')
+		}
+		if want := map[bool]int{true: 13, false: 11}[value == code128Stop]; sum != want {
+			t.Fatalf("symbol %d is %d modules wide, expected %d", value, sum, want)
+		}
+	}
+
+	symbols, err := code128Symbols("PJJ123C")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []int{code128StartB, 48, 42, 42, 17, 18, 19, 35, 55, code128Stop}
+	if len(symbols) != len(want) {
+		t.Fatalf("expected %v, got %v", want, symbols)
+	}
+	for i := range want {
+		if symbols[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, symbols)
+		}
+	}
+
+	// Six digits are packed into three subset C symbols
+	if symbols, _ := code128Symbols("A123456"); len(symbols) != 1+1+1+3+2 || symbols[2] != code128CodeC || symbols[3] != 12 {
+		t.Fatalf("expected a switch to subset C, got %v", symbols)
+	}
+	if _, err := Code128("café"); err == nil {
+		t.Fatal("expected an error for non-ASCII text")
+	}
+}
@@ -0,0 +1,204 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReorderSettings are the reorder rules for products without their own reorder point and quantity
+// When there are no sales to go on, a product's stock thresholds are used: its own, else its category's
+// from CategoryThresholds, else StockThresholds
+type ReorderSettings struct {
+	LeadTimeDays       int
+	SafetyDays         int
+	CoverDays          int
+	StockThresholds    dto.StockThresholds
+	CategoryThresholds map[string]dto.StockThresholds
+}
+
+// AverageDailySales returns each product's average units sold per day over the saved daily reports
+// Days without a saved report are not known to have had no sales, so they are left out of the average
+func AverageDailySales(reports []dto.DailyReportDocument) map[string]float64 {
+	averages := make(map[string]float64)
+	if len(reports) == 0 {
+		return averages
+	}
+	for _, report := range reports {
+		for _, sold := range report.ProductsSold {
+			averages[sold.ProductID] += float64(sold.Quantity)
+		}
+	}
+	for productId := range averages {
+		averages[productId] /= float64(len(reports))
+	}
+	return averages
+}
+
+// OnOrderQuantities sums per product what is still outstanding on purchase orders that are not closed
+// Drafts count too, so suggestions already turned into draft orders are not suggested again
+func OnOrderQuantities(orders []dto.PurchaseOrder) map[string]int {
+	onOrder := make(map[string]int)
+	for _, po := range orders {
+		if po.Status == dto.POClosed {
+			continue
+		}
+		for i := range po.Items {
+			onOrder[po.Items[i].ProductId] += PurchaseOrderOutstanding(&po.Items[i])
+		}
+	}
+	return onOrder
+}
+
+// PrimarySuppliers picks the supplier each product is reordered from: the first one it was assigned to
+func PrimarySuppliers(assignments []dto.SupplierProduct) map[string]dto.SupplierProduct {
+	primary := make(map[string]dto.SupplierProduct)
+	for _, assignment := range assignments {
+		current, exists := primary[assignment.ProductID]
+		if !exists || assignment.AssignedAt.Before(current.AssignedAt) {
+			primary[assignment.ProductID] = assignment
+		}
+	}
+	return primary
+}
+
+// SuggestReorder works out a product's reorder point and, when its stock plus what is on order has fallen
+// to that point, how much to order
+// The reorder point is the product's own, else the average daily sales over the lead time plus the safety days,
+// else the low stock threshold. The quantity is the product's own, ordered in as many multiples as it takes
+// to get back above the reorder point; else enough to cover CoverDays of sales above the reorder point,
+// or to reach the average stock threshold when nothing has sold
+func SuggestReorder(product *dto.Product, averageDaily float64, onOrder int, leadTimeDays int, settings ReorderSettings) (dto.ReorderSuggestion, bool) {
+	suggestion := dto.ReorderSuggestion{
+		ProductId:         product.ProductId,
+		ProductName:       product.Name,
+		StockQty:          product.StockQty,
+		OnOrderQty:        onOrder,
+		AverageDailySales: math.Round(averageDaily*100) / 100,
+		UnitCost:          product.CostPrice,
+	}
+
+	thresholds, _ := ResolveStockThresholds(product, settings.CategoryThresholds, settings.StockThresholds)
+	switch {
+	case product.ReorderPoint > 0:
+		suggestion.ReorderPoint = product.ReorderPoint
+		suggestion.ReorderPointSource = dto.ReorderFromProduct
+	case averageDaily > 0:
+		suggestion.ReorderPoint = int(math.Ceil(averageDaily * float64(leadTimeDays+settings.SafetyDays)))
+		suggestion.ReorderPointSource = dto.ReorderFromSales
+	default:
+		suggestion.ReorderPoint = thresholds.LowThreshold
+		suggestion.ReorderPointSource = dto.ReorderFromThreshold
+	}
+
+	available := product.StockQty + onOrder
+	if available > suggestion.ReorderPoint {
+		return suggestion, false
+	}
+
+	if product.ReorderQty > 0 {
+		packs := (suggestion.ReorderPoint-available)/product.ReorderQty + 1
+		suggestion.SuggestedQty = packs * product.ReorderQty
+	} else {
+		target := suggestion.ReorderPoint + int(math.Ceil(averageDaily*float64(settings.CoverDays)))
+		if averageDaily <= 0 && target < thresholds.AverageThreshold {
+			target = thresholds.AverageThreshold
+		}
+		if target <= suggestion.ReorderPoint {
+			target = suggestion.ReorderPoint + 1
+		}
+		suggestion.SuggestedQty = target - available
+	}
+	suggestion.EstimatedCost = suggestion.UnitCost.Times(suggestion.SuggestedQty)
+	return suggestion, true
+}
+
+// BuildReorderSuggestions suggests reorders for every product and groups them by the product's primary supplier
+// Suppliers are listed by name with products that have no supplier last; within a supplier the products
+// furthest below their reorder point come first
+func BuildReorderSuggestions(products []dto.Product, assignments []dto.SupplierProduct, suppliers []dto.Supplier,
+	reports []dto.DailyReportDocument, orders []dto.PurchaseOrder, settings ReorderSettings) []dto.ReorderSupplierGroup {
+
+	averages := AverageDailySales(reports)
+	onOrder := OnOrderQuantities(orders)
+	primary := PrimarySuppliers(assignments)
+	supplierById := make(map[string]*dto.Supplier, len(suppliers))
+	for i := range suppliers {
+		supplierById[suppliers[i].SupplierId] = &suppliers[i]
+	}
+
+	groups := make(map[string]*dto.ReorderSupplierGroup)
+	for i := range products {
+		product := &products[i]
+		if product.Deleted {
+			continue
+		}
+
+		supplierId, supplierName, leadTime := "", "", settings.LeadTimeDays
+		if assignment, assigned := primary[product.ProductId]; assigned {
+			supplierId, supplierName = assignment.SupplierID, assignment.SupplierName
+			if supplier := supplierById[supplierId]; supplier != nil {
+				supplierName = supplier.Name
+				if supplier.LeadTimeDays > 0 {
+					leadTime = supplier.LeadTimeDays
+				}
+			}
+		}
+
+		suggestion, reorder := SuggestReorder(product, averages[product.ProductId], onOrder[product.ProductId], leadTime, settings)
+		if !reorder {
+			continue
+		}
+
+		group, exists := groups[supplierId]
+		if !exists {
+			group = &dto.ReorderSupplierGroup{SupplierId: supplierId, SupplierName: supplierName, LeadTimeDays: leadTime}
+			groups[supplierId] = group
+		}
+		group.Suggestions = append(group.Suggestions, suggestion)
+		group.EstimatedCost += suggestion.EstimatedCost
+	}
+
+	list := make([]dto.ReorderSupplierGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Slice(group.Suggestions, func(i, j int) bool {
+			a, b := group.Suggestions[i], group.Suggestions[j]
+			shortA := a.StockQty + a.OnOrderQty - a.ReorderPoint
+			shortB := b.StockQty + b.OnOrderQty - b.ReorderPoint
+			if shortA != shortB {
+				return shortA < shortB
+			}
+			return a.ProductName < b.ProductName
+		})
+		list = append(list, *group)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if (list[i].SupplierId == "") != (list[j].SupplierId == "") {
+			return list[j].SupplierId == ""
+		}
+		return strings.ToLower(list[i].SupplierName) < strings.ToLower(list[j].SupplierName)
+	})
+	return list
+}
+
+// ReorderPurchaseOrderItems turns a supplier's suggestions into purchase order lines at the products' cost prices
+func ReorderPurchaseOrderItems(suggestions []dto.ReorderSuggestion) []dto.PurchaseOrderItem {
+	items := make([]dto.PurchaseOrderItem, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		items = append(items, dto.PurchaseOrderItem{
+			ProductId:   suggestion.ProductId,
+			ProductName: suggestion.ProductName,
+			OrderedQty:  suggestion.SuggestedQty,
+			UnitCost:    suggestion.UnitCost,
+		})
+	}
+	return items
+}
+
+// ReorderVelocityWindow returns the business days whose saved reports are averaged: the velocityDays before today
+func ReorderVelocityWindow(now time.Time, velocityDays int, loc *time.Location) (time.Time, time.Time) {
+	end := BusinessDay(now, loc)
+	return end.AddDate(0, 0, -velocityDays), end
+}
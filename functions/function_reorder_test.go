@@ -0,0 +1,96 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"testing"
+	"time"
+)
+
+var testReorderSettings = ReorderSettings{
+	LeadTimeDays:    7,
+	SafetyDays:      3,
+	CoverDays:       14,
+	StockThresholds: dto.StockThresholds{LowThreshold: 10, AverageThreshold: 25},
+}
+
+func TestAverageDailySales(t *testing.T) {
+	reports := []dto.DailyReportDocument{
+		{ProductsSold: []dto.ProductSoldSummary{{ProductID: "PRD-001", Quantity: 3}, {ProductID: "PRD-002", Quantity: 1}}},
+		{ProductsSold: []dto.ProductSoldSummary{{ProductID: "PRD-001", Quantity: 5}}},
+	}
+	averages := AverageDailySales(reports)
+	if averages["PRD-001"] != 4 || averages["PRD-002"] != 0.5 || averages["PRD-003"] != 0 {
+		t.Fatalf("expected 4 and 0.5 a day, got %v", averages)
+	}
+	if len(AverageDailySales(nil)) != 0 {
+		t.Fatal("expected no averages without reports")
+	}
+}
+
+func TestSuggestReorder(t *testing.T) {
+	product := dto.Product{ProductId: "PRD-001", Name: "Rice", StockQty: 5, CostPrice: dto.MoneyFromFloat(50)}
+
+	// 2 a day over 7 days' lead time and 3 safety days: reorder at 20, order up to 20 + 14 days of sales
+	suggestion, reorder := SuggestReorder(&product, 2, 0, 7, testReorderSettings)
+	if !reorder || suggestion.ReorderPoint != 20 || suggestion.ReorderPointSource != dto.ReorderFromSales || suggestion.SuggestedQty != 43 {
+		t.Fatalf("expected 43 ordered at a reorder point of 20, got %+v", suggestion)
+	}
+	if suggestion.EstimatedCost != dto.MoneyFromFloat(2150) {
+		t.Fatalf("expected 43 x 50, got %v", suggestion.EstimatedCost)
+	}
+
+	if _, reorder := SuggestReorder(&product, 2, 20, 7, testReorderSettings); reorder {
+		t.Fatal("stock plus what is on order above the reorder point should not be reordered")
+	}
+
+	// Without sales the low and average stock thresholds apply
+	suggestion, reorder = SuggestReorder(&product, 0, 0, 7, testReorderSettings)
+	if !reorder || suggestion.ReorderPoint != 10 || suggestion.ReorderPointSource != dto.ReorderFromThreshold || suggestion.SuggestedQty != 20 {
+		t.Fatalf("expected 20 ordered to reach the average threshold, got %+v", suggestion)
+	}
+
+	// The product's own settings win; its quantity is ordered in packs until above the reorder point
+	product.ReorderPoint = 30
+	product.ReorderQty = 12
+	suggestion, reorder = SuggestReorder(&product, 2, 0, 7, testReorderSettings)
+	if !reorder || suggestion.ReorderPoint != 30 || suggestion.ReorderPointSource != dto.ReorderFromProduct || suggestion.SuggestedQty != 36 {
+		t.Fatalf("expected three packs of 12, got %+v", suggestion)
+	}
+}
+
+func TestBuildReorderSuggestions(t *testing.T) {
+	assigned := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	products := []dto.Product{
+		{ProductId: "PRD-001", Name: "Rice", StockQty: 2},
+		{ProductId: "PRD-002", Name: "Milk", StockQty: 8},
+		{ProductId: "PRD-003", Name: "Salt", StockQty: 3},
+		{ProductId: "PRD-004", Name: "Sugar", StockQty: 100},
+		{ProductId: "PRD-005", Name: "Old", StockQty: 0, Deleted: true},
+	}
+	assignments := []dto.SupplierProduct{
+		{SupplierID: "SUP-002", SupplierName: "Zeta", ProductID: "PRD-001", AssignedAt: assigned.AddDate(0, 1, 0)},
+		{SupplierID: "SUP-001", SupplierName: "Acme", ProductID: "PRD-001", AssignedAt: assigned},
+		{SupplierID: "SUP-001", SupplierName: "Acme", ProductID: "PRD-002", AssignedAt: assigned},
+		{SupplierID: "SUP-002", SupplierName: "Zeta", ProductID: "PRD-004", AssignedAt: assigned},
+	}
+	suppliers := []dto.Supplier{{SupplierId: "SUP-001", Name: "Acme", LeadTimeDays: 2}, {SupplierId: "SUP-002", Name: "Zeta"}}
+	orders := []dto.PurchaseOrder{
+		{Status: dto.PODraft, Items: []dto.PurchaseOrderItem{{ProductId: "PRD-003", OrderedQty: 5}}},
+		{Status: dto.POClosed, Items: []dto.PurchaseOrderItem{{ProductId: "PRD-002", OrderedQty: 50}}},
+	}
+
+	groups := BuildReorderSuggestions(products, assignments, suppliers, nil, orders, testReorderSettings)
+	if len(groups) != 2 || groups[0].SupplierId != "SUP-001" || groups[1].SupplierId != "" {
+		t.Fatalf("expected Acme then the unassigned products, got %+v", groups)
+	}
+
+	acme := groups[0]
+	if acme.LeadTimeDays != 2 || len(acme.Suggestions) != 2 || acme.Suggestions[0].ProductId != "PRD-001" || acme.Suggestions[1].ProductId != "PRD-002" {
+		t.Fatalf("expected Rice (the first supplier it was assigned to) before Milk, got %+v", acme)
+	}
+
+	unassigned := groups[1]
+	if len(unassigned.Suggestions) != 1 || unassigned.Suggestions[0].OnOrderQty != 5 || unassigned.Suggestions[0].SuggestedQty != 17 {
+		t.Fatalf("expected Salt with the draft order counted, got %+v", unassigned.Suggestions)
+	}
+}
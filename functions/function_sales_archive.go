@@ -0,0 +1,126 @@
+package functions
+
+import (
+	"bytes"
+	"compress/gzip"
+	"employee-crud/dto"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SalesArchiveEncoding is the format of dto.SalesArchive.Data: a BSON document {sales: [...]} compressed with gzip
+const SalesArchiveEncoding = "bson+gzip"
+
+type archivedSales struct {
+	Sales []dto.Sale `bson:"sales"`
+}
+
+// BusinessDay returns the start of the business day t falls on
+func BusinessDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// GroupSalesByDay splits sales by the business day they were created on
+func GroupSalesByDay(sales []dto.Sale, loc *time.Location) map[time.Time][]dto.Sale {
+	days := make(map[time.Time][]dto.Sale)
+	for _, sale := range sales {
+		day := BusinessDay(sale.CreatedAt, loc)
+		days[day] = append(days[day], sale)
+	}
+	return days
+}
+
+// BuildSalesArchive compresses one day of sales into an archive document
+// Sales are stored oldest first and a saleId appearing twice is only kept once
+func BuildSalesArchive(day time.Time, sales []dto.Sale, now time.Time) (*dto.SalesArchive, error) {
+	if len(sales) == 0 {
+		return nil, fmt.Errorf("no sales to archive for %s", day.Format("2006-01-02"))
+	}
+
+	seen := make(map[string]bool, len(sales))
+	unique := make([]dto.Sale, 0, len(sales))
+	for _, sale := range sales {
+		if seen[sale.SaleID] {
+			continue
+		}
+		seen[sale.SaleID] = true
+		unique = append(unique, sale)
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		return unique[i].CreatedAt.Before(unique[j].CreatedAt)
+	})
+
+	data, err := CompressSales(unique)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &dto.SalesArchive{
+		Day:         day.UTC(),
+		SaleCount:   len(unique),
+		FirstSaleAt: unique[0].CreatedAt,
+		LastSaleAt:  unique[len(unique)-1].CreatedAt,
+		Encoding:    SalesArchiveEncoding,
+		Data:        data,
+		ArchivedAt:  now,
+	}
+	customers := make(map[string]bool)
+	for _, sale := range unique {
+		archive.SaleIds = append(archive.SaleIds, sale.SaleID)
+		if !sale.Voided {
+			archive.TotalRevenue += sale.Total
+		}
+		if sale.CustomerID != "" && !customers[sale.CustomerID] {
+			customers[sale.CustomerID] = true
+			archive.CustomerIds = append(archive.CustomerIds, sale.CustomerID)
+		}
+	}
+	return archive, nil
+}
+
+// CompressSales encodes sales in the SalesArchiveEncoding format
+func CompressSales(sales []dto.Sale) ([]byte, error) {
+	raw, err := bson.Marshal(archivedSales{Sales: sales})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecompressSales decodes the sales stored in an archive document
+func DecompressSales(archive *dto.SalesArchive) ([]dto.Sale, error) {
+	if archive.Encoding != SalesArchiveEncoding {
+		return nil, fmt.Errorf("unsupported sales archive encoding %q", archive.Encoding)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(archive.Data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded archivedSales
+	if err := bson.Unmarshal(raw, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded.Sales, nil
+}
@@ -0,0 +1,66 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"testing"
+	"time"
+)
+
+func TestBuildSalesArchiveRoundTrip(t *testing.T) {
+	loc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
+	morning := time.Date(2026, 3, 2, 9, 0, 0, 0, loc).UTC()
+	evening := time.Date(2026, 3, 2, 20, 0, 0, 0, loc).UTC()
+
+	sales := []dto.Sale{
+		{SaleID: "S-2", Total: dto.MoneyFromFloat(150.25), CreatedAt: evening, Items: []dto.SaleItem{{ProductID: "PRD-002", Quantity: 1}}},
+		{SaleID: "S-1", Total: dto.MoneyFromFloat(100), CreatedAt: morning, Items: []dto.SaleItem{{ProductID: "PRD-001", Quantity: 2}}},
+		{SaleID: "S-1", Total: dto.MoneyFromFloat(100), CreatedAt: morning},
+	}
+
+	day := BusinessDay(morning, loc)
+	archive, err := BuildSalesArchive(day, sales, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if archive.SaleCount != 2 || archive.TotalRevenue != dto.MoneyFromFloat(250.25) {
+		t.Fatalf("expected 2 sales worth 250.25, got %d worth %v", archive.SaleCount, archive.TotalRevenue)
+	}
+	if !archive.FirstSaleAt.Equal(morning) || !archive.LastSaleAt.Equal(evening) {
+		t.Fatalf("unexpected sale window %v - %v", archive.FirstSaleAt, archive.LastSaleAt)
+	}
+	if !archive.Day.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, loc)) {
+		t.Fatalf("expected the business day start, got %v", archive.Day)
+	}
+
+	restored, err := DecompressSales(archive)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	if len(restored) != 2 || restored[0].SaleID != "S-1" || restored[1].SaleID != "S-2" {
+		t.Fatalf("expected S-1 then S-2, got %+v", restored)
+	}
+	if restored[0].Items[0].Quantity != 2 || !restored[0].CreatedAt.Equal(morning) {
+		t.Fatalf("sale details were not preserved: %+v", restored[0])
+	}
+}
+
+func TestGroupSalesByDayUsesBusinessTimeZone(t *testing.T) {
+	loc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
+
+	// 20:00 UTC on the 1st is already the 2nd in Colombo
+	lateUTC := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	earlyUTC := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	days := GroupSalesByDay([]dto.Sale{
+		{SaleID: "S-1", CreatedAt: earlyUTC},
+		{SaleID: "S-2", CreatedAt: lateUTC},
+	}, loc)
+
+	if len(days) != 2 {
+		t.Fatalf("expected two business days, got %d", len(days))
+	}
+	if got := days[time.Date(2026, 3, 2, 0, 0, 0, 0, loc)]; len(got) != 1 || got[0].SaleID != "S-2" {
+		t.Fatalf("expected S-2 on the 2nd, got %+v", got)
+	}
+}
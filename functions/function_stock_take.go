@@ -0,0 +1,264 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrInvalidStockTake is returned for a stock take with an unknown scope or no products to count
+	ErrInvalidStockTake = errors.New("invalid stock take")
+	// ErrInvalidStockTakeCount is returned for a count that matches no line, or more than one, or is negative
+	ErrInvalidStockTakeCount = errors.New("invalid stock take count")
+)
+
+// StockTakeInScope reports whether a product is counted by a stock take of the given scope
+func StockTakeInScope(product *dto.Product, scope string, scopeId string) bool {
+	switch scope {
+	case dto.StockTakeAll:
+		return true
+	case dto.StockTakeCategory:
+		return product.CategoryID == scopeId
+	case dto.StockTakeBrand:
+		return product.BrandID == scopeId
+	}
+	return false
+}
+
+// BuildStockTakeLines freezes the quantity of every batch of the products in scope held at locationId
+// (every location when empty), by product name, location then expiry
+// A legacy product without batches is counted at the main location as a single line without a batch id
+func BuildStockTakeLines(products []dto.Product, scope string, scopeId string, locationId string) ([]dto.StockTakeLine, error) {
+	switch scope {
+	case dto.StockTakeAll:
+	case dto.StockTakeCategory, dto.StockTakeBrand:
+		if scopeId == "" {
+			return nil, fmt.Errorf("%w: scopeId is required for a %s stock take", ErrInvalidStockTake, scope)
+		}
+	default:
+		return nil, fmt.Errorf("%w: scope must be all, category or brand", ErrInvalidStockTake)
+	}
+
+	var lines []dto.StockTakeLine
+	for i := range products {
+		product := &products[i]
+		if product.Deleted || !StockTakeInScope(product, scope, scopeId) {
+			continue
+		}
+		if len(product.Batches) == 0 {
+			if locationId != "" && locationId != dto.MainLocationId {
+				continue
+			}
+			lines = append(lines, dto.StockTakeLine{
+				ProductId:   product.ProductId,
+				ProductName: product.Name,
+				Barcode:     product.Barcode,
+				LocationId:  dto.MainLocationId,
+				ExpiryDate:  product.ExpiryDate,
+				UnitCost:    product.CostPrice,
+				ExpectedQty: product.StockQty,
+			})
+			continue
+		}
+		for _, batch := range product.Batches {
+			batchLocation := BatchLocation(&batch)
+			if locationId != "" && batchLocation != locationId {
+				continue
+			}
+			lines = append(lines, dto.StockTakeLine{
+				ProductId:   product.ProductId,
+				ProductName: product.Name,
+				Barcode:     product.Barcode,
+				BatchId:     batch.BatchId,
+				LocationId:  batchLocation,
+				ExpiryDate:  batch.ExpiryDate,
+				UnitCost:    batch.CostPrice,
+				ExpectedQty: batch.StockQty,
+			})
+		}
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: no products in scope", ErrInvalidStockTake)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].ProductName != lines[j].ProductName {
+			return lines[i].ProductName < lines[j].ProductName
+		}
+		if lines[i].ProductId != lines[j].ProductId {
+			return lines[i].ProductId < lines[j].ProductId
+		}
+		if lines[i].LocationId != lines[j].LocationId {
+			return lines[i].LocationId < lines[j].LocationId
+		}
+		return LaterExpiry(lines[j].ExpiryDate, lines[i].ExpiryDate)
+	})
+	return lines, nil
+}
+
+// StockTakesOverlap reports whether two stock takes count any of the same products at the same location,
+// which would post the same variance twice
+func StockTakesOverlap(a *dto.StockTake, b *dto.StockTake) bool {
+	counted := make(map[[2]string]bool, len(a.Lines))
+	for _, line := range a.Lines {
+		counted[[2]string{line.ProductId, NormalizeLocationId(line.LocationId)}] = true
+	}
+	for _, line := range b.Lines {
+		if counted[[2]string{line.ProductId, NormalizeLocationId(line.LocationId)}] {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyStockTakeCounts records counts on an open stock take's lines and works out the variances again
+// A count finds its line by productId or barcode, and needs a batchId when the product has several lines
+func ApplyStockTakeCounts(stockTake *dto.StockTake, counts []dto.StockTakeCount, userId string, now time.Time) error {
+	for _, count := range counts {
+		line, err := stockTakeLine(stockTake, count)
+		if err != nil {
+			return err
+		}
+
+		counted := count.Quantity
+		if count.Add && line.CountedQty != nil {
+			counted += *line.CountedQty
+		}
+		if counted < 0 {
+			return fmt.Errorf("%w: %s cannot be counted below 0", ErrInvalidStockTakeCount, line.ProductId)
+		}
+		line.CountedQty = &counted
+		line.CountedBy = userId
+		countedAt := now
+		line.CountedAt = &countedAt
+	}
+	SummarizeStockTake(stockTake)
+	return nil
+}
+
+func stockTakeLine(stockTake *dto.StockTake, count dto.StockTakeCount) (*dto.StockTakeLine, error) {
+	if count.ProductId == "" && count.Barcode == "" {
+		return nil, fmt.Errorf("%w: productId or barcode is required", ErrInvalidStockTakeCount)
+	}
+
+	var found *dto.StockTakeLine
+	matches := 0
+	for i := range stockTake.Lines {
+		line := &stockTake.Lines[i]
+		if (count.ProductId != "" && line.ProductId != count.ProductId) ||
+			(count.Barcode != "" && line.Barcode != count.Barcode) ||
+			(count.BatchId != "" && line.BatchId != count.BatchId) {
+			continue
+		}
+		found = line
+		matches++
+	}
+
+	product := count.ProductId
+	if product == "" {
+		product = count.Barcode
+	}
+	switch {
+	case matches == 0:
+		return nil, fmt.Errorf("%w: %s %s is not in this stock take", ErrInvalidStockTakeCount, product, count.BatchId)
+	case matches > 1:
+		return nil, fmt.Errorf("%w: %s has several batches, give the batchId", ErrInvalidStockTakeCount, product)
+	}
+	return found, nil
+}
+
+// SummarizeStockTake works out each counted line's variance at its unit cost and the stock take's totals
+// Lines not yet counted have no variance
+func SummarizeStockTake(stockTake *dto.StockTake) {
+	stockTake.CountedLines = 0
+	stockTake.ExpectedValue = 0
+	stockTake.VarianceValue = 0
+	stockTake.ShortageValue = 0
+	stockTake.SurplusValue = 0
+
+	for i := range stockTake.Lines {
+		line := &stockTake.Lines[i]
+		stockTake.ExpectedValue += line.UnitCost.Times(line.ExpectedQty)
+
+		line.VarianceQty = 0
+		line.VarianceValue = 0
+		if line.CountedQty == nil {
+			continue
+		}
+		stockTake.CountedLines++
+		line.VarianceQty = *line.CountedQty - line.ExpectedQty
+		line.VarianceValue = line.UnitCost.Times(line.VarianceQty)
+
+		stockTake.VarianceValue += line.VarianceValue
+		if line.VarianceValue < 0 {
+			stockTake.ShortageValue -= line.VarianceValue
+		} else {
+			stockTake.SurplusValue += line.VarianceValue
+		}
+	}
+}
+
+// StockTakeProductIds returns the products with a counted variance to post, in line order
+func StockTakeProductIds(stockTake *dto.StockTake) []string {
+	seen := make(map[string]bool)
+	var productIds []string
+	for _, line := range stockTake.Lines {
+		if line.CountedQty == nil || line.VarianceQty == 0 || seen[line.ProductId] {
+			continue
+		}
+		seen[line.ProductId] = true
+		productIds = append(productIds, line.ProductId)
+	}
+	return productIds
+}
+
+// ApplyStockTakeVariance posts the counted variances of one product's stock-take lines into its batches
+// Only the variance is posted, so units sold or received while counting stay accounted for; a batch never
+// goes below 0, and a batch sold out while counting is put back at its location when more was counted than expected
+// Each line's PostedQty records the adjustment actually made
+func ApplyStockTakeVariance(product *dto.Product, stockTake *dto.StockTake, now time.Time) {
+	for i := range stockTake.Lines {
+		line := &stockTake.Lines[i]
+		if line.ProductId != product.ProductId || line.CountedQty == nil || line.VarianceQty == 0 {
+			continue
+		}
+
+		if line.BatchId == "" {
+			// Legacy product; if it has been given batches since, there is no telling which one was counted
+			if len(product.Batches) > 0 {
+				continue
+			}
+			before := product.StockQty
+			product.StockQty = max(0, before+line.VarianceQty)
+			line.PostedQty = product.StockQty - before
+			continue
+		}
+
+		batch := findBatch(product, line.BatchId)
+		if batch == nil {
+			if line.VarianceQty < 0 {
+				continue
+			}
+			product.Batches = append(product.Batches, dto.Batch{
+				BatchId:      line.BatchId,
+				LocationId:   NormalizeLocationId(line.LocationId),
+				ExpiryDate:   line.ExpiryDate,
+				CostPrice:    line.UnitCost,
+				SellingPrice: CurrentSellingPrice(product),
+				CreatedAt:    now,
+			})
+			batch = &product.Batches[len(product.Batches)-1]
+		}
+		before := batch.StockQty
+		batch.StockQty = max(0, before+line.VarianceQty)
+		batch.UpdatedAt = now
+		line.PostedQty = batch.StockQty - before
+	}
+
+	if len(product.Batches) > 0 {
+		removeEmptyBatches(product)
+	}
+}
@@ -0,0 +1,111 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+	"time"
+)
+
+func stockTakeTestProducts(now time.Time) []dto.Product {
+	soon := now.AddDate(0, 0, 5)
+	later := now.AddDate(0, 2, 0)
+	return []dto.Product{
+		{
+			ProductId: "PRD-RICE", Name: "Rice", Barcode: "111", CategoryID: "CAT-FOOD", StockQty: 30,
+			Batches: []dto.Batch{
+				{BatchId: "BATCH-LATER", StockQty: 20, ExpiryDate: &later, CostPrice: dto.MoneyFromFloat(70)},
+				{BatchId: "BATCH-SOON", StockQty: 10, ExpiryDate: &soon, CostPrice: dto.MoneyFromFloat(60)},
+			},
+		},
+		{ProductId: "PRD-MATCHES", Name: "Matches", Barcode: "222", CategoryID: "CAT-HOUSEHOLD", StockQty: 8, CostPrice: dto.MoneyFromFloat(5)},
+	}
+}
+
+func TestBuildStockTakeLinesFreezesBatchesInScope(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	products := stockTakeTestProducts(now)
+
+	lines, err := BuildStockTakeLines(products, dto.StockTakeCategory, "CAT-FOOD", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 2 || lines[0].BatchId != "BATCH-SOON" || lines[0].ExpectedQty != 10 || lines[1].BatchId != "BATCH-LATER" {
+		t.Fatalf("expected the rice batches, soonest expiry first, got %+v", lines)
+	}
+
+	lines, _ = BuildStockTakeLines(products, dto.StockTakeAll, "", "")
+	if len(lines) != 3 || lines[0].ProductId != "PRD-MATCHES" || lines[0].BatchId != "" || lines[0].UnitCost != dto.MoneyFromFloat(5) {
+		t.Fatalf("expected the legacy matches as a line without a batch, got %+v", lines)
+	}
+
+	if _, err := BuildStockTakeLines(products, dto.StockTakeBrand, "", ""); !errors.Is(err, ErrInvalidStockTake) {
+		t.Fatalf("expected a brand stock take without a brand to be rejected, got %v", err)
+	}
+	if _, err := BuildStockTakeLines(products, dto.StockTakeBrand, "BRD-NONE", ""); !errors.Is(err, ErrInvalidStockTake) {
+		t.Fatalf("expected a stock take with nothing to count to be rejected, got %v", err)
+	}
+}
+
+func TestApplyStockTakeCounts(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	lines, _ := BuildStockTakeLines(stockTakeTestProducts(now), dto.StockTakeAll, "", "")
+	stockTake := &dto.StockTake{Lines: lines}
+
+	// The matches are scanned three times, then twice more
+	scan := dto.StockTakeCount{Barcode: "222", Quantity: 1, Add: true}
+	if err := ApplyStockTakeCounts(stockTake, []dto.StockTakeCount{scan, scan, scan}, "USR-1", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ApplyStockTakeCounts(stockTake, []dto.StockTakeCount{{Barcode: "222", Quantity: 2, Add: true}}, "USR-1", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ApplyStockTakeCounts(stockTake, []dto.StockTakeCount{{ProductId: "PRD-RICE", Quantity: 5}}, "USR-1", now); !errors.Is(err, ErrInvalidStockTakeCount) {
+		t.Fatalf("expected a count for a product with two batches to need the batch, got %v", err)
+	}
+	if err := ApplyStockTakeCounts(stockTake, []dto.StockTakeCount{{ProductId: "PRD-RICE", BatchId: "BATCH-LATER", Quantity: 22}}, "USR-1", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	matches := stockTake.Lines[0]
+	if matches.CountedQty == nil || *matches.CountedQty != 5 || matches.VarianceQty != -3 || matches.VarianceValue != dto.MoneyFromFloat(-15) {
+		t.Fatalf("expected 5 matches counted, 3 short at 5 each, got %+v", matches)
+	}
+	// 3 matches short at 5 and 2 rice over at 70; the soon batch is not counted yet
+	if stockTake.CountedLines != 2 || stockTake.ShortageValue != dto.MoneyFromFloat(15) ||
+		stockTake.SurplusValue != dto.MoneyFromFloat(140) || stockTake.VarianceValue != dto.MoneyFromFloat(125) {
+		t.Fatalf("unexpected totals %+v", stockTake)
+	}
+	if productIds := StockTakeProductIds(stockTake); len(productIds) != 2 || productIds[0] != "PRD-MATCHES" {
+		t.Fatalf("expected both products to have a variance to post, got %v", productIds)
+	}
+}
+
+func TestApplyStockTakeVariancePostsOnlyTheVariance(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	products := stockTakeTestProducts(now)
+	lines, _ := BuildStockTakeLines(products, dto.StockTakeCategory, "CAT-FOOD", "")
+	stockTake := &dto.StockTake{Lines: lines}
+	ApplyStockTakeCounts(stockTake, []dto.StockTakeCount{
+		{ProductId: "PRD-RICE", BatchId: "BATCH-SOON", Quantity: 12},
+		{ProductId: "PRD-RICE", BatchId: "BATCH-LATER", Quantity: 17},
+	}, "USR-1", now)
+
+	// While counting, the soon batch sold out and 4 were sold from the later batch
+	product := products[0]
+	product.Batches = []dto.Batch{{BatchId: "BATCH-LATER", StockQty: 16, CostPrice: dto.MoneyFromFloat(70)}}
+	product.StockQty = 16
+
+	ApplyStockTakeVariance(&product, stockTake, now)
+
+	if len(product.Batches) != 2 || product.StockQty != 15 {
+		t.Fatalf("expected the later batch 3 down and the soon batch back with 2, got %+v", product.Batches)
+	}
+	later, soon := product.Batches[0], product.Batches[1]
+	if later.StockQty != 13 || soon.BatchId != "BATCH-SOON" || soon.StockQty != 2 || soon.CostPrice != dto.MoneyFromFloat(60) {
+		t.Fatalf("unexpected batches %+v", product.Batches)
+	}
+	if stockTake.Lines[0].PostedQty != 2 || stockTake.Lines[1].PostedQty != -3 {
+		t.Fatalf("expected +2 and -3 posted, got %+v", stockTake.Lines)
+	}
+}
@@ -0,0 +1,35 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+)
+
+// ErrInvalidStockThresholds is returned when a low threshold is not positive or not below the average threshold
+var ErrInvalidStockThresholds = errors.New("lowThreshold must be positive and below averageThreshold")
+
+// ValidateStockThresholds checks thresholds being set on a product or category; nil clears them and is always valid
+func ValidateStockThresholds(thresholds *dto.StockThresholds) error {
+	if thresholds != nil && !thresholds.Valid() {
+		return ErrInvalidStockThresholds
+	}
+	return nil
+}
+
+// ResolveStockThresholds returns the thresholds that apply to a product and where they come from:
+// the product's own, else its category's, else the global ones
+func ResolveStockThresholds(product *dto.Product, categories map[string]dto.StockThresholds, global dto.StockThresholds) (dto.StockThresholds, string) {
+	if product.StockThresholds != nil {
+		return *product.StockThresholds, dto.StockThresholdsFromProduct
+	}
+	if thresholds, ok := categories[product.CategoryID]; ok {
+		return thresholds, dto.StockThresholdsFromCategory
+	}
+	return global, dto.StockThresholdsFromGlobal
+}
+
+// ProductStockStatus returns the status of a product's total stock under its resolved thresholds
+func ProductStockStatus(product *dto.Product, categories map[string]dto.StockThresholds, global dto.StockThresholds) string {
+	thresholds, _ := ResolveStockThresholds(product, categories, global)
+	return thresholds.Status(product.StockQty)
+}
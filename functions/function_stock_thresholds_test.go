@@ -0,0 +1,48 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+)
+
+func TestResolveStockThresholds(t *testing.T) {
+	global := dto.StockThresholds{LowThreshold: 10, AverageThreshold: 25}
+	categories := map[string]dto.StockThresholds{"CAT-RICE": {LowThreshold: 50, AverageThreshold: 200}}
+
+	product := dto.Product{ProductId: "PRD-001", CategoryID: "CAT-RICE", StockQty: 40}
+	thresholds, source := ResolveStockThresholds(&product, categories, global)
+	if thresholds.LowThreshold != 50 || source != dto.StockThresholdsFromCategory {
+		t.Fatalf("expected the category's thresholds, got %+v from %s", thresholds, source)
+	}
+	if status := ProductStockStatus(&product, categories, global); status != dto.StockStatusLow {
+		t.Fatalf("expected 40 bags of rice to be low, got %s", status)
+	}
+
+	product.StockThresholds = &dto.StockThresholds{LowThreshold: 5, AverageThreshold: 30}
+	if thresholds, source = ResolveStockThresholds(&product, categories, global); thresholds.LowThreshold != 5 || source != dto.StockThresholdsFromProduct {
+		t.Fatalf("expected the product's own thresholds, got %+v from %s", thresholds, source)
+	}
+	if status := ProductStockStatus(&product, categories, global); status != dto.StockStatusGood {
+		t.Fatalf("expected 40 to be good above the product's 30, got %s", status)
+	}
+
+	matches := dto.Product{ProductId: "PRD-002", CategoryID: "CAT-MATCHES", StockQty: 20}
+	if thresholds, source = ResolveStockThresholds(&matches, categories, global); thresholds != global || source != dto.StockThresholdsFromGlobal {
+		t.Fatalf("expected the global thresholds, got %+v from %s", thresholds, source)
+	}
+	if status := ProductStockStatus(&matches, categories, global); status != dto.StockStatusAverage {
+		t.Fatalf("expected 20 to be average, got %s", status)
+	}
+}
+
+func TestValidateStockThresholds(t *testing.T) {
+	if err := ValidateStockThresholds(nil); err != nil {
+		t.Fatalf("clearing thresholds should be valid, got %v", err)
+	}
+	for _, thresholds := range []dto.StockThresholds{{LowThreshold: 0, AverageThreshold: 5}, {LowThreshold: 10, AverageThreshold: 10}} {
+		if err := ValidateStockThresholds(&thresholds); !errors.Is(err, ErrInvalidStockThresholds) {
+			t.Fatalf("expected %+v to be rejected, got %v", thresholds, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"sort"
+	"time"
+)
+
+// SummarizeSales builds the daily sales summary for the given sales
+func SummarizeSales(targetDate time.Time, sales []dto.Sale) *dto.DailySalesSummary {
+	// Calculate summary
+	summary := &dto.DailySalesSummary{
+		ReportDate:   targetDate,
+		TaxBreakdown: make([]dto.TaxSummary, 0),
+		Tenders:      make([]dto.TenderSummary, 0),
+		ProductsSold: make([]dto.ProductSoldSummary, 0),
+		VoidedSales:  make([]dto.VoidedSaleSummary, 0),
+	}
+
+	// Map to aggregate product sales
+	productMap := make(map[string]*dto.ProductSoldSummary)
+
+	// Process each sale
+	for _, sale := range sales {
+		// Voided sales are listed on their own and count towards nothing else
+		if sale.Voided {
+			summary.VoidedSales = append(summary.VoidedSales, voidedSaleSummary(&sale))
+			continue
+		}
+
+		summary.TotalSales++
+		summary.TotalRevenue += sale.Total
+		summary.TotalDiscount += sale.Discount + sale.PromotionDiscount
+		summary.TotalTax += sale.Tax + sale.IncludedTax
+		summary.TaxBreakdown = AddTaxSummaries(summary.TaxBreakdown, SaleTaxBreakdown(&sale))
+
+		// Revenue per tender type; a split sale counts towards each of its tenders
+		summary.Tenders = AddTenderSummaries(summary.Tenders, summarizeTenders(&sale))
+
+		// Aggregate product sales, net of promotions
+		for _, item := range sale.Items {
+			if existing, exists := productMap[item.ProductID]; exists {
+				existing.Quantity += item.Quantity
+				existing.TotalAmount += item.TotalPrice - item.Discount
+			} else {
+				productMap[item.ProductID] = &dto.ProductSoldSummary{
+					ProductID:   item.ProductID,
+					ProductName: item.ProductName,
+					Quantity:    item.Quantity,
+					UnitPrice:   item.UnitPrice,
+					TotalAmount: item.TotalPrice - item.Discount,
+				}
+			}
+		}
+	}
+
+	// Convert map to slice
+	for _, product := range productMap {
+		summary.ProductsSold = append(summary.ProductsSold, *product)
+	}
+
+	// Sort products by total amount (descending)
+	sort.Slice(summary.ProductsSold, func(i, j int) bool {
+		return summary.ProductsSold[i].TotalAmount > summary.ProductsSold[j].TotalAmount
+	})
+
+	// Get top 10 selling items
+	topCount := 10
+	if len(summary.ProductsSold) < topCount {
+		topCount = len(summary.ProductsSold)
+	}
+	summary.TopSellingItems = summary.ProductsSold[:topCount]
+
+	sort.Slice(summary.VoidedSales, func(i, j int) bool {
+		return summary.VoidedSales[i].SoldAt.Before(summary.VoidedSales[j].SoldAt)
+	})
+
+	return summary
+}
+
+func voidedSaleSummary(sale *dto.Sale) dto.VoidedSaleSummary {
+	voided := dto.VoidedSaleSummary{
+		SaleID:   sale.SaleID,
+		Total:    sale.Total,
+		Reason:   sale.VoidReason,
+		VoidedBy: sale.VoidedBy,
+		SoldAt:   sale.CreatedAt,
+	}
+	if sale.VoidedAt != nil {
+		voided.VoidedAt = *sale.VoidedAt
+	}
+	return voided
+}
+
+// BuildDailyReport turns a daily sales summary into the report saved for its day
+// CreatedAt and ExpiresAt are left to the caller
+func BuildDailyReport(summary *dto.DailySalesSummary) dto.DailyReportDocument {
+	profit := summary.Profit
+	return dto.DailyReportDocument{
+		ReportDate:      summary.ReportDate,
+		LocationId:      summary.LocationId,
+		Month:           int(summary.ReportDate.Month()),
+		Year:            summary.ReportDate.Year(),
+		TotalSales:      summary.TotalSales,
+		TotalRevenue:    summary.TotalRevenue,
+		TotalDiscount:   summary.TotalDiscount,
+		TotalTax:        summary.TotalTax,
+		TaxBreakdown:    summary.TaxBreakdown,
+		Tenders:         summary.Tenders,
+		ProductsSold:    summary.ProductsSold,
+		TopSellingItems: summary.TopSellingItems,
+		VoidedSales:     summary.VoidedSales,
+		Profit:          &profit,
+	}
+}
@@ -0,0 +1,115 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidTaxClass is returned when a tax class has no name or an impossible rate
+var ErrInvalidTaxClass = errors.New("invalid tax class")
+
+// ValidateTaxClass checks a tax class's name and rate
+func ValidateTaxClass(class *dto.TaxClass) error {
+	if class.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTaxClass)
+	}
+	if class.Rate < 0 || class.Rate > 100 {
+		return fmt.Errorf("%w: rate must be between 0 and 100", ErrInvalidTaxClass)
+	}
+	return nil
+}
+
+// ApplyLineTaxes taxes each priced line, after its promotion discount, at the rate of its product's tax class
+// classes holds the tax class of each line by product id; lines without one are not taxed.
+// Exclusive classes add the tax to the line, inclusive classes take it out of the line's price.
+// Each item's tax fields are overwritten. Returns the tax to add to the bill, the tax already in the prices and
+// the breakdown by class
+func ApplyLineTaxes(items []dto.SaleItem, classes map[string]*dto.TaxClass) (added dto.Money, included dto.Money, breakdown []dto.TaxSummary) {
+	for i := range items {
+		item := &items[i]
+		item.TaxClassID = ""
+		item.TaxRate = 0
+		item.TaxInclusive = false
+		item.Tax = 0
+
+		class := classes[item.ProductID]
+		if class == nil {
+			continue
+		}
+
+		lineValue := item.TotalPrice - item.Discount
+		taxable := lineValue
+		if class.Inclusive {
+			item.Tax = lineValue.IncludedPercent(class.Rate)
+			taxable = lineValue - item.Tax
+			included += item.Tax
+		} else {
+			item.Tax = lineValue.Percent(class.Rate)
+			added += item.Tax
+		}
+		item.TaxClassID = class.TaxClassID
+		item.TaxRate = class.Rate
+		item.TaxInclusive = class.Inclusive
+
+		breakdown = AddTaxSummaries(breakdown, []dto.TaxSummary{{
+			TaxClassID:    class.TaxClassID,
+			Name:          class.Name,
+			Rate:          class.Rate,
+			Inclusive:     class.Inclusive,
+			TaxableAmount: taxable,
+			Tax:           item.Tax,
+		}})
+	}
+	return added, included, breakdown
+}
+
+// SaleTaxBreakdown returns the tax breakdown of a sale; the bill-level tax of a sale made before tax classes
+// is one entry without a class
+func SaleTaxBreakdown(sale *dto.Sale) []dto.TaxSummary {
+	if len(sale.TaxBreakdown) > 0 || sale.Tax == 0 {
+		return sale.TaxBreakdown
+	}
+	return []dto.TaxSummary{{
+		Name:          "Bill-level tax",
+		Rate:          sale.TaxPercentage,
+		TaxableAmount: sale.Subtotal - sale.PromotionDiscount,
+		Tax:           sale.Tax,
+	}}
+}
+
+// ReportTaxBreakdown returns the tax breakdown of a saved daily report; a report saved before tax classes
+// has its total tax as one entry without a class or taxable amount
+func ReportTaxBreakdown(report *dto.DailyReportDocument) []dto.TaxSummary {
+	if len(report.TaxBreakdown) > 0 || report.TotalTax == 0 {
+		return report.TaxBreakdown
+	}
+	return []dto.TaxSummary{{Name: "Bill-level tax", Tax: report.TotalTax}}
+}
+
+// AddTaxSummaries merges add into totals by tax class and rate, and returns totals highest rate first
+func AddTaxSummaries(totals []dto.TaxSummary, add []dto.TaxSummary) []dto.TaxSummary {
+	for _, tax := range add {
+		found := false
+		for i := range totals {
+			if totals[i].TaxClassID == tax.TaxClassID && totals[i].Rate == tax.Rate && totals[i].Inclusive == tax.Inclusive {
+				totals[i].TaxableAmount += tax.TaxableAmount
+				totals[i].Tax += tax.Tax
+				found = true
+				break
+			}
+		}
+		if !found {
+			totals = append(totals, tax)
+		}
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		if totals[i].Rate != totals[j].Rate {
+			return totals[i].Rate > totals[j].Rate
+		}
+		return totals[i].TaxClassID < totals[j].TaxClassID
+	})
+	return totals
+}
@@ -0,0 +1,53 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"testing"
+	"time"
+)
+
+func TestApplyLineTaxesMixedBasket(t *testing.T) {
+	standard := &dto.TaxClass{TaxClassID: "TAX-001", Name: "Standard", Rate: 18}
+	inclusive := &dto.TaxClass{TaxClassID: "TAX-002", Name: "Standard (in price)", Rate: 25, Inclusive: true}
+	items := []dto.SaleItem{
+		{ProductID: "PRD-001", Quantity: 2, UnitPrice: dto.MoneyFromFloat(100), TotalPrice: dto.MoneyFromFloat(200), Discount: dto.MoneyFromFloat(50)},
+		{ProductID: "PRD-002", Quantity: 1, UnitPrice: dto.MoneyFromFloat(125), TotalPrice: dto.MoneyFromFloat(125)},
+		{ProductID: "PRD-003", Quantity: 4, UnitPrice: dto.MoneyFromFloat(25), TotalPrice: dto.MoneyFromFloat(100)}, // Exempt groceries
+	}
+	classes := map[string]*dto.TaxClass{"PRD-001": standard, "PRD-002": inclusive}
+
+	added, included, breakdown := ApplyLineTaxes(items, classes)
+
+	// 18% of the 150 left after the promotion; 125 includes 25 of tax
+	if added != dto.MoneyFromFloat(27) || included != dto.MoneyFromFloat(25) {
+		t.Fatalf("expected 27 added and 25 included, got %v and %v", added, included)
+	}
+	if items[0].Tax != dto.MoneyFromFloat(27) || items[0].TaxClassID != "TAX-001" || items[1].Tax != dto.MoneyFromFloat(25) || !items[1].TaxInclusive || items[2].Tax != 0 {
+		t.Fatalf("unexpected line taxes %+v", items)
+	}
+	want := []dto.TaxSummary{
+		{TaxClassID: "TAX-002", Name: "Standard (in price)", Rate: 25, Inclusive: true, TaxableAmount: dto.MoneyFromFloat(100), Tax: dto.MoneyFromFloat(25)},
+		{TaxClassID: "TAX-001", Name: "Standard", Rate: 18, TaxableAmount: dto.MoneyFromFloat(150), Tax: dto.MoneyFromFloat(27)},
+	}
+	if len(breakdown) != len(want) || breakdown[0] != want[0] || breakdown[1] != want[1] {
+		t.Fatalf("expected breakdown %+v, got %+v", want, breakdown)
+	}
+}
+
+func TestSummarizeSalesTaxBreakdown(t *testing.T) {
+	sales := []dto.Sale{
+		{Total: dto.MoneyFromFloat(118), Tax: dto.MoneyFromFloat(18), TaxBreakdown: []dto.TaxSummary{{TaxClassID: "TAX-001", Name: "Standard", Rate: 18, TaxableAmount: dto.MoneyFromFloat(100), Tax: dto.MoneyFromFloat(18)}}},
+		{Total: dto.MoneyFromFloat(125), IncludedTax: dto.MoneyFromFloat(25), TaxBreakdown: []dto.TaxSummary{{TaxClassID: "TAX-002", Name: "Inclusive", Rate: 25, Inclusive: true, TaxableAmount: dto.MoneyFromFloat(100), Tax: dto.MoneyFromFloat(25)}}},
+		// Made before tax classes: one bill-level rate
+		{Total: dto.MoneyFromFloat(110), Subtotal: dto.MoneyFromFloat(100), Tax: dto.MoneyFromFloat(10), TaxPercentage: 10},
+	}
+
+	summary := SummarizeSales(time.Now(), sales)
+
+	if summary.TotalTax != dto.MoneyFromFloat(53) {
+		t.Fatalf("expected 53 total tax including the inclusive tax, got %v", summary.TotalTax)
+	}
+	if len(summary.TaxBreakdown) != 3 || summary.TaxBreakdown[2].TaxClassID != "" || summary.TaxBreakdown[2].TaxableAmount != dto.MoneyFromFloat(100) {
+		t.Fatalf("expected 3 entries with the bill-level tax last, got %+v", summary.TaxBreakdown)
+	}
+}
@@ -0,0 +1,172 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidTender is returned when the tenders of a sale are missing, malformed or do not cover the total
+var ErrInvalidTender = errors.New("invalid tender")
+
+// IsTenderType reports whether tenderType is one of dto.TenderTypes
+func IsTenderType(tenderType string) bool {
+	return tenderIndex(tenderType) < len(dto.TenderTypes)
+}
+
+// tenderIndex is the position of tenderType in dto.TenderTypes, or len(dto.TenderTypes) for unknown types
+func tenderIndex(tenderType string) int {
+	for i, known := range dto.TenderTypes {
+		if known == tenderType {
+			return i
+		}
+	}
+	return len(dto.TenderTypes)
+}
+
+// SettleTenders checks the tenders offered for a sale total and works out the change
+// Non-cash tenders are charged exactly, so together they may not exceed the total; any overpayment has to be
+// cash and is given back as change. The returned tenders hold the amounts applied to the sale (cash net of
+// change, zero amounts dropped) and add up to total. cashReceived is the cash handed over before change
+func SettleTenders(total dto.Money, tenders []dto.Tender) (applied []dto.Tender, cashReceived dto.Money, change dto.Money, err error) {
+	if len(tenders) == 0 {
+		return nil, 0, 0, fmt.Errorf("%w: at least one tender is required", ErrInvalidTender)
+	}
+
+	var nonCash dto.Money
+	for _, tender := range tenders {
+		if !IsTenderType(tender.Type) {
+			return nil, 0, 0, fmt.Errorf("%w: unknown tender type '%s'", ErrInvalidTender, tender.Type)
+		}
+		if tender.Amount < 0 {
+			return nil, 0, 0, fmt.Errorf("%w: %s amount cannot be negative", ErrInvalidTender, tender.Type)
+		}
+		if tender.Type == dto.TenderCash {
+			cashReceived += tender.Amount
+		} else {
+			nonCash += tender.Amount
+		}
+	}
+
+	if nonCash > total {
+		return nil, 0, 0, fmt.Errorf("%w: non-cash tenders (%s) exceed the total (%s)", ErrInvalidTender, nonCash, total)
+	}
+	if nonCash+cashReceived < total {
+		return nil, 0, 0, fmt.Errorf("%w: amount tendered (%s) is less than the total (%s)", ErrInvalidTender, nonCash+cashReceived, total)
+	}
+	change = nonCash + cashReceived - total
+
+	// Take the change out of the cash tenders, last one first
+	applied = make([]dto.Tender, len(tenders))
+	copy(applied, tenders)
+	remaining := change
+	for i := len(applied) - 1; i >= 0 && remaining > 0; i-- {
+		if applied[i].Type != dto.TenderCash {
+			continue
+		}
+		taken := applied[i].Amount
+		if taken > remaining {
+			taken = remaining
+		}
+		applied[i].Amount -= taken
+		remaining -= taken
+	}
+
+	kept := applied[:0]
+	for _, tender := range applied {
+		if tender.Amount > 0 {
+			kept = append(kept, tender)
+		}
+	}
+	return kept, cashReceived, change, nil
+}
+
+// tenderLabels are the display names of the tender types
+var tenderLabels = map[string]string{
+	dto.TenderCash:          "Cash",
+	dto.TenderCard:          "Card",
+	dto.TenderBankTransfer:  "Bank Transfer",
+	dto.TenderVoucher:       "Voucher",
+	dto.TenderStoreCredit:   "Store Credit",
+	dto.TenderLoyaltyPoints: "Loyalty Points",
+}
+
+// TenderLabel returns the display name of a tender type; unknown types are shown as they are
+func TenderLabel(tenderType string) string {
+	if label, ok := tenderLabels[tenderType]; ok {
+		return label
+	}
+	return tenderType
+}
+
+// PaymentMethodOf returns the single tender type of tenders, or dto.PaymentSplit when several types were used
+func PaymentMethodOf(tenders []dto.Tender) string {
+	method := ""
+	for _, tender := range tenders {
+		if method != "" && tender.Type != method {
+			return dto.PaymentSplit
+		}
+		method = tender.Type
+	}
+	return method
+}
+
+// SaleTenders returns the tenders of a sale; a sale made before split payments is one tender of its PaymentMethod
+func SaleTenders(sale *dto.Sale) []dto.Tender {
+	if len(sale.Tenders) > 0 || sale.PaymentMethod == "" {
+		return sale.Tenders
+	}
+	return []dto.Tender{{Type: sale.PaymentMethod, Amount: sale.Total}}
+}
+
+// ReportTenders returns the revenue per tender of a saved daily report, including reports saved before tenders
+func ReportTenders(report *dto.DailyReportDocument) []dto.TenderSummary {
+	if len(report.Tenders) > 0 {
+		return report.Tenders
+	}
+
+	var tenders []dto.TenderSummary
+	if report.CashSales > 0 || report.CashRevenue != 0 {
+		tenders = append(tenders, dto.TenderSummary{Type: dto.TenderCash, Sales: report.CashSales, Amount: report.CashRevenue})
+	}
+	if report.CardSales > 0 || report.CardRevenue != 0 {
+		tenders = append(tenders, dto.TenderSummary{Type: dto.TenderCard, Sales: report.CardSales, Amount: report.CardRevenue})
+	}
+	return tenders
+}
+
+// AddTenderSummaries merges add into totals by tender type and returns totals in report order
+func AddTenderSummaries(totals []dto.TenderSummary, add []dto.TenderSummary) []dto.TenderSummary {
+	for _, tender := range add {
+		found := false
+		for i := range totals {
+			if totals[i].Type == tender.Type {
+				totals[i].Sales += tender.Sales
+				totals[i].Amount += tender.Amount
+				found = true
+				break
+			}
+		}
+		if !found {
+			totals = append(totals, tender)
+		}
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		return tenderIndex(totals[i].Type) < tenderIndex(totals[j].Type)
+	})
+	return totals
+}
+
+// summarizeTenders returns the revenue per tender type of one sale, counting the sale once per type
+func summarizeTenders(sale *dto.Sale) []dto.TenderSummary {
+	var tenders []dto.TenderSummary
+	for _, tender := range SaleTenders(sale) {
+		tenders = AddTenderSummaries(tenders, []dto.TenderSummary{{Type: tender.Type, Amount: tender.Amount}})
+	}
+	for i := range tenders {
+		tenders[i].Sales = 1
+	}
+	return tenders
+}
@@ -0,0 +1,74 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSettleTendersGivesChangeFromCashOnly(t *testing.T) {
+	tenders := []dto.Tender{
+		{Type: dto.TenderCard, Amount: dto.MoneyFromFloat(600), Reference: "AUTH-1"},
+		{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(500)},
+	}
+
+	applied, cashReceived, change, err := SettleTenders(dto.MoneyFromFloat(1000), tenders)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cashReceived != dto.MoneyFromFloat(500) || change != dto.MoneyFromFloat(100) {
+		t.Fatalf("expected 500 cash received and 100 change, got %v and %v", cashReceived, change)
+	}
+	if len(applied) != 2 || applied[0].Amount != dto.MoneyFromFloat(600) || applied[1].Amount != dto.MoneyFromFloat(400) {
+		t.Fatalf("expected card 600 and cash 400 applied, got %+v", applied)
+	}
+	if tenders[1].Amount != dto.MoneyFromFloat(500) {
+		t.Fatalf("expected the offered tenders to be left unchanged, got %+v", tenders)
+	}
+	if PaymentMethodOf(tenders) != dto.PaymentSplit {
+		t.Fatalf("expected a split payment, got %s", PaymentMethodOf(tenders))
+	}
+}
+
+func TestSettleTendersRejectsInvalidTenders(t *testing.T) {
+	cases := map[string][]dto.Tender{
+		"no tenders":          nil,
+		"unknown type":        {{Type: "cheque", Amount: dto.MoneyFromFloat(1000)}},
+		"negative amount":     {{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(-1)}, {Type: dto.TenderCard, Amount: dto.MoneyFromFloat(1001)}},
+		"non-cash over":       {{Type: dto.TenderCard, Amount: dto.MoneyFromFloat(1200)}},
+		"not enough tendered": {{Type: dto.TenderVoucher, Amount: dto.MoneyFromFloat(300)}, {Type: dto.TenderCash, Amount: dto.MoneyFromFloat(500)}},
+	}
+	for name, tenders := range cases {
+		if _, _, _, err := SettleTenders(dto.MoneyFromFloat(1000), tenders); !errors.Is(err, ErrInvalidTender) {
+			t.Errorf("%s: expected ErrInvalidTender, got %v", name, err)
+		}
+	}
+}
+
+func TestSummarizeSalesReportsRevenuePerTender(t *testing.T) {
+	sales := []dto.Sale{
+		{Total: dto.MoneyFromFloat(1000), PaymentMethod: dto.PaymentSplit, Tenders: []dto.Tender{
+			{Type: dto.TenderCard, Amount: dto.MoneyFromFloat(600)},
+			{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(400)},
+		}},
+		{Total: dto.MoneyFromFloat(250), PaymentMethod: dto.TenderCash, Tenders: []dto.Tender{{Type: dto.TenderCash, Amount: dto.MoneyFromFloat(250)}}},
+		// Made before split payments: only the payment method is recorded
+		{Total: dto.MoneyFromFloat(300), PaymentMethod: dto.TenderCard},
+	}
+
+	summary := SummarizeSales(time.Now(), sales)
+
+	want := []dto.TenderSummary{
+		{Type: dto.TenderCash, Sales: 2, Amount: dto.MoneyFromFloat(650)},
+		{Type: dto.TenderCard, Sales: 2, Amount: dto.MoneyFromFloat(900)},
+	}
+	if len(summary.Tenders) != len(want) {
+		t.Fatalf("expected tenders %+v, got %+v", want, summary.Tenders)
+	}
+	for i := range want {
+		if summary.Tenders[i] != want[i] {
+			t.Fatalf("expected tenders %+v, got %+v", want, summary.Tenders)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidVoid is returned when a sale cannot be voided
+var ErrInvalidVoid = errors.New("sale cannot be voided")
+
+// CheckSaleVoidable returns ErrInvalidVoid if the sale was already voided or has returns against it
+// Units that were returned are refunded and restocked by their return, so the sale can no longer be undone as a whole
+func CheckSaleVoidable(sale *dto.Sale) error {
+	if sale.Voided {
+		return fmt.Errorf("%w: sale %s is already voided", ErrInvalidVoid, sale.SaleID)
+	}
+	for _, item := range sale.Items {
+		if item.ReturnedQty > 0 {
+			return fmt.Errorf("%w: sale %s has returns against it", ErrInvalidVoid, sale.SaleID)
+		}
+	}
+	return nil
+}
+
+// ApplySaleVoid puts quantity units of a voided sale back into the product
+// allocations are the batches the sale took the product from: each unit goes back to its batch,
+// and a batch the sale emptied (and so removed) is recreated at the sale's location with its id, expiry and prices
+// Units the allocations do not account for (sales recorded before either) are restocked like a return
+func ApplySaleVoid(product *dto.Product, quantity int, allocations []dto.BatchAllocation, locationId string, newBatchId func() (string, error), now time.Time) error {
+	remaining := quantity
+	for _, allocation := range allocations {
+		units := allocation.Quantity
+		if units <= 0 || remaining <= 0 {
+			continue
+		}
+		if units > remaining {
+			units = remaining
+		}
+
+		if allocation.BatchID == "" {
+			// Legacy product without batches; if it has been converted since, restock it like a return
+			if len(product.Batches) > 0 {
+				continue
+			}
+			product.StockQty += units
+			remaining -= units
+			continue
+		}
+
+		if batch := findBatch(product, allocation.BatchID); batch != nil {
+			batch.StockQty += units
+			batch.UpdatedAt = now
+		} else {
+			product.Batches = append(product.Batches, dto.Batch{
+				BatchId:      allocation.BatchID,
+				BatchNumber:  allocation.BatchNumber,
+				LocationId:   NormalizeLocationId(locationId),
+				StockQty:     units,
+				ExpiryDate:   allocation.ExpiryDate,
+				CostPrice:    allocation.CostPrice,
+				SellingPrice: allocation.SellingPrice,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			})
+		}
+		product.StockQty = TotalBatchStock(product.Batches)
+		remaining -= units
+	}
+
+	if remaining > 0 {
+		if _, err := ApplyReturnRestock(product, remaining, "", nil, locationId, newBatchId, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaleHasAllocations reports whether every line of the sale recorded the batches it was deducted from
+// Sales recorded before allocations need their ledger entries to be voided
+func SaleHasAllocations(sale *dto.Sale) bool {
+	for _, item := range sale.Items {
+		if len(item.Batches) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SaleAllocationsByProduct returns the batches a sale took each product from
+// Lines' own allocations are used when every line has them; otherwise the sale's ledger entries are
+func SaleAllocationsByProduct(sale *dto.Sale, movements []dto.StockMovement) map[string][]dto.BatchAllocation {
+	byProduct := make(map[string][]dto.BatchAllocation)
+	if SaleHasAllocations(sale) {
+		for _, item := range sale.Items {
+			byProduct[item.ProductID] = append(byProduct[item.ProductID], item.Batches...)
+		}
+		return byProduct
+	}
+
+	for _, movement := range movements {
+		if movement.Type == dto.MovementSale && movement.Delta < 0 {
+			byProduct[movement.ProductId] = append(byProduct[movement.ProductId], dto.BatchAllocation{
+				BatchID:      movement.BatchId,
+				Quantity:     -movement.Delta,
+				CostPrice:    movement.CostPrice,
+				SellingPrice: movement.SellingPrice,
+				ExpiryDate:   movement.ExpiryDate,
+			})
+		}
+	}
+	return byProduct
+}
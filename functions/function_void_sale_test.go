@@ -0,0 +1,123 @@
+package functions
+
+import (
+	"employee-crud/dto"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApplySaleVoidRestoresTheSameBatches(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	soon := now.AddDate(0, 0, 5)
+	later := now.AddDate(0, 2, 0)
+
+	product := &dto.Product{
+		ProductId: "PRD-001",
+		Batches: []dto.Batch{
+			{BatchId: "BATCH-SOON", StockQty: 3, ExpiryDate: &soon, CostPrice: dto.MoneyFromFloat(60), SellingPrice: dto.MoneyFromFloat(100)},
+			{BatchId: "BATCH-LATER", StockQty: 10, ExpiryDate: &later, CostPrice: dto.MoneyFromFloat(70), SellingPrice: dto.MoneyFromFloat(110)},
+		},
+	}
+	product.StockQty = TotalBatchStock(product.Batches)
+
+	// Selling 5 empties (and removes) BATCH-SOON and takes 2 from BATCH-LATER
+	allocations, err := ApplySaleDeduction(product, "", 5, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(allocations) != 2 || allocations[0].BatchID != "BATCH-SOON" || allocations[0].Quantity != 3 ||
+		allocations[1].BatchID != "BATCH-LATER" || allocations[1].Quantity != 2 || allocations[1].CostPrice != dto.MoneyFromFloat(70) {
+		t.Fatalf("expected 3 from BATCH-SOON then 2 from BATCH-LATER, got %+v", allocations)
+	}
+
+	newBatchId := func() (string, error) {
+		t.Fatal("no new batch should be created")
+		return "", nil
+	}
+	if err := ApplySaleVoid(product, 5, allocations, "", newBatchId, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if product.StockQty != 13 || len(product.Batches) != 2 {
+		t.Fatalf("expected both batches back with 13 units, got %d in %+v", product.StockQty, product.Batches)
+	}
+	restored := findBatch(product, "BATCH-SOON")
+	if restored == nil || restored.StockQty != 3 || restored.CostPrice != dto.MoneyFromFloat(60) || !DatesMatch(restored.ExpiryDate, &soon) {
+		t.Fatalf("expected BATCH-SOON recreated with 3 units at its cost and expiry, got %+v", restored)
+	}
+	if batch := findBatch(product, "BATCH-LATER"); batch.StockQty != 10 {
+		t.Fatalf("expected BATCH-LATER back at 10, got %d", batch.StockQty)
+	}
+}
+
+func TestSaleAllocationsFallBackToTheLedger(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	product := &dto.Product{ProductId: "PRD-001", Batches: []dto.Batch{{BatchId: "BATCH-001", StockQty: 4, CostPrice: dto.MoneyFromFloat(50)}}, StockQty: 4}
+
+	before := *product
+	before.Batches = append([]dto.Batch(nil), product.Batches...)
+	if _, err := ApplySaleDeduction(product, "", 3, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ref := dto.StockMovementRef{Type: dto.MovementSale, ReferenceType: dto.ReferenceSale, ReferenceId: "SALE-1"}
+	movements := BuildStockMovements(&before, product, ref, now)
+
+	// A sale recorded before its lines carried allocations
+	sale := &dto.Sale{SaleID: "SALE-1", Items: []dto.SaleItem{{ProductID: "PRD-001", Quantity: 3}}}
+	allocations := SaleAllocationsByProduct(sale, movements)["PRD-001"]
+	if len(allocations) != 1 || allocations[0].BatchID != "BATCH-001" || allocations[0].Quantity != 3 || allocations[0].CostPrice != dto.MoneyFromFloat(50) {
+		t.Fatalf("expected 3 units from BATCH-001 taken from the ledger, got %+v", allocations)
+	}
+}
+
+func TestApplySaleVoidWithoutLedger(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	product := &dto.Product{ProductId: "PRD-001", Batches: []dto.Batch{{BatchId: "BATCH-001", StockQty: 4}}, StockQty: 4}
+
+	// Sales recorded before the ledger are restocked like a return
+	if err := ApplySaleVoid(product, 2, nil, "", nil, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.StockQty != 6 || product.Batches[0].StockQty != 6 {
+		t.Fatalf("expected 6 units in BATCH-001, got %+v", product.Batches)
+	}
+}
+
+func TestCheckSaleVoidable(t *testing.T) {
+	sale := &dto.Sale{SaleID: "SALE-1", Items: []dto.SaleItem{{ProductID: "PRD-001", Quantity: 2}}}
+	if err := CheckSaleVoidable(sale); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sale.Items[0].ReturnedQty = 1
+	if err := CheckSaleVoidable(sale); !errors.Is(err, ErrInvalidVoid) {
+		t.Errorf("a sale with returns: expected ErrInvalidVoid, got %v", err)
+	}
+
+	sale.Items[0].ReturnedQty = 0
+	sale.Voided = true
+	if err := CheckSaleVoidable(sale); !errors.Is(err, ErrInvalidVoid) {
+		t.Errorf("a voided sale: expected ErrInvalidVoid, got %v", err)
+	}
+}
+
+func TestSummarizeSalesListsVoidedSalesApart(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	voidedAt := day.Add(3 * time.Hour)
+	sales := []dto.Sale{
+		{SaleID: "SALE-1", Total: dto.MoneyFromFloat(200), PaymentMethod: dto.TenderCash, CreatedAt: day.Add(time.Hour),
+			Items: []dto.SaleItem{{ProductID: "PRD-001", Quantity: 2, TotalPrice: dto.MoneyFromFloat(200)}}},
+		{SaleID: "SALE-2", Total: dto.MoneyFromFloat(500), PaymentMethod: dto.TenderCash, CreatedAt: day.Add(2 * time.Hour),
+			Items:  []dto.SaleItem{{ProductID: "PRD-002", Quantity: 5, TotalPrice: dto.MoneyFromFloat(500)}},
+			Voided: true, VoidReason: "Rung up twice", VoidedAt: &voidedAt},
+	}
+
+	summary := SummarizeSales(day, sales)
+	if summary.TotalSales != 1 || summary.TotalRevenue != dto.MoneyFromFloat(200) || len(summary.ProductsSold) != 1 {
+		t.Fatalf("expected only SALE-1 counted, got %d sales, %v revenue, %+v", summary.TotalSales, summary.TotalRevenue, summary.ProductsSold)
+	}
+	if len(summary.VoidedSales) != 1 || summary.VoidedSales[0].SaleID != "SALE-2" || summary.VoidedSales[0].Reason != "Rung up twice" {
+		t.Fatalf("expected SALE-2 listed as voided, got %+v", summary.VoidedSales)
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"employee-crud/api"
 	"employee-crud/apiHandlers"
+	"employee-crud/config"
 	"employee-crud/dao"
 	"employee-crud/dbConfigs"
 	"employee-crud/utils"
-	"os"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
@@ -15,6 +18,12 @@ import (
 )
 
 func main() {
+	// Load settings from CONFIG_FILE and the environment; stop with the list of missing keys if invalid
+	cfg, err := config.Init()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	app := fiber.New()
 
 	// GZIP Compression Middleware - Compresses responses for better performance
@@ -29,40 +38,111 @@ func main() {
 		TimeZone:   "Local",
 	}))
 
-	// Configure CORS to allow your specific domains
+	// Configure CORS to allow the configured frontend origins
 	app.Use(cors.New(cors.Config{
-		AllowOrigins:     "http://localhost:3000,http://localhost:8080,https://pos-frontend-tan.vercel.app",
+		AllowOrigins:     strings.Join(cfg.Server.CORSOrigins, ","),
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS,HEAD,PATCH",
 		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Requested-With,Access-Control-Request-Method,Access-Control-Request-Headers",
 		ExposeHeaders:    "Content-Length,Access-Control-Allow-Origin,Access-Control-Allow-Headers,Cache-Control,Content-Language,Content-Type",
 		AllowCredentials: true,
 	}))
 
-	dbConfigs.ConnectMongoDB()
+	dbConfigs.ConnectMongoDB(cfg.Mongo.URI, cfg.Mongo.Database)
 	dao.InitReturnsCollection(dbConfigs.DATABASE)
 
-	// Setup TTL index for Sales collection (auto-delete after 24 hours)
-	if err := dbConfigs.SetupSalesTTL(); err != nil {
+	// Setup TTL indexes for Sales and SalesArchive (only when a retention period is configured)
+	if err := dbConfigs.SetupSalesTTL(cfg.Sales.RetentionDays); err != nil {
 		log.Fatal("Failed to setup Sales TTL index:", err)
 	}
 
+	// Setup indexes for the compressed SalesArchive collection
+	if err := dbConfigs.SetupSalesArchiveIndexes(); err != nil {
+		log.Fatal("Failed to setup SalesArchive indexes:", err)
+	}
+
 	// Setup TTL index for DailyReports collection (auto-delete at end of month)
 	if err := dbConfigs.SetupDailyReportsTTL(); err != nil {
 		log.Fatal("Failed to setup DailyReports TTL index:", err)
 	}
 
+	// Setup unique index for the permanent monthly and yearly ReportRollups
+	if err := dbConfigs.SetupReportRollupIndexes(); err != nil {
+		log.Fatal("Failed to setup ReportRollups indexes:", err)
+	}
+
+	// Setup indexes for the StockMovements ledger
+	if err := dbConfigs.SetupStockMovementsIndexes(); err != nil {
+		log.Fatal("Failed to setup StockMovements indexes:", err)
+	}
+
+	// Setup indexes for the Customers registry and customer purchase history
+	if err := dbConfigs.SetupCustomersIndexes(); err != nil {
+		log.Fatal("Failed to setup Customers indexes:", err)
+	}
+
+	// Setup indexes for the Promotions evaluated at checkout
+	if err := dbConfigs.SetupPromotionsIndexes(); err != nil {
+		log.Fatal("Failed to setup Promotions indexes:", err)
+	}
+
+	// Setup indexes for the draft Carts parked at terminals
+	if err := dbConfigs.SetupCartsIndexes(); err != nil {
+		log.Fatal("Failed to setup Carts indexes:", err)
+	}
+
+	// Setup indexes for PurchaseOrders and the GRNs received against them
+	if err := dbConfigs.SetupPurchaseOrdersIndexes(); err != nil {
+		log.Fatal("Failed to setup PurchaseOrders indexes:", err)
+	}
+
+	// Setup indexes for the StockTakes counted against frozen batch quantities
+	if err := dbConfigs.SetupStockTakesIndexes(); err != nil {
+		log.Fatal("Failed to setup StockTakes indexes:", err)
+	}
+
+	// Setup indexes for the Locations and the stock, sales and reports kept per location
+	if err := dbConfigs.SetupLocationsIndexes(); err != nil {
+		log.Fatal("Failed to setup Locations indexes:", err)
+	}
+
+	// Setup indexes for TaxClasses and the products and categories assigned to them
+	if err := dbConfigs.SetupTaxClassesIndexes(); err != nil {
+		log.Fatal("Failed to setup TaxClasses indexes:", err)
+	}
+
+	// Setup unique indexes for the Users collection
+	if err := dbConfigs.SetupUsersIndexes(); err != nil {
+		log.Fatal("Failed to setup Users indexes:", err)
+	}
+
+	// Token signing secret and lifetime for authentication
+	api.InitAuth(cfg.Auth.TokenSecret, time.Duration(cfg.Auth.TokenTTLHours)*time.Hour)
+
+	// Everything recorded without a location belongs to the main location
+	mainLocationName := cfg.Receipt.StoreName
+	if mainLocationName == "" {
+		mainLocationName = "Main store"
+	}
+	if err := dao.DB_EnsureMainLocation(mainLocationName); err != nil {
+		log.Fatal("Failed to create main location:", err)
+	}
+
+	// Create the first admin account on a fresh database
+	if err := api.EnsureInitialAdmin(cfg.Auth.AdminUsername, cfg.Auth.AdminPassword); err != nil {
+		log.Fatal("Failed to create initial admin user:", err)
+	}
+
 	// Start background scheduler for automatic daily report saving
 	utils.StartDailyReportScheduler()
 
 	// Check and save any missing reports from the past 7 days
 	go utils.SaveMissingReports()
 
+	// Move old sales into SalesArchive every night
+	utils.StartSalesArchiveScheduler(cfg.Sales.ArchiveAfterDays)
+
 	apiHandlers.SetupRoutes(app)
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
-	}
-	log.Fatal(app.Listen("0.0.0.0:" + port))
+	log.Fatal(app.Listen("0.0.0.0:" + cfg.Server.Port))
 
 }
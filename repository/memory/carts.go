@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"sort"
+	"time"
+)
+
+type carts struct{ s *Store }
+
+func cloneCart(c dto.Cart) dto.Cart {
+	c.Items = append([]dto.CartItem(nil), c.Items...)
+	return c
+}
+
+// findCart returns the stored cart (not a copy); the caller holds the lock
+func (s *Store) findCart(cartId string) *dto.Cart {
+	for i := range s.data.carts {
+		if s.data.carts[i].CartID == cartId {
+			return &s.data.carts[i]
+		}
+	}
+	return nil
+}
+
+func (r carts) Create(cart *dto.Cart) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.data.carts = append(r.s.data.carts, cloneCart(*cart))
+	return nil
+}
+
+func (r carts) FindById(cartId string) (*dto.Cart, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findCart(cartId)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	cart := cloneCart(*stored)
+	return &cart, nil
+}
+
+func (r carts) FindByTerminal(terminalId string, status string) ([]dto.Cart, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := []dto.Cart{}
+	for i := range r.s.data.carts {
+		cart := &r.s.data.carts[i]
+		if cart.Status == status && (terminalId == "" || cart.TerminalID == terminalId) {
+			list = append(list, cloneCart(*cart))
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].UpdatedAt.After(list[j].UpdatedAt)
+	})
+	return list, nil
+}
+
+func (r carts) Update(cart *dto.Cart, fromStatuses ...string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.replaceCart(cart, fromStatuses...)
+}
+
+// replaceCart is carts.Update for a caller that already holds the lock
+func (s *Store) replaceCart(cart *dto.Cart, fromStatuses ...string) error {
+	stored := s.findCart(cart.CartID)
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	for _, status := range fromStatuses {
+		if stored.Status == status {
+			*stored = cloneCart(*cart)
+			return nil
+		}
+	}
+	return repository.ErrCartStatusChanged
+}
+
+func (r carts) ReservedQuantities(locationId string, excludeCartId string, now time.Time) (map[string]int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	locationId = functions.NormalizeLocationId(locationId)
+	reserved := map[string]int{}
+	for i := range r.s.data.carts {
+		cart := &r.s.data.carts[i]
+		if cart.CartID == excludeCartId || !functions.CartHoldsReservation(cart, now) ||
+			functions.NormalizeLocationId(cart.LocationId) != locationId {
+			continue
+		}
+		for _, item := range cart.Items {
+			reserved[item.ProductID] += item.Quantity
+		}
+	}
+	return reserved, nil
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type customers struct{ s *Store }
+
+// findCustomer returns the stored active customer (not a copy); the caller holds the lock
+func (s *Store) findCustomer(match func(c *dto.Customer) bool) *dto.Customer {
+	for i := range s.data.customers {
+		if !s.data.customers[i].Deleted && match(&s.data.customers[i]) {
+			return &s.data.customers[i]
+		}
+	}
+	return nil
+}
+
+// mobileTaken reports whether an active customer other than customerId has the mobile number; the caller holds the lock
+func (s *Store) mobileTaken(mobileNumber string, customerId string) bool {
+	return s.findCustomer(func(c *dto.Customer) bool {
+		return c.MobileNumber == mobileNumber && c.CustomerID != customerId
+	}) != nil
+}
+
+// applyCustomerSale mirrors the dao: points earned minus redeemed, spend and sale count; the caller holds the lock
+func (s *Store) applyCustomerSale(sale *dto.Sale) error {
+	if sale.CustomerID == "" {
+		return nil
+	}
+
+	customer := s.findCustomer(func(c *dto.Customer) bool { return c.CustomerID == sale.CustomerID })
+	if customer == nil {
+		return repository.ErrNotFound
+	}
+	if customer.LoyaltyPoints < sale.PointsRedeemed {
+		return fmt.Errorf("%w: customer %s", functions.ErrInsufficientPoints, sale.CustomerID)
+	}
+
+	purchasedAt := sale.CreatedAt
+	customer.LoyaltyPoints += sale.PointsEarned - sale.PointsRedeemed
+	customer.LifetimeSpend += sale.Total
+	customer.SaleCount++
+	customer.LastPurchaseAt = &purchasedAt
+	customer.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// reverseCustomerSale mirrors the dao: points, spend and sale count are taken back, the balance stops at 0;
+// the caller holds the lock
+func (s *Store) reverseCustomerSale(sale *dto.Sale) {
+	if sale.CustomerID == "" {
+		return
+	}
+
+	customer := s.findCustomer(func(c *dto.Customer) bool { return c.CustomerID == sale.CustomerID })
+	if customer == nil {
+		return
+	}
+	customer.LoyaltyPoints += sale.PointsRedeemed - sale.PointsEarned
+	if customer.LoyaltyPoints < 0 {
+		customer.LoyaltyPoints = 0
+	}
+	customer.LifetimeSpend -= sale.Total
+	if customer.SaleCount > 0 {
+		customer.SaleCount--
+	}
+	customer.UpdatedAt = time.Now().UTC()
+}
+
+// returnCustomerSale mirrors the dao: a return's share of spend and points is taken back, the balance and sale
+// count stop at 0; the caller holds the lock
+func (s *Store) returnCustomerSale(customerId string, reversal functions.ReturnReversal) {
+	if customerId == "" {
+		return
+	}
+
+	customer := s.findCustomer(func(c *dto.Customer) bool { return c.CustomerID == customerId })
+	if customer == nil {
+		return
+	}
+	customer.LoyaltyPoints += reversal.PointsRedeemed - reversal.PointsEarned
+	if customer.LoyaltyPoints < 0 {
+		customer.LoyaltyPoints = 0
+	}
+	customer.LifetimeSpend -= reversal.Spend
+	customer.SaleCount -= reversal.SaleCount
+	if customer.SaleCount < 0 {
+		customer.SaleCount = 0
+	}
+	customer.UpdatedAt = time.Now().UTC()
+}
+
+func (r customers) Create(customer *dto.Customer) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.s.mobileTaken(customer.MobileNumber, customer.CustomerID) {
+		return repository.ErrMobileTaken
+	}
+	r.s.data.customers = append(r.s.data.customers, *customer)
+	return nil
+}
+
+func (r customers) FindById(customerId string) (*dto.Customer, error) {
+	return r.find(func(c *dto.Customer) bool { return c.CustomerID == customerId })
+}
+
+func (r customers) FindByMobile(mobileNumber string) (*dto.Customer, error) {
+	return r.find(func(c *dto.Customer) bool { return c.MobileNumber == mobileNumber })
+}
+
+func (r customers) find(match func(c *dto.Customer) bool) (*dto.Customer, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findCustomer(match)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	customer := *stored
+	return &customer, nil
+}
+
+func (r customers) FindAll(search string) ([]dto.Customer, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	search = strings.ToLower(search)
+	list := []dto.Customer{}
+	for _, customer := range r.s.data.customers {
+		if customer.Deleted {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(customer.Name), search) && !strings.Contains(customer.MobileNumber, search) {
+			continue
+		}
+		list = append(list, customer)
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (r customers) Update(customer *dto.Customer) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findCustomer(func(c *dto.Customer) bool { return c.CustomerID == customer.CustomerID })
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	if r.s.mobileTaken(customer.MobileNumber, customer.CustomerID) {
+		return repository.ErrMobileTaken
+	}
+	stored.Name = customer.Name
+	stored.MobileNumber = customer.MobileNumber
+	stored.Email = customer.Email
+	stored.UpdatedAt = customer.UpdatedAt
+	return nil
+}
+
+func (r customers) Delete(customerId string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findCustomer(func(c *dto.Customer) bool { return c.CustomerID == customerId })
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	stored.Deleted = true
+	stored.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+func (r customers) FindSales(customerId string, page int, limit int) ([]dto.Sale, int64, error) {
+	if page < 1 || limit < 1 {
+		return nil, 0, fmt.Errorf("page and limit must be positive")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	matching := []dto.Sale{}
+	for _, sale := range r.s.data.sales {
+		if sale.CustomerID == customerId {
+			matching = append(matching, cloneSale(sale))
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].CreatedAt.After(matching[j].CreatedAt)
+	})
+
+	total := int64(len(matching))
+	start := (page - 1) * limit
+	if start > len(matching) {
+		start = len(matching)
+	}
+	end := start + limit
+	if end > len(matching) {
+		end = len(matching)
+	}
+	return matching[start:end], total, nil
+}
@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type grns struct{ s *Store }
+
+// findGRN returns the stored, non-deleted GRN (not a copy); the caller holds the lock
+func (s *Store) findGRN(grnId string) *dto.GRN {
+	for i := range s.data.grns {
+		if s.data.grns[i].GRNId == grnId && !s.data.grns[i].Deleted {
+			return &s.data.grns[i]
+		}
+	}
+	return nil
+}
+
+func (r grns) Create(grn *dto.GRN, userId string) error {
+	return r.s.atomically(func() error {
+		created := cloneGRN(*grn)
+		if created.Status != "pending" {
+			if err := r.post(&created, created.CreatedAt, userId); err != nil {
+				return err
+			}
+		}
+
+		r.s.data.grns = append(r.s.data.grns, cloneGRN(created))
+		if created.PurchaseOrderId != "" {
+			if err := r.s.receivePurchaseOrder(created.PurchaseOrderId, created.CreatedAt); err != nil {
+				return err
+			}
+		}
+		*grn = created
+		return nil
+	})
+}
+
+// UpdateStatus mirrors the Mongo implementation: received lines are posted once into batches,
+// and a GRN with posted lines cannot go back to pending
+func (r grns) UpdateStatus(grnId string, status string, updatedAt time.Time, userId string) (*dto.GRN, error) {
+	var result dto.GRN
+	err := r.s.atomically(func() error {
+		grn := r.s.findGRN(grnId)
+		if grn == nil {
+			return repository.ErrNotFound
+		}
+
+		if status == "pending" {
+			for _, item := range grn.Items {
+				if item.PostedBatchId != "" {
+					return fmt.Errorf("%w: %s", repository.ErrGRNAlreadyPosted, grnId)
+				}
+			}
+		} else if err := r.post(grn, updatedAt, userId); err != nil {
+			return err
+		}
+
+		grn.Status = status
+		grn.UpdatedAt = updatedAt
+		result = cloneGRN(*grn)
+
+		if grn.PurchaseOrderId != "" {
+			return r.s.receivePurchaseOrder(grn.PurchaseOrderId, updatedAt)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// post mirrors dao.postGRN: the GRN is checked against its purchase order, then every received line
+// that has not been posted yet goes into the product's batches; the caller holds the lock
+func (r grns) post(grn *dto.GRN, postedAt time.Time, userId string) error {
+	if grn.PurchaseOrderId != "" {
+		po := r.s.findPurchaseOrder(grn.PurchaseOrderId)
+		if po == nil {
+			return repository.ErrNotFound
+		}
+		if err := functions.CheckGRNPosting(grn, po); err != nil {
+			return err
+		}
+	}
+
+	ref := dto.StockMovementRef{
+		Type:          dto.MovementGRNReceipt,
+		ReferenceType: dto.ReferenceGRN,
+		ReferenceId:   grn.GRNId,
+		UserId:        userId,
+	}
+	if ref.UserId == "" {
+		ref.UserId = grn.ReceivedBy
+	}
+	for i := range grn.Items {
+		if err := r.postItem(&grn.Items[i], grn.LocationId, postedAt, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postItem receives one GRN line into the product's batches at the GRN's location; the caller holds the lock
+func (r grns) postItem(item *dto.GRNItem, locationId string, postedAt time.Time, ref dto.StockMovementRef) error {
+	if item.PostedBatchId != "" || item.ReceivedQty <= 0 {
+		return nil
+	}
+
+	var batchId string
+	_, err := r.s.updateBatches(item.ProductId, ref, func(product *dto.Product) error {
+		id, err := functions.ApplyGRNItem(product, item, locationId, r.s.batchIdGenerator(), time.Now().UTC())
+		batchId = id
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to post GRN item %s: %w", item.ProductId, err)
+	}
+
+	item.PostedBatchId = batchId
+	item.PostedQty = item.ReceivedQty
+	item.PostedAt = &postedAt
+	return nil
+}
+
+func (r grns) Exists(grnId string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.findGRN(grnId) != nil, nil
+}
+
+func (r grns) FindById(grnId string) (*dto.GRN, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findGRN(grnId)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	grn := cloneGRN(*stored)
+	return &grn, nil
+}
+
+func (r grns) FindAllPaginated(page int, limit int) ([]dto.GRN, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var active []dto.GRN
+	for _, grn := range r.s.data.grns {
+		if !grn.Deleted {
+			active = append(active, cloneGRN(grn))
+		}
+	}
+
+	start := (page - 1) * limit
+	if start < 0 || start > len(active) {
+		start = len(active)
+	}
+	end := start + limit
+	if limit <= 0 || end > len(active) {
+		end = len(active)
+	}
+	return active[start:end], int64(len(active)), nil
+}
+
+func (r grns) FindByPurchaseOrder(poId string) ([]dto.GRN, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := []dto.GRN{}
+	for _, grn := range r.s.data.grns {
+		if grn.PurchaseOrderId == poId && !grn.Deleted {
+			list = append(list, cloneGRN(grn))
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list, nil
+}
+
+func (r grns) CountTotal() (int64, error) {
+	return r.count(func(grn *dto.GRN) bool { return true })
+}
+
+func (r grns) CountByStatus(status string) (int64, error) {
+	return r.count(func(grn *dto.GRN) bool { return grn.Status == status })
+}
+
+func (r grns) count(keep func(grn *dto.GRN) bool) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var count int64
+	for i := range r.s.data.grns {
+		if !r.s.data.grns[i].Deleted && keep(&r.s.data.grns[i]) {
+			count++
+		}
+	}
+	return count, nil
+}
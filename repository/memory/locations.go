@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/repository"
+	"sort"
+)
+
+type locations struct{ s *Store }
+
+// findLocation returns the stored location (not a copy); the caller holds the lock
+func (s *Store) findLocation(locationId string) *dto.Location {
+	for i := range s.data.locations {
+		if s.data.locations[i].LocationId == locationId {
+			return &s.data.locations[i]
+		}
+	}
+	return nil
+}
+
+func (r locations) Create(location *dto.Location) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.data.locations = append(r.s.data.locations, *location)
+	return nil
+}
+
+func (r locations) FindById(locationId string) (*dto.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findLocation(locationId)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	location := *stored
+	return &location, nil
+}
+
+func (r locations) FindAll(status string) ([]dto.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := []dto.Location{}
+	for _, location := range r.s.data.locations {
+		if status != "" && location.Status != status {
+			continue
+		}
+		list = append(list, location)
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (r locations) Update(location *dto.Location) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findLocation(location.LocationId)
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	stored.Name = location.Name
+	stored.Type = location.Type
+	stored.Address = location.Address
+	stored.Phone = location.Phone
+	stored.Status = location.Status
+	stored.UpdatedAt = location.UpdatedAt
+	return nil
+}
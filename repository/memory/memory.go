@@ -0,0 +1,261 @@
+// Package memory implements the repository interfaces on in-process maps and slices
+// It is meant for handler tests: no database is needed and every operation is atomic under one lock
+package memory
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/repository"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Store holds every collection of the in-memory repositories
+type Store struct {
+	mu   sync.Mutex
+	data data
+}
+
+type data struct {
+	counters         map[string]int
+	products         []dto.Product
+	sales            []dto.Sale
+	grns             []dto.GRN
+	suppliers        []dto.Supplier
+	supplierProducts []dto.SupplierProduct
+	returns          []dto.ReturnDTO
+	customers        []dto.Customer
+	promotions       []dto.Promotion
+	taxClasses       []dto.TaxClass
+	carts            []dto.Cart
+	purchaseOrders   []dto.PurchaseOrder
+	stockTakes       []dto.StockTake
+	locations        []dto.Location
+	users            []dto.User
+	categoryTaxes    map[string]string              // categoryId -> taxClassId
+	categoryStock    map[string]dto.StockThresholds // categoryId -> stock thresholds
+	writeOffs        []dto.StockWriteOff
+	movements        []dto.StockMovement
+	dailyReports     []dto.DailyReportDocument
+	rollups          []dto.ReportRollup
+}
+
+// NewStore returns a store that is empty apart from the main location
+func NewStore() *Store {
+	return &Store{data: data{
+		counters:      map[string]int{},
+		categoryTaxes: map[string]string{},
+		categoryStock: map[string]dto.StockThresholds{},
+		locations:     []dto.Location{{LocationId: dto.MainLocationId, Name: "Main store", Type: dto.LocationStore, Status: "active"}},
+	}}
+}
+
+// NewRepositories returns repositories backed by a new empty store
+func NewRepositories() Repositories {
+	return NewStore().Repositories()
+}
+
+// Repositories is repository.Repositories plus direct access to the underlying store
+type Repositories struct {
+	repository.Repositories
+	Store *Store
+}
+
+// Repositories returns the repository set backed by this store
+func (s *Store) Repositories() Repositories {
+	return Repositories{
+		Repositories: repository.Repositories{
+			Products:       products{s},
+			Stocks:         stocks{s},
+			Sales:          sales{s},
+			GRNs:           grns{s},
+			Suppliers:      suppliers{s},
+			Reports:        reports{s},
+			Returns:        returns{s},
+			Customers:      customers{s},
+			Promotions:     promotions{s},
+			Taxes:          taxes{s},
+			Carts:          carts{s},
+			PurchaseOrders: purchaseOrders{s},
+			StockTakes:     stockTakes{s},
+			Locations:      locations{s},
+			Users:          users{s},
+			Ids:            ids{s},
+		},
+		Store: s,
+	}
+}
+
+// Movements returns a copy of the stock movement ledger in insertion order
+func (s *Store) Movements() []dto.StockMovement {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]dto.StockMovement(nil), s.data.movements...)
+}
+
+// WriteOffs returns a copy of the recorded stock write-offs
+func (s *Store) WriteOffs() []dto.StockWriteOff {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]dto.StockWriteOff(nil), s.data.writeOffs...)
+}
+
+// SaveDailyReport stores a saved daily report so the report endpoints can read it
+func (s *Store) SaveDailyReport(report dto.DailyReportDocument) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.dailyReports = append(s.data.dailyReports, report)
+}
+
+// atomically runs fn under the lock and rolls every collection back if it fails,
+// the in-memory counterpart of a MongoDB transaction
+func (s *Store) atomically(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := s.data.clone()
+	if err := fn(); err != nil {
+		s.data = saved
+		return err
+	}
+	return nil
+}
+
+// nextId issues ids in the same PREFIX-001 format as dao.GenerateId; the caller holds the lock
+func (s *Store) nextId(collectionName string, prefix string) string {
+	s.data.counters[collectionName]++
+	return fmt.Sprintf("%s-%03d", prefix, s.data.counters[collectionName])
+}
+
+type ids struct{ s *Store }
+
+func (r ids) NextId(ctx context.Context, collectionName string, prefix string) (string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.nextId(collectionName, prefix), nil
+}
+
+func (d data) clone() data {
+	c := data{
+		counters:         make(map[string]int, len(d.counters)),
+		products:         make([]dto.Product, len(d.products)),
+		sales:            make([]dto.Sale, len(d.sales)),
+		grns:             make([]dto.GRN, len(d.grns)),
+		suppliers:        append([]dto.Supplier(nil), d.suppliers...),
+		supplierProducts: append([]dto.SupplierProduct(nil), d.supplierProducts...),
+		returns:          make([]dto.ReturnDTO, len(d.returns)),
+		customers:        append([]dto.Customer(nil), d.customers...),
+		promotions:       make([]dto.Promotion, len(d.promotions)),
+		taxClasses:       append([]dto.TaxClass(nil), d.taxClasses...),
+		carts:            make([]dto.Cart, len(d.carts)),
+		purchaseOrders:   make([]dto.PurchaseOrder, len(d.purchaseOrders)),
+		stockTakes:       make([]dto.StockTake, len(d.stockTakes)),
+		locations:        append([]dto.Location(nil), d.locations...),
+		users:            append([]dto.User(nil), d.users...),
+		categoryTaxes:    make(map[string]string, len(d.categoryTaxes)),
+		categoryStock:    make(map[string]dto.StockThresholds, len(d.categoryStock)),
+		writeOffs:        append([]dto.StockWriteOff(nil), d.writeOffs...),
+		movements:        append([]dto.StockMovement(nil), d.movements...),
+		dailyReports:     append([]dto.DailyReportDocument(nil), d.dailyReports...),
+		rollups:          append([]dto.ReportRollup(nil), d.rollups...),
+	}
+	for k, v := range d.counters {
+		c.counters[k] = v
+	}
+	for k, v := range d.categoryTaxes {
+		c.categoryTaxes[k] = v
+	}
+	for k, v := range d.categoryStock {
+		c.categoryStock[k] = v
+	}
+	for i := range d.products {
+		c.products[i] = cloneProduct(d.products[i])
+	}
+	for i := range d.promotions {
+		c.promotions[i] = clonePromotion(d.promotions[i])
+	}
+	for i := range d.carts {
+		c.carts[i] = cloneCart(d.carts[i])
+	}
+	for i := range d.purchaseOrders {
+		c.purchaseOrders[i] = clonePurchaseOrder(d.purchaseOrders[i])
+	}
+	for i := range d.stockTakes {
+		c.stockTakes[i] = cloneStockTake(d.stockTakes[i])
+	}
+	for i := range d.sales {
+		c.sales[i] = cloneSale(d.sales[i])
+	}
+	for i := range d.grns {
+		c.grns[i] = cloneGRN(d.grns[i])
+	}
+	for i := range d.returns {
+		c.returns[i] = cloneReturn(d.returns[i])
+	}
+	return c
+}
+
+func cloneProduct(p dto.Product) dto.Product {
+	p.Batches = append([]dto.Batch(nil), p.Batches...)
+	p.StockThresholds = cloneStockThresholds(p.StockThresholds)
+	return p
+}
+
+func cloneStockThresholds(t *dto.StockThresholds) *dto.StockThresholds {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func cloneSale(s dto.Sale) dto.Sale {
+	s.Items = append([]dto.SaleItem(nil), s.Items...)
+	for i := range s.Items {
+		s.Items[i].PromotionIDs = append([]string(nil), s.Items[i].PromotionIDs...)
+		s.Items[i].Batches = append([]dto.BatchAllocation(nil), s.Items[i].Batches...)
+	}
+	s.Tenders = append([]dto.Tender(nil), s.Tenders...)
+	return s
+}
+
+func cloneGRN(g dto.GRN) dto.GRN {
+	g.Items = append([]dto.GRNItem(nil), g.Items...)
+	return g
+}
+
+func cloneReturn(r dto.ReturnDTO) dto.ReturnDTO {
+	r.Products = append([]dto.ReturnProduct(nil), r.Products...)
+	return r
+}
+
+// page returns the window of n items starting at the offset encoded in cursor
+// Cursors are plain offsets here; callers only ever pass back the nextCursor they were given
+func page(n int, limit int, cursor string) (start int, end int, nextCursor string, hasMore bool, err error) {
+	if cursor != "" {
+		start, err = strconv.Atoi(cursor)
+		if err != nil || start < 0 {
+			return 0, 0, "", false, fmt.Errorf("invalid cursor: %s", cursor)
+		}
+	}
+	if start > n {
+		start = n
+	}
+	end = n
+	if limit > 0 && start+limit < n {
+		end = start + limit
+	}
+	if end > start {
+		nextCursor = strconv.Itoa(end)
+	}
+	return start, end, nextCursor, end < n, nil
+}
+
+// sortNewestFirst orders products by created_at descending like the paginated product queries
+func sortNewestFirst(list []dto.Product) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+}
@@ -0,0 +1,357 @@
+package memory
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type products struct{ s *Store }
+
+// findProduct returns the stored product (not a copy); the caller holds the lock
+func (s *Store) findProduct(productId string, deleted bool) *dto.Product {
+	for i := range s.data.products {
+		if s.data.products[i].ProductId == productId && s.data.products[i].Deleted == deleted {
+			return &s.data.products[i]
+		}
+	}
+	return nil
+}
+
+// activeProducts returns copies of the non-deleted products matching keep; the caller holds the lock
+func (s *Store) activeProducts(keep func(p *dto.Product) bool) []dto.Product {
+	var list []dto.Product
+	for i := range s.data.products {
+		p := &s.data.products[i]
+		if p.Deleted || (keep != nil && !keep(p)) {
+			continue
+		}
+		list = append(list, cloneProduct(*p))
+	}
+	return list
+}
+
+// updateBatches is the in-memory counterpart of the dao's versioned batch update:
+// mutate works on a copy, and only a successful mutation is stored, versioned and written to the ledger
+// The caller holds the lock
+func (s *Store) updateBatches(productId string, ref dto.StockMovementRef, mutate func(product *dto.Product) error) (*dto.Product, error) {
+	stored := s.findProduct(productId, false)
+	if stored == nil {
+		return nil, fmt.Errorf("product not found: %w", repository.ErrNotFound)
+	}
+
+	before := cloneProduct(*stored)
+	product := cloneProduct(*stored)
+	if err := mutate(&product); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	product.Version++
+	product.UpdatedAt = now
+	*stored = product
+
+	s.recordMovements(&before, &product, ref, now)
+
+	result := cloneProduct(product)
+	return &result, nil
+}
+
+// recordMovements appends the ledger entries for a product change; the caller holds the lock
+func (s *Store) recordMovements(before *dto.Product, after *dto.Product, ref dto.StockMovementRef, now time.Time) {
+	s.data.movements = append(s.data.movements, functions.BuildStockMovements(before, after, ref, now)...)
+}
+
+// batchIdGenerator returns a newBatchId func for the functions.Apply* mutations; the caller holds the lock
+func (s *Store) batchIdGenerator() func() (string, error) {
+	return func() (string, error) {
+		return s.nextId("Batches", "BATCH"), nil
+	}
+}
+
+func (r products) FindById(productId string) (*dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findProduct(productId, false)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	product := cloneProduct(*stored)
+	return &product, nil
+}
+
+func (r products) FindByAttributes(categoryId, brandId, subCategoryId string) (*dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := r.s.activeProducts(func(p *dto.Product) bool {
+		return p.CategoryID == categoryId && p.BrandID == brandId && p.SubCategoryID == subCategoryId
+	})
+	if len(list) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &list[0], nil
+}
+
+func (r products) FindAll() ([]dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.activeProducts(nil), nil
+}
+
+func (r products) FindAllCursorPaginated(limit int, cursor string) ([]dto.Product, string, bool, error) {
+	return r.findPaginated(limit, cursor, nil)
+}
+
+func (r products) FindByBarcodeCursorPaginated(barcode string, limit int, cursor string) ([]dto.Product, string, bool, error) {
+	return r.findPaginated(limit, cursor, func(p *dto.Product) bool { return p.Barcode == barcode })
+}
+
+func (r products) FindByBrandCursorPaginated(brandId string, limit int, cursor string) ([]dto.Product, string, bool, error) {
+	return r.findPaginated(limit, cursor, func(p *dto.Product) bool { return p.BrandID == brandId })
+}
+
+func (r products) FindByCategoryCursorPaginated(categoryId string, limit int, cursor string) ([]dto.Product, string, bool, error) {
+	return r.findPaginated(limit, cursor, func(p *dto.Product) bool { return p.CategoryID == categoryId })
+}
+
+func (r products) findPaginated(limit int, cursor string, keep func(p *dto.Product) bool) ([]dto.Product, string, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := r.s.activeProducts(keep)
+	sortNewestFirst(list)
+
+	start, end, nextCursor, hasMore, err := page(len(list), limit, cursor)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return list[start:end], nextCursor, hasMore, nil
+}
+
+func (r products) FindBySubCategory(subCategoryId string) ([]dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.activeProducts(func(p *dto.Product) bool { return p.SubCategoryID == subCategoryId }), nil
+}
+
+func (r products) FindAllDeleted() ([]dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var list []dto.Product
+	for _, p := range r.s.data.products {
+		if p.Deleted {
+			list = append(list, cloneProduct(p))
+		}
+	}
+	return list, nil
+}
+
+func (r products) CountTotal() (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.s.activeProducts(nil))), nil
+}
+
+func (r products) CountCategorized() (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.s.activeProducts(func(p *dto.Product) bool { return p.CategoryID != "" }))), nil
+}
+
+func (r products) CountByBrand(brandId string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.s.activeProducts(func(p *dto.Product) bool { return p.BrandID == brandId }))), nil
+}
+
+func (r products) CountByCategory(categoryId string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.s.activeProducts(func(p *dto.Product) bool { return p.CategoryID == categoryId }))), nil
+}
+
+func (r products) Create(product *dto.Product, ref dto.StockMovementRef) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.data.products = append(r.s.data.products, cloneProduct(*product))
+	r.s.recordMovements(nil, product, ref, time.Now().UTC())
+	return nil
+}
+
+func (r products) Update(ctx context.Context, product *dto.Product, settings dto.ProductSettingChanges) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findProduct(product.ProductId, false)
+	if stored == nil {
+		stored = r.s.findProduct(product.ProductId, true)
+	}
+	if stored == nil {
+		return fmt.Errorf("categoryId %s not found", product.ProductId)
+	}
+
+	stored.Name = product.Name
+	stored.Barcode = product.Barcode
+	stored.CategoryID = product.CategoryID
+	stored.BrandID = product.BrandID
+	stored.SubCategoryID = product.SubCategoryID
+	stored.CostPrice = product.CostPrice
+	stored.SellingPrice = product.SellingPrice
+	stored.ExpiryDate = product.ExpiryDate
+	stored.Deleted = product.Deleted
+	if settings.TaxClassID != nil {
+		stored.TaxClassID = *settings.TaxClassID
+	}
+	if settings.ReorderPoint != nil {
+		stored.ReorderPoint = *settings.ReorderPoint
+	}
+	if settings.ReorderQty != nil {
+		stored.ReorderQty = *settings.ReorderQty
+	}
+	if settings.StockThresholds != nil {
+		stored.StockThresholds = cloneStockThresholds(settings.StockThresholds)
+	}
+	if settings.ClearStockThresholds {
+		stored.StockThresholds = nil
+	}
+	stored.UpdatedAt = product.UpdatedAt
+	return nil
+}
+
+func (r products) Delete(productId string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findProduct(productId, false)
+	if stored == nil {
+		return errors.New("Specified Id not found or already deleted!")
+	}
+	stored.Deleted = true
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r products) DeletePermanent(productId string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i := range r.s.data.products {
+		if r.s.data.products[i].ProductId == productId {
+			r.s.data.products = append(r.s.data.products[:i], r.s.data.products[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("Specified productId not found")
+}
+
+func (r products) Restore(productId, categoryId, brandId, subCategoryId string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findProduct(productId, true)
+	if stored == nil {
+		return errors.New("Product not found or already active")
+	}
+	stored.Deleted = false
+	stored.CategoryID = categoryId
+	stored.BrandID = brandId
+	stored.SubCategoryID = subCategoryId
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r products) AddBatch(productId string, batch dto.Batch, ref dto.StockMovementRef) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	_, err := r.s.updateBatches(productId, ref, func(product *dto.Product) error {
+		product.Batches = append(product.Batches, batch)
+		product.StockQty += batch.StockQty
+		return nil
+	})
+	return err
+}
+
+func (r products) ConvertToBatches(product *dto.Product, initialBatch dto.Batch, ref dto.StockMovementRef) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findProduct(product.ProductId, false)
+	if stored == nil || stored.Version != product.Version {
+		return fmt.Errorf("%w: %s", repository.ErrProductVersionConflict, product.ProductId)
+	}
+
+	updated, err := r.s.updateBatches(product.ProductId, ref, func(p *dto.Product) error {
+		p.Batches = []dto.Batch{initialBatch}
+		p.StockQty = initialBatch.StockQty
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	product.Batches = updated.Batches
+	product.StockQty = updated.StockQty
+	product.Version = updated.Version
+	return nil
+}
+
+func (r products) AddStock(productId string, locationId string, stockQty int, expiryDate *time.Time, costPrice dto.Money, sellingPrice dto.Money, ref dto.StockMovementRef) (*dto.Product, string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var batchId string
+	product, err := r.s.updateBatches(productId, ref, func(product *dto.Product) error {
+		id, err := functions.ApplyAddStock(product, locationId, stockQty, expiryDate, costPrice, sellingPrice, r.s.batchIdGenerator(), time.Now().UTC())
+		batchId = id
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return product, batchId, nil
+}
+
+func (r products) EditBatchStock(productId string, batchId string, newStockQty int, ref dto.StockMovementRef) (*dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.updateBatches(productId, ref, func(product *dto.Product) error {
+		return functions.ApplyEditBatchStock(product, batchId, newStockQty, time.Now().UTC())
+	})
+}
+
+func (r products) EditBatchDetails(productId string, batchId string, expiryDate *time.Time, costPrice dto.Money, sellingPrice dto.Money, ref dto.StockMovementRef) (*dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.updateBatches(productId, ref, func(product *dto.Product) error {
+		return functions.ApplyEditBatchDetails(product, batchId, expiryDate, costPrice, sellingPrice, time.Now().UTC())
+	})
+}
+
+func (r products) RemoveStockFromBatch(productId string, batchId string, quantityToRemove int, ref dto.StockMovementRef) (*dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.updateBatches(productId, ref, func(product *dto.Product) error {
+		return functions.ApplyRemoveStockFromBatch(product, batchId, quantityToRemove, time.Now().UTC())
+	})
+}
+
+func (r products) DeleteBatch(productId string, batchId string, ref dto.StockMovementRef) (*dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.updateBatches(productId, ref, func(product *dto.Product) error {
+		return functions.ApplyDeleteBatch(product, batchId)
+	})
+}
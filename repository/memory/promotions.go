@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/repository"
+	"sort"
+	"time"
+)
+
+type promotions struct{ s *Store }
+
+func clonePromotion(p dto.Promotion) dto.Promotion {
+	p.TargetIDs = append([]string(nil), p.TargetIDs...)
+	p.DaysOfWeek = append([]int(nil), p.DaysOfWeek...)
+	return p
+}
+
+// findPromotion returns the stored promotion (not a copy) unless it was deleted; the caller holds the lock
+func (s *Store) findPromotion(promotionId string) *dto.Promotion {
+	for i := range s.data.promotions {
+		if !s.data.promotions[i].Deleted && s.data.promotions[i].PromotionID == promotionId {
+			return &s.data.promotions[i]
+		}
+	}
+	return nil
+}
+
+func (r promotions) Create(promotion *dto.Promotion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.data.promotions = append(r.s.data.promotions, clonePromotion(*promotion))
+	return nil
+}
+
+func (r promotions) FindById(promotionId string) (*dto.Promotion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findPromotion(promotionId)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	promotion := clonePromotion(*stored)
+	return &promotion, nil
+}
+
+func (r promotions) FindAll(activeOnly bool) ([]dto.Promotion, error) {
+	return r.find(func(p *dto.Promotion) bool { return !activeOnly || p.Active })
+}
+
+func (r promotions) FindRunning(now time.Time) ([]dto.Promotion, error) {
+	return r.find(func(p *dto.Promotion) bool {
+		return p.Active && (p.StartsAt == nil || !now.Before(*p.StartsAt)) && (p.EndsAt == nil || now.Before(*p.EndsAt))
+	})
+}
+
+// find returns copies of the promotions that are not deleted and match, highest priority first like the dao
+func (r promotions) find(match func(p *dto.Promotion) bool) ([]dto.Promotion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := []dto.Promotion{}
+	for i := range r.s.data.promotions {
+		if !r.s.data.promotions[i].Deleted && match(&r.s.data.promotions[i]) {
+			list = append(list, clonePromotion(r.s.data.promotions[i]))
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Priority != list[j].Priority {
+			return list[i].Priority > list[j].Priority
+		}
+		return list[i].PromotionID < list[j].PromotionID
+	})
+	return list, nil
+}
+
+func (r promotions) Update(promotion *dto.Promotion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findPromotion(promotion.PromotionID)
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	*stored = clonePromotion(*promotion)
+	return nil
+}
+
+func (r promotions) Delete(promotionId string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findPromotion(promotionId)
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	stored.Deleted = true
+	stored.Active = false
+	stored.UpdatedAt = time.Now().UTC()
+	return nil
+}
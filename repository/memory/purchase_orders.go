@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"sort"
+	"time"
+)
+
+type purchaseOrders struct{ s *Store }
+
+func clonePurchaseOrder(po dto.PurchaseOrder) dto.PurchaseOrder {
+	po.Items = append([]dto.PurchaseOrderItem(nil), po.Items...)
+	po.GRNIds = append([]string(nil), po.GRNIds...)
+	return po
+}
+
+// findPurchaseOrder returns the stored purchase order (not a copy); the caller holds the lock
+func (s *Store) findPurchaseOrder(poId string) *dto.PurchaseOrder {
+	for i := range s.data.purchaseOrders {
+		if s.data.purchaseOrders[i].POId == poId {
+			return &s.data.purchaseOrders[i]
+		}
+	}
+	return nil
+}
+
+// receivePurchaseOrder recounts a purchase order from its GRNs, as the Mongo implementation does
+// whenever one of them is created or posted; the caller holds the lock
+func (s *Store) receivePurchaseOrder(poId string, now time.Time) error {
+	po := s.findPurchaseOrder(poId)
+	if po == nil {
+		return repository.ErrNotFound
+	}
+	functions.ReceivePurchaseOrder(po, s.data.grns, now)
+	po.UpdatedAt = now
+	return nil
+}
+
+func (r purchaseOrders) Create(po *dto.PurchaseOrder) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.data.purchaseOrders = append(r.s.data.purchaseOrders, clonePurchaseOrder(*po))
+	return nil
+}
+
+func (r purchaseOrders) FindById(poId string) (*dto.PurchaseOrder, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findPurchaseOrder(poId)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	po := clonePurchaseOrder(*stored)
+	return &po, nil
+}
+
+func (r purchaseOrders) FindAll(supplierId string, status string) ([]dto.PurchaseOrder, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := []dto.PurchaseOrder{}
+	for i := range r.s.data.purchaseOrders {
+		po := &r.s.data.purchaseOrders[i]
+		if (supplierId == "" || po.SupplierId == supplierId) && (status == "" || po.Status == status) {
+			list = append(list, clonePurchaseOrder(*po))
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list, nil
+}
+
+func (r purchaseOrders) Update(po *dto.PurchaseOrder, fromStatuses ...string) error {
+	return r.update(po.POId, fromStatuses, func(stored *dto.PurchaseOrder) {
+		stored.PONumber = po.PONumber
+		stored.Items = append([]dto.PurchaseOrderItem(nil), po.Items...)
+		stored.TotalAmount = po.TotalAmount
+		stored.ExpectedDate = po.ExpectedDate
+		stored.Notes = po.Notes
+		stored.UpdatedAt = po.UpdatedAt
+	})
+}
+
+func (r purchaseOrders) UpdateStatus(po *dto.PurchaseOrder, fromStatuses ...string) error {
+	return r.update(po.POId, fromStatuses, func(stored *dto.PurchaseOrder) {
+		stored.Status = po.Status
+		stored.ApprovedBy = po.ApprovedBy
+		stored.ApprovedAt = po.ApprovedAt
+		stored.SentAt = po.SentAt
+		stored.ClosedAt = po.ClosedAt
+		stored.UpdatedAt = po.UpdatedAt
+	})
+}
+
+func (r purchaseOrders) update(poId string, fromStatuses []string, apply func(stored *dto.PurchaseOrder)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findPurchaseOrder(poId)
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	for _, status := range fromStatuses {
+		if stored.Status == status {
+			apply(stored)
+			return nil
+		}
+	}
+	return repository.ErrPurchaseOrderStatusChanged
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"employee-crud/config"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"sort"
+	"time"
+)
+
+type reports struct{ s *Store }
+
+func (r reports) GetDailySalesSummary(targetDate time.Time, locationId string) (*dto.DailySalesSummary, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// Same UTC day window as the Mongo query
+	startOfDay := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, time.UTC)
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	daySales := functions.SalesAtLocation(r.salesBetween(startOfDay, endOfDay), locationId)
+	summary := functions.SummarizeSales(targetDate, daySales)
+	summary.Profit = functions.SummarizeProfit(daySales, r.soldProducts(daySales), nil)
+	summary.LocationId = locationId
+	return summary, nil
+}
+
+func (r reports) GetGrossProfitReport(start time.Time, end time.Time, locationId string) (*dto.GrossProfitReport, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	sales := functions.SalesAtLocation(r.salesBetween(start, end), locationId)
+	report := functions.SummarizeProfit(sales, r.soldProducts(sales), config.Location())
+	return &report, nil
+}
+
+// salesBetween returns copies of the sales created in [start, end)
+func (r reports) salesBetween(start time.Time, end time.Time) []dto.Sale {
+	var sales []dto.Sale
+	for _, sale := range r.s.data.sales {
+		if !sale.CreatedAt.Before(start) && sale.CreatedAt.Before(end) {
+			sales = append(sales, cloneSale(sale))
+		}
+	}
+	return sales
+}
+
+// soldProducts returns copies of the products sold in the sales by id, deleted ones included
+func (r reports) soldProducts(sales []dto.Sale) map[string]*dto.Product {
+	products := make(map[string]*dto.Product)
+	for _, sale := range sales {
+		for _, item := range sale.Items {
+			if _, found := products[item.ProductID]; found {
+				continue
+			}
+			stored := r.s.findProduct(item.ProductID, false)
+			if stored == nil {
+				stored = r.s.findProduct(item.ProductID, true)
+			}
+			if stored != nil {
+				product := cloneProduct(*stored)
+				products[item.ProductID] = &product
+			}
+		}
+	}
+	return products
+}
+
+func (r reports) GetSavedDailyReport(date time.Time, locationId string) (*dto.DailyReportDocument, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, config.Location())
+	end := start.AddDate(0, 0, 1)
+	for _, report := range r.s.data.dailyReports {
+		if report.LocationId == locationId && !report.ReportDate.Before(start) && report.ReportDate.Before(end) {
+			found := report
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r reports) GetSavedDailyReportsByMonth(year int, month int, locationId string) ([]dto.DailyReportDocument, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var list []dto.DailyReportDocument
+	for _, report := range r.s.data.dailyReports {
+		if report.LocationId == locationId && report.Year == year && report.Month == month {
+			list = append(list, report)
+		}
+	}
+	return list, nil
+}
+
+func (r reports) GetSavedDailyReportsBetween(start time.Time, end time.Time, locationId string) ([]dto.DailyReportDocument, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := []dto.DailyReportDocument{}
+	for _, report := range r.s.data.dailyReports {
+		if report.LocationId == locationId && !report.ReportDate.Before(start) && report.ReportDate.Before(end) {
+			list = append(list, report)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].ReportDate.Before(list[j].ReportDate)
+	})
+	return list, nil
+}
+
+// SaveDailyReport replaces the saved report of the summary's day and location, without the TTL of the Mongo collection
+func (r reports) SaveDailyReport(summary *dto.DailySalesSummary) error {
+	report := functions.BuildDailyReport(summary)
+	report.CreatedAt = time.Now().In(config.Location())
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	date := summary.ReportDate
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, config.Location())
+	end := start.AddDate(0, 0, 1)
+	for i := range r.s.data.dailyReports {
+		saved := &r.s.data.dailyReports[i]
+		if saved.LocationId == report.LocationId && !saved.ReportDate.Before(start) && saved.ReportDate.Before(end) {
+			report.ID = r.s.data.dailyReports[i].ID
+			r.s.data.dailyReports[i] = report
+			return nil
+		}
+	}
+	r.s.data.dailyReports = append(r.s.data.dailyReports, report)
+	return nil
+}
+
+func (r reports) CalculateTotalAndExpectedCost() (dto.Money, dto.Money, error) {
+	return r.costSummary(func(p *dto.Product) bool { return true })
+}
+
+func (r reports) GetBrandCostSummary(brandId string) (dto.Money, dto.Money, error) {
+	return r.costSummary(func(p *dto.Product) bool { return p.BrandID == brandId })
+}
+
+// costSummary sums cost and selling value of the product-level prices and stock, like the Mongo aggregations
+func (r reports) costSummary(keep func(p *dto.Product) bool) (dto.Money, dto.Money, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var totalCost, expectedCost dto.Money
+	for _, product := range r.s.activeProducts(keep) {
+		totalCost += product.CostPrice.Times(product.StockQty)
+		expectedCost += product.SellingPrice.Times(product.StockQty)
+	}
+	return totalCost, expectedCost, nil
+}
+
+// findRollup returns the stored rollup (not a copy); the caller holds the lock
+func (s *Store) findRollup(period string, year int, month int) *dto.ReportRollup {
+	for i := range s.data.rollups {
+		rollup := &s.data.rollups[i]
+		if rollup.Period == period && rollup.Year == year && rollup.Month == month {
+			return rollup
+		}
+	}
+	return nil
+}
+
+// saveRollup inserts or replaces the rollup for its period, keeping the original createdAt; the caller holds the lock
+func (s *Store) saveRollup(rollup dto.ReportRollup) {
+	if existing := s.findRollup(rollup.Period, rollup.Year, rollup.Month); existing != nil {
+		rollup.CreatedAt = existing.CreatedAt
+		*existing = rollup
+		return
+	}
+	s.data.rollups = append(s.data.rollups, rollup)
+}
+
+// findRollups returns the rollups of one period type starting in [from, to], oldest first; the caller holds the lock
+func (s *Store) findRollups(period string, from time.Time, to time.Time) []dto.ReportRollup {
+	list := []dto.ReportRollup{}
+	for _, rollup := range s.data.rollups {
+		if rollup.Period == period && !rollup.PeriodStart.Before(from) && !rollup.PeriodStart.After(to) {
+			list = append(list, rollup)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].PeriodStart.Before(list[j].PeriodStart)
+	})
+	return list
+}
+
+func (r reports) GetRollup(period string, year int, month int) (*dto.ReportRollup, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findRollup(period, year, month)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	rollup := *stored
+	return &rollup, nil
+}
+
+func (r reports) FindRollups(period string, from time.Time, to time.Time) ([]dto.ReportRollup, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.findRollups(period, from, to), nil
+}
+
+// RefreshRollups mirrors the Mongo implementation, including never shrinking an existing monthly rollup
+func (r reports) RefreshRollups(year int, month int) (*dto.ReportRollup, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var daily []dto.DailyReportDocument
+	for _, report := range r.s.data.dailyReports {
+		if report.LocationId == "" && report.Year == year && report.Month == month {
+			daily = append(daily, report)
+		}
+	}
+
+	loc := config.Location()
+	now := time.Now().In(loc)
+	monthly := functions.BuildMonthlyRollup(year, month, daily, loc, now)
+	if existing := r.s.findRollup(dto.RollupMonthly, year, month); existing != nil && existing.DaysCovered > monthly.DaysCovered {
+		monthly = existing
+	} else if len(daily) == 0 {
+		return nil, repository.ErrNotFound
+	} else {
+		r.s.saveRollup(*monthly)
+	}
+
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	months := r.s.findRollups(dto.RollupMonthly, yearStart, yearStart.AddDate(1, 0, -1))
+	r.s.saveRollup(*functions.BuildYearlyRollup(year, months, loc, now))
+
+	result := *monthly
+	return &result, nil
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type returns struct{ s *Store }
+
+// CreateSaleReturn mirrors the Mongo implementation: lines are validated and priced against the sale,
+// resellable lines marked for restock go back into a batch and damaged lines are written off,
+// and the returned share of the sale's spend and points is taken back off its customer
+func (r returns) CreateSaleReturn(ret *dto.ReturnDTO, userId string) error {
+	return r.s.atomically(func() error {
+		sale := r.s.findSale(ret.SaleID)
+		if sale == nil {
+			return repository.ErrNotFound
+		}
+
+		updated := cloneSale(*sale)
+		returnedBefore := functions.ReturnedSaleValue(&updated)
+		lines, totalRefund, err := functions.PrepareSaleReturn(&updated, append([]dto.ReturnProduct(nil), ret.Products...))
+		if err != nil {
+			return err
+		}
+		updated.UpdatedAt = time.Now()
+		*sale = updated
+
+		r.s.returnCustomerSale(sale.CustomerID, functions.SaleReturnReversal(sale, returnedBefore, totalRefund))
+
+		ref := dto.StockMovementRef{
+			Type:          dto.MovementReturn,
+			ReferenceType: dto.ReferenceReturn,
+			ReferenceId:   ret.ID,
+			UserId:        userId,
+		}
+
+		for i := range lines {
+			line := &lines[i]
+			line.WriteOffID = ""
+			switch {
+			case line.Condition == dto.ReturnConditionDamaged:
+				if err := r.writeOff(line, ref); err != nil {
+					return err
+				}
+			case line.Restock:
+				if err := r.restock(line, sale.Items[*line.LineIndex].Batches, functions.SaleLocation(sale), ref); err != nil {
+					return err
+				}
+			}
+		}
+
+		ret.Products = lines
+		ret.TotalRefund = totalRefund
+		if ret.OriginalBillNumber == "" {
+			ret.OriginalBillNumber = sale.SaleID
+		}
+		if ret.CustomerName == "" {
+			ret.CustomerName = sale.CustomerName
+		}
+		if ret.ContactNumber == "" {
+			ret.ContactNumber = sale.MobileNumber
+		}
+
+		r.s.data.returns = append(r.s.data.returns, cloneReturn(*ret))
+		return nil
+	})
+}
+
+// restock puts a resellable returned line back into stock at the sale's location, preferring a batch its sale line
+// was sold from; the caller holds the lock
+func (r returns) restock(line *dto.ReturnProduct, soldFrom []dto.BatchAllocation, locationId string, ref dto.StockMovementRef) error {
+	ref.Note = line.Reason
+
+	var batchId string
+	_, err := r.s.updateBatches(line.ProductID, ref, func(product *dto.Product) error {
+		id, err := functions.ApplyReturnRestock(product, line.Quantity, line.BatchID, soldFrom, locationId, r.s.batchIdGenerator(), time.Now().UTC())
+		batchId = id
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restock product %s: %w", line.ProductID, err)
+	}
+
+	line.BatchID = batchId
+	return nil
+}
+
+// writeOff records a damaged returned line at cost; the caller holds the lock
+func (r returns) writeOff(line *dto.ReturnProduct, ref dto.StockMovementRef) error {
+	product := r.s.findProduct(line.ProductID, false)
+	if product == nil {
+		product = r.s.findProduct(line.ProductID, true)
+	}
+	if product == nil {
+		return fmt.Errorf("product not found: %w", repository.ErrNotFound)
+	}
+
+	costPrice := product.CostPrice
+	for _, batch := range product.Batches {
+		if batch.BatchId == line.BatchID {
+			costPrice = batch.CostPrice
+			break
+		}
+	}
+
+	writeOff := dto.StockWriteOff{
+		WriteOffId:    uuid.New().String(),
+		ProductId:     line.ProductID,
+		ProductName:   line.ProductName,
+		Quantity:      line.Quantity,
+		CostPrice:     costPrice,
+		TotalCost:     costPrice.Times(line.Quantity),
+		Reason:        line.Reason,
+		ReferenceType: ref.ReferenceType,
+		ReferenceId:   ref.ReferenceId,
+		UserId:        ref.UserId,
+		CreatedAt:     time.Now().UTC(),
+	}
+	r.s.data.writeOffs = append(r.s.data.writeOffs, writeOff)
+
+	line.WriteOffID = writeOff.WriteOffId
+	line.Restock = false
+	return nil
+}
+
+func (r returns) FindAll(ctx context.Context) ([]dto.ReturnDTO, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var list []dto.ReturnDTO
+	for _, ret := range r.s.data.returns {
+		list = append(list, cloneReturn(ret))
+	}
+	return list, nil
+}
+
+func (r returns) FindById(ctx context.Context, id string) (*dto.ReturnDTO, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, ret := range r.s.data.returns {
+		if ret.ID == id {
+			found := cloneReturn(ret)
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r returns) FindByDateRange(ctx context.Context, start time.Time, end time.Time) ([]dto.ReturnDTO, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var list []dto.ReturnDTO
+	for _, ret := range r.s.data.returns {
+		createdAt, err := time.Parse(time.RFC3339, ret.CreatedAt)
+		if err != nil || createdAt.Before(start) || createdAt.After(end) {
+			continue
+		}
+		list = append(list, cloneReturn(ret))
+	}
+	return list, nil
+}
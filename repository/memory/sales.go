@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"time"
+)
+
+type sales struct{ s *Store }
+
+// findSale returns the stored sale (not a copy); the caller holds the lock
+func (s *Store) findSale(saleId string) *dto.Sale {
+	for i := range s.data.sales {
+		if s.data.sales[i].SaleID == saleId {
+			return &s.data.sales[i]
+		}
+	}
+	return nil
+}
+
+// Checkout deducts every item FEFO, records the sale with the batches each line came from, books the sale on its customer
+// and converts the cart it was made from, if any; nothing is kept if any step fails
+func (r sales) Checkout(sale *dto.Sale, userId string, cart *dto.Cart) error {
+	ref := dto.StockMovementRef{
+		Type:          dto.MovementSale,
+		ReferenceType: dto.ReferenceSale,
+		ReferenceId:   sale.SaleID,
+		UserId:        userId,
+	}
+
+	return r.s.atomically(func() error {
+		for i := range sale.Items {
+			item := &sale.Items[i]
+			_, err := r.s.updateBatches(item.ProductID, ref, func(product *dto.Product) error {
+				allocations, err := functions.ApplySaleDeduction(product, functions.SaleLocation(sale), item.Quantity, time.Now().UTC())
+				item.Batches = allocations
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		r.s.data.sales = append(r.s.data.sales, cloneSale(*sale))
+		if err := r.s.applyCustomerSale(sale); err != nil {
+			return err
+		}
+
+		if cart != nil {
+			converted := *cart
+			functions.ConvertCart(&converted, sale.SaleID, time.Now().UTC())
+			if err := r.s.replaceCart(&converted, cart.Status); err != nil {
+				return err
+			}
+			*cart = converted
+		}
+		return nil
+	})
+}
+
+// Void marks the sale voided, restocks each unit into the batch its sale line (or the ledger) says it came from
+// and reverses the customer booking; nothing is kept if any step fails
+func (r sales) Void(saleId string, reason string, userId string) (*dto.Sale, error) {
+	ref := dto.StockMovementRef{
+		Type:          dto.MovementSaleVoid,
+		ReferenceType: dto.ReferenceSale,
+		ReferenceId:   saleId,
+		UserId:        userId,
+		Note:          reason,
+	}
+
+	var voided dto.Sale
+	err := r.s.atomically(func() error {
+		stored := r.s.findSale(saleId)
+		if stored == nil {
+			return repository.ErrNotFound
+		}
+		if err := functions.CheckSaleVoidable(stored); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		stored.Voided = true
+		stored.VoidReason = reason
+		stored.VoidedBy = userId
+		stored.VoidedAt = &now
+		stored.UpdatedAt = now
+		sale := cloneSale(*stored)
+
+		var saleMovements []dto.StockMovement
+		for _, movement := range r.s.data.movements {
+			if movement.ReferenceType == dto.ReferenceSale && movement.ReferenceId == saleId {
+				saleMovements = append(saleMovements, movement)
+			}
+		}
+		allocations := functions.SaleAllocationsByProduct(&sale, saleMovements)
+
+		quantities := make(map[string]int)
+		var productIds []string
+		for _, item := range sale.Items {
+			if _, seen := quantities[item.ProductID]; !seen {
+				productIds = append(productIds, item.ProductID)
+			}
+			quantities[item.ProductID] += item.Quantity
+		}
+
+		newBatchId := r.s.batchIdGenerator()
+		for _, productId := range productIds {
+			_, err := r.s.updateBatches(productId, ref, func(product *dto.Product) error {
+				return functions.ApplySaleVoid(product, quantities[productId], allocations[productId], functions.SaleLocation(&sale), newBatchId, now)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		r.s.reverseCustomerSale(&sale)
+		voided = sale
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &voided, nil
+}
+
+func (r sales) FindById(saleId string) (*dto.Sale, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findSale(saleId)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	sale := cloneSale(*stored)
+	return &sale, nil
+}
+
+// FindAll returns every sale; like the Mongo implementation it does not page yet
+func (r sales) FindAll(limit int64, offset int64) ([]dto.Sale, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var list []dto.Sale
+	for _, sale := range r.s.data.sales {
+		list = append(list, cloneSale(sale))
+	}
+	return list, nil
+}
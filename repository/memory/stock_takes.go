@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"employee-crud/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type stockTakes struct{ s *Store }
+
+func cloneStockTake(st dto.StockTake) dto.StockTake {
+	st.Lines = append([]dto.StockTakeLine(nil), st.Lines...)
+	return st
+}
+
+// findStockTake returns the stored stock take (not a copy); the caller holds the lock
+func (s *Store) findStockTake(stockTakeId string) *dto.StockTake {
+	for i := range s.data.stockTakes {
+		if s.data.stockTakes[i].StockTakeId == stockTakeId {
+			return &s.data.stockTakes[i]
+		}
+	}
+	return nil
+}
+
+// openStockTake returns the stored stock take if it is still open; the caller holds the lock
+func (s *Store) openStockTake(stockTakeId string) (*dto.StockTake, error) {
+	stored := s.findStockTake(stockTakeId)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	if stored.Status != dto.StockTakeOpen {
+		return nil, fmt.Errorf("%w: %s is %s", repository.ErrStockTakeStatusChanged, stockTakeId, stored.Status)
+	}
+	return stored, nil
+}
+
+func (r stockTakes) Create(stockTake *dto.StockTake) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.data.stockTakes = append(r.s.data.stockTakes, cloneStockTake(*stockTake))
+	return nil
+}
+
+func (r stockTakes) FindById(stockTakeId string) (*dto.StockTake, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findStockTake(stockTakeId)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	st := cloneStockTake(*stored)
+	return &st, nil
+}
+
+func (r stockTakes) FindAll(status string, withLines bool) ([]dto.StockTake, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := []dto.StockTake{}
+	for i := range r.s.data.stockTakes {
+		st := &r.s.data.stockTakes[i]
+		if status != "" && st.Status != status {
+			continue
+		}
+		c := cloneStockTake(*st)
+		if !withLines {
+			c.Lines = nil
+		}
+		list = append(list, c)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list, nil
+}
+
+func (r stockTakes) UpdateCounts(stockTake *dto.StockTake) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, err := r.s.openStockTake(stockTake.StockTakeId)
+	if err != nil {
+		return err
+	}
+	stored.Lines = append([]dto.StockTakeLine(nil), stockTake.Lines...)
+	stored.CountedLines = stockTake.CountedLines
+	stored.ExpectedValue = stockTake.ExpectedValue
+	stored.VarianceValue = stockTake.VarianceValue
+	stored.ShortageValue = stockTake.ShortageValue
+	stored.SurplusValue = stockTake.SurplusValue
+	stored.UpdatedAt = stockTake.UpdatedAt
+	return nil
+}
+
+func (r stockTakes) Cancel(stockTakeId string, now time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, err := r.s.openStockTake(stockTakeId)
+	if err != nil {
+		return err
+	}
+	stored.Status = dto.StockTakeCancelled
+	stored.CancelledAt = &now
+	stored.UpdatedAt = now
+	return nil
+}
+
+// Approve posts the variances into the batches, skipping products deleted since the count started;
+// nothing is kept if any product fails
+func (r stockTakes) Approve(stockTakeId string, userId string) (*dto.StockTake, error) {
+	ref := dto.StockMovementRef{
+		Type:          dto.MovementStockTake,
+		ReferenceType: dto.ReferenceStockTake,
+		ReferenceId:   stockTakeId,
+		UserId:        userId,
+	}
+
+	var approved dto.StockTake
+	err := r.s.atomically(func() error {
+		stored, err := r.s.openStockTake(stockTakeId)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		for _, productId := range functions.StockTakeProductIds(stored) {
+			_, err := r.s.updateBatches(productId, ref, func(product *dto.Product) error {
+				functions.ApplyStockTakeVariance(product, stored, now)
+				return nil
+			})
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to adjust product %s: %w", productId, err)
+			}
+		}
+
+		stored.Status = dto.StockTakeApproved
+		stored.ApprovedBy = userId
+		stored.ApprovedAt = &now
+		stored.UpdatedAt = now
+		approved = cloneStockTake(*stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &approved, nil
+}
@@ -0,0 +1,353 @@
+package memory
+
+import (
+	"employee-crud/config"
+	"employee-crud/dao"
+	"employee-crud/dto"
+	"employee-crud/functions"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stocks derives the Stocks view from the products on every read, so there is nothing to sync or clean up
+type stocks struct{ s *Store }
+
+// stockThresholds resolves the stock thresholds of a product; the caller holds the lock
+func (s *Store) stockThresholds(product *dto.Product) dto.StockThresholds {
+	thresholds, _ := functions.ResolveStockThresholds(product, s.data.categoryStock, config.Get().Stock.Thresholds())
+	return thresholds
+}
+
+// productStocks returns one Stock per batch, or a single entry for a legacy product without batches
+// A non-empty locationId keeps only the batches at that location
+func productStocks(product dto.Product, thresholds dto.StockThresholds, locationId string) []dto.Stock {
+	batches := product.Batches
+	if locationId != "" {
+		batches = nil
+		for _, batch := range product.Batches {
+			if functions.BatchLocation(&batch) == locationId {
+				batches = append(batches, batch)
+			}
+		}
+	}
+
+	if len(batches) == 0 {
+		stockQty := product.StockQty
+		if locationId != "" {
+			stockQty = functions.LocationStock(&product, locationId)
+		}
+		stock := dto.Stock{
+			ProductId:  product.ProductId,
+			Name:       product.Name,
+			LocationId: locationId,
+			StockQty:   stockQty,
+			ExpiryDate: product.ExpiryDate,
+			CreatedAt:  product.CreatedAt,
+			UpdatedAt:  product.UpdatedAt,
+		}
+		stock.CalculateStatus(thresholds)
+		return []dto.Stock{stock}
+	}
+
+	var list []dto.Stock
+	for _, batch := range batches {
+		stock := dto.Stock{
+			ProductId:  product.ProductId,
+			BatchId:    batch.BatchId,
+			LocationId: functions.BatchLocation(&batch),
+			Name:       product.Name,
+			StockQty:   batch.StockQty,
+			ExpiryDate: batch.ExpiryDate,
+			CreatedAt:  batch.CreatedAt,
+			UpdatedAt:  batch.UpdatedAt,
+		}
+		stock.CalculateStatus(thresholds)
+		list = append(list, stock)
+	}
+	return list
+}
+
+// batchStocks returns the batch stock entries of every non-deleted product, at one location when locationId is set,
+// newest update first
+// Legacy products are left out, as they are from the synced Stocks collection
+func (r stocks) batchStocks(locationId string) []dto.Stock {
+	var list []dto.Stock
+	for _, product := range r.s.activeProducts(func(p *dto.Product) bool { return len(p.Batches) > 0 }) {
+		for _, stock := range productStocks(product, r.s.stockThresholds(&product), locationId) {
+			if stock.BatchId != "" {
+				list = append(list, stock)
+			}
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].UpdatedAt.After(list[j].UpdatedAt)
+	})
+	return list
+}
+
+func productInfo(product dto.Product, thresholds dto.StockThresholds, stockQty int, expiryDate *time.Time, batchId string) dao.ProductWithStockInfo {
+	info := dao.ProductWithStockInfo{
+		ProductId:       product.ProductId,
+		Name:            product.Name,
+		StockQty:        stockQty,
+		ProductStockQty: product.StockQty,
+		ExpiryDate:      expiryDate,
+		BatchId:         batchId,
+		HasBatches:      len(product.Batches) > 0,
+		BatchCount:      len(product.Batches),
+		CreatedAt:       product.CreatedAt,
+		UpdatedAt:       product.UpdatedAt,
+	}
+	info.CalculateProductStatus(product.StockQty, thresholds)
+	return info
+}
+
+func (r stocks) SyncProduct(product *dto.Product) error {
+	return nil
+}
+
+func (r stocks) SyncAll() error {
+	return nil
+}
+
+func (r stocks) Count(locationId string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.batchStocks(locationId))), nil
+}
+
+func (r stocks) FindAllCursorPaginated(limit int, cursor string, locationId string) ([]dto.Stock, string, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := r.batchStocks(locationId)
+	start, end, nextCursor, hasMore, err := page(len(list), limit, cursor)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return list[start:end], nextCursor, hasMore, nil
+}
+
+func (r stocks) FindFilteredCursorPaginated(limit int, cursor string, status string, locationId string) ([]dto.Stock, string, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// Filtering is on the status of the product's total (or location) quantity, then every batch of a matching
+	// product (at the location) is listed
+	matching := r.s.activeProducts(r.s.inStockStatus(status, locationId))
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].UpdatedAt.After(matching[j].UpdatedAt)
+	})
+
+	start, end, nextCursor, hasMore, err := page(len(matching), limit, cursor)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	var list []dto.Stock
+	for _, product := range matching[start:end] {
+		list = append(list, productStocks(product, r.s.stockThresholds(&product), locationId)...)
+	}
+	return list, nextCursor, hasMore, nil
+}
+
+func (r stocks) CountFiltered(status string, locationId string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.s.activeProducts(r.s.inStockStatus(status, locationId)))), nil
+}
+
+// inStockStatus matches products whose total stock, or stock at the location when locationId is set,
+// is in status under their resolved thresholds
+func (s *Store) inStockStatus(status string, locationId string) func(p *dto.Product) bool {
+	return func(p *dto.Product) bool {
+		stockQty := p.StockQty
+		if locationId != "" {
+			stockQty = functions.LocationStock(p, locationId)
+		}
+		return s.stockThresholds(p).Status(stockQty) == status
+	}
+}
+
+func (r stocks) TotalQuantity() (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var total int64
+	for _, product := range r.s.activeProducts(nil) {
+		total += int64(product.StockQty)
+	}
+	return total, nil
+}
+
+func (r stocks) StatusCounts() (*dao.StockStatusCounts, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	counts := &dao.StockStatusCounts{}
+	for _, product := range r.s.activeProducts(nil) {
+		switch r.s.stockThresholds(&product).Status(product.StockQty) {
+		case dto.StockStatusLow:
+			counts.LowStock++
+		case dto.StockStatusAverage:
+			counts.AverageStock++
+		default:
+			counts.GoodStock++
+		}
+		counts.Total++
+	}
+	return counts, nil
+}
+
+func (r stocks) CleanupOrphaned() (int64, error) {
+	return 0, nil
+}
+
+func (r stocks) ValidateIntegrity() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"orphaned_stocks_count": 0,
+		"issues":                []string{},
+		"total_issues":          0,
+		"status":                "ok",
+	}, nil
+}
+
+func (r stocks) FindProductsWithStock(limit int, cursor string) ([]dao.ProductWithStockInfo, string, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := r.s.activeProducts(nil)
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].UpdatedAt.After(list[j].UpdatedAt)
+	})
+
+	start, end, nextCursor, hasMore, err := page(len(list), limit, cursor)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	var infos []dao.ProductWithStockInfo
+	for _, product := range list[start:end] {
+		if len(product.Batches) == 0 {
+			infos = append(infos, productInfo(product, r.s.stockThresholds(&product), product.StockQty, product.ExpiryDate, ""))
+			continue
+		}
+		for _, batch := range product.Batches {
+			infos = append(infos, productInfo(product, r.s.stockThresholds(&product), batch.StockQty, batch.ExpiryDate, batch.BatchId))
+		}
+	}
+	return infos, nextCursor, hasMore, nil
+}
+
+func (r stocks) CountProductsWithStock() (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.s.activeProducts(nil))), nil
+}
+
+func (r stocks) FindTopExpiring(topN int, now time.Time, until time.Time) ([]dao.ProductWithStockInfo, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	expiring := func(date *time.Time) bool {
+		return date != nil && date.After(now) && date.Before(until)
+	}
+
+	var infos []dao.ProductWithStockInfo
+	for _, product := range r.s.activeProducts(nil) {
+		if len(product.Batches) == 0 {
+			if expiring(product.ExpiryDate) {
+				infos = append(infos, productInfo(product, r.s.stockThresholds(&product), product.StockQty, product.ExpiryDate, ""))
+			}
+			continue
+		}
+		for _, batch := range product.Batches {
+			if expiring(batch.ExpiryDate) {
+				infos = append(infos, productInfo(product, r.s.stockThresholds(&product), batch.StockQty, batch.ExpiryDate, batch.BatchId))
+			}
+		}
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].StockQty > infos[j].StockQty
+	})
+	if topN > 0 && len(infos) > topN {
+		infos = infos[:topN]
+	}
+	return infos, nil
+}
+
+func (r stocks) FindLowStockProducts(limit int) ([]dto.Product, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := r.s.activeProducts(func(p *dto.Product) bool { return p.StockQty < r.s.stockThresholds(p).LowThreshold })
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].StockQty < list[j].StockQty
+	})
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (r stocks) FindMovements(filter dao.StockMovementFilter, page int, limit int) ([]dto.StockMovement, int64, error) {
+	if page < 1 || limit < 1 {
+		return nil, 0, fmt.Errorf("page and limit must be positive")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	matching := []dto.StockMovement{}
+	for i := len(r.s.data.movements) - 1; i >= 0; i-- {
+		movement := r.s.data.movements[i]
+		if (filter.ProductId != "" && movement.ProductId != filter.ProductId) ||
+			(filter.BatchId != "" && movement.BatchId != filter.BatchId) ||
+			(filter.LocationId != "" && functions.NormalizeLocationId(movement.LocationId) != filter.LocationId) ||
+			(filter.Type != "" && movement.Type != filter.Type) ||
+			(filter.StartDate != nil && movement.CreatedAt.Before(*filter.StartDate)) ||
+			(filter.EndDate != nil && !movement.CreatedAt.Before(*filter.EndDate)) {
+			continue
+		}
+		matching = append(matching, movement)
+	}
+
+	total := int64(len(matching))
+	start := (page - 1) * limit
+	if start > len(matching) {
+		start = len(matching)
+	}
+	end := start + limit
+	if end > len(matching) {
+		end = len(matching)
+	}
+	return matching[start:end], total, nil
+}
+
+// SetCategoryThresholds records the thresholds only; categories themselves are not kept in memory
+func (r stocks) SetCategoryThresholds(categoryId string, thresholds *dto.StockThresholds) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if thresholds == nil {
+		delete(r.s.data.categoryStock, categoryId)
+		return nil
+	}
+	// Fiber's query values point into a reused request buffer, so the key is copied before it is kept
+	r.s.data.categoryStock[strings.Clone(categoryId)] = *thresholds
+	return nil
+}
+
+func (r stocks) CategoryThresholds() (map[string]dto.StockThresholds, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	thresholds := make(map[string]dto.StockThresholds, len(r.s.data.categoryStock))
+	for categoryId, categoryThresholds := range r.s.data.categoryStock {
+		thresholds[categoryId] = categoryThresholds
+	}
+	return thresholds, nil
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"employee-crud/dto"
+	"employee-crud/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type suppliers struct{ s *Store }
+
+// findSupplier returns the stored supplier (not a copy); the caller holds the lock
+func (s *Store) findSupplier(supplierId string) *dto.Supplier {
+	for i := range s.data.suppliers {
+		if s.data.suppliers[i].SupplierId == supplierId {
+			return &s.data.suppliers[i]
+		}
+	}
+	return nil
+}
+
+func (r suppliers) Create(supplier *dto.Supplier) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.data.suppliers = append(r.s.data.suppliers, *supplier)
+	return nil
+}
+
+func (r suppliers) FindById(supplierId string) (*dto.Supplier, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findSupplier(supplierId)
+	if stored == nil || stored.Deleted {
+		return nil, repository.ErrNotFound
+	}
+	supplier := *stored
+	return &supplier, nil
+}
+
+func (r suppliers) FindAll(status string) ([]dto.Supplier, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var list []dto.Supplier
+	for _, supplier := range r.s.data.suppliers {
+		if supplier.Deleted || (status != "" && supplier.Status != status) {
+			continue
+		}
+		list = append(list, supplier)
+	}
+	return list, nil
+}
+
+func (r suppliers) Update(ctx context.Context, supplier *dto.Supplier) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findSupplier(supplier.SupplierId)
+	if stored == nil {
+		return fmt.Errorf("supplierId %s not found", supplier.SupplierId)
+	}
+	stored.Name = supplier.Name
+	stored.Contact = supplier.Contact
+	stored.Email = supplier.Email
+	stored.Address = supplier.Address
+	stored.LeadTimeDays = supplier.LeadTimeDays
+	stored.UpdatedAt = supplier.UpdatedAt
+	return nil
+}
+
+func (r suppliers) UpdateStatus(ctx context.Context, supplierId string, status string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if stored := r.s.findSupplier(supplierId); stored != nil {
+		stored.Status = status
+	}
+	return nil
+}
+
+func (r suppliers) Delete(supplierId string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findSupplier(supplierId)
+	if stored == nil || stored.Deleted {
+		return errors.New("Specified Id not found or already deleted!")
+	}
+	stored.Deleted = true
+	return nil
+}
+
+func (r suppliers) StatusCounts(ctx context.Context) (int64, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var active, inactive int64
+	for _, supplier := range r.s.data.suppliers {
+		if supplier.Deleted {
+			continue
+		}
+		switch supplier.Status {
+		case "active":
+			active++
+		case "inactive":
+			inactive++
+		}
+	}
+	return active, inactive, nil
+}
+
+func (r suppliers) AssignProduct(supplierId string, productId string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	supplier := r.s.findSupplier(supplierId)
+	if supplier == nil || supplier.Deleted {
+		return errors.New("supplier not found")
+	}
+
+	product := r.s.findProduct(productId, false)
+	if product == nil {
+		product = r.s.findProduct(productId, true)
+	}
+	if product == nil {
+		return errors.New("product not found")
+	}
+
+	for _, assignment := range r.s.data.supplierProducts {
+		if assignment.SupplierID == supplierId && assignment.ProductID == productId {
+			return errors.New("product already assigned to this supplier")
+		}
+	}
+
+	now := time.Now()
+	r.s.data.supplierProducts = append(r.s.data.supplierProducts, dto.SupplierProduct{
+		SupplierID:   supplier.SupplierId,
+		SupplierName: supplier.Name,
+		ProductID:    product.ProductId,
+		ProductName:  product.Name,
+		AssignedAt:   now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	return nil
+}
+
+func (r suppliers) FindProductsBySupplier(supplierId string) ([]dto.SupplierProduct, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var list []dto.SupplierProduct
+	for _, assignment := range r.s.data.supplierProducts {
+		if assignment.SupplierID == supplierId {
+			list = append(list, assignment)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/repository"
+	"sort"
+	"time"
+)
+
+type taxes struct{ s *Store }
+
+// findTaxClass returns the stored tax class (not a copy) unless it was deleted; the caller holds the lock
+func (s *Store) findTaxClass(taxClassId string) *dto.TaxClass {
+	for i := range s.data.taxClasses {
+		if !s.data.taxClasses[i].Deleted && s.data.taxClasses[i].TaxClassID == taxClassId {
+			return &s.data.taxClasses[i]
+		}
+	}
+	return nil
+}
+
+func (r taxes) CreateClass(class *dto.TaxClass) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.data.taxClasses = append(r.s.data.taxClasses, *class)
+	return nil
+}
+
+func (r taxes) FindClassById(taxClassId string) (*dto.TaxClass, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findTaxClass(taxClassId)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	class := *stored
+	return &class, nil
+}
+
+func (r taxes) FindAllClasses() ([]dto.TaxClass, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := []dto.TaxClass{}
+	for _, class := range r.s.data.taxClasses {
+		if !class.Deleted {
+			list = append(list, class)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (r taxes) UpdateClass(class *dto.TaxClass) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findTaxClass(class.TaxClassID)
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	stored.Name = class.Name
+	stored.Rate = class.Rate
+	stored.Inclusive = class.Inclusive
+	stored.UpdatedAt = class.UpdatedAt
+	return nil
+}
+
+func (r taxes) DeleteClass(taxClassId string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, product := range r.s.data.products {
+		if !product.Deleted && product.TaxClassID == taxClassId {
+			return repository.ErrTaxClassInUse
+		}
+	}
+	for _, classId := range r.s.data.categoryTaxes {
+		if classId == taxClassId {
+			return repository.ErrTaxClassInUse
+		}
+	}
+
+	stored := r.s.findTaxClass(taxClassId)
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	stored.Deleted = true
+	stored.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetCategoryClass records the assignment only; categories themselves are not kept in memory
+func (r taxes) SetCategoryClass(categoryId string, taxClassId string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if taxClassId == "" {
+		delete(r.s.data.categoryTaxes, categoryId)
+		return nil
+	}
+	r.s.data.categoryTaxes[categoryId] = taxClassId
+	return nil
+}
+
+func (r taxes) CategoryClasses() (map[string]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	classes := make(map[string]string, len(r.s.data.categoryTaxes))
+	for categoryId, classId := range r.s.data.categoryTaxes {
+		classes[categoryId] = classId
+	}
+	return classes, nil
+}
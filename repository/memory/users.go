@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"employee-crud/dto"
+	"employee-crud/repository"
+	"sort"
+	"time"
+)
+
+type users struct{ s *Store }
+
+// findUser returns the stored user (not a copy); the caller holds the lock
+func (s *Store) findUser(match func(u *dto.User) bool) *dto.User {
+	for i := range s.data.users {
+		if match(&s.data.users[i]) {
+			return &s.data.users[i]
+		}
+	}
+	return nil
+}
+
+func (r users) Create(user *dto.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.s.findUser(func(u *dto.User) bool { return u.Username == user.Username }) != nil {
+		return repository.ErrUsernameTaken
+	}
+	r.s.data.users = append(r.s.data.users, *user)
+	return nil
+}
+
+func (r users) FindById(userId string) (*dto.User, error) {
+	return r.find(func(u *dto.User) bool { return u.UserId == userId })
+}
+
+func (r users) FindByUsername(username string) (*dto.User, error) {
+	return r.find(func(u *dto.User) bool { return u.Username == username })
+}
+
+func (r users) find(match func(u *dto.User) bool) (*dto.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findUser(match)
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	user := *stored
+	return &user, nil
+}
+
+func (r users) FindAll() ([]dto.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	list := append([]dto.User{}, r.s.data.users...)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Username < list[j].Username })
+	return list, nil
+}
+
+func (r users) Count() (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.s.data.users)), nil
+}
+
+func (r users) Update(userId string, changes dto.UserChanges) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.findUser(func(u *dto.User) bool { return u.UserId == userId })
+	if stored == nil {
+		return repository.ErrNotFound
+	}
+	if changes.FullName != nil {
+		stored.FullName = *changes.FullName
+	}
+	if changes.Role != nil {
+		stored.Role = *changes.Role
+	}
+	if changes.Active != nil {
+		stored.Active = *changes.Active
+	}
+	if changes.PasswordHash != nil {
+		stored.PasswordHash = *changes.PasswordHash
+		stored.TokenVersion++
+	}
+	stored.UpdatedAt = time.Now().UTC()
+	return nil
+}
@@ -0,0 +1,562 @@
+package repository
+
+import (
+	"context"
+	"employee-crud/dao"
+	"employee-crud/dto"
+	"time"
+)
+
+// NewMongoRepositories returns repositories backed by the dao package and the global MongoDB connection
+func NewMongoRepositories() Repositories {
+	return Repositories{
+		Products:       mongoProducts{},
+		Stocks:         mongoStocks{},
+		Sales:          mongoSales{},
+		GRNs:           mongoGRNs{},
+		Suppliers:      mongoSuppliers{},
+		Reports:        mongoReports{},
+		Returns:        mongoReturns{},
+		Customers:      mongoCustomers{},
+		Promotions:     mongoPromotions{},
+		Taxes:          mongoTaxes{},
+		Carts:          mongoCarts{},
+		PurchaseOrders: mongoPurchaseOrders{},
+		StockTakes:     mongoStockTakes{},
+		Locations:      mongoLocations{},
+		Users:          mongoUsers{},
+		Ids:            mongoIds{},
+	}
+}
+
+type mongoIds struct{}
+
+func (mongoIds) NextId(ctx context.Context, collectionName string, prefix string) (string, error) {
+	return dao.GenerateId(ctx, collectionName, prefix)
+}
+
+type mongoProducts struct{}
+
+func (mongoProducts) FindById(productId string) (*dto.Product, error) {
+	return dao.DB_FindProductById(productId)
+}
+
+func (mongoProducts) FindByAttributes(categoryId, brandId, subCategoryId string) (*dto.Product, error) {
+	return dao.DB_FindProductByAttributes(categoryId, brandId, subCategoryId)
+}
+
+func (mongoProducts) FindAll() ([]dto.Product, error) {
+	return dao.DB_FindAllProducts()
+}
+
+func (mongoProducts) FindAllCursorPaginated(limit int, cursor string) ([]dto.Product, string, bool, error) {
+	return dao.DB_FindAllProductsCursorPaginated(limit, cursor)
+}
+
+func (mongoProducts) FindByBarcodeCursorPaginated(barcode string, limit int, cursor string) ([]dto.Product, string, bool, error) {
+	return dao.DB_FindProductsByBarcodeCursorPaginated(barcode, limit, cursor)
+}
+
+func (mongoProducts) FindByBrandCursorPaginated(brandId string, limit int, cursor string) ([]dto.Product, string, bool, error) {
+	return dao.DB_FindProductsByBrandCursorPaginated(brandId, limit, cursor)
+}
+
+func (mongoProducts) FindByCategoryCursorPaginated(categoryId string, limit int, cursor string) ([]dto.Product, string, bool, error) {
+	return dao.DB_FindProductsByCategoryCursorPaginated(categoryId, limit, cursor)
+}
+
+func (mongoProducts) FindBySubCategory(subCategoryId string) ([]dto.Product, error) {
+	return dao.DB_FindProductsBySubCategory(subCategoryId)
+}
+
+func (mongoProducts) FindAllDeleted() ([]dto.Product, error) {
+	return dao.DB_FindAllDeletedProducts()
+}
+
+func (mongoProducts) CountTotal() (int64, error) {
+	return dao.DB_GetTotalProducts()
+}
+
+func (mongoProducts) CountCategorized() (int64, error) {
+	return dao.DB_GetCategorizedProductsCount()
+}
+
+func (mongoProducts) CountByBrand(brandId string) (int64, error) {
+	return dao.DB_GetProductsCountByBrand(brandId)
+}
+
+func (mongoProducts) CountByCategory(categoryId string) (int64, error) {
+	return dao.DB_GetProductsCountByCategory(categoryId)
+}
+
+func (mongoProducts) Create(product *dto.Product, ref dto.StockMovementRef) error {
+	return dao.DB_CreateProduct(product, ref)
+}
+
+func (mongoProducts) Update(ctx context.Context, product *dto.Product, settings dto.ProductSettingChanges) error {
+	return dao.DB_UpdateProduct(ctx, product, settings)
+}
+
+func (mongoProducts) Delete(productId string) error {
+	return dao.DB_DeleteProductByID(productId)
+}
+
+func (mongoProducts) DeletePermanent(productId string) error {
+	return dao.DB_PermanentDeleteProductByID(productId)
+}
+
+func (mongoProducts) Restore(productId, categoryId, brandId, subCategoryId string) error {
+	return dao.DB_RestoreProductByID(productId, categoryId, brandId, subCategoryId)
+}
+
+func (mongoProducts) AddBatch(productId string, batch dto.Batch, ref dto.StockMovementRef) error {
+	return dao.DB_AddBatchToProduct(productId, batch, ref)
+}
+
+func (mongoProducts) ConvertToBatches(product *dto.Product, initialBatch dto.Batch, ref dto.StockMovementRef) error {
+	return dao.DB_UpdateProductWithBatch(product, initialBatch, ref)
+}
+
+func (mongoProducts) AddStock(productId string, locationId string, stockQty int, expiryDate *time.Time, costPrice dto.Money, sellingPrice dto.Money, ref dto.StockMovementRef) (*dto.Product, string, error) {
+	return dao.DB_AddStockToProduct(productId, locationId, stockQty, expiryDate, costPrice, sellingPrice, ref)
+}
+
+func (mongoProducts) EditBatchStock(productId string, batchId string, newStockQty int, ref dto.StockMovementRef) (*dto.Product, error) {
+	return dao.DB_EditBatchStock(productId, batchId, newStockQty, ref)
+}
+
+func (mongoProducts) EditBatchDetails(productId string, batchId string, expiryDate *time.Time, costPrice dto.Money, sellingPrice dto.Money, ref dto.StockMovementRef) (*dto.Product, error) {
+	return dao.DB_EditBatchDetails(productId, batchId, expiryDate, costPrice, sellingPrice, ref)
+}
+
+func (mongoProducts) RemoveStockFromBatch(productId string, batchId string, quantityToRemove int, ref dto.StockMovementRef) (*dto.Product, error) {
+	return dao.DB_RemoveStockFromBatch(productId, batchId, quantityToRemove, ref)
+}
+
+func (mongoProducts) DeleteBatch(productId string, batchId string, ref dto.StockMovementRef) (*dto.Product, error) {
+	return dao.DB_DeleteBatch(productId, batchId, ref)
+}
+
+type mongoStocks struct{}
+
+func (mongoStocks) SyncProduct(product *dto.Product) error {
+	return dao.DB_SyncSingleProductStock(product)
+}
+
+func (mongoStocks) SyncAll() error {
+	return dao.DB_SyncStocksFromProducts()
+}
+
+func (mongoStocks) Count(locationId string) (int64, error) {
+	return dao.DB_GetStocksCount(locationId)
+}
+
+func (mongoStocks) FindAllCursorPaginated(limit int, cursor string, locationId string) ([]dto.Stock, string, bool, error) {
+	return dao.DB_FindAllStocksCursorPaginated(limit, cursor, locationId)
+}
+
+func (mongoStocks) FindFilteredCursorPaginated(limit int, cursor string, status string, locationId string) ([]dto.Stock, string, bool, error) {
+	return dao.DB_FindAllStocksFilteredCursorPaginated(limit, cursor, status, locationId)
+}
+
+func (mongoStocks) CountFiltered(status string, locationId string) (int64, error) {
+	return dao.DB_GetStocksCountFiltered(status, locationId)
+}
+
+func (mongoStocks) TotalQuantity() (int64, error) {
+	return dao.DB_CalculateTotalStockQuantity()
+}
+
+func (mongoStocks) StatusCounts() (*dao.StockStatusCounts, error) {
+	return dao.DB_GetStockStatusCounts()
+}
+
+func (mongoStocks) CleanupOrphaned() (int64, error) {
+	return dao.DB_CleanupOrphanedStocks()
+}
+
+func (mongoStocks) ValidateIntegrity() (map[string]interface{}, error) {
+	return dao.DB_ValidateStockIntegrity()
+}
+
+func (mongoStocks) FindProductsWithStock(limit int, cursor string) ([]dao.ProductWithStockInfo, string, bool, error) {
+	return dao.DB_FindAllProductsWithStock(limit, cursor)
+}
+
+func (mongoStocks) CountProductsWithStock() (int64, error) {
+	return dao.DB_GetProductsWithStockCount()
+}
+
+func (mongoStocks) FindTopExpiring(topN int, now time.Time, until time.Time) ([]dao.ProductWithStockInfo, error) {
+	return dao.DB_FindTopExpiringStocksNext7Days(topN, now, until)
+}
+
+func (mongoStocks) FindLowStockProducts(limit int) ([]dto.Product, error) {
+	return dao.DB_GetTopLowStockProducts(limit)
+}
+
+func (mongoStocks) FindMovements(filter dao.StockMovementFilter, page int, limit int) ([]dto.StockMovement, int64, error) {
+	return dao.DB_FindStockMovements(filter, page, limit)
+}
+
+func (mongoStocks) SetCategoryThresholds(categoryId string, thresholds *dto.StockThresholds) error {
+	return dao.DB_SetCategoryStockThresholds(categoryId, thresholds)
+}
+
+func (mongoStocks) CategoryThresholds() (map[string]dto.StockThresholds, error) {
+	return dao.DB_FindCategoryStockThresholds()
+}
+
+type mongoSales struct{}
+
+func (mongoSales) Checkout(sale *dto.Sale, userId string, cart *dto.Cart) error {
+	return dao.DB_CheckoutSale(sale, userId, cart)
+}
+
+func (mongoSales) Void(saleId string, reason string, userId string) (*dto.Sale, error) {
+	return dao.DB_VoidSale(saleId, reason, userId)
+}
+
+func (mongoSales) FindById(saleId string) (*dto.Sale, error) {
+	return dao.FindSaleBySaleId(saleId)
+}
+
+func (mongoSales) FindAll(limit int64, offset int64) ([]dto.Sale, error) {
+	return dao.FindAllSales(limit, offset)
+}
+
+type mongoGRNs struct{}
+
+func (mongoGRNs) Create(grn *dto.GRN, userId string) error {
+	return dao.DB_CreateGRN(grn, userId)
+}
+
+func (mongoGRNs) UpdateStatus(grnId string, status string, updatedAt time.Time, userId string) (*dto.GRN, error) {
+	return dao.DB_UpdateGRNStatus(grnId, status, updatedAt, userId)
+}
+
+func (mongoGRNs) Exists(grnId string) (bool, error) {
+	return dao.DB_CheckGRNExists(grnId)
+}
+
+func (mongoGRNs) FindById(grnId string) (*dto.GRN, error) {
+	return dao.DB_FindGRNById(grnId)
+}
+
+func (mongoGRNs) FindAllPaginated(page int, limit int) ([]dto.GRN, int64, error) {
+	return dao.DB_FindAllGRNsPaginated(page, limit)
+}
+
+func (mongoGRNs) CountTotal() (int64, error) {
+	return dao.DB_CountTotalGRNs()
+}
+
+func (mongoGRNs) CountByStatus(status string) (int64, error) {
+	return dao.DB_CountGRNsByStatus(status)
+}
+
+func (mongoGRNs) FindByPurchaseOrder(poId string) ([]dto.GRN, error) {
+	return dao.DB_FindGRNsByPurchaseOrder(poId)
+}
+
+type mongoPurchaseOrders struct{}
+
+func (mongoPurchaseOrders) Create(po *dto.PurchaseOrder) error {
+	return dao.DB_CreatePurchaseOrder(po)
+}
+
+func (mongoPurchaseOrders) FindById(poId string) (*dto.PurchaseOrder, error) {
+	return dao.DB_FindPurchaseOrderById(poId)
+}
+
+func (mongoPurchaseOrders) FindAll(supplierId string, status string) ([]dto.PurchaseOrder, error) {
+	return dao.DB_FindPurchaseOrders(supplierId, status)
+}
+
+func (mongoPurchaseOrders) Update(po *dto.PurchaseOrder, fromStatuses ...string) error {
+	return dao.DB_UpdatePurchaseOrder(po, fromStatuses...)
+}
+
+func (mongoPurchaseOrders) UpdateStatus(po *dto.PurchaseOrder, fromStatuses ...string) error {
+	return dao.DB_UpdatePurchaseOrderStatus(po, fromStatuses...)
+}
+
+type mongoStockTakes struct{}
+
+func (mongoStockTakes) Create(stockTake *dto.StockTake) error {
+	return dao.DB_CreateStockTake(stockTake)
+}
+
+func (mongoStockTakes) FindById(stockTakeId string) (*dto.StockTake, error) {
+	return dao.DB_FindStockTakeById(stockTakeId)
+}
+
+func (mongoStockTakes) FindAll(status string, withLines bool) ([]dto.StockTake, error) {
+	return dao.DB_FindStockTakes(status, withLines)
+}
+
+func (mongoStockTakes) UpdateCounts(stockTake *dto.StockTake) error {
+	return dao.DB_UpdateStockTakeCounts(stockTake)
+}
+
+func (mongoStockTakes) Cancel(stockTakeId string, now time.Time) error {
+	return dao.DB_CancelStockTake(stockTakeId, now)
+}
+
+func (mongoStockTakes) Approve(stockTakeId string, userId string) (*dto.StockTake, error) {
+	return dao.DB_ApproveStockTake(stockTakeId, userId)
+}
+
+type mongoLocations struct{}
+
+func (mongoLocations) Create(location *dto.Location) error {
+	return dao.DB_CreateLocation(location)
+}
+
+func (mongoLocations) FindById(locationId string) (*dto.Location, error) {
+	return dao.DB_FindLocationById(locationId)
+}
+
+func (mongoLocations) FindAll(status string) ([]dto.Location, error) {
+	return dao.DB_FindLocations(status)
+}
+
+func (mongoLocations) Update(location *dto.Location) error {
+	return dao.DB_UpdateLocation(location)
+}
+
+type mongoSuppliers struct{}
+
+func (mongoSuppliers) Create(supplier *dto.Supplier) error {
+	return dao.DB_CreateSupplier(supplier)
+}
+
+func (mongoSuppliers) FindById(supplierId string) (*dto.Supplier, error) {
+	return dao.DB_FindSupplierById(supplierId)
+}
+
+func (mongoSuppliers) FindAll(status string) ([]dto.Supplier, error) {
+	return dao.DB_FindAllSuppliers(status)
+}
+
+func (mongoSuppliers) Update(ctx context.Context, supplier *dto.Supplier) error {
+	return dao.DB_UpdateSupplier(ctx, supplier)
+}
+
+func (mongoSuppliers) UpdateStatus(ctx context.Context, supplierId string, status string) error {
+	return dao.DB_UpdateSupplierStatus(ctx, supplierId, status)
+}
+
+func (mongoSuppliers) Delete(supplierId string) error {
+	return dao.DB_DeleteSupplierByID(supplierId)
+}
+
+func (mongoSuppliers) StatusCounts(ctx context.Context) (int64, int64, error) {
+	return dao.DB_GetSupplierStatusCounts(ctx)
+}
+
+func (mongoSuppliers) AssignProduct(supplierId string, productId string) error {
+	return dao.DB_AssignProductToSupplier(supplierId, productId)
+}
+
+func (mongoSuppliers) FindProductsBySupplier(supplierId string) ([]dto.SupplierProduct, error) {
+	return dao.DB_FindProductsBySupplierID(supplierId)
+}
+
+type mongoReports struct{}
+
+func (mongoReports) GetDailySalesSummary(targetDate time.Time, locationId string) (*dto.DailySalesSummary, error) {
+	return dao.GetDailySalesSummary(targetDate, locationId)
+}
+
+func (mongoReports) GetGrossProfitReport(start time.Time, end time.Time, locationId string) (*dto.GrossProfitReport, error) {
+	return dao.DB_GetGrossProfitReport(start, end, locationId)
+}
+
+func (mongoReports) GetSavedDailyReport(date time.Time, locationId string) (*dto.DailyReportDocument, error) {
+	return dao.GetDailyReportByDate(date, locationId)
+}
+
+func (mongoReports) GetSavedDailyReportsByMonth(year int, month int, locationId string) ([]dto.DailyReportDocument, error) {
+	return dao.GetDailyReportsByMonth(year, month, locationId)
+}
+
+func (mongoReports) GetSavedDailyReportsBetween(start time.Time, end time.Time, locationId string) ([]dto.DailyReportDocument, error) {
+	return dao.GetDailyReportsBetween(start, end, locationId)
+}
+
+func (mongoReports) SaveDailyReport(summary *dto.DailySalesSummary) error {
+	return dao.SaveDailyReport(summary)
+}
+
+func (mongoReports) CalculateTotalAndExpectedCost() (dto.Money, dto.Money, error) {
+	return dao.DB_CalculateTotalAndExpectedCost()
+}
+
+func (mongoReports) GetBrandCostSummary(brandId string) (dto.Money, dto.Money, error) {
+	return dao.DB_GetBrandCostSummary(brandId)
+}
+
+func (mongoReports) GetRollup(period string, year int, month int) (*dto.ReportRollup, error) {
+	return dao.DB_FindReportRollup(period, year, month)
+}
+
+func (mongoReports) FindRollups(period string, from time.Time, to time.Time) ([]dto.ReportRollup, error) {
+	return dao.DB_FindReportRollups(period, from, to)
+}
+
+func (mongoReports) RefreshRollups(year int, month int) (*dto.ReportRollup, error) {
+	return dao.DB_RefreshReportRollups(year, month)
+}
+
+type mongoReturns struct{}
+
+func (mongoReturns) CreateSaleReturn(ret *dto.ReturnDTO, userId string) error {
+	return dao.DB_CreateSaleReturn(ret, userId)
+}
+
+func (mongoReturns) FindAll(ctx context.Context) ([]dto.ReturnDTO, error) {
+	return dao.GetAllReturns(ctx)
+}
+
+func (mongoReturns) FindById(ctx context.Context, id string) (*dto.ReturnDTO, error) {
+	return dao.GetReturnByID(ctx, id)
+}
+
+func (mongoReturns) FindByDateRange(ctx context.Context, start time.Time, end time.Time) ([]dto.ReturnDTO, error) {
+	return dao.GetReturnsByDateRange(ctx, start, end)
+}
+
+type mongoCustomers struct{}
+
+func (mongoCustomers) Create(customer *dto.Customer) error {
+	return dao.DB_CreateCustomer(customer)
+}
+
+func (mongoCustomers) FindById(customerId string) (*dto.Customer, error) {
+	return dao.DB_FindCustomerById(customerId)
+}
+
+func (mongoCustomers) FindByMobile(mobileNumber string) (*dto.Customer, error) {
+	return dao.DB_FindCustomerByMobile(mobileNumber)
+}
+
+func (mongoCustomers) FindAll(search string) ([]dto.Customer, error) {
+	return dao.DB_FindAllCustomers(search)
+}
+
+func (mongoCustomers) Update(customer *dto.Customer) error {
+	return dao.DB_UpdateCustomer(customer)
+}
+
+func (mongoCustomers) Delete(customerId string) error {
+	return dao.DB_DeleteCustomer(customerId)
+}
+
+func (mongoCustomers) FindSales(customerId string, page int, limit int) ([]dto.Sale, int64, error) {
+	return dao.DB_FindCustomerSales(customerId, page, limit)
+}
+
+type mongoPromotions struct{}
+
+func (mongoPromotions) Create(promotion *dto.Promotion) error {
+	return dao.DB_CreatePromotion(promotion)
+}
+
+func (mongoPromotions) FindById(promotionId string) (*dto.Promotion, error) {
+	return dao.DB_FindPromotionById(promotionId)
+}
+
+func (mongoPromotions) FindAll(activeOnly bool) ([]dto.Promotion, error) {
+	return dao.DB_FindAllPromotions(activeOnly)
+}
+
+func (mongoPromotions) Update(promotion *dto.Promotion) error {
+	return dao.DB_UpdatePromotion(promotion)
+}
+
+func (mongoPromotions) Delete(promotionId string) error {
+	return dao.DB_DeletePromotion(promotionId)
+}
+
+func (mongoPromotions) FindRunning(now time.Time) ([]dto.Promotion, error) {
+	return dao.DB_FindRunningPromotions(now)
+}
+
+type mongoCarts struct{}
+
+func (mongoCarts) Create(cart *dto.Cart) error {
+	return dao.DB_CreateCart(cart)
+}
+
+func (mongoCarts) FindById(cartId string) (*dto.Cart, error) {
+	return dao.DB_FindCartById(cartId)
+}
+
+func (mongoCarts) FindByTerminal(terminalId string, status string) ([]dto.Cart, error) {
+	return dao.DB_FindCartsByTerminal(terminalId, status)
+}
+
+func (mongoCarts) Update(cart *dto.Cart, fromStatuses ...string) error {
+	return dao.DB_UpdateCart(cart, fromStatuses...)
+}
+
+func (mongoCarts) ReservedQuantities(locationId string, excludeCartId string, now time.Time) (map[string]int, error) {
+	return dao.DB_FindReservedQuantities(locationId, excludeCartId, now)
+}
+
+type mongoTaxes struct{}
+
+func (mongoTaxes) CreateClass(class *dto.TaxClass) error {
+	return dao.DB_CreateTaxClass(class)
+}
+
+func (mongoTaxes) FindClassById(taxClassId string) (*dto.TaxClass, error) {
+	return dao.DB_FindTaxClassById(taxClassId)
+}
+
+func (mongoTaxes) FindAllClasses() ([]dto.TaxClass, error) {
+	return dao.DB_FindAllTaxClasses()
+}
+
+func (mongoTaxes) UpdateClass(class *dto.TaxClass) error {
+	return dao.DB_UpdateTaxClass(class)
+}
+
+func (mongoTaxes) DeleteClass(taxClassId string) error {
+	return dao.DB_DeleteTaxClass(taxClassId)
+}
+
+func (mongoTaxes) SetCategoryClass(categoryId string, taxClassId string) error {
+	return dao.DB_SetCategoryTaxClass(categoryId, taxClassId)
+}
+
+func (mongoTaxes) CategoryClasses() (map[string]string, error) {
+	return dao.DB_FindCategoryTaxClasses()
+}
+
+type mongoUsers struct{}
+
+func (mongoUsers) Create(user *dto.User) error {
+	return dao.DB_CreateUser(user)
+}
+
+func (mongoUsers) FindById(userId string) (*dto.User, error) {
+	return dao.DB_FindUserById(userId)
+}
+
+func (mongoUsers) FindByUsername(username string) (*dto.User, error) {
+	return dao.DB_FindUserByUsername(username)
+}
+
+func (mongoUsers) FindAll() ([]dto.User, error) {
+	return dao.DB_FindAllUsers()
+}
+
+func (mongoUsers) Count() (int64, error) {
+	return dao.DB_CountUsers()
+}
+
+func (mongoUsers) Update(userId string, changes dto.UserChanges) error {
+	return dao.DB_UpdateUser(userId, changes)
+}
@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"employee-crud/dao"
+	"employee-crud/dto"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Errors every implementation returns for the same situations, so handlers can map them to status codes
+var (
+	// ErrNotFound is returned when a document does not exist
+	ErrNotFound = mongo.ErrNoDocuments
+	// ErrProductVersionConflict is returned when a product keeps changing underneath a batch mutation
+	ErrProductVersionConflict = dao.ErrProductVersionConflict
+	// ErrGRNAlreadyPosted is returned when a posted GRN is moved back to pending
+	ErrGRNAlreadyPosted = dao.ErrGRNAlreadyPosted
+	// ErrMobileTaken is returned when another active customer already has the mobile number
+	ErrMobileTaken = dao.ErrMobileTaken
+	// ErrTaxClassInUse is returned when a tax class that products or categories are assigned to is deleted
+	ErrTaxClassInUse = dao.ErrTaxClassInUse
+	// ErrCartStatusChanged is returned when a cart is no longer in the status a change expects
+	ErrCartStatusChanged = dao.ErrCartStatusChanged
+	// ErrPurchaseOrderStatusChanged is returned when a purchase order is no longer in the status a change expects
+	ErrPurchaseOrderStatusChanged = dao.ErrPurchaseOrderStatusChanged
+	// ErrStockTakeStatusChanged is returned when a stock take is no longer open
+	ErrStockTakeStatusChanged = dao.ErrStockTakeStatusChanged
+	// ErrUsernameTaken is returned when creating a user whose username already exists
+	ErrUsernameTaken = dao.ErrUsernameTaken
+)
+
+// Repositories groups the data access used by the api handlers
+type Repositories struct {
+	Products       ProductRepository
+	Stocks         StockRepository
+	Sales          SaleRepository
+	GRNs           GRNRepository
+	Suppliers      SupplierRepository
+	Reports        ReportRepository
+	Returns        ReturnRepository
+	Customers      CustomerRepository
+	Promotions     PromotionRepository
+	Taxes          TaxRepository
+	Carts          CartRepository
+	PurchaseOrders PurchaseOrderRepository
+	StockTakes     StockTakeRepository
+	Locations      LocationRepository
+	Users          UserRepository
+	Ids            IdGenerator
+}
+
+// IdGenerator issues sequential ids such as PRD-001 per collection
+type IdGenerator interface {
+	NextId(ctx context.Context, collectionName string, prefix string) (string, error)
+}
+
+// ProductRepository reads and writes products and their batches
+// Batch mutations record their quantity changes in the stock movement ledger with the given ref
+type ProductRepository interface {
+	FindById(productId string) (*dto.Product, error)
+	FindByAttributes(categoryId, brandId, subCategoryId string) (*dto.Product, error)
+	FindAll() ([]dto.Product, error)
+	FindAllCursorPaginated(limit int, cursor string) ([]dto.Product, string, bool, error)
+	FindByBarcodeCursorPaginated(barcode string, limit int, cursor string) ([]dto.Product, string, bool, error)
+	FindByBrandCursorPaginated(brandId string, limit int, cursor string) ([]dto.Product, string, bool, error)
+	FindByCategoryCursorPaginated(categoryId string, limit int, cursor string) ([]dto.Product, string, bool, error)
+	FindBySubCategory(subCategoryId string) ([]dto.Product, error)
+	FindAllDeleted() ([]dto.Product, error)
+	CountTotal() (int64, error)
+	CountCategorized() (int64, error)
+	CountByBrand(brandId string) (int64, error)
+	CountByCategory(categoryId string) (int64, error)
+
+	Create(product *dto.Product, ref dto.StockMovementRef) error
+	// Update saves a product's details; its tax class, reorder settings and stock thresholds only change as set in settings,
+	// and its own thresholds are only dropped when settings.ClearStockThresholds is set
+	Update(ctx context.Context, product *dto.Product, settings dto.ProductSettingChanges) error
+	Delete(productId string) error
+	DeletePermanent(productId string) error
+	Restore(productId, categoryId, brandId, subCategoryId string) error
+
+	AddBatch(productId string, batch dto.Batch, ref dto.StockMovementRef) error
+	ConvertToBatches(product *dto.Product, initialBatch dto.Batch, ref dto.StockMovementRef) error
+	// AddStock adds stock at a location; an empty locationId is the main location
+	AddStock(productId string, locationId string, stockQty int, expiryDate *time.Time, costPrice dto.Money, sellingPrice dto.Money, ref dto.StockMovementRef) (*dto.Product, string, error)
+	EditBatchStock(productId string, batchId string, newStockQty int, ref dto.StockMovementRef) (*dto.Product, error)
+	EditBatchDetails(productId string, batchId string, expiryDate *time.Time, costPrice dto.Money, sellingPrice dto.Money, ref dto.StockMovementRef) (*dto.Product, error)
+	RemoveStockFromBatch(productId string, batchId string, quantityToRemove int, ref dto.StockMovementRef) (*dto.Product, error)
+	DeleteBatch(productId string, batchId string, ref dto.StockMovementRef) (*dto.Product, error)
+}
+
+// StockRepository reads the per-batch Stocks view and the stock movement ledger
+// A non-empty locationId limits a listing or count to the batches held at that location
+type StockRepository interface {
+	SyncProduct(product *dto.Product) error
+	SyncAll() error
+	Count(locationId string) (int64, error)
+	FindAllCursorPaginated(limit int, cursor string, locationId string) ([]dto.Stock, string, bool, error)
+	// FindFilteredCursorPaginated and CountFiltered match products by the status of their total stock, or their
+	// stock at the location, one of dto.StockStatusLow, dto.StockStatusAverage and dto.StockStatusGood, under their
+	// resolved thresholds
+	FindFilteredCursorPaginated(limit int, cursor string, status string, locationId string) ([]dto.Stock, string, bool, error)
+	CountFiltered(status string, locationId string) (int64, error)
+	TotalQuantity() (int64, error)
+	StatusCounts() (*dao.StockStatusCounts, error)
+	CleanupOrphaned() (int64, error)
+	ValidateIntegrity() (map[string]interface{}, error)
+	FindProductsWithStock(limit int, cursor string) ([]dao.ProductWithStockInfo, string, bool, error)
+	CountProductsWithStock() (int64, error)
+	FindTopExpiring(topN int, now time.Time, until time.Time) ([]dao.ProductWithStockInfo, error)
+	FindLowStockProducts(limit int) ([]dto.Product, error)
+	FindMovements(filter dao.StockMovementFilter, page int, limit int) ([]dto.StockMovement, int64, error)
+	// SetCategoryThresholds sets the stock thresholds of a category's products that have none of their own;
+	// nil removes them
+	SetCategoryThresholds(categoryId string, thresholds *dto.StockThresholds) error
+	// CategoryThresholds returns the stock thresholds of every category that has its own
+	CategoryThresholds() (map[string]dto.StockThresholds, error)
+}
+
+// SaleRepository records and reads sales
+type SaleRepository interface {
+	// Checkout records the sale and deducts its items from stock atomically,
+	// booking points and spend on the sale's customer if it has one
+	// A sale made from cart (nil otherwise) marks the cart converted in the same step, as long as it is still in
+	// the status it was read in; otherwise nothing is recorded and ErrCartStatusChanged is returned
+	Checkout(sale *dto.Sale, userId string, cart *dto.Cart) error
+	// Void marks a sale voided, puts its units back into the batches they came from and reverses its customer booking
+	// Returns functions.ErrInvalidVoid if it was already voided or has returns
+	Void(saleId string, reason string, userId string) (*dto.Sale, error)
+	FindById(saleId string) (*dto.Sale, error)
+	FindAll(limit int64, offset int64) ([]dto.Sale, error)
+}
+
+// GRNRepository records goods received notes and posts them into inventory
+type GRNRepository interface {
+	// Create records the GRN; one raised against a purchase order is linked to it in the same step,
+	// and one created in any status but pending has its received lines posted into stock in that step too
+	Create(grn *dto.GRN, userId string) error
+	// UpdateStatus changes the status; completed and partial_received post the received lines into batches
+	// and recount what the GRN's purchase order has received
+	UpdateStatus(grnId string, status string, updatedAt time.Time, userId string) (*dto.GRN, error)
+	Exists(grnId string) (bool, error)
+	FindById(grnId string) (*dto.GRN, error)
+	FindAllPaginated(page int, limit int) ([]dto.GRN, int64, error)
+	CountTotal() (int64, error)
+	CountByStatus(status string) (int64, error)
+	// FindByPurchaseOrder returns the GRNs raised against a purchase order, oldest first
+	FindByPurchaseOrder(poId string) ([]dto.GRN, error)
+}
+
+// PurchaseOrderRepository stores the purchase orders placed with suppliers
+// Received quantities are never written here: GRNRepository recounts them as GRNs are created and posted
+type PurchaseOrderRepository interface {
+	Create(po *dto.PurchaseOrder) error
+	FindById(poId string) (*dto.PurchaseOrder, error)
+	// FindAll returns the purchase orders of a supplier in a status, newest first; empty filters match everything
+	FindAll(supplierId string, status string) ([]dto.PurchaseOrder, error)
+	// Update saves the number, lines, total, expected date and notes if the PO is still in one of fromStatuses,
+	// otherwise returns ErrPurchaseOrderStatusChanged
+	Update(po *dto.PurchaseOrder, fromStatuses ...string) error
+	// UpdateStatus saves the status and approval, sent and closed details if the PO is still in one of fromStatuses,
+	// otherwise returns ErrPurchaseOrderStatusChanged
+	UpdateStatus(po *dto.PurchaseOrder, fromStatuses ...string) error
+}
+
+// StockTakeRepository stores stock takes and posts them into the batches
+type StockTakeRepository interface {
+	Create(stockTake *dto.StockTake) error
+	FindById(stockTakeId string) (*dto.StockTake, error)
+	// FindAll returns the stock takes in a status, newest first; an empty status matches everything
+	// withLines false leaves the lines out
+	FindAll(status string, withLines bool) ([]dto.StockTake, error)
+	// UpdateCounts saves the lines and totals if the stock take is still open, otherwise returns ErrStockTakeStatusChanged
+	UpdateCounts(stockTake *dto.StockTake) error
+	// Cancel closes an open stock take without touching stock, otherwise returns ErrStockTakeStatusChanged
+	Cancel(stockTakeId string, now time.Time) error
+	// Approve posts the variances of an open stock take into the batches and the stock movement ledger
+	// in one transaction, and returns it approved; returns ErrStockTakeStatusChanged if it is no longer open
+	Approve(stockTakeId string, userId string) (*dto.StockTake, error)
+}
+
+// LocationRepository reads and writes the stores and warehouses
+type LocationRepository interface {
+	Create(location *dto.Location) error
+	// FindById returns mongo.ErrNoDocuments if the location does not exist
+	FindById(locationId string) (*dto.Location, error)
+	// FindAll returns the locations in a status by name; an empty status matches everything
+	FindAll(status string) ([]dto.Location, error)
+	// Update saves the location's details and status, or returns mongo.ErrNoDocuments if it does not exist
+	Update(location *dto.Location) error
+}
+
+// UserRepository reads and writes the accounts that sign in to the API
+type UserRepository interface {
+	// Create returns ErrUsernameTaken if another user already has the username
+	Create(user *dto.User) error
+	// FindById and FindByUsername return mongo.ErrNoDocuments if the user does not exist
+	FindById(userId string) (*dto.User, error)
+	FindByUsername(username string) (*dto.User, error)
+	// FindAll returns every user by username
+	FindAll() ([]dto.User, error)
+	Count() (int64, error)
+	// Update applies the changes, or returns mongo.ErrNoDocuments if the user does not exist
+	Update(userId string, changes dto.UserChanges) error
+}
+
+// SupplierRepository reads and writes suppliers and their product assignments
+type SupplierRepository interface {
+	Create(supplier *dto.Supplier) error
+	FindById(supplierId string) (*dto.Supplier, error)
+	FindAll(status string) ([]dto.Supplier, error)
+	Update(ctx context.Context, supplier *dto.Supplier) error
+	UpdateStatus(ctx context.Context, supplierId string, status string) error
+	Delete(supplierId string) error
+	StatusCounts(ctx context.Context) (active int64, inactive int64, err error)
+	AssignProduct(supplierId string, productId string) error
+	FindProductsBySupplier(supplierId string) ([]dto.SupplierProduct, error)
+}
+
+// CustomerRepository manages the customer registry
+// Loyalty points and spend are not set here: SaleRepository.Checkout updates them with the sale
+type CustomerRepository interface {
+	Create(customer *dto.Customer) error
+	FindById(customerId string) (*dto.Customer, error)
+	FindByMobile(mobileNumber string) (*dto.Customer, error)
+	FindAll(search string) ([]dto.Customer, error)
+	Update(customer *dto.Customer) error
+	Delete(customerId string) error
+	// FindSales returns one page of the customer's sales, newest first, and the total count
+	FindSales(customerId string, page int, limit int) ([]dto.Sale, int64, error)
+}
+
+// PromotionRepository stores the promotion rules evaluated at checkout
+type PromotionRepository interface {
+	Create(promotion *dto.Promotion) error
+	FindById(promotionId string) (*dto.Promotion, error)
+	FindAll(activeOnly bool) ([]dto.Promotion, error)
+	Update(promotion *dto.Promotion) error
+	Delete(promotionId string) error
+	// FindRunning returns the active promotions whose dates include now; days and times are left to functions.PromotionRunsAt
+	FindRunning(now time.Time) ([]dto.Promotion, error)
+}
+
+// TaxRepository stores tax classes and the tax class of each category
+type TaxRepository interface {
+	CreateClass(class *dto.TaxClass) error
+	FindClassById(taxClassId string) (*dto.TaxClass, error)
+	FindAllClasses() ([]dto.TaxClass, error)
+	UpdateClass(class *dto.TaxClass) error
+	// DeleteClass returns ErrTaxClassInUse while an active product or category is assigned to the class
+	DeleteClass(taxClassId string) error
+	// SetCategoryClass assigns a tax class to a category; an empty taxClassId removes the assignment
+	SetCategoryClass(categoryId string, taxClassId string) error
+	// CategoryClasses returns the tax class id of every category that has one
+	CategoryClasses() (map[string]string, error)
+}
+
+// CartRepository stores the draft carts held at terminals
+type CartRepository interface {
+	Create(cart *dto.Cart) error
+	FindById(cartId string) (*dto.Cart, error)
+	// FindByTerminal returns the carts of a terminal (every terminal if empty) in a status, most recently updated first
+	FindByTerminal(terminalId string, status string) ([]dto.Cart, error)
+	// Update replaces the cart if it is still in one of fromStatuses, otherwise returns ErrCartStatusChanged
+	Update(cart *dto.Cart, fromStatuses ...string) error
+	// ReservedQuantities sums per product the quantities reserved at a location at now by every cart but excludeCartId
+	ReservedQuantities(locationId string, excludeCartId string, now time.Time) (map[string]int, error)
+}
+
+// ReportRepository builds sales summaries and reads saved daily reports, report rollups and cost totals
+// Summaries and daily reports take a locationId; empty means the whole business
+type ReportRepository interface {
+	GetDailySalesSummary(targetDate time.Time, locationId string) (*dto.DailySalesSummary, error)
+	// GetGrossProfitReport works out the gross profit of the sales created in [start, end)
+	GetGrossProfitReport(start time.Time, end time.Time, locationId string) (*dto.GrossProfitReport, error)
+	GetSavedDailyReport(date time.Time, locationId string) (*dto.DailyReportDocument, error)
+	GetSavedDailyReportsByMonth(year int, month int, locationId string) ([]dto.DailyReportDocument, error)
+	// GetSavedDailyReportsBetween returns the saved reports of the days starting in [start, end), oldest first
+	GetSavedDailyReportsBetween(start time.Time, end time.Time, locationId string) ([]dto.DailyReportDocument, error)
+	// SaveDailyReport saves the report of the summary's day and location, replacing one saved before
+	SaveDailyReport(summary *dto.DailySalesSummary) error
+	CalculateTotalAndExpectedCost() (dto.Money, dto.Money, error)
+	GetBrandCostSummary(brandId string) (dto.Money, dto.Money, error)
+
+	// GetRollup returns a permanent monthly or yearly rollup (month is 0 for yearly)
+	GetRollup(period string, year int, month int) (*dto.ReportRollup, error)
+	// FindRollups returns the rollups of one period type starting in [from, to], oldest first
+	FindRollups(period string, from time.Time, to time.Time) ([]dto.ReportRollup, error)
+	// RefreshRollups rebuilds a month's rollup from its saved daily reports and then its year's rollup
+	RefreshRollups(year int, month int) (*dto.ReportRollup, error)
+}
+
+// ReturnRepository records returns against sales
+type ReturnRepository interface {
+	// CreateSaleReturn validates the return against its sale, restocks or writes off each line and saves it
+	CreateSaleReturn(ret *dto.ReturnDTO, userId string) error
+	FindAll(ctx context.Context) ([]dto.ReturnDTO, error)
+	FindById(ctx context.Context, id string) (*dto.ReturnDTO, error)
+	FindByDateRange(ctx context.Context, start time.Time, end time.Time) ([]dto.ReturnDTO, error)
+}
@@ -0,0 +1,192 @@
+// Money Migration Script
+// Converts money fields stored as doubles (or whole numbers) to Decimal128 rounded to the cent, the way
+// dto.Money rounds them: to the nearest cent, halves away from zero. Safe to run more than once; values that
+// are already Decimal128 are left alone. The server reads both forms, so it can run while the server is up.
+// Sales kept inside SalesArchive data are decoded through dto.Money when restored and are not rewritten.
+// Run with: go run scripts/money_migration/migrate_money_decimal.go
+package main
+
+import (
+	"context"
+	"employee-crud/config"
+	"employee-crud/dto"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// moneyFields lists the money fields of each collection; dotted paths go through sub-documents and arrays
+// Report product lines and returns have no bson tags, so their keys are the lowercased Go field names
+var moneyFields = map[string][]string{
+	"Products": {"costPrice", "sellingPrice", "batches.costPrice", "batches.sellingPrice"},
+	"Sales": {
+		"subtotal", "promotionDiscount", "tax", "includedTax", "discount", "total", "loyaltyDiscount",
+		"amountReceived", "change",
+		"items.unitPrice", "items.totalPrice", "items.discount", "items.tax",
+		"tenders.amount", "taxBreakdown.taxableAmount", "taxBreakdown.tax",
+	},
+	"GRNs": {"totalAmount", "items.unitCost", "items.totalCost"},
+	"DailyReports": {
+		"totalRevenue", "totalDiscount", "totalTax", "cashRevenue", "cardRevenue",
+		"tenders.amount", "taxBreakdown.taxableAmount", "taxBreakdown.tax",
+		"productsSold.unitprice", "productsSold.totalamount", "topSellingItems.unitprice", "topSellingItems.totalamount",
+	},
+	"ReportRollups": {
+		"totalRevenue", "totalDiscount", "totalTax",
+		"tenders.amount", "taxBreakdown.taxableAmount", "taxBreakdown.tax",
+		"productsSold.unitprice", "productsSold.totalamount", "topSellingItems.unitprice", "topSellingItems.totalamount",
+	},
+	"SalesArchive":   {"totalRevenue"},
+	"StockMovements": {"costPrice", "sellingPrice"},
+	"StockWriteOffs": {"costPrice", "totalCost"},
+	"Customers":      {"lifetimeSpend"},
+	"Promotions":     {"bundlePrice", "minBasketValue"},
+	"returns":        {"totalrefund", "products.amount", "products.unitprice", "products.discountshare", "products.taxshare"},
+}
+
+func main() {
+	fmt.Println("=== Migrate Money Fields to Decimal128 ===")
+	fmt.Println("Starting at:", time.Now().Format(time.RFC3339))
+
+	// Database configuration comes from CONFIG_FILE / MONGO_URI / DB_NAME, like the server
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+	if cfg.Mongo.URI == "" {
+		log.Fatal("missing required configuration: mongo.uri (MONGO_URI)")
+	}
+
+	// Connect to MongoDB
+	fmt.Println("\nConnecting to MongoDB...")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(cfg.Mongo.URI))
+	if err != nil {
+		log.Fatal("Failed to connect to MongoDB:", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	ctx := context.Background()
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		log.Fatal("Failed to ping MongoDB:", err)
+	}
+	fmt.Println("✓ Connected to MongoDB successfully")
+
+	database := client.Database(cfg.Mongo.Database)
+	for name, paths := range moneyFields {
+		fmt.Printf("\nMigrating %s...\n", name)
+		updated, err := migrateCollection(ctx, database.Collection(name), paths)
+		if err != nil {
+			log.Fatalf("Failed to migrate %s: %v", name, err)
+		}
+		fmt.Printf("  ✓ %d documents updated\n", updated)
+	}
+
+	fmt.Println("\n=== Migration completed at:", time.Now().Format(time.RFC3339), "===")
+}
+
+// migrateCollection rewrites the money fields of every document that still has one that is not Decimal128
+func migrateCollection(ctx context.Context, collection *mongo.Collection, paths []string) (int, error) {
+	// Only the top-level fields are needed, since a whole array is written back at once
+	projection := bson.M{}
+	for _, path := range paths {
+		projection[strings.Split(path, ".")[0]] = 1
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return updated, err
+		}
+
+		set := bson.M{}
+		for _, path := range paths {
+			parts := strings.Split(path, ".")
+			value, exists := doc[parts[0]]
+			if !exists {
+				continue
+			}
+			if converted, changed := convertMoney(value, parts[1:]); changed {
+				doc[parts[0]] = converted
+				set[parts[0]] = converted
+			}
+		}
+		if len(set) == 0 {
+			continue
+		}
+
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc["_id"]}, bson.M{"$set": set}); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, cursor.Err()
+}
+
+// convertMoney converts the value at path inside value to Decimal128, going through sub-documents and arrays
+// Returns the value with the conversion made and whether anything changed
+func convertMoney(value interface{}, path []string) (interface{}, bool) {
+	if array, ok := value.(primitive.A); ok {
+		changed := false
+		for i := range array {
+			converted, elementChanged := convertMoney(array[i], path)
+			if elementChanged {
+				array[i] = converted
+				changed = true
+			}
+		}
+		return array, changed
+	}
+
+	if len(path) == 0 {
+		switch number := value.(type) {
+		case float64:
+			return dto.MoneyFromFloat(number).Decimal128(), true
+		case int32:
+			return (dto.Money(number) * 100).Decimal128(), true
+		case int64:
+			return (dto.Money(number) * 100).Decimal128(), true
+		}
+		return value, false
+	}
+
+	if ordered, ok := value.(bson.D); ok {
+		changed := false
+		for i := range ordered {
+			if ordered[i].Key != path[0] {
+				continue
+			}
+			ordered[i].Value, changed = convertMoney(ordered[i].Value, path[1:])
+		}
+		return ordered, changed
+	}
+
+	doc, ok := value.(bson.M)
+	if !ok {
+		return value, false
+	}
+	field, exists := doc[path[0]]
+	if !exists {
+		return value, false
+	}
+	converted, changed := convertMoney(field, path[1:])
+	if changed {
+		doc[path[0]] = converted
+	}
+	return doc, changed
+}
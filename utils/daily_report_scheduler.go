@@ -1,16 +1,19 @@
 package utils
 
 import (
+	"employee-crud/config"
 	"employee-crud/dao"
 	"log"
 	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // StartDailyReportScheduler starts a background job that saves daily reports
 // It runs every hour and checks if it's time to save yesterday's report
 func StartDailyReportScheduler() {
-	// Sri Lanka timezone
-	sriLankaLoc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
+	// Business timezone
+	businessLoc := config.Location()
 
 	go func() {
 		ticker := time.NewTicker(1 * time.Hour) // Check every hour
@@ -21,7 +24,7 @@ func StartDailyReportScheduler() {
 		for {
 			select {
 			case <-ticker.C:
-				now := time.Now().In(sriLankaLoc)
+				now := time.Now().In(businessLoc)
 
 				// Check if it's past midnight (between 00:00 and 01:00)
 				// This ensures we save yesterday's report after the day is complete
@@ -31,7 +34,7 @@ func StartDailyReportScheduler() {
 					log.Printf("Attempting to save daily report for: %s\n", yesterday.Format("2006-01-02"))
 
 					// Get the sales summary for yesterday
-					summary, err := dao.GetDailySalesSummary(yesterday)
+					summary, err := dao.GetDailySalesSummary(yesterday, "")
 					if err != nil {
 						log.Printf("Error getting daily sales summary for %s: %v\n", yesterday.Format("2006-01-02"), err)
 						continue
@@ -43,6 +46,8 @@ func StartDailyReportScheduler() {
 						log.Printf("Error saving daily report for %s: %v\n", yesterday.Format("2006-01-02"), err)
 					} else {
 						log.Printf("Successfully saved daily report for %s\n", yesterday.Format("2006-01-02"))
+						saveLocationReports(yesterday)
+						refreshReportRollups(yesterday.Year(), int(yesterday.Month()))
 					}
 				}
 
@@ -64,20 +69,24 @@ func StartDailyReportScheduler() {
 // SaveMissingReports checks and saves reports for any missing dates in the past 7 days
 // This is useful for recovering from downtime
 func SaveMissingReports() {
-	sriLankaLoc := time.FixedZone("Asia/Colombo", 5*3600+30*60)
-	today := time.Now().In(sriLankaLoc)
+	businessLoc := config.Location()
+	today := time.Now().In(businessLoc)
+
+	// Months whose rollups need refreshing; always include the month of yesterday's report
+	yesterday := today.AddDate(0, 0, -1)
+	months := map[[2]int]bool{{yesterday.Year(), int(yesterday.Month())}: true}
 
 	// Check last 7 days
 	for i := 1; i <= 7; i++ {
 		checkDate := today.AddDate(0, 0, -i)
 
 		// Check if report already exists
-		_, err := dao.GetDailyReportByDate(checkDate)
+		_, err := dao.GetDailyReportByDate(checkDate, "")
 		if err != nil {
 			// Report doesn't exist, try to create it
 			log.Printf("Missing report detected for %s, attempting to create...\n", checkDate.Format("2006-01-02"))
 
-			summary, err := dao.GetDailySalesSummary(checkDate)
+			summary, err := dao.GetDailySalesSummary(checkDate, "")
 			if err != nil {
 				log.Printf("Error getting sales summary for %s: %v\n", checkDate.Format("2006-01-02"), err)
 				continue
@@ -88,7 +97,38 @@ func SaveMissingReports() {
 				log.Printf("Error saving report for %s: %v\n", checkDate.Format("2006-01-02"), err)
 			} else {
 				log.Printf("Successfully saved missing report for %s\n", checkDate.Format("2006-01-02"))
+				saveLocationReports(checkDate)
+				months[[2]int{checkDate.Year(), int(checkDate.Month())}] = true
 			}
 		}
 	}
+
+	for month := range months {
+		refreshReportRollups(month[0], month[1])
+	}
+}
+
+// saveLocationReports saves the day's report of every active location next to the one for the whole business
+func saveLocationReports(date time.Time) {
+	locations, err := dao.DB_FindLocations("active")
+	if err != nil {
+		log.Printf("Error finding locations for the reports of %s: %v\n", date.Format("2006-01-02"), err)
+		return
+	}
+	for _, location := range locations {
+		summary, err := dao.GetDailySalesSummary(date, location.LocationId)
+		if err == nil {
+			err = dao.SaveDailyReport(summary)
+		}
+		if err != nil {
+			log.Printf("Error saving %s report for %s: %v\n", location.LocationId, date.Format("2006-01-02"), err)
+		}
+	}
+}
+
+// refreshReportRollups rebuilds the permanent monthly and yearly rollups from the month's saved daily reports
+func refreshReportRollups(year int, month int) {
+	if _, err := dao.DB_RefreshReportRollups(year, month); err != nil && err != mongo.ErrNoDocuments {
+		log.Printf("Error refreshing report rollups for %d-%02d: %v\n", year, month, err)
+	}
 }
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"employee-crud/config"
+	"employee-crud/dao"
+	"employee-crud/functions"
+	"log"
+	"time"
+)
+
+// StartSalesArchiveScheduler starts a background job that moves sales older than archiveAfterDays into SalesArchive
+// It runs once at startup and then every night at 3 AM; 0 disables archiving
+func StartSalesArchiveScheduler(archiveAfterDays int) {
+	if archiveAfterDays <= 0 {
+		log.Println("Sales archiving disabled: sales stay in the Sales collection")
+		return
+	}
+
+	businessLoc := config.Location()
+
+	go func() {
+		archiveSales(archiveAfterDays, businessLoc)
+
+		ticker := time.NewTicker(1 * time.Hour) // Check every hour
+		defer ticker.Stop()
+
+		log.Println("Sales Archive Scheduler started")
+
+		for range ticker.C {
+			if time.Now().In(businessLoc).Hour() == 3 {
+				archiveSales(archiveAfterDays, businessLoc)
+			}
+		}
+	}()
+}
+
+// archiveSales archives every sale made before the start of the business day archiveAfterDays ago
+// Cutting at a day boundary keeps each archived day complete
+func archiveSales(archiveAfterDays int, businessLoc *time.Location) {
+	cutoff := functions.BusinessDay(time.Now(), businessLoc).AddDate(0, 0, -archiveAfterDays)
+
+	count, err := dao.DB_ArchiveSalesBefore(cutoff)
+	if err != nil {
+		log.Printf("Error archiving sales before %s: %v\n", cutoff.Format("2006-01-02"), err)
+	}
+	if count > 0 {
+		log.Printf("Archived %d sales made before %s\n", count, cutoff.Format("2006-01-02"))
+	}
+}